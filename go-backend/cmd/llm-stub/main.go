@@ -0,0 +1,27 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+
+	"sical-go-backend/pkg/llm/llmstub"
+)
+
+func main() {
+	// 解析命令行参数
+	var (
+		addr    = flag.String("addr", ":8090", "监听地址")
+		latency = flag.Duration("latency", 0, "每次响应前的等待时间，用于模拟慢速模型")
+	)
+	flag.Parse()
+
+	handler := llmstub.NewHandler()
+	handler.Latency = *latency
+
+	// 客户端以 LLM_BASE_URL=http://localhost:8090/v1 访问 /v1/chat/completions
+	log.Printf("LLM桩服务已启动: %s", *addr)
+	if err := http.ListenAndServe(*addr, handler); err != nil {
+		log.Fatalf("LLM桩服务退出: %v", err)
+	}
+}
@@ -1,13 +1,19 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
 
 	"sical-go-backend/internal/domain/entities"
+	domainrepos "sical-go-backend/internal/domain/repositories"
+	"sical-go-backend/internal/domain/services"
 	"sical-go-backend/internal/infrastructure/database"
+	"sical-go-backend/internal/infrastructure/repositories"
 	"sical-go-backend/internal/pkg"
 	"sical-go-backend/pkg/logger"
 )
@@ -15,8 +21,15 @@ import (
 func main() {
 	// 解析命令行参数
 	var (
-		action = flag.String("action", "migrate", "迁移操作: migrate, rollback, seed")
+		action = flag.String("action", "migrate", "迁移操作: migrate, rollback, seed, import-knowledge, export-knowledge")
 		env    = flag.String("env", "development", "环境: development, production, test")
+
+		// 知识点批量导入导出参数
+		format   = flag.String("format", "jsonl", "知识点文件格式: csv, jsonl, markdown")
+		path     = flag.String("path", "", "导入的文件或Markdown目录，导出的目标文件(csv、jsonl)或目录(markdown)")
+		dryRun   = flag.Bool("dry-run", false, "导入时只校验不保存")
+		category = flag.String("category", "", "按类别筛选导出的知识点")
+		status   = flag.String("status", "", "按编辑状态筛选导出的知识点")
 	)
 	flag.Parse()
 
@@ -75,6 +88,15 @@ func main() {
 		if err := runSeed(db); err != nil {
 			logger.Fatal("种子数据创建失败", logger.Err(err))
 		}
+	case "import-knowledge":
+		if err := runKnowledgeImport(db, services.KnowledgeTransferFormat(*format), *path, *dryRun); err != nil {
+			logger.Fatal("导入知识点失败", logger.Err(err))
+		}
+	case "export-knowledge":
+		filter := domainrepos.KnowledgeExportFilter{Category: *category, Status: *status}
+		if err := runKnowledgeExport(db, services.KnowledgeTransferFormat(*format), *path, filter); err != nil {
+			logger.Fatal("导出知识点失败", logger.Err(err))
+		}
 	case "rollback":
 		logger.Warn("回滚功能暂未实现")
 	default:
@@ -96,6 +118,46 @@ func runMigration(db *database.Database) error {
 		&entities.GoalAnalysis{},
 		&entities.LearningPath{},
 		&entities.KnowledgePoint{},
+		&entities.StepTimeLog{},
+		&entities.GoalProgressHistory{},
+		&entities.StatusTransition{},
+		&entities.Category{},
+		&entities.Skill{},
+		&entities.CategorySkill{},
+		&entities.CategoryDuration{},
+		&entities.AnalysisUsage{},
+		&entities.AnalysisJob{},
+		&entities.RecommendationOutcome{},
+		&entities.Assessment{},
+		&entities.AssessmentQuestion{},
+		&entities.AssessmentAttempt{},
+		&entities.AttemptAnswer{},
+		&entities.RegradeRequest{},
+		&entities.DiagnosticSession{},
+		&entities.DiagnosticResponse{},
+		&entities.UserKnowledgeMastery{},
+		&entities.Comment{},
+		&entities.CommentEdit{},
+		&entities.CommentLike{},
+		&entities.CommentMention{},
+		&entities.ModerationRule{},
+		&entities.ModerationCase{},
+		&entities.ContentReport{},
+		&entities.Rating{},
+		&entities.RatingHelpfulVote{},
+		&entities.RatingSummary{},
+		&entities.SearchLog{},
+		&entities.KnowledgePointRevision{},
+		&entities.KnowledgeReviewEvent{},
+		&entities.KnowledgeResource{},
+		&entities.KnowledgePointRendering{},
+		&entities.KnowledgeRelation{},
+		&entities.KnowledgeCooccurrence{},
+	}
+
+	// 用户ID列改为账号ID，自动迁移无法直接转换UUID列
+	if err := repositories.MigrateAccountIDColumns(context.Background(), db.DB); err != nil {
+		return fmt.Errorf("转换用户ID列失败: %w", err)
 	}
 
 	// 执行自动迁移
@@ -108,6 +170,14 @@ func runMigration(db *database.Database) error {
 		}
 	}
 
+	// 知识点全文检索配置、生成列和索引
+	if err := repositories.SetupKnowledgeSearch(context.Background(), db.DB); err != nil {
+		return fmt.Errorf("创建知识点检索索引失败: %w", err)
+	}
+	if err := repositories.SetupKnowledgeSuggest(context.Background(), db.DB); err != nil {
+		return fmt.Errorf("创建检索建议索引失败: %w", err)
+	}
+
 	logger.Info("数据库迁移成功完成")
 	return nil
 }
@@ -116,9 +186,115 @@ func runMigration(db *database.Database) error {
 func runSeed(db *database.Database) error {
 	logger.Info("开始创建种子数据...")
 
-	// 暂时没有种子数据需要创建
-	// 可以在这里添加默认用户、角色等初始数据
+	// 默认类别与技能体系
+	taxonomyService := services.NewTaxonomyService(repositories.NewTaxonomyRepository(db.DB))
+	if err := taxonomyService.SeedDefaults(context.Background()); err != nil {
+		return fmt.Errorf("创建默认类别体系失败: %w", err)
+	}
 
 	logger.Info("种子数据创建完成")
 	return nil
-}
\ No newline at end of file
+}
+
+// newKnowledgeTransferService 创建知识点批量导入导出服务
+func newKnowledgeTransferService(db *database.Database) *services.KnowledgeTransferService {
+	taxonomyService := services.NewTaxonomyService(repositories.NewTaxonomyRepository(db.DB))
+	return services.NewKnowledgeTransferService(repositories.NewKnowledgeTransferRepository(db.DB), taxonomyService)
+}
+
+// runKnowledgeImport 从文件或Markdown目录批量导入知识点，以系统身份创建草稿，导入报告输出到标准输出
+func runKnowledgeImport(db *database.Database, format services.KnowledgeTransferFormat, path string, dryRun bool) error {
+	if path == "" {
+		return fmt.Errorf("请通过-path指定导入的文件或目录")
+	}
+
+	var batch *services.KnowledgeImportBatch
+	switch format {
+	case services.KnowledgeFormatCSV, services.KnowledgeFormatJSONL:
+		file, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("打开导入文件失败: %w", err)
+		}
+		defer file.Close()
+		if format == services.KnowledgeFormatCSV {
+			batch, err = services.DecodeKnowledgeCSV(file)
+		} else {
+			batch, err = services.DecodeKnowledgeJSONL(file)
+		}
+		if err != nil {
+			return err
+		}
+	case services.KnowledgeFormatMarkdown:
+		var err error
+		if batch, err = services.DecodeKnowledgeMarkdown(os.DirFS(path)); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("不支持的格式: %s", format)
+	}
+
+	editor := services.KnowledgeEditor{Author: true}
+	report, err := newKnowledgeTransferService(db).Import(context.Background(), batch, editor, dryRun)
+	if err != nil {
+		return err
+	}
+	output, _ := json.MarshalIndent(report, "", "  ")
+	fmt.Println(string(output))
+
+	if len(report.Errors) > 0 {
+		return fmt.Errorf("导入校验发现%d个问题，未保存任何知识点", len(report.Errors))
+	}
+	logger.Info("知识点导入完成",
+		logger.Bool("dry_run", dryRun),
+		logger.Int("created", report.Created),
+		logger.Int("updated", report.Updated),
+		logger.Int("unchanged", report.Unchanged))
+	return nil
+}
+
+// runKnowledgeExport 导出知识点，markdown格式写入path目录，其余格式写入path文件
+func runKnowledgeExport(db *database.Database, format services.KnowledgeTransferFormat, path string, filter domainrepos.KnowledgeExportFilter) error {
+	if !format.IsValid() {
+		return fmt.Errorf("不支持的格式: %s", format)
+	}
+	if path == "" {
+		return fmt.Errorf("请通过-path指定导出的目标文件或目录")
+	}
+	editor := services.KnowledgeEditor{Author: true, Reviewer: true}
+	records, err := newKnowledgeTransferService(db).Export(context.Background(), filter, editor)
+	if err != nil {
+		return err
+	}
+
+	if format == services.KnowledgeFormatMarkdown {
+		files, err := services.EncodeKnowledgeMarkdown(records)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(path, 0o755); err != nil {
+			return fmt.Errorf("创建导出目录失败: %w", err)
+		}
+		for _, file := range files {
+			if err := os.WriteFile(filepath.Join(path, file.Name), file.Data, 0o644); err != nil {
+				return fmt.Errorf("写入%s失败: %w", file.Name, err)
+			}
+		}
+	} else {
+		file, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("创建导出文件失败: %w", err)
+		}
+		defer file.Close()
+		if format == services.KnowledgeFormatCSV {
+			err = services.EncodeKnowledgeCSV(file, records)
+		} else {
+			err = services.EncodeKnowledgeJSONL(file, records)
+		}
+		if err != nil {
+			return fmt.Errorf("写入导出文件失败: %w", err)
+		}
+	}
+
+	logger.Info("知识点导出完成", logger.Int("count", len(records)))
+	return nil
+}
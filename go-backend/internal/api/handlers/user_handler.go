@@ -418,7 +418,7 @@ func (h *UserHandler) UpdateUserRole(c *gin.Context) {
 	}
 
 	var req struct {
-		Role string `json:"role" binding:"required,oneof=user admin super_admin"`
+		Role string `json:"role" binding:"required,oneof=user author reviewer moderator admin super_admin"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
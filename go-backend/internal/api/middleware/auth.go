@@ -86,6 +86,11 @@ func (m *AuthMiddleware) RequireAdmin() gin.HandlerFunc {
 	return m.RequireRole("admin", "super_admin")
 }
 
+// RequireModerator 需要审核员或管理员权限的中间件
+func (m *AuthMiddleware) RequireModerator() gin.HandlerFunc {
+	return m.RequireRole("moderator", "admin", "super_admin")
+}
+
 // RequireSuperAdmin 需要超级管理员权限的中间件
 func (m *AuthMiddleware) RequireSuperAdmin() gin.HandlerFunc {
 	return m.RequireRole("super_admin")
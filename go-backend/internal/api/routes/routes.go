@@ -6,7 +6,10 @@ import (
 
 	"sical-go-backend/internal/api/handlers"
 	"sical-go-backend/internal/api/middleware"
+	"sical-go-backend/internal/domain/services"
+	"sical-go-backend/internal/infrastructure/cache"
 	"sical-go-backend/internal/interfaces/http/routes"
+	"sical-go-backend/internal/pkg"
 )
 
 // Router 路由配置
@@ -14,18 +17,37 @@ type Router struct {
 	userHandler    *handlers.UserHandler
 	authMiddleware *middleware.AuthMiddleware
 	db             *gorm.DB
+	aiConfig       *pkg.AIConfig
+	storageConfig  *pkg.StorageConfig
+	redisCache     *cache.Redis
+
+	moderationService *services.ModerationService
+	workers           *routes.Workers
 }
 
 // NewRouter 创建路由实例
+// moderationService 由 routes.NewModerationService 创建，须与创建用户服务时传入的实例相同；
+// workers 为同一后台任务注册表，设置路由时注册的后台任务由服务启动后调用 workers.Start 运行，关闭时调用 workers.Stop 停止
 func NewRouter(
 	userHandler *handlers.UserHandler,
 	authMiddleware *middleware.AuthMiddleware,
 	db *gorm.DB,
+	aiConfig *pkg.AIConfig,
+	storageConfig *pkg.StorageConfig,
+	redisCache *cache.Redis,
+	moderationService *services.ModerationService,
+	workers *routes.Workers,
 ) *Router {
 	return &Router{
 		userHandler:    userHandler,
 		authMiddleware: authMiddleware,
 		db:             db,
+		aiConfig:       aiConfig,
+		storageConfig:  storageConfig,
+		redisCache:     redisCache,
+
+		moderationService: moderationService,
+		workers:           workers,
 	}
 }
 
@@ -64,7 +86,8 @@ func (r *Router) SetupRoutes(engine *gin.Engine) {
 		learning := v1.Group("/learning")
 		learning.Use(r.authMiddleware.RequireAuth())
 		{
-			routes.SetupLearningGoalRoutes(learning, r.db)
+			routes.SetupLearningGoalRoutes(learning, r.db, r.aiConfig, r.workers)
+			routes.SetupLearningPathRoutes(learning, r.db)
 		}
 
 		// 管理员相关路由（需要管理员权限）
@@ -79,6 +102,50 @@ func (r *Router) SetupRoutes(engine *gin.Engine) {
 				users.PUT("/:id/status", r.userHandler.UpdateUserStatus)
 				users.PUT("/:id/role", r.userHandler.UpdateUserRole)
 			}
+
+			// 分析结果洞察
+			routes.SetupAnalysisInsightRoutes(admin, r.db)
+		}
+
+		// 类别与技能体系（只读接口需要认证，维护接口需要管理员权限）
+		taxonomy := v1.Group("")
+		taxonomy.Use(r.authMiddleware.RequireAuth())
+		{
+			routes.SetupTaxonomyRoutes(taxonomy, admin, r.db)
+		}
+
+		// 测评（作答接口需要认证，维护接口需要管理员权限，人工评分需要审核员权限）
+		assessments := v1.Group("")
+		assessments.Use(r.authMiddleware.RequireAuth())
+		grading := v1.Group("/grading")
+		grading.Use(r.authMiddleware.RequireModerator())
+		{
+			routes.SetupAssessmentRoutes(assessments, admin, grading, r.db)
+		}
+
+		// 知识点掌握度与间隔复习（需要认证）
+		review := v1.Group("")
+		review.Use(r.authMiddleware.RequireAuth())
+		{
+			routes.SetupReviewRoutes(review, r.db)
+		}
+
+		// 评论、评分、举报与内容审核（需要认证，审核队列需要审核员权限，过滤规则需要管理员权限）
+		community := v1.Group("")
+		community.Use(r.authMiddleware.RequireAuth())
+		moderation := v1.Group("/moderation")
+		moderation.Use(r.authMiddleware.RequireModerator())
+		{
+			routes.SetupModerationRoutes(community, moderation, admin, r.moderationService)
+			routes.SetupCommentRoutes(community, moderation, r.db, r.moderationService)
+			routes.SetupRatingRoutes(community, r.db)
+		}
+
+		// 知识点（需要认证，编辑需要作者权限，审核、发布和归档需要审核员权限）
+		knowledge := v1.Group("")
+		knowledge.Use(r.authMiddleware.RequireAuth())
+		{
+			routes.SetupKnowledgePointRoutes(knowledge, v1, r.db, r.redisCache, r.storageConfig, r.workers)
 		}
 	}
 }
\ No newline at end of file
@@ -0,0 +1,37 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AnalysisJobStatus 分析任务状态
+type AnalysisJobStatus string
+
+const (
+	AnalysisJobQueued    AnalysisJobStatus = "queued"
+	AnalysisJobRunning   AnalysisJobStatus = "running"
+	AnalysisJobSucceeded AnalysisJobStatus = "succeeded"
+	AnalysisJobFailed    AnalysisJobStatus = "failed"
+)
+
+// IsFinished 任务是否已结束
+func (s AnalysisJobStatus) IsFinished() bool {
+	return s == AnalysisJobSucceeded || s == AnalysisJobFailed
+}
+
+// AnalysisJob 学习目标异步分析任务
+type AnalysisJob struct {
+	ID         uuid.UUID     `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	GoalID     uuid.UUID     `gorm:"type:uuid;not null;index" json:"goal_id"`
+	Status     string        `gorm:"type:varchar(20);not null;default:'queued';index" json:"status"` // queued, running, succeeded, failed
+	Types      AnalysisTypes `gorm:"type:jsonb" json:"types"`                                        // 需要执行的分析类型
+	DedupKey   string        `gorm:"type:varchar(200);not null;index" json:"-"`
+	Attempts   int           `gorm:"not null;default:0" json:"attempts"`
+	Error      string        `gorm:"type:text" json:"error,omitempty"`
+	StartedAt  *time.Time    `gorm:"type:timestamp" json:"started_at"`
+	FinishedAt *time.Time    `gorm:"type:timestamp" json:"finished_at"`
+	CreatedAt  time.Time     `gorm:"autoCreateTime;index" json:"created_at"`
+	UpdatedAt  time.Time     `gorm:"autoUpdateTime" json:"updated_at"`
+}
@@ -0,0 +1,24 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AnalysisUsage 目标分析的模型调用记录，用于按用户统计费用
+type AnalysisUsage struct {
+	ID               uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID           uint      `gorm:"not null;index:idx_analysis_usage_user_created" json:"user_id"`
+	GoalID           uuid.UUID `gorm:"type:uuid;not null;index" json:"goal_id"`
+	Analyzer         string    `gorm:"type:varchar(50);not null" json:"analyzer"`
+	AnalysisType     string    `gorm:"type:varchar(50)" json:"analysis_type"`
+	Model            string    `gorm:"type:varchar(100)" json:"model"`
+	PromptTokens     int       `gorm:"not null;default:0" json:"prompt_tokens"`
+	CompletionTokens int       `gorm:"not null;default:0" json:"completion_tokens"`
+	Cost             float64   `gorm:"type:decimal(10,6);not null;default:0" json:"cost"` // 美元
+	Fallback         bool      `gorm:"not null;default:false" json:"fallback"`            // 是否降级到规则分析器
+	Error            string    `gorm:"type:text" json:"error"`
+	DurationMs       int64     `gorm:"not null;default:0" json:"duration_ms"`
+	CreatedAt        time.Time `gorm:"autoCreateTime;index:idx_analysis_usage_user_created" json:"created_at"`
+}
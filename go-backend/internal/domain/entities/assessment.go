@@ -0,0 +1,284 @@
+package entities
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AssessmentType 测评类型
+type AssessmentType string
+
+const (
+	AssessmentTypeQuiz       AssessmentType = "quiz"
+	AssessmentTypeAssignment AssessmentType = "assignment"
+	AssessmentTypeExam       AssessmentType = "exam"
+)
+
+// QuestionType 题目类型
+type QuestionType string
+
+const (
+	QuestionTypeMultipleChoice QuestionType = "multiple_choice"
+	QuestionTypeTrueFalse      QuestionType = "true_false"
+	QuestionTypeShortAnswer    QuestionType = "short_answer"
+	QuestionTypeEssay          QuestionType = "essay"
+)
+
+// IsObjective 是否为可自动评分的客观题
+func (t QuestionType) IsObjective() bool {
+	return t == QuestionTypeMultipleChoice || t == QuestionTypeTrueFalse
+}
+
+// AttemptStatus 作答状态
+type AttemptStatus string
+
+const (
+	AttemptInProgress AttemptStatus = "in_progress"
+	AttemptSubmitted  AttemptStatus = "submitted" // 已提交，主观题待人工评分
+	AttemptGraded     AttemptStatus = "graded"
+)
+
+// AnswerGradingStatus 单题评分状态
+type AnswerGradingStatus string
+
+const (
+	AnswerUnanswered  AnswerGradingStatus = "unanswered"
+	AnswerAutoGraded  AnswerGradingStatus = "auto_graded"
+	AnswerNeedsReview AnswerGradingStatus = "needs_review"
+	AnswerGraded      AnswerGradingStatus = "graded"
+)
+
+// StringList 字符串列表，以jsonb存储
+type StringList []string
+
+// Value 实现driver.Valuer
+func (l StringList) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal(l)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan 实现sql.Scanner
+func (l *StringList) Scan(value interface{}) error {
+	if err := scanJSONB(value, l); err != nil {
+		return fmt.Errorf("解析字符串列表失败: %w", err)
+	}
+	return nil
+}
+
+// RubricCriterion 评分细则中的一项评分标准
+type RubricCriterion struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description,omitempty"`
+	Points      float64 `json:"points"`
+}
+
+// GradingRubric 主观题评分细则，以jsonb存储
+type GradingRubric []RubricCriterion
+
+// Value 实现driver.Valuer
+func (r GradingRubric) Value() (driver.Value, error) {
+	if r == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan 实现sql.Scanner
+func (r *GradingRubric) Scan(value interface{}) error {
+	if err := scanJSONB(value, r); err != nil {
+		return fmt.Errorf("解析评分细则失败: %w", err)
+	}
+	return nil
+}
+
+// TotalPoints 评分细则的总分
+func (r GradingRubric) TotalPoints() float64 {
+	total := 0.0
+	for _, c := range r {
+		total += c.Points
+	}
+	return total
+}
+
+// Find 按名称查找评分标准
+func (r GradingRubric) Find(name string) (*RubricCriterion, bool) {
+	for i := range r {
+		if r[i].Name == name {
+			return &r[i], true
+		}
+	}
+	return nil, false
+}
+
+// RubricScore 按评分标准给出的得分和评语
+type RubricScore struct {
+	Criterion string  `json:"criterion"`
+	Points    float64 `json:"points"`
+	Comment   string  `json:"comment,omitempty"`
+}
+
+// RubricScores 评分细则得分，以jsonb存储
+type RubricScores []RubricScore
+
+// Value 实现driver.Valuer
+func (s RubricScores) Value() (driver.Value, error) {
+	if s == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan 实现sql.Scanner
+func (s *RubricScores) Scan(value interface{}) error {
+	if err := scanJSONB(value, s); err != nil {
+		return fmt.Errorf("解析评分细则得分失败: %w", err)
+	}
+	return nil
+}
+
+// Assessment 测评
+type Assessment struct {
+	ID             uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Title          string         `gorm:"type:varchar(100);not null" json:"title"`
+	Description    string         `gorm:"type:text" json:"description"`
+	Type           string         `gorm:"type:varchar(20);not null;index" json:"type"` // quiz, assignment, exam
+	Category       string         `gorm:"type:varchar(100);not null;index" json:"category"`
+	Difficulty     string         `gorm:"type:varchar(50);not null;index" json:"difficulty"` // beginner, intermediate, advanced
+	TimeLimit      int            `gorm:"not null;default:0" json:"time_limit"`              // 作答时限(分钟)，0表示不限
+	PassingScore   float64        `gorm:"type:decimal(5,2);not null" json:"passing_score"`   // 0-100
+	MaxAttempts    int            `gorm:"not null;default:0" json:"max_attempts"`            // 每个用户的作答次数上限，0表示不限
+	IsPublished    bool           `gorm:"not null;default:false;index" json:"is_published"`
+	PublishedAt    *time.Time     `gorm:"type:timestamp" json:"published_at"`
+	CreatedBy      uint           `gorm:"not null;index" json:"created_by"`
+	CompletedCount int            `gorm:"not null;default:0" json:"completed_count"`
+	AverageScore   float64        `gorm:"type:decimal(5,2);not null;default:0" json:"average_score"`
+	CreatedAt      time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// 关联关系
+	Questions       []AssessmentQuestion `gorm:"foreignKey:AssessmentID" json:"questions,omitempty"`
+	KnowledgePoints []KnowledgePoint     `gorm:"many2many:assessment_knowledge_points;" json:"knowledge_points,omitempty"`
+}
+
+// TotalPoints 测评总分
+func (a *Assessment) TotalPoints() float64 {
+	total := 0.0
+	for _, q := range a.Questions {
+		total += q.Points
+	}
+	return total
+}
+
+// AssessmentQuestion 测评题目
+type AssessmentQuestion struct {
+	ID               uuid.UUID     `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	AssessmentID     uuid.UUID     `gorm:"type:uuid;not null;index" json:"assessment_id"`
+	Type             string        `gorm:"type:varchar(20);not null" json:"type"` // multiple_choice, true_false, short_answer, essay
+	Content          string        `gorm:"type:text;not null" json:"content"`
+	Options          StringList    `gorm:"type:jsonb" json:"options,omitempty"`
+	CorrectAnswer    StringList    `gorm:"type:jsonb" json:"correct_answer,omitempty"` // 多选题需全部选中；简答题为参考答案
+	Points           float64       `gorm:"type:decimal(6,2);not null;default:1" json:"points"`
+	Explanation      string        `gorm:"type:text" json:"explanation,omitempty"`
+	Rubric           GradingRubric `gorm:"type:jsonb" json:"rubric,omitempty"` // 主观题评分细则，为空时按题目分值直接给分
+	KnowledgePointID *uuid.UUID    `gorm:"type:uuid;index" json:"knowledge_point_id"`
+	Difficulty       string        `gorm:"type:varchar(50)" json:"difficulty"`
+	Order            int           `gorm:"not null;default:0" json:"order"`
+	CreatedAt        time.Time     `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt        time.Time     `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// AssessmentAttempt 用户的一次测评作答
+type AssessmentAttempt struct {
+	ID            uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	AssessmentID  uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_attempt_user_number" json:"assessment_id"`
+	UserID        uint       `gorm:"not null;uniqueIndex:idx_attempt_user_number;index" json:"user_id"`
+	AttemptNumber int        `gorm:"not null;uniqueIndex:idx_attempt_user_number" json:"attempt_number"`
+	Status        string     `gorm:"type:varchar(20);not null;default:'in_progress';index" json:"status"` // in_progress, submitted, graded
+	StartedAt     time.Time  `gorm:"type:timestamp;not null" json:"started_at"`
+	ExpiresAt     *time.Time `gorm:"type:timestamp" json:"expires_at"`
+	SubmittedAt   *time.Time `gorm:"type:timestamp" json:"submitted_at"`
+	GradedAt      *time.Time `gorm:"type:timestamp" json:"graded_at"`
+	TimedOut      bool       `gorm:"not null;default:false" json:"timed_out"` // 超时后提交，仅统计超时前保存的答案
+	TotalPoints   float64    `gorm:"type:decimal(8,2);not null;default:0" json:"total_points"`
+	EarnedPoints  float64    `gorm:"type:decimal(8,2);not null;default:0" json:"earned_points"`
+	Score         float64    `gorm:"type:decimal(5,2);not null;default:0" json:"score"` // 百分制
+	Passed        bool       `gorm:"not null;default:false" json:"passed"`
+	CreatedAt     time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// 关联关系
+	Answers []AttemptAnswer `gorm:"foreignKey:AttemptID" json:"answers,omitempty"`
+}
+
+// IsExpired 作答是否已超过时限，grace为允许的网络延迟
+func (a *AssessmentAttempt) IsExpired(now time.Time, grace time.Duration) bool {
+	return a.ExpiresAt != nil && now.After(a.ExpiresAt.Add(grace))
+}
+
+// Recalculate 根据各题得分汇总作答成绩；所有题目评分完成时标记为已评分并判定是否通过
+func (a *AssessmentAttempt) Recalculate(passingScore float64, now time.Time) {
+	earned := 0.0
+	pending := false
+	for _, answer := range a.Answers {
+		if answer.GradingStatus == string(AnswerNeedsReview) {
+			pending = true
+			continue
+		}
+		if answer.EarnedPoints != nil {
+			earned += *answer.EarnedPoints
+		}
+	}
+
+	a.EarnedPoints = math.Round(earned*100) / 100
+	if a.TotalPoints > 0 {
+		a.Score = math.Round(earned/a.TotalPoints*10000) / 100
+	}
+
+	if pending {
+		a.Status = string(AttemptSubmitted)
+		a.Passed = false
+		a.GradedAt = nil
+		return
+	}
+	a.Status = string(AttemptGraded)
+	a.Passed = a.Score >= passingScore
+	a.GradedAt = &now
+}
+
+// AttemptAnswer 作答中单题的答案及评分结果
+type AttemptAnswer struct {
+	ID            uuid.UUID    `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	AttemptID     uuid.UUID    `gorm:"type:uuid;not null;uniqueIndex:idx_attempt_question" json:"attempt_id"`
+	QuestionID    uuid.UUID    `gorm:"type:uuid;not null;uniqueIndex:idx_attempt_question;index" json:"question_id"`
+	Answer        StringList   `gorm:"type:jsonb" json:"answer"`
+	IsCorrect     *bool        `json:"is_correct"`
+	EarnedPoints  *float64     `gorm:"type:decimal(6,2)" json:"earned_points"`
+	GradingStatus string       `gorm:"type:varchar(20);not null;default:'unanswered';index" json:"grading_status"` // unanswered, auto_graded, needs_review, graded
+	RubricScores  RubricScores `gorm:"type:jsonb" json:"rubric_scores,omitempty"`
+	Feedback      string       `gorm:"type:text" json:"feedback,omitempty"` // 评分人评语
+	GradedBy      *uint        `json:"graded_by,omitempty"`
+	GradedAt      *time.Time   `gorm:"type:timestamp" json:"graded_at,omitempty"`
+	CreatedAt     time.Time    `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time    `gorm:"autoUpdateTime" json:"updated_at"`
+}
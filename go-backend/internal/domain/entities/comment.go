@@ -0,0 +1,94 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CommentTargetType 评论对象类型
+type CommentTargetType string
+
+const (
+	CommentTargetKnowledgePoint CommentTargetType = "knowledge_point"
+	CommentTargetLearningPath   CommentTargetType = "learning_path"
+)
+
+// IsValid 检查评论对象类型是否有效
+func (t CommentTargetType) IsValid() bool {
+	return t == CommentTargetKnowledgePoint || t == CommentTargetLearningPath
+}
+
+// MaxCommentLength 评论内容的最大字符数
+const MaxCommentLength = 1000
+
+// Comment 评论实体
+// 顶层评论即讨论串，回复通过ParentID形成树，RootID指向所在讨论串；置顶和锁定只作用于讨论串
+type Comment struct {
+	ID           uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	TargetType   string         `gorm:"type:varchar(20);not null;index:idx_comment_target" json:"target_type"`
+	TargetID     uuid.UUID      `gorm:"type:uuid;not null;index:idx_comment_target" json:"target_id"`
+	UserID       uint           `gorm:"not null;index" json:"user_id"`
+	ParentID     *uuid.UUID     `gorm:"type:uuid;index" json:"parent_id"`
+	RootID       *uuid.UUID     `gorm:"type:uuid;index" json:"root_id"`
+	Content      string         `gorm:"type:text;not null" json:"content"`
+	LikeCount    int            `gorm:"not null;default:0" json:"like_count"`
+	ReplyCount   int            `gorm:"not null;default:0" json:"reply_count"` // 讨论串的回复总数，回复本身为直接回复数
+	IsEdited     bool           `gorm:"not null;default:false" json:"is_edited"`
+	EditedAt     *time.Time     `gorm:"type:timestamp" json:"edited_at"`
+	IsHidden     bool           `gorm:"not null;default:false" json:"is_hidden"`
+	HiddenBy     *uint          `json:"hidden_by,omitempty"`
+	HiddenReason string         `gorm:"type:varchar(255)" json:"hidden_reason,omitempty"`
+	IsPinned     bool           `gorm:"not null;default:false" json:"is_pinned"`
+	IsLocked     bool           `gorm:"not null;default:false" json:"is_locked"`
+	CreatedAt    time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// 关联关系
+	User     *User            `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Mentions []CommentMention `gorm:"foreignKey:CommentID" json:"mentions,omitempty"`
+}
+
+// IsRoot 是否为讨论串的顶层评论
+func (c *Comment) IsRoot() bool {
+	return c.ParentID == nil
+}
+
+// ThreadID 所在讨论串的ID
+func (c *Comment) ThreadID() uuid.UUID {
+	if c.RootID != nil {
+		return *c.RootID
+	}
+	return c.ID
+}
+
+// IsDeleted 是否已删除
+func (c *Comment) IsDeleted() bool {
+	return c.DeletedAt.Valid
+}
+
+// CommentEdit 评论编辑历史，保存编辑前的内容
+type CommentEdit struct {
+	ID              uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	CommentID       uuid.UUID `gorm:"type:uuid;not null;index" json:"comment_id"`
+	PreviousContent string    `gorm:"type:text;not null" json:"previous_content"`
+	EditedBy        uint      `gorm:"not null" json:"edited_by"`
+	CreatedAt       time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// CommentLike 评论点赞，每个用户对每条评论只能点赞一次
+type CommentLike struct {
+	CommentID uuid.UUID `gorm:"type:uuid;primaryKey" json:"comment_id"`
+	UserID    uint      `gorm:"primaryKey;index" json:"user_id"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// CommentMention 评论中@提及的用户
+type CommentMention struct {
+	CommentID uuid.UUID `gorm:"type:uuid;primaryKey" json:"comment_id"`
+	UserID    uint      `gorm:"primaryKey;index" json:"user_id"`
+	Username  string    `gorm:"type:varchar(50);not null" json:"username"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
@@ -0,0 +1,94 @@
+package entities
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DiagnosticStatus 诊断测评状态
+type DiagnosticStatus string
+
+const (
+	DiagnosticInProgress DiagnosticStatus = "in_progress"
+	DiagnosticCompleted  DiagnosticStatus = "completed"
+)
+
+// KnowledgeEstimate 诊断测评对单个知识点的能力估计
+type KnowledgeEstimate struct {
+	KnowledgePointID uuid.UUID `json:"knowledge_point_id"`
+	Title            string    `json:"title"`
+	Ability          float64   `json:"ability"`   // 能力估计值(logit)
+	StdError         float64   `json:"std_error"` // 估计的标准误，越小越可信
+	Answered         int       `json:"answered"`
+	Correct          int       `json:"correct"`
+	Mastery          float64   `json:"mastery"` // 按目标难度换算的掌握度(0-1)
+}
+
+// KnowledgeEstimates 知识点能力估计列表，以jsonb存储
+type KnowledgeEstimates []KnowledgeEstimate
+
+// Value 实现driver.Valuer
+func (e KnowledgeEstimates) Value() (driver.Value, error) {
+	if e == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan 实现sql.Scanner
+func (e *KnowledgeEstimates) Scan(value interface{}) error {
+	if err := scanJSONB(value, e); err != nil {
+		return fmt.Errorf("解析知识点能力估计失败: %w", err)
+	}
+	return nil
+}
+
+// Find 查找知识点的能力估计
+func (e KnowledgeEstimates) Find(pointID uuid.UUID) (*KnowledgeEstimate, bool) {
+	for i := range e {
+		if e[i].KnowledgePointID == pointID {
+			return &e[i], true
+		}
+	}
+	return nil, false
+}
+
+// DiagnosticSession 针对学习目标的自适应诊断测评
+// 题目从目标类别知识点关联的客观题中逐题选取，难度随作答结果调整
+type DiagnosticSession struct {
+	ID                uuid.UUID          `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	GoalID            uuid.UUID          `gorm:"type:uuid;not null;index" json:"goal_id"`
+	UserID            uint               `gorm:"not null;index" json:"user_id"`
+	Status            string             `gorm:"type:varchar(20);not null;default:'in_progress';index" json:"status"` // in_progress, completed
+	TargetDifficulty  string             `gorm:"type:varchar(50);not null" json:"target_difficulty"`                  // 目标难度，用于换算掌握度
+	MaxItems          int                `gorm:"not null" json:"max_items"`
+	CurrentQuestionID *uuid.UUID         `gorm:"type:uuid" json:"current_question_id"`
+	Estimates         KnowledgeEstimates `gorm:"type:jsonb" json:"estimates"`
+	CompletedAt       *time.Time         `gorm:"type:timestamp;index" json:"completed_at"`
+	CreatedAt         time.Time          `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt         time.Time          `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// 关联关系
+	Responses []DiagnosticResponse `gorm:"foreignKey:SessionID" json:"responses,omitempty"`
+}
+
+// DiagnosticResponse 诊断测评中的单题作答
+type DiagnosticResponse struct {
+	ID               uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	SessionID        uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_diagnostic_question" json:"session_id"`
+	QuestionID       uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_diagnostic_question" json:"question_id"`
+	KnowledgePointID uuid.UUID  `gorm:"type:uuid;not null;index" json:"knowledge_point_id"`
+	ItemDifficulty   float64    `gorm:"type:decimal(5,2);not null" json:"item_difficulty"` // 题目难度参数(logit)
+	Answer           StringList `gorm:"type:jsonb" json:"answer"`
+	Correct          bool       `gorm:"not null" json:"correct"`
+	Sequence         int        `gorm:"not null" json:"sequence"`
+	CreatedAt        time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
@@ -0,0 +1,295 @@
+package entities
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AnalysisSchemaVersion 当前分析结果结构版本
+// 1: 初始版本（技能差距、前置条件、难度、预估时间、推荐摘要）
+// 2: 增加已掌握技能、缺失前置条件、难度因素和分析证据
+const AnalysisSchemaVersion = 2
+
+// 分析类型
+const (
+	AnalysisTypeSkillGap      = "skill_gap"
+	AnalysisTypePrerequisite  = "prerequisite"
+	AnalysisTypeDifficulty    = "difficulty_assessment"
+	AnalysisTypeTimeEstimate  = "time_estimate"
+	AnalysisTypeComprehensive = "comprehensive" // 由各单项分析组合而成，早期版本直接以该类型存储
+)
+
+// IndividualAnalysisTypes 可单独执行和存储的分析类型
+var IndividualAnalysisTypes = []string{
+	AnalysisTypeSkillGap,
+	AnalysisTypePrerequisite,
+	AnalysisTypeDifficulty,
+	AnalysisTypeTimeEstimate,
+}
+
+// IsIndividualAnalysisType 是否为可单独执行的分析类型
+func IsIndividualAnalysisType(analysisType string) bool {
+	for _, t := range IndividualAnalysisTypes {
+		if t == analysisType {
+			return true
+		}
+	}
+	return false
+}
+
+// AnalysisResult 分析结果，以jsonb存储
+type AnalysisResult struct {
+	SchemaVersion        int               `json:"schema_version"`
+	SkillGaps            []string          `json:"skill_gaps"`
+	Strengths            []string          `json:"strengths"`
+	Prerequisites        []string          `json:"prerequisites"`
+	MissingPrerequisites []string          `json:"missing_prerequisites"`
+	DifficultyLevel      string            `json:"difficulty_level"`
+	EstimatedTime        int               `json:"estimated_time"` // 小时
+	DifficultyFactors    []string          `json:"difficulty_factors"`
+	Recommendations      []string          `json:"recommendations"`
+	Evidence             *AnalysisEvidence `json:"evidence"`
+}
+
+// Extract 提取指定分析类型相关的结果字段，推荐摘要由调用方按类型填写
+func (r AnalysisResult) Extract(analysisType string) AnalysisResult {
+	part := AnalysisResult{
+		SchemaVersion: r.SchemaVersion,
+		Evidence:      r.Evidence,
+	}
+	switch analysisType {
+	case AnalysisTypeSkillGap:
+		part.SkillGaps = r.SkillGaps
+		part.Strengths = r.Strengths
+	case AnalysisTypePrerequisite:
+		part.Prerequisites = r.Prerequisites
+		part.MissingPrerequisites = r.MissingPrerequisites
+	case AnalysisTypeDifficulty:
+		part.DifficultyLevel = r.DifficultyLevel
+		part.DifficultyFactors = r.DifficultyFactors
+	case AnalysisTypeTimeEstimate:
+		part.EstimatedTime = r.EstimatedTime
+	}
+	return part
+}
+
+// Merge 将单项分析结果合并到综合结果中
+func (r *AnalysisResult) Merge(analysisType string, part AnalysisResult) {
+	switch analysisType {
+	case AnalysisTypeSkillGap:
+		r.SkillGaps = part.SkillGaps
+		r.Strengths = part.Strengths
+	case AnalysisTypePrerequisite:
+		r.Prerequisites = part.Prerequisites
+		r.MissingPrerequisites = part.MissingPrerequisites
+	case AnalysisTypeDifficulty:
+		r.DifficultyLevel = part.DifficultyLevel
+		r.DifficultyFactors = part.DifficultyFactors
+	case AnalysisTypeTimeEstimate:
+		r.EstimatedTime = part.EstimatedTime
+	}
+	r.Recommendations = append(r.Recommendations, part.Recommendations...)
+	if part.SchemaVersion > r.SchemaVersion {
+		r.SchemaVersion = part.SchemaVersion
+	}
+	if part.Evidence != nil {
+		r.Evidence = part.Evidence
+	}
+}
+
+// AnalysisEvidence 分析所依据的用户数据量，用于推导置信度
+type AnalysisEvidence struct {
+	KnowledgePoints int `json:"knowledge_points"` // 类别下可比对的知识点数
+	RequiredSkills  int `json:"required_skills"`
+	AssessedSkills  int `json:"assessed_skills"` // 有测评数据的必备技能数
+	CompletedPaths  int `json:"completed_paths"`
+	GoalHistory     int `json:"goal_history"`
+	CompletedGoals  int `json:"completed_goals"`
+}
+
+// Value 实现driver.Valuer，写入时补全结构版本
+func (r AnalysisResult) Value() (driver.Value, error) {
+	if r.SchemaVersion == 0 {
+		r.SchemaVersion = AnalysisSchemaVersion
+	}
+	data, err := json.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan 实现sql.Scanner，未记录版本的历史数据视为版本1
+func (r *AnalysisResult) Scan(value interface{}) error {
+	if err := scanJSONB(value, r); err != nil {
+		return fmt.Errorf("解析分析结果失败: %w", err)
+	}
+	if r.SchemaVersion == 0 {
+		r.SchemaVersion = 1
+	}
+	return nil
+}
+
+// RecommendationStatus 推荐处理状态
+type RecommendationStatus string
+
+const (
+	RecommendationPending   RecommendationStatus = "pending"
+	RecommendationAccepted  RecommendationStatus = "accepted"
+	RecommendationDismissed RecommendationStatus = "dismissed"
+	RecommendationSnoozed   RecommendationStatus = "snoozed"
+)
+
+// Recommendation 推荐
+type Recommendation struct {
+	ID                uuid.UUID                `json:"id"`
+	Type              string                   `json:"type"` // learning_path, resource, skill_building
+	Title             string                   `json:"title"`
+	Description       string                   `json:"description"`
+	Priority          string                   `json:"priority"` // high, medium, low
+	EstimatedTime     int                      `json:"estimated_time"`
+	Reason            string                   `json:"reason"`                        // 推荐依据
+	AnalysisType      string                   `json:"analysis_type,omitempty"`       // 产生该推荐的分析类型
+	KnowledgePointIDs []uuid.UUID              `json:"knowledge_point_ids,omitempty"` // 推荐学习的知识点
+	PathTemplate      *PathTemplate            `json:"path_template,omitempty"`       // 接受推荐时据此创建学习路径
+	Resources         []RecommendationResource `json:"resources,omitempty"`
+	Status            RecommendationStatus     `json:"status,omitempty"`
+	SnoozedUntil      *time.Time               `json:"snoozed_until,omitempty"`
+}
+
+// CurrentStatus 推荐当前的处理状态，暂缓到期后恢复为待处理
+func (r *Recommendation) CurrentStatus(now time.Time) RecommendationStatus {
+	switch r.Status {
+	case "":
+		return RecommendationPending
+	case RecommendationSnoozed:
+		if r.SnoozedUntil == nil || !now.Before(*r.SnoozedUntil) {
+			return RecommendationPending
+		}
+	}
+	return r.Status
+}
+
+// PathTemplate 推荐的学习路径模板
+type PathTemplate struct {
+	Title     string             `json:"title"`
+	Steps     []PathTemplateStep `json:"steps"`
+	TotalTime int                `json:"total_time"` // 小时
+}
+
+// PathTemplateStep 学习路径模板中的步骤
+type PathTemplateStep struct {
+	Title             string     `json:"title"`
+	Description       string     `json:"description"`
+	EstimatedDuration int        `json:"estimated_duration"` // 小时
+	KnowledgePointID  *uuid.UUID `json:"knowledge_point_id,omitempty"`
+}
+
+// RecommendationResource 推荐的学习资源，来自知识点的资源配置
+type RecommendationResource struct {
+	Title            string    `json:"title"`
+	URL              string    `json:"url"`
+	KnowledgePointID uuid.UUID `json:"knowledge_point_id"`
+}
+
+// Recommendations 推荐列表，以jsonb存储
+type Recommendations []Recommendation
+
+// Value 实现driver.Valuer
+func (r Recommendations) Value() (driver.Value, error) {
+	if r == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan 实现sql.Scanner
+func (r *Recommendations) Scan(value interface{}) error {
+	if err := scanJSONB(value, r); err != nil {
+		return fmt.Errorf("解析推荐失败: %w", err)
+	}
+	return nil
+}
+
+// OfType 筛选指定分析类型产生的推荐
+func (r Recommendations) OfType(analysisType string) Recommendations {
+	filtered := Recommendations{}
+	for _, rec := range r {
+		if rec.AnalysisType == analysisType {
+			filtered = append(filtered, rec)
+		}
+	}
+	return filtered
+}
+
+// Actionable 筛选当前仍需用户处理或已接受的推荐，排除已忽略和暂缓中的推荐
+func (r Recommendations) Actionable(now time.Time) Recommendations {
+	filtered := Recommendations{}
+	for i := range r {
+		switch r[i].CurrentStatus(now) {
+		case RecommendationDismissed, RecommendationSnoozed:
+			continue
+		}
+		filtered = append(filtered, r[i])
+	}
+	return filtered
+}
+
+// Summaries 推荐摘要列表
+func (r Recommendations) Summaries() []string {
+	summaries := make([]string, 0, len(r))
+	for _, rec := range r {
+		summaries = append(summaries, rec.Description)
+	}
+	return summaries
+}
+
+// AnalysisTypes 分析类型列表，以jsonb存储
+type AnalysisTypes []string
+
+// Value 实现driver.Valuer
+func (t AnalysisTypes) Value() (driver.Value, error) {
+	if t == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal(t)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan 实现sql.Scanner
+func (t *AnalysisTypes) Scan(value interface{}) error {
+	if err := scanJSONB(value, t); err != nil {
+		return fmt.Errorf("解析分析类型失败: %w", err)
+	}
+	return nil
+}
+
+// scanJSONB 将数据库中的jsonb值解析到dest
+func scanJSONB(value interface{}, dest interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("不支持的jsonb类型: %T", value)
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, dest)
+}
@@ -0,0 +1,87 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// KnowledgeRevisionAction 知识点修订的来源
+type KnowledgeRevisionAction string
+
+const (
+	KnowledgeRevisionCreate   KnowledgeRevisionAction = "create"
+	KnowledgeRevisionUpdate   KnowledgeRevisionAction = "update"
+	KnowledgeRevisionRestore  KnowledgeRevisionAction = "restore"
+	KnowledgeRevisionBaseline KnowledgeRevisionAction = "baseline" // 启用修订记录前已存在的内容，在首次修改时补录
+)
+
+// MaxChangeSummaryLength 修改说明的最大字符数
+const MaxChangeSummaryLength = 500
+
+// KnowledgePointRevision 知识点修订，保存每个版本修改后的完整内容，创建后不可修改
+type KnowledgePointRevision struct {
+	ID               uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	KnowledgePointID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_knowledge_revision_version,priority:1" json:"knowledge_point_id"`
+	Version          int       `gorm:"not null;uniqueIndex:idx_knowledge_revision_version,priority:2" json:"version"`
+	Action           string    `gorm:"type:varchar(20);not null" json:"action"`
+	AuthorID         *uint     `gorm:"index" json:"author_id"` // 未登录时为空
+	ChangeSummary    string    `gorm:"type:varchar(500)" json:"change_summary"`
+	RestoredFrom     *int      `json:"restored_from,omitempty"` // 回滚来源的版本号
+	Title            string    `gorm:"type:varchar(255);not null" json:"title"`
+	Description      string    `gorm:"type:text" json:"description"`
+	Content          string    `gorm:"type:text" json:"content"`
+	Category         string    `gorm:"type:varchar(100);not null" json:"category"`
+	Difficulty       string    `gorm:"type:varchar(50);not null" json:"difficulty"`
+	Resources        string    `gorm:"type:text" json:"resources"`
+	Prerequisites    string    `gorm:"type:text" json:"prerequisites"`
+	Tags             string    `gorm:"type:text" json:"tags"`
+	CreatedAt        time.Time `gorm:"autoCreateTime" json:"created_at"`
+
+	// 关联关系
+	Author *User `gorm:"foreignKey:AuthorID" json:"author,omitempty"`
+}
+
+// NewKnowledgePointRevision 以知识点的当前内容和版本号创建修订
+func NewKnowledgePointRevision(point *KnowledgePoint, action KnowledgeRevisionAction) *KnowledgePointRevision {
+	return &KnowledgePointRevision{
+		ID:               uuid.New(),
+		KnowledgePointID: point.ID,
+		Version:          point.Version,
+		Action:           string(action),
+		Title:            point.Title,
+		Description:      point.Description,
+		Content:          point.Content,
+		Category:         point.Category,
+		Difficulty:       point.Difficulty,
+		Resources:        point.Resources,
+		Prerequisites:    point.Prerequisites,
+		Tags:             point.Tags,
+	}
+}
+
+// ApplyTo 将修订的内容写回知识点，不修改版本号
+func (r *KnowledgePointRevision) ApplyTo(point *KnowledgePoint) {
+	point.Title = r.Title
+	point.Description = r.Description
+	point.Content = r.Content
+	point.Category = r.Category
+	point.Difficulty = r.Difficulty
+	point.Resources = r.Resources
+	point.Prerequisites = r.Prerequisites
+	point.Tags = r.Tags
+}
+
+// Fields 按固定顺序返回参与比较的字段名和值
+func (r *KnowledgePointRevision) Fields() [][2]string {
+	return [][2]string{
+		{"title", r.Title},
+		{"description", r.Description},
+		{"content", r.Content},
+		{"category", r.Category},
+		{"difficulty", r.Difficulty},
+		{"resources", r.Resources},
+		{"prerequisites", r.Prerequisites},
+		{"tags", r.Tags},
+	}
+}
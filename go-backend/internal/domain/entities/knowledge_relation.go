@@ -0,0 +1,34 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// KnowledgeRelation 作者维护的相关知识点链接，双向保存，每个方向一行
+type KnowledgeRelation struct {
+	ID               uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	KnowledgePointID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_knowledge_relation_pair,priority:1" json:"knowledge_point_id"`
+	RelatedID        uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_knowledge_relation_pair,priority:2;index" json:"related_id"`
+	Note             string    `gorm:"type:varchar(255)" json:"note"` // 关联说明，展示为推荐理由
+	CreatedBy        *uint     `json:"created_by,omitempty"`
+	CreatedAt        time.Time `gorm:"autoCreateTime" json:"created_at"`
+
+	// 关联关系
+	Related *KnowledgePoint `gorm:"foreignKey:RelatedID" json:"related,omitempty"`
+}
+
+// KnowledgeCooccurrence 由定时任务计算的知识点相似度(物品协同过滤)，每次计算整体替换
+// 同时出现在同一学习路径或同一学习者的学习记录中视为共现，Score为两类共现余弦相似度的加权和
+type KnowledgeCooccurrence struct {
+	KnowledgePointID uuid.UUID `gorm:"type:uuid;primary_key" json:"knowledge_point_id"`
+	RelatedID        uuid.UUID `gorm:"type:uuid;primary_key" json:"related_id"`
+	Score            float64   `gorm:"not null;index" json:"score"`
+	PathCount        int       `gorm:"not null;default:0" json:"path_count"`    // 同时包含两者的学习路径数
+	LearnerCount     int       `gorm:"not null;default:0" json:"learner_count"` // 同时学习过两者的学习者数
+	ComputedAt       time.Time `gorm:"not null" json:"computed_at"`
+
+	// 关联关系
+	Related *KnowledgePoint `gorm:"foreignKey:RelatedID" json:"related,omitempty"`
+}
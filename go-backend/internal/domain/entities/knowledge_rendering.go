@@ -0,0 +1,37 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// KnowledgeTOCEntry 目录项，Anchor为渲染后HTML中标题的id
+type KnowledgeTOCEntry struct {
+	Level  int    `json:"level"`
+	Title  string `json:"title"`
+	Anchor string `json:"anchor"`
+}
+
+// KnowledgePointRendering 知识点正文的渲染缓存
+// 正文哈希或渲染规则版本与当前不一致时视为过期，读取时重新渲染
+type KnowledgePointRendering struct {
+	KnowledgePointID uuid.UUID `gorm:"type:uuid;primary_key" json:"knowledge_point_id"`
+	ContentHash      string    `gorm:"type:varchar(64);not null" json:"-"` // 正文的SHA-256
+	RendererVersion  int       `gorm:"not null" json:"-"`
+	HTML             string    `gorm:"type:text;not null" json:"html"` // 清洗后的HTML
+	TOC              string    `gorm:"type:jsonb;not null;default:'[]'" json:"-"`
+	WordCount        int       `gorm:"not null;default:0" json:"word_count"` // 汉字按字、其他文字按词计数
+	ReadingMinutes   int       `gorm:"not null;default:0" json:"reading_minutes"`
+	RenderedAt       time.Time `gorm:"not null" json:"rendered_at"`
+}
+
+// TOCEntries 解析目录，格式无效时返回空列表
+func (r *KnowledgePointRendering) TOCEntries() []KnowledgeTOCEntry {
+	entries := []KnowledgeTOCEntry{}
+	if r.TOC != "" {
+		_ = json.Unmarshal([]byte(r.TOC), &entries)
+	}
+	return entries
+}
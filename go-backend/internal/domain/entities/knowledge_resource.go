@@ -0,0 +1,77 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// KnowledgeResourceType 知识点资源类型
+type KnowledgeResourceType string
+
+const (
+	KnowledgeResourceImage       KnowledgeResourceType = "image"
+	KnowledgeResourceVideo       KnowledgeResourceType = "video"
+	KnowledgeResourceChart       KnowledgeResourceType = "chart"
+	KnowledgeResourceModel3D     KnowledgeResourceType = "3d_model"
+	KnowledgeResourceInteractive KnowledgeResourceType = "interactive"
+	KnowledgeResourceDocument    KnowledgeResourceType = "document"  // PDF等文档
+	KnowledgeResourceReference   KnowledgeResourceType = "reference" // 参考文献
+	KnowledgeResourceLink        KnowledgeResourceType = "link"      // 其他外部链接
+)
+
+// IsValid 是否为支持的资源类型
+func (t KnowledgeResourceType) IsValid() bool {
+	switch t {
+	case KnowledgeResourceImage, KnowledgeResourceVideo, KnowledgeResourceChart, KnowledgeResourceModel3D,
+		KnowledgeResourceInteractive, KnowledgeResourceDocument, KnowledgeResourceReference, KnowledgeResourceLink:
+		return true
+	}
+	return false
+}
+
+// AllowsUpload 是否可以上传文件，图片支持常见图片格式，文档只支持PDF
+func (t KnowledgeResourceType) AllowsUpload() bool {
+	return t == KnowledgeResourceImage || t == KnowledgeResourceDocument
+}
+
+// KnowledgeLinkStatus 外部链接的检查状态
+type KnowledgeLinkStatus string
+
+const (
+	KnowledgeLinkUnchecked KnowledgeLinkStatus = "unchecked"
+	KnowledgeLinkOK        KnowledgeLinkStatus = "ok"
+	KnowledgeLinkBroken    KnowledgeLinkStatus = "broken" // 连续多次检查失败
+)
+
+// KnowledgeResource 知识点的结构化资源，同一知识点的资源按Position排序
+// 资源可以是外部链接(URL)、上传的文件(StorageKey)或内嵌数据(Data，如图表数据、3D模型参数)
+type KnowledgeResource struct {
+	ID               uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	KnowledgePointID uuid.UUID  `gorm:"type:uuid;not null;index:idx_knowledge_resource_position,priority:1" json:"knowledge_point_id"`
+	Position         int        `gorm:"not null;default:0;index:idx_knowledge_resource_position,priority:2" json:"position"`
+	Type             string     `gorm:"type:varchar(20);not null" json:"type"`
+	Title            string     `gorm:"type:varchar(255);not null" json:"title"`
+	Description      string     `gorm:"type:text" json:"description"`
+	URL              string     `gorm:"type:varchar(2048);not null;default:''" json:"url"`
+	Data             string     `gorm:"type:jsonb;not null;default:'{}'" json:"data"`
+	Author           string     `gorm:"type:varchar(255)" json:"author"` // 参考文献作者
+	Source           string     `gorm:"type:varchar(255)" json:"source"` // 参考文献出处
+	Year             *int       `json:"year,omitempty"`                  // 参考文献发表年份
+	StorageKey       string     `gorm:"type:varchar(255);not null;default:''" json:"-"`
+	FileName         string     `gorm:"type:varchar(255)" json:"file_name"`
+	ContentType      string     `gorm:"type:varchar(100)" json:"content_type"`
+	Size             int64      `json:"size"`
+	LinkStatus       string     `gorm:"type:varchar(20);not null;default:'unchecked';index" json:"link_status"`
+	LinkFailures     int        `gorm:"not null;default:0" json:"link_failures"` // 连续检查失败次数
+	LinkError        string     `gorm:"type:varchar(500)" json:"link_error"`
+	LinkCheckedAt    *time.Time `gorm:"index" json:"link_checked_at,omitempty"`
+	CreatedBy        *uint      `json:"created_by,omitempty"`
+	CreatedAt        time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt        time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// IsUpload 是否为上传的文件
+func (r *KnowledgeResource) IsUpload() bool {
+	return r.StorageKey != ""
+}
@@ -0,0 +1,86 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// KnowledgeStatus 知识点编辑状态
+type KnowledgeStatus string
+
+const (
+	KnowledgeStatusDraft     KnowledgeStatus = "draft"
+	KnowledgeStatusInReview  KnowledgeStatus = "in_review"
+	KnowledgeStatusApproved  KnowledgeStatus = "approved"
+	KnowledgeStatusPublished KnowledgeStatus = "published"
+	KnowledgeStatusArchived  KnowledgeStatus = "archived"
+)
+
+// KnowledgeWorkflowAction 知识点编辑流程中的操作
+type KnowledgeWorkflowAction string
+
+const (
+	KnowledgeActionSubmit   KnowledgeWorkflowAction = "submit"   // 提交审核
+	KnowledgeActionApprove  KnowledgeWorkflowAction = "approve"  // 审核通过
+	KnowledgeActionReject   KnowledgeWorkflowAction = "reject"   // 退回修改
+	KnowledgeActionPublish  KnowledgeWorkflowAction = "publish"  // 立即发布
+	KnowledgeActionSchedule KnowledgeWorkflowAction = "schedule" // 定时发布
+	KnowledgeActionArchive  KnowledgeWorkflowAction = "archive"  // 下线归档
+	KnowledgeActionReopen   KnowledgeWorkflowAction = "reopen"   // 撤回为草稿重新编辑
+)
+
+// MaxReviewCommentLength 审核意见的最大字符数
+const MaxReviewCommentLength = 2000
+
+// knowledgeStatusTransitions 知识点允许的状态转换
+var knowledgeStatusTransitions = map[KnowledgeStatus][]KnowledgeStatus{
+	KnowledgeStatusDraft:     {KnowledgeStatusInReview},
+	KnowledgeStatusInReview:  {KnowledgeStatusApproved, KnowledgeStatusDraft},
+	KnowledgeStatusApproved:  {KnowledgeStatusPublished, KnowledgeStatusDraft},
+	KnowledgeStatusPublished: {KnowledgeStatusArchived, KnowledgeStatusDraft},
+	KnowledgeStatusArchived:  {KnowledgeStatusDraft},
+}
+
+// IsValid 检查是否为合法的编辑状态
+func (s KnowledgeStatus) IsValid() bool {
+	_, exists := knowledgeStatusTransitions[s]
+	return exists
+}
+
+// CanTransitionTo 检查编辑状态是否允许转换到指定状态
+func (s KnowledgeStatus) CanTransitionTo(target KnowledgeStatus) bool {
+	for _, allowed := range knowledgeStatusTransitions[s] {
+		if allowed == target {
+			return true
+		}
+	}
+	return false
+}
+
+// KnowledgeReviewEvent 知识点编辑流程记录，每次状态变化及其审核意见
+type KnowledgeReviewEvent struct {
+	ID               uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	KnowledgePointID uuid.UUID  `gorm:"type:uuid;not null;index" json:"knowledge_point_id"`
+	Action           string     `gorm:"type:varchar(20);not null" json:"action"`
+	FromStatus       string     `gorm:"type:varchar(20);not null" json:"from_status"`
+	ToStatus         string     `gorm:"type:varchar(20);not null" json:"to_status"`
+	ActorID          *uint      `gorm:"index" json:"actor_id"`   // 定时发布由系统执行时为空
+	Version          int        `gorm:"not null" json:"version"` // 操作时的内容版本
+	Comment          string     `gorm:"type:text" json:"comment"`
+	PublishAt        *time.Time `json:"publish_at,omitempty"` // 定时发布时间
+	CreatedAt        time.Time  `gorm:"autoCreateTime" json:"created_at"`
+
+	// 关联关系
+	Actor *User `gorm:"foreignKey:ActorID" json:"actor,omitempty"`
+}
+
+// IsPublished 是否已发布，只有已发布的知识点对学习者可见
+func (kp *KnowledgePoint) IsPublished() bool {
+	return kp.Status == string(KnowledgeStatusPublished)
+}
+
+// IsDraft 是否为草稿，只有草稿可以修改内容
+func (kp *KnowledgePoint) IsDraft() bool {
+	return kp.Status == string(KnowledgeStatusDraft)
+}
@@ -1,6 +1,8 @@
 package entities
 
 import (
+	"encoding/json"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -10,7 +12,7 @@ import (
 // LearningGoal 学习目标实体
 type LearningGoal struct {
 	ID          uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
-	UserID      uuid.UUID `gorm:"type:uuid;not null;index" json:"user_id"`
+	UserID      uint      `gorm:"not null;index" json:"user_id"`
 	Title       string    `gorm:"type:varchar(255);not null" json:"title"`
 	Description string    `gorm:"type:text" json:"description"`
 	Category    string    `gorm:"type:varchar(100);not null" json:"category"`
@@ -18,6 +20,7 @@ type LearningGoal struct {
 	Status      string    `gorm:"type:varchar(50);not null;default:'active'" json:"status"` // active, completed, paused
 	TargetDate  *time.Time `gorm:"type:timestamp" json:"target_date"`
 	Progress    float64   `gorm:"type:decimal(5,2);default:0" json:"progress"` // 0-100
+	CompletedAt *time.Time `gorm:"type:timestamp" json:"completed_at"`
 	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
@@ -32,10 +35,12 @@ type LearningGoal struct {
 type GoalAnalysis struct {
 	ID               uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
 	GoalID           uuid.UUID `gorm:"type:uuid;not null;index" json:"goal_id"`
-	AnalysisType     string    `gorm:"type:varchar(50);not null" json:"analysis_type"` // skill_gap, prerequisite, difficulty_assessment
-	Result           string    `gorm:"type:jsonb" json:"result"`
-	Recommendations  string    `gorm:"type:jsonb" json:"recommendations"`
-	ConfidenceScore  float64   `gorm:"type:decimal(3,2)" json:"confidence_score"` // 0-1
+	AnalysisType     string    `gorm:"type:varchar(50);not null;index" json:"analysis_type"` // skill_gap, prerequisite, difficulty_assessment, time_estimate, comprehensive
+	JobID            *uuid.UUID `gorm:"type:uuid;index" json:"job_id"` // 产生该分析的异步任务
+	Analyzer         string    `gorm:"type:varchar(50);not null;default:'rules'" json:"analyzer"` // rules, llm
+	Result           AnalysisResult  `gorm:"type:jsonb;index:idx_goal_analysis_result,type:gin" json:"result"`
+	Recommendations  Recommendations `gorm:"type:jsonb" json:"recommendations"`
+	ConfidenceScore  float64   `gorm:"type:decimal(3,2);index" json:"confidence_score"` // 0-1
 	CreatedAt        time.Time `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt        time.Time `gorm:"autoUpdateTime" json:"updated_at"`
 
@@ -52,6 +57,9 @@ type LearningPath struct {
 	Order       int       `gorm:"not null" json:"order"`
 	EstimatedDuration int `gorm:"not null" json:"estimated_duration"` // 预估学习时间(小时)
 	Status      string    `gorm:"type:varchar(50);not null;default:'pending'" json:"status"` // pending, in_progress, completed
+	StartedAt   *time.Time `gorm:"type:timestamp" json:"started_at"`
+	CompletedAt *time.Time `gorm:"type:timestamp" json:"completed_at"`
+	TimeSpent   int       `gorm:"not null;default:0" json:"time_spent"` // 累计学习时间(分钟)
 	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
@@ -64,17 +72,54 @@ type LearningPath struct {
 // KnowledgePoint 知识点
 type KnowledgePoint struct {
 	ID          uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	ExternalKey *string   `gorm:"type:varchar(100);uniqueIndex:idx_knowledge_points_external_key,where:deleted_at IS NULL" json:"external_key,omitempty"` // 批量导入使用的稳定外部标识
 	Title       string    `gorm:"type:varchar(255);not null" json:"title"`
+	TitlePinyin string    `gorm:"type:text;not null;default:''" json:"-"` // 标题全拼，用于检索建议的拼音匹配
+	TitleInitials string  `gorm:"type:varchar(255);not null;default:''" json:"-"` // 标题拼音首字母
 	Description string    `gorm:"type:text" json:"description"`
 	Category    string    `gorm:"type:varchar(100);not null" json:"category"`
 	Difficulty  string    `gorm:"type:varchar(50);not null" json:"difficulty"`
 	Content     string    `gorm:"type:text" json:"content"`
 	Resources   string    `gorm:"type:jsonb" json:"resources"` // 学习资源链接等
 	Prerequisites string  `gorm:"type:jsonb" json:"prerequisites"` // 前置知识点
+	Tags        string    `gorm:"type:jsonb;not null;default:'[]';index:idx_knowledge_points_tags,type:gin" json:"tags"` // 标签列表，JSON字符串数组
+	Version     int       `gorm:"not null;default:1" json:"version"` // 内容版本号，每次修改递增，用于乐观锁
+	Status      string    `gorm:"type:varchar(20);not null;default:'published';index" json:"status"` // 编辑状态，启用审核流程前创建的知识点视为已发布
+	AuthorID    *uint     `gorm:"index" json:"author_id"` // 创建者
+	SubmittedBy *uint     `json:"submitted_by,omitempty"` // 最近一次提交审核的用户，不能审核自己提交的内容
+	PublishAt   *time.Time `gorm:"index" json:"publish_at,omitempty"` // 定时发布时间
+	PublishedAt *time.Time `json:"published_at,omitempty"`
 	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
 
 	// 关联关系
 	LearningPaths []LearningPath `gorm:"many2many:path_knowledge_points;" json:"learning_paths,omitempty"`
+}
+
+// TagList 解析标签列表，格式无效时返回空列表
+func (kp *KnowledgePoint) TagList() []string {
+	tags := []string{}
+	if kp.Tags != "" {
+		if err := json.Unmarshal([]byte(kp.Tags), &tags); err != nil {
+			return []string{}
+		}
+	}
+	return tags
+}
+
+// SetTags 去除空白和重复后保存标签列表
+func (kp *KnowledgePoint) SetTags(tags []string) {
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	data, _ := json.Marshal(normalized)
+	kp.Tags = string(data)
 }
\ No newline at end of file
@@ -0,0 +1,36 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StepTimeLog 学习路径步骤的学习时间记录
+type StepTimeLog struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	PathID    uuid.UUID `gorm:"type:uuid;not null;index" json:"path_id"`
+	GoalID    uuid.UUID `gorm:"type:uuid;not null;index" json:"goal_id"`
+	Minutes   int       `gorm:"not null" json:"minutes"`
+	Note      string    `gorm:"type:text" json:"note"`
+	LoggedAt  time.Time `gorm:"type:timestamp;not null;index" json:"logged_at"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+
+	// 关联关系
+	LearningPath LearningPath `gorm:"foreignKey:PathID" json:"-"`
+}
+
+// GoalProgressHistory 学习目标进度历史（用于进度曲线图表）
+type GoalProgressHistory struct {
+	ID             uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	GoalID         uuid.UUID `gorm:"type:uuid;not null;index:idx_goal_progress_history_goal_recorded" json:"goal_id"`
+	Progress       float64   `gorm:"type:decimal(5,2);not null" json:"progress"` // 0-100
+	CompletedSteps int       `gorm:"not null" json:"completed_steps"`
+	TotalSteps     int       `gorm:"not null" json:"total_steps"`
+	CompletedHours int       `gorm:"not null" json:"completed_hours"`
+	TotalHours     int       `gorm:"not null" json:"total_hours"`
+	RecordedAt     time.Time `gorm:"type:timestamp;not null;index:idx_goal_progress_history_goal_recorded" json:"recorded_at"`
+
+	// 关联关系
+	LearningGoal LearningGoal `gorm:"foreignKey:GoalID" json:"-"`
+}
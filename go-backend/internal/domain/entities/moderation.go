@@ -0,0 +1,107 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ModerationContentType 审核内容类型
+type ModerationContentType string
+
+const (
+	ModerationContentComment ModerationContentType = "comment"
+	ModerationContentUserBio ModerationContentType = "user_bio"
+)
+
+// IsValid 检查审核内容类型是否有效
+func (t ModerationContentType) IsValid() bool {
+	return t == ModerationContentComment || t == ModerationContentUserBio
+}
+
+// ModerationMatchType 过滤规则匹配方式
+type ModerationMatchType string
+
+const (
+	ModerationMatchKeyword ModerationMatchType = "keyword" // 关键词，忽略大小写、全半角和字间空白
+	ModerationMatchRegex   ModerationMatchType = "regex"   // 正则表达式
+)
+
+// ModerationAction 审核判定结果
+type ModerationAction string
+
+const (
+	ModerationAllow  ModerationAction = "allow"
+	ModerationReview ModerationAction = "review" // 允许发布但进入人工审核队列
+	ModerationBlock  ModerationAction = "block"  // 直接拒绝发布
+)
+
+// ModerationCaseStatus 审核案件状态
+type ModerationCaseStatus string
+
+const (
+	ModerationPending  ModerationCaseStatus = "pending"
+	ModerationApproved ModerationCaseStatus = "approved"
+	ModerationRejected ModerationCaseStatus = "rejected"
+)
+
+// ModerationCaseSource 审核案件来源
+type ModerationCaseSource string
+
+const (
+	ModerationSourceAuto   ModerationCaseSource = "auto"   // 自动过滤命中
+	ModerationSourceReport ModerationCaseSource = "report" // 用户举报
+)
+
+// ModerationRule 内容过滤规则，修改后过滤器会重新加载
+type ModerationRule struct {
+	ID        uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Name      string         `gorm:"type:varchar(100);not null" json:"name"`
+	Pattern   string         `gorm:"type:varchar(500);not null" json:"pattern"`
+	MatchType string         `gorm:"type:varchar(20);not null;default:'keyword'" json:"match_type"` // keyword, regex
+	Action    string         `gorm:"type:varchar(20);not null;default:'review'" json:"action"`      // review, block
+	Category  string         `gorm:"type:varchar(50)" json:"category"`                              // 如 spam, abuse, politics
+	IsActive  bool           `gorm:"not null;default:true;index" json:"is_active"`
+	CreatedBy uint           `gorm:"not null" json:"created_by"`
+	CreatedAt time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// ModerationCase 审核案件，同一内容同时只有一个待处理案件，多次举报累计到该案件
+type ModerationCase struct {
+	ID              uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	ContentType     string     `gorm:"type:varchar(20);not null;uniqueIndex:idx_moderation_pending_content,where:status = 'pending'" json:"content_type"`
+	ContentID       string     `gorm:"type:varchar(64);not null;uniqueIndex:idx_moderation_pending_content,where:status = 'pending'" json:"content_id"` // 评论ID或用户ID
+	AuthorID        uint       `gorm:"not null;index" json:"author_id"`
+	Content         string     `gorm:"type:text;not null" json:"content"`                               // 进入审核时的内容快照
+	Source          string     `gorm:"type:varchar(20);not null" json:"source"`                         // auto, report
+	Status          string     `gorm:"type:varchar(20);not null;default:'pending';index" json:"status"` // pending, approved, rejected
+	AutoAction      string     `gorm:"type:varchar(20)" json:"auto_action,omitempty"`                   // 自动判定结果：review, block
+	MatchedRuleID   *uuid.UUID `gorm:"type:uuid" json:"matched_rule_id,omitempty"`
+	MatchedRuleName string     `gorm:"type:varchar(100)" json:"matched_rule_name,omitempty"`
+	MatchedText     string     `gorm:"type:varchar(500)" json:"matched_text,omitempty"`
+	ReportCount     int        `gorm:"not null;default:0" json:"report_count"`
+	Resolution      string     `gorm:"type:varchar(20)" json:"resolution,omitempty"` // approve, reject, suspend, ban
+	ResolutionNote  string     `gorm:"type:text" json:"resolution_note,omitempty"`
+	ResolvedBy      *uint      `json:"resolved_by,omitempty"`
+	ResolvedAt      *time.Time `gorm:"type:timestamp" json:"resolved_at,omitempty"`
+	CreatedAt       time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt       time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// 关联关系
+	Reports []ContentReport `gorm:"foreignKey:CaseID" json:"reports,omitempty"`
+}
+
+// ContentReport 用户举报，每个用户对同一内容只能举报一次
+type ContentReport struct {
+	ID          uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	CaseID      uuid.UUID `gorm:"type:uuid;not null;index" json:"case_id"`
+	ContentType string    `gorm:"type:varchar(20);not null;uniqueIndex:idx_report_reporter_content" json:"content_type"`
+	ContentID   string    `gorm:"type:varchar(64);not null;uniqueIndex:idx_report_reporter_content" json:"content_id"`
+	ReporterID  uint      `gorm:"not null;uniqueIndex:idx_report_reporter_content" json:"reporter_id"`
+	Reason      string    `gorm:"type:varchar(50);not null" json:"reason"` // spam, abuse, inappropriate, other
+	Detail      string    `gorm:"type:text" json:"detail,omitempty"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
@@ -0,0 +1,78 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RatingTargetType 评分对象类型
+type RatingTargetType string
+
+const (
+	RatingTargetKnowledgePoint RatingTargetType = "knowledge_point"
+	RatingTargetLearningPath   RatingTargetType = "learning_path"
+)
+
+// IsValid 检查评分对象类型是否有效
+func (t RatingTargetType) IsValid() bool {
+	return t == RatingTargetKnowledgePoint || t == RatingTargetLearningPath
+}
+
+const (
+	MinRatingScore  = 1    // 最低星级
+	MaxRatingScore  = 5    // 最高星级
+	MaxReviewLength = 2000 // 评价内容的最大字符数
+)
+
+// Rating 评分实体，每个用户对每个知识点或学习路径只能评分一次，可修改
+type Rating struct {
+	ID           uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	TargetType   string    `gorm:"type:varchar(20);not null;uniqueIndex:idx_rating_target_user" json:"target_type"`
+	TargetID     uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_rating_target_user" json:"target_id"`
+	UserID       uint      `gorm:"not null;uniqueIndex:idx_rating_target_user;index" json:"user_id"`
+	Score        int       `gorm:"not null" json:"score"`
+	Review       string    `gorm:"type:text" json:"review"`
+	HelpfulCount int       `gorm:"not null;default:0" json:"helpful_count"`
+	IsEdited     bool      `gorm:"not null;default:false" json:"is_edited"`
+	CreatedAt    time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// 关联关系
+	User *User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+// HasReview 是否附带文字评价
+func (r *Rating) HasReview() bool {
+	return r.Review != ""
+}
+
+// RatingHelpfulVote 评价的"有帮助"投票，每个用户对每条评价只能投一次
+type RatingHelpfulVote struct {
+	RatingID  uuid.UUID `gorm:"type:uuid;primaryKey" json:"rating_id"`
+	UserID    uint      `gorm:"primaryKey;index" json:"user_id"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// RatingSummary 评分汇总，随评分的创建、修改和删除增量维护
+type RatingSummary struct {
+	TargetType  string    `gorm:"type:varchar(20);primaryKey" json:"target_type"`
+	TargetID    uuid.UUID `gorm:"type:uuid;primaryKey" json:"target_id"`
+	RatingCount int       `gorm:"not null;default:0" json:"rating_count"`
+	ScoreSum    int       `gorm:"not null;default:0" json:"score_sum"`
+	Average     float64   `gorm:"not null;default:0" json:"average"`
+	OneStar     int       `gorm:"not null;default:0" json:"one_star"`
+	TwoStar     int       `gorm:"not null;default:0" json:"two_star"`
+	ThreeStar   int       `gorm:"not null;default:0" json:"three_star"`
+	FourStar    int       `gorm:"not null;default:0" json:"four_star"`
+	FiveStar    int       `gorm:"not null;default:0" json:"five_star"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// BayesianScore 贝叶斯加权评分，评分数较少时向先验均值收缩，避免少量高分排在前面
+func (s *RatingSummary) BayesianScore(priorMean, priorWeight float64) float64 {
+	if s.RatingCount == 0 {
+		return priorMean
+	}
+	return (priorMean*priorWeight + float64(s.ScoreSum)) / (priorWeight + float64(s.RatingCount))
+}
@@ -0,0 +1,35 @@
+package entities
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRatingSummaryBayesianScore(t *testing.T) {
+	tests := []struct {
+		name        string
+		summary     RatingSummary
+		priorMean   float64
+		priorWeight float64
+		want        float64
+	}{
+		{"unrated uses the prior mean", RatingSummary{}, 3.5, 5, 3.5},
+		{"single rating shrinks to the prior", RatingSummary{RatingCount: 1, ScoreSum: 5}, 3, 5, 20.0 / 6},
+		{"low rating is pulled up", RatingSummary{RatingCount: 2, ScoreSum: 2}, 3, 5, 17.0 / 7},
+		{"many ratings approach the average", RatingSummary{RatingCount: 95, ScoreSum: 475}, 3, 5, 4.9},
+		{"zero prior weight is the plain average", RatingSummary{RatingCount: 4, ScoreSum: 14}, 3, 0, 3.5},
+	}
+	for _, tt := range tests {
+		if got := tt.summary.BayesianScore(tt.priorMean, tt.priorWeight); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("%s: BayesianScore() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	// 少量满分排在大量高分之后
+	few := RatingSummary{RatingCount: 2, ScoreSum: 10}
+	many := RatingSummary{RatingCount: 40, ScoreSum: 180}
+	if few.BayesianScore(3, 5) >= many.BayesianScore(3, 5) {
+		t.Errorf("two 5-star ratings = %v, forty 4.5 average = %v, want fewer ratings ranked lower",
+			few.BayesianScore(3, 5), many.BayesianScore(3, 5))
+	}
+}
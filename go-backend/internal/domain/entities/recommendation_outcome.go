@@ -0,0 +1,34 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RecommendationOutcome 用户对分析推荐的处理记录，用于衡量推荐质量
+type RecommendationOutcome struct {
+	ID                 uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	RecommendationID   uuid.UUID  `gorm:"type:uuid;not null;index;uniqueIndex:idx_recommendation_accepted,where:action = 'accepted'" json:"recommendation_id"`
+	AnalysisID         uuid.UUID  `gorm:"type:uuid;not null;index" json:"analysis_id"`
+	GoalID             uuid.UUID  `gorm:"type:uuid;not null;index" json:"goal_id"`
+	UserID             uint       `gorm:"not null;index" json:"user_id"`
+	Action             string     `gorm:"type:varchar(20);not null;index" json:"action"` // accepted, dismissed, snoozed
+	Reason             string     `gorm:"type:text" json:"reason,omitempty"`
+	SnoozedUntil       *time.Time `gorm:"type:timestamp" json:"snoozed_until,omitempty"`
+	RecommendationType string     `gorm:"type:varchar(50);not null" json:"recommendation_type"`
+	AnalysisType       string     `gorm:"type:varchar(50)" json:"analysis_type"`
+	Analyzer           string     `gorm:"type:varchar(50)" json:"analyzer"`
+	Priority           string     `gorm:"type:varchar(20)" json:"priority"`
+	CreatedPaths       int        `gorm:"not null;default:0" json:"created_paths"` // 接受时创建的学习路径步骤数
+	CreatedAt          time.Time  `gorm:"autoCreateTime;index" json:"created_at"`
+}
+
+// RecommendationOutcomeStat 推荐处理结果统计
+type RecommendationOutcomeStat struct {
+	Analyzer           string `json:"analyzer"`
+	AnalysisType       string `json:"analysis_type"`
+	RecommendationType string `json:"recommendation_type"`
+	Action             string `json:"action"`
+	Count              int64  `json:"count"`
+}
@@ -0,0 +1,34 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RegradeStatus 复核申请状态
+type RegradeStatus string
+
+const (
+	RegradePending  RegradeStatus = "pending"
+	RegradeAccepted RegradeStatus = "accepted" // 已重新评分
+	RegradeRejected RegradeStatus = "rejected" // 维持原评分
+)
+
+// RegradeRequest 学习者对单题评分提出的复核申请
+type RegradeRequest struct {
+	ID             uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	AttemptID      uuid.UUID  `gorm:"type:uuid;not null;index" json:"attempt_id"`
+	AnswerID       uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_regrade_pending_answer,where:status = 'pending'" json:"answer_id"` // 同一答案同时只能有一个待处理申请
+	QuestionID     uuid.UUID  `gorm:"type:uuid;not null" json:"question_id"`
+	UserID         uint       `gorm:"not null;index" json:"user_id"`
+	Reason         string     `gorm:"type:text;not null" json:"reason"`
+	Status         string     `gorm:"type:varchar(20);not null;default:'pending';index" json:"status"` // pending, accepted, rejected
+	Response       string     `gorm:"type:text" json:"response,omitempty"`                             // 评分人答复
+	PreviousPoints float64    `gorm:"type:decimal(6,2);not null;default:0" json:"previous_points"`
+	NewPoints      *float64   `gorm:"type:decimal(6,2)" json:"new_points,omitempty"`
+	ResolvedBy     *uint      `json:"resolved_by,omitempty"`
+	ResolvedAt     *time.Time `gorm:"type:timestamp" json:"resolved_at,omitempty"`
+	CreatedAt      time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
@@ -0,0 +1,16 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SearchLog 知识点检索日志，用于统计热门检索词
+type SearchLog struct {
+	ID          uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Query       string    `gorm:"type:varchar(100);not null" json:"query"`
+	Normalized  string    `gorm:"type:varchar(100);not null;index:idx_search_log_normalized" json:"normalized"` // 去除首尾空白并转为小写的检索词
+	ResultCount int64     `gorm:"not null;default:0" json:"result_count"`
+	CreatedAt   time.Time `gorm:"autoCreateTime;index" json:"created_at"`
+}
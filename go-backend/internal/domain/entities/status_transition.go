@@ -0,0 +1,111 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GoalStatus 学习目标状态
+type GoalStatus string
+
+const (
+	GoalStatusActive    GoalStatus = "active"
+	GoalStatusCompleted GoalStatus = "completed"
+	GoalStatusPaused    GoalStatus = "paused"
+)
+
+// PathStatus 学习路径步骤状态
+type PathStatus string
+
+const (
+	PathStatusPending    PathStatus = "pending"
+	PathStatusInProgress PathStatus = "in_progress"
+	PathStatusCompleted  PathStatus = "completed"
+)
+
+// TransitionEntityType 状态转换所属实体类型
+type TransitionEntityType string
+
+const (
+	TransitionEntityGoal TransitionEntityType = "learning_goal"
+	TransitionEntityPath TransitionEntityType = "learning_path"
+)
+
+// TransitionTrigger 状态转换触发来源
+type TransitionTrigger string
+
+const (
+	TransitionTriggerUser   TransitionTrigger = "user"
+	TransitionTriggerSystem TransitionTrigger = "system"
+)
+
+// goalStatusTransitions 学习目标允许的状态转换
+var goalStatusTransitions = map[GoalStatus][]GoalStatus{
+	GoalStatusActive:    {GoalStatusPaused, GoalStatusCompleted},
+	GoalStatusPaused:    {GoalStatusActive},
+	GoalStatusCompleted: {GoalStatusActive}, // 新增或重开步骤时重新激活
+}
+
+// pathStatusTransitions 学习路径步骤允许的状态转换
+var pathStatusTransitions = map[PathStatus][]PathStatus{
+	PathStatusPending:    {PathStatusInProgress},
+	PathStatusInProgress: {PathStatusCompleted, PathStatusPending},
+	PathStatusCompleted:  {PathStatusInProgress}, // 重开复习
+}
+
+// StatusTransition 状态转换历史
+type StatusTransition struct {
+	ID         uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	EntityType string    `gorm:"type:varchar(50);not null;index:idx_status_transitions_entity" json:"entity_type"` // learning_goal, learning_path
+	EntityID   uuid.UUID `gorm:"type:uuid;not null;index:idx_status_transitions_entity" json:"entity_id"`
+	GoalID     uuid.UUID `gorm:"type:uuid;not null;index" json:"goal_id"`
+	FromStatus string    `gorm:"type:varchar(50);not null" json:"from_status"`
+	ToStatus   string    `gorm:"type:varchar(50);not null" json:"to_status"`
+	Trigger    string    `gorm:"type:varchar(20);not null;default:'user'" json:"trigger"` // user, system
+	Override   bool      `gorm:"not null;default:false" json:"override"`                  // 是否跳过前置步骤检查
+	Reason     string    `gorm:"type:text" json:"reason"`
+	CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// IsValid 检查是否为合法的目标状态
+func (s GoalStatus) IsValid() bool {
+	_, exists := goalStatusTransitions[s]
+	return exists
+}
+
+// CanTransitionTo 检查目标状态是否允许转换到指定状态
+func (s GoalStatus) CanTransitionTo(target GoalStatus) bool {
+	for _, allowed := range goalStatusTransitions[s] {
+		if allowed == target {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValid 检查是否为合法的步骤状态
+func (s PathStatus) IsValid() bool {
+	_, exists := pathStatusTransitions[s]
+	return exists
+}
+
+// CanTransitionTo 检查步骤状态是否允许转换到指定状态
+func (s PathStatus) CanTransitionTo(target PathStatus) bool {
+	for _, allowed := range pathStatusTransitions[s] {
+		if allowed == target {
+			return true
+		}
+	}
+	return false
+}
+
+// IsCompleted 检查步骤是否已完成
+func (p *LearningPath) IsCompleted() bool {
+	return p.Status == string(PathStatusCompleted)
+}
+
+// IsCompleted 检查目标是否已完成
+func (g *LearningGoal) IsCompleted() bool {
+	return g.Status == string(GoalStatusCompleted)
+}
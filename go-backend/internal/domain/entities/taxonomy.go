@@ -0,0 +1,81 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CategorySkillKind 类别技能映射类型
+type CategorySkillKind string
+
+const (
+	CategorySkillRequired     CategorySkillKind = "required"     // 必备技能
+	CategorySkillPrerequisite CategorySkillKind = "prerequisite" // 前置条件
+)
+
+// IsValid 检查映射类型是否合法
+func (k CategorySkillKind) IsValid() bool {
+	return k == CategorySkillRequired || k == CategorySkillPrerequisite
+}
+
+// Category 知识类别（ParentID非空时为子类别）
+// 学习目标和知识点的Category字段存储类别名称
+type Category struct {
+	ID          uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	ParentID    *uuid.UUID     `gorm:"type:uuid;index" json:"parent_id"`
+	Name        string         `gorm:"type:varchar(100);not null;uniqueIndex" json:"name"`
+	Description string         `gorm:"type:text" json:"description"`
+	BaseHours   int            `gorm:"not null;default:0" json:"base_hours"` // 达成该类别目标的基准学习时间(小时)
+	SortOrder   int            `gorm:"not null;default:0" json:"sort_order"`
+	IsActive    bool           `gorm:"not null;default:true" json:"is_active"`
+	CreatedAt   time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// 关联关系
+	Subcategories []Category         `gorm:"foreignKey:ParentID" json:"subcategories,omitempty"`
+	Durations     []CategoryDuration `gorm:"foreignKey:CategoryID" json:"durations,omitempty"`
+}
+
+// IsSubcategory 是否为子类别
+func (c *Category) IsSubcategory() bool {
+	return c.ParentID != nil
+}
+
+// Skill 技能
+type Skill struct {
+	ID          uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Name        string         `gorm:"type:varchar(100);not null;uniqueIndex" json:"name"`
+	Description string         `gorm:"type:text" json:"description"`
+	CreatedAt   time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// CategorySkill 类别与技能的映射
+// Difficulty为空表示适用于所有难度
+type CategorySkill struct {
+	ID         uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	CategoryID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_category_skill_mapping" json:"category_id"`
+	SkillID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_category_skill_mapping" json:"skill_id"`
+	Kind       string    `gorm:"type:varchar(20);not null;uniqueIndex:idx_category_skill_mapping" json:"kind"` // required, prerequisite
+	Difficulty string    `gorm:"type:varchar(50);not null;default:'';uniqueIndex:idx_category_skill_mapping" json:"difficulty"`
+	SortOrder  int       `gorm:"not null;default:0" json:"sort_order"`
+	CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`
+
+	// 关联关系
+	Category Category `gorm:"foreignKey:CategoryID" json:"-"`
+	Skill    Skill    `gorm:"foreignKey:SkillID" json:"skill,omitempty"`
+}
+
+// CategoryDuration 类别在各难度下单个知识点的基准学习时间
+type CategoryDuration struct {
+	ID         uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	CategoryID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_category_duration_difficulty" json:"category_id"`
+	Difficulty string    `gorm:"type:varchar(50);not null;uniqueIndex:idx_category_duration_difficulty" json:"difficulty"`
+	StepHours  int       `gorm:"not null" json:"step_hours"`
+	CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt  time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
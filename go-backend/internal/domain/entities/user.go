@@ -67,6 +67,8 @@ type UserRole string
 const (
 	RoleAdmin     UserRole = "admin"
 	RoleModerator UserRole = "moderator"
+	RoleAuthor    UserRole = "author"   // 知识点作者，可创建和修改草稿
+	RoleReviewer  UserRole = "reviewer" // 知识点审核员，可审核和发布
 	RoleUser      UserRole = "user"
 	RoleGuest     UserRole = "guest"
 )
@@ -0,0 +1,120 @@
+package entities
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MasterySource 掌握度更新来源
+type MasterySource string
+
+const (
+	MasterySourceAssessment MasterySource = "assessment"
+	MasterySourceDiagnostic MasterySource = "diagnostic"
+	MasterySourcePath       MasterySource = "path"
+	MasterySourceReview     MasterySource = "review"
+)
+
+// SM-2 间隔重复参数
+const (
+	DefaultEaseFactor = 2.5
+	MinEaseFactor     = 1.3
+	MaxReviewQuality  = 5
+	PassingQuality    = 3 // 低于该值视为遗忘，重新开始复习
+)
+
+// UserKnowledgeMastery 用户对知识点的掌握度及复习计划（SM-2）
+type UserKnowledgeMastery struct {
+	ID               uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID           uint       `gorm:"not null;uniqueIndex:idx_user_knowledge_mastery" json:"user_id"`
+	KnowledgePointID uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_user_knowledge_mastery;index" json:"knowledge_point_id"`
+	Mastery          float64    `gorm:"type:decimal(4,3);not null;default:0" json:"mastery"` // 0-1
+	EvidenceCount    int        `gorm:"not null;default:0" json:"evidence_count"`
+	LastSource       string     `gorm:"type:varchar(20);not null" json:"last_source"` // assessment, diagnostic, path, review
+	EaseFactor       float64    `gorm:"type:decimal(4,2);not null;default:2.5" json:"ease_factor"`
+	IntervalDays     int        `gorm:"not null;default:0" json:"interval_days"`
+	Repetitions      int        `gorm:"not null;default:0" json:"repetitions"`
+	LastQuality      int        `gorm:"not null;default:0" json:"last_quality"`
+	LastReviewedAt   *time.Time `gorm:"type:timestamp" json:"last_reviewed_at"`
+	NextReviewAt     time.Time  `gorm:"type:timestamp;not null;index" json:"next_review_at"`
+	CreatedAt        time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt        time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// 关联关系
+	KnowledgePoint *KnowledgePoint `gorm:"foreignKey:KnowledgePointID" json:"knowledge_point,omitempty"`
+}
+
+// TableName 指定表名
+func (UserKnowledgeMastery) TableName() string {
+	return "user_knowledge_mastery"
+}
+
+// NewUserKnowledgeMastery 创建初始掌握度记录
+func NewUserKnowledgeMastery(userID uint, pointID uuid.UUID, now time.Time) *UserKnowledgeMastery {
+	return &UserKnowledgeMastery{
+		UserID:           userID,
+		KnowledgePointID: pointID,
+		EaseFactor:       DefaultEaseFactor,
+		NextReviewAt:     now,
+	}
+}
+
+// Schedule 按SM-2算法根据回忆质量(0-5)计算下次复习时间
+func (m *UserKnowledgeMastery) Schedule(quality int, now time.Time) {
+	if quality < 0 {
+		quality = 0
+	}
+	if quality > MaxReviewQuality {
+		quality = MaxReviewQuality
+	}
+
+	if quality < PassingQuality {
+		m.Repetitions = 0
+		m.IntervalDays = 1
+	} else {
+		switch m.Repetitions {
+		case 0:
+			m.IntervalDays = 1
+		case 1:
+			m.IntervalDays = 6
+		default:
+			m.IntervalDays = int(math.Round(float64(m.IntervalDays) * m.EaseFactor))
+		}
+		m.Repetitions++
+	}
+
+	q := float64(MaxReviewQuality - quality)
+	m.EaseFactor += 0.1 - q*(0.08+q*0.02)
+	if m.EaseFactor < MinEaseFactor {
+		m.EaseFactor = MinEaseFactor
+	}
+	m.EaseFactor = math.Round(m.EaseFactor*100) / 100
+
+	m.LastQuality = quality
+	m.LastReviewedAt = &now
+	m.NextReviewAt = now.AddDate(0, 0, m.IntervalDays)
+}
+
+// Observe 以指数移动平均合并一次掌握度观测，weight为本次观测的权重(0-1)
+func (m *UserKnowledgeMastery) Observe(level, weight float64, source MasterySource) {
+	if m.EvidenceCount == 0 {
+		m.Mastery = level
+	} else {
+		m.Mastery = m.Mastery*(1-weight) + level*weight
+	}
+	m.Mastery = math.Round(math.Max(0, math.Min(1, m.Mastery))*1000) / 1000
+	m.EvidenceCount++
+	m.LastSource = string(source)
+}
+
+// IsDue 是否到了复习时间
+func (m *UserKnowledgeMastery) IsDue(now time.Time) bool {
+	return !m.NextReviewAt.After(now)
+}
+
+// QualityFromScore 将得分率(0-1)换算为SM-2回忆质量(0-5)
+func QualityFromScore(ratio float64) int {
+	return int(math.Round(math.Max(0, math.Min(1, ratio)) * MaxReviewQuality))
+}
@@ -0,0 +1,130 @@
+package entities
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestUserKnowledgeMasterySchedule(t *testing.T) {
+	now := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+	type step struct {
+		quality      int
+		wantInterval int
+		wantReps     int
+		wantEase     float64
+	}
+	tests := []struct {
+		name  string
+		start UserKnowledgeMastery
+		steps []step
+	}{
+		{
+			name:  "intervals 1, 6, then interval times ease factor",
+			start: UserKnowledgeMastery{EaseFactor: DefaultEaseFactor},
+			steps: []step{
+				{4, 1, 1, 2.5},
+				{4, 6, 2, 2.5},
+				{4, 15, 3, 2.5},
+				{4, 38, 4, 2.5}, // 15×2.5=37.5，四舍五入
+			},
+		},
+		{
+			name:  "perfect recall raises ease factor",
+			start: UserKnowledgeMastery{EaseFactor: DefaultEaseFactor},
+			steps: []step{
+				{5, 1, 1, 2.6},
+				{5, 6, 2, 2.7},
+				{5, 16, 3, 2.8}, // 6×2.7=16.2，间隔按更新前的难度系数计算
+			},
+		},
+		{
+			name:  "quality below passing resets repetitions",
+			start: UserKnowledgeMastery{EaseFactor: DefaultEaseFactor, Repetitions: 3, IntervalDays: 15},
+			steps: []step{
+				{2, 1, 0, 2.18},
+				{3, 1, 1, 2.04},
+				{3, 6, 2, 1.9},
+			},
+		},
+		{
+			name:  "ease factor never drops below the floor",
+			start: UserKnowledgeMastery{EaseFactor: 1.4, Repetitions: 2, IntervalDays: 10},
+			steps: []step{
+				{0, 1, 0, MinEaseFactor},
+				{1, 1, 0, MinEaseFactor},
+				{3, 1, 1, MinEaseFactor},
+			},
+		},
+		{
+			name:  "quality is clamped to 0-5",
+			start: UserKnowledgeMastery{EaseFactor: DefaultEaseFactor},
+			steps: []step{
+				{9, 1, 1, 2.6},
+				{-3, 1, 0, 1.8},
+			},
+		},
+	}
+	for _, tt := range tests {
+		m := tt.start
+		for i, s := range tt.steps {
+			m.Schedule(s.quality, now)
+			if m.IntervalDays != s.wantInterval || m.Repetitions != s.wantReps || m.EaseFactor != s.wantEase {
+				t.Fatalf("%s: step %d: interval = %d, repetitions = %d, ease = %v, want %d, %d, %v",
+					tt.name, i, m.IntervalDays, m.Repetitions, m.EaseFactor, s.wantInterval, s.wantReps, s.wantEase)
+			}
+			wantQuality := min(max(s.quality, 0), MaxReviewQuality)
+			if m.LastQuality != wantQuality {
+				t.Fatalf("%s: step %d: last quality = %d, want %d", tt.name, i, m.LastQuality, wantQuality)
+			}
+			if m.LastReviewedAt == nil || !m.LastReviewedAt.Equal(now) || !m.NextReviewAt.Equal(now.AddDate(0, 0, s.wantInterval)) {
+				t.Fatalf("%s: step %d: reviewed at %v, next review %v", tt.name, i, m.LastReviewedAt, m.NextReviewAt)
+			}
+		}
+	}
+}
+
+func TestUserKnowledgeMasteryObserve(t *testing.T) {
+	tests := []struct {
+		name         string
+		observations [][2]float64 // level, weight
+		want         float64
+	}{
+		{"first observation is taken as is", [][2]float64{{0.8, 0.3}}, 0.8},
+		{"later observations are weighted", [][2]float64{{0.8, 0.3}, {0.2, 0.5}}, 0.5},
+		{"moving average", [][2]float64{{1, 0.3}, {0, 0.3}, {0, 0.3}}, 0.49},
+		{"rounded to three decimals", [][2]float64{{0.1, 1}, {0.2, 1.0 / 3}}, 0.133},
+		{"clamped to 0-1", [][2]float64{{1.5, 0.3}}, 1},
+	}
+	for _, tt := range tests {
+		m := NewUserKnowledgeMastery(1, uuid.New(), time.Now())
+		for _, o := range tt.observations {
+			m.Observe(o[0], o[1], MasterySourceAssessment)
+		}
+		if m.Mastery != tt.want || m.EvidenceCount != len(tt.observations) || m.LastSource != string(MasterySourceAssessment) {
+			t.Errorf("%s: mastery = %v, evidence = %d, source = %s, want %v, %d", tt.name, m.Mastery, m.EvidenceCount, m.LastSource, tt.want, len(tt.observations))
+		}
+	}
+}
+
+func TestQualityFromScore(t *testing.T) {
+	tests := []struct {
+		ratio float64
+		want  int
+	}{
+		{-0.5, 0},
+		{0, 0},
+		{0.29, 1},
+		{0.5, 3},
+		{0.59, 3},
+		{0.7, 4},
+		{1, 5},
+		{1.8, 5},
+	}
+	for _, tt := range tests {
+		if got := QualityFromScore(tt.ratio); got != tt.want {
+			t.Errorf("QualityFromScore(%v) = %d, want %d", tt.ratio, got, tt.want)
+		}
+	}
+}
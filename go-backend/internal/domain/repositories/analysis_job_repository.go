@@ -0,0 +1,31 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+)
+
+// AnalysisJobRepository 分析任务仓储接口
+type AnalysisJobRepository interface {
+	// Create 创建任务
+	Create(ctx context.Context, job *entities.AnalysisJob) error
+
+	// GetByID 根据ID获取任务
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.AnalysisJob, error)
+
+	// FindRecentByDedupKey 查找同键的排队中任务、staleBefore之后开始执行的任务或since之后创建的成功任务
+	FindRecentByDedupKey(ctx context.Context, dedupKey string, since, staleBefore time.Time) (*entities.AnalysisJob, error)
+
+	// ClaimNext 领取下一个待执行任务（含执行超时的任务），没有任务时返回nil
+	// 执行超时且已达最大尝试次数的任务标记为失败
+	ClaimNext(ctx context.Context, staleBefore time.Time, maxAttempts int) (*entities.AnalysisJob, error)
+
+	// MarkSucceeded 标记任务成功
+	MarkSucceeded(ctx context.Context, id uuid.UUID) error
+
+	// MarkFailed 标记任务失败
+	MarkFailed(ctx context.Context, id uuid.UUID, errMsg string) error
+}
@@ -0,0 +1,20 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"sical-go-backend/internal/domain/entities"
+)
+
+// AnalysisUsageRepository 分析调用记录仓储接口
+type AnalysisUsageRepository interface {
+	// Create 创建调用记录
+	Create(ctx context.Context, usage *entities.AnalysisUsage) error
+
+	// GetByUserID 获取用户在时间范围内的调用记录（from为空表示不限）
+	GetByUserID(ctx context.Context, userID uint, from *time.Time) ([]*entities.AnalysisUsage, error)
+
+	// SumCostByUserID 统计用户自from起的调用费用
+	SumCostByUserID(ctx context.Context, userID uint, from time.Time) (float64, error)
+}
@@ -0,0 +1,113 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+)
+
+// AssessmentFilter 测评列表筛选条件
+type AssessmentFilter struct {
+	Category      string
+	Difficulty    string
+	Type          string
+	PublishedOnly bool
+}
+
+// AssessmentRepository 测评仓储接口
+type AssessmentRepository interface {
+	// Create 创建测评及其题目和关联知识点
+	Create(ctx context.Context, assessment *entities.Assessment) error
+
+	// GetByID 根据ID获取测评，题目按顺序加载
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.Assessment, error)
+
+	// Update 更新测评基本信息
+	Update(ctx context.Context, assessment *entities.Assessment) error
+
+	// List 按条件分页获取测评列表
+	List(ctx context.Context, filter AssessmentFilter, offset, limit int) ([]*entities.Assessment, int64, error)
+
+	// ListQuestionsByKnowledgePoints 获取已发布测评中关联指定知识点的题目，types为空时不限题型
+	ListQuestionsByKnowledgePoints(ctx context.Context, knowledgePointIDs []uuid.UUID, types []string) ([]*entities.AssessmentQuestion, error)
+
+	// GetQuestionByID 根据ID获取题目
+	GetQuestionByID(ctx context.Context, id uuid.UUID) (*entities.AssessmentQuestion, error)
+
+	// RecordCompletion 累计一次完成的作答，同步更新完成人次和平均分
+	RecordCompletion(ctx context.Context, id uuid.UUID, score float64) error
+
+	// AdjustAverageScore 已计入统计的作答成绩变化时修正平均分
+	AdjustAverageScore(ctx context.Context, id uuid.UUID, delta float64) error
+}
+
+// AssessmentAttemptRepository 测评作答仓储接口
+type AssessmentAttemptRepository interface {
+	// Create 创建作答记录
+	Create(ctx context.Context, attempt *entities.AssessmentAttempt) error
+
+	// GetByID 根据ID获取作答记录及答案
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.AssessmentAttempt, error)
+
+	// GetInProgress 获取用户在测评上进行中的作答，不存在时返回nil
+	GetInProgress(ctx context.Context, assessmentID uuid.UUID, userID uint) (*entities.AssessmentAttempt, error)
+
+	// CountByUser 统计用户在测评上的作答次数
+	CountByUser(ctx context.Context, assessmentID uuid.UUID, userID uint) (int64, error)
+
+	// ListByUser 获取用户在测评上的作答记录，按作答次序倒序
+	ListByUser(ctx context.Context, assessmentID uuid.UUID, userID uint) ([]*entities.AssessmentAttempt, error)
+
+	// SaveAnswers 保存答案，同一题目的答案会被覆盖
+	SaveAnswers(ctx context.Context, answers []*entities.AttemptAnswer) error
+
+	// MarkSubmitted 将进行中的作答标记为已提交，作答已不在进行中时返回false
+	MarkSubmitted(ctx context.Context, id uuid.UUID, submittedAt time.Time) (bool, error)
+
+	// Update 更新作答记录及其答案的评分结果
+	Update(ctx context.Context, attempt *entities.AssessmentAttempt) error
+
+	// ListPendingReview 分页获取有待人工评分答案的作答，按提交时间先后排序
+	ListPendingReview(ctx context.Context, assessmentID *uuid.UUID, offset, limit int) ([]*entities.AssessmentAttempt, int64, error)
+
+	// UpdateAnswer 更新单题答案的评分结果
+	UpdateAnswer(ctx context.Context, answer *entities.AttemptAnswer) error
+
+	// UpdateScore 更新作答成绩，仅当作答状态仍为fromStatus时生效，状态已变化时返回false
+	UpdateScore(ctx context.Context, attempt *entities.AssessmentAttempt, fromStatus string) (bool, error)
+}
+
+// RegradeRequestRepository 评分复核申请仓储接口
+type RegradeRequestRepository interface {
+	// Create 创建复核申请
+	Create(ctx context.Context, request *entities.RegradeRequest) error
+
+	// GetByID 根据ID获取复核申请
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.RegradeRequest, error)
+
+	// ListByStatus 按状态分页获取复核申请，按申请时间先后排序；status为空时不筛选
+	ListByStatus(ctx context.Context, status string, offset, limit int) ([]*entities.RegradeRequest, int64, error)
+
+	// ListByAttempt 获取作答的复核申请，按申请时间倒序
+	ListByAttempt(ctx context.Context, attemptID uuid.UUID) ([]*entities.RegradeRequest, error)
+
+	// Resolve 处理待处理的复核申请，申请已被处理时返回false
+	Resolve(ctx context.Context, request *entities.RegradeRequest) (bool, error)
+}
+
+// DiagnosticSessionRepository 自适应诊断测评仓储接口
+type DiagnosticSessionRepository interface {
+	// Create 创建诊断测评
+	Create(ctx context.Context, session *entities.DiagnosticSession) error
+
+	// GetByID 根据ID获取诊断测评及作答记录
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.DiagnosticSession, error)
+
+	// GetInProgress 获取目标进行中的诊断测评，不存在时返回nil
+	GetInProgress(ctx context.Context, goalID uuid.UUID) (*entities.DiagnosticSession, error)
+
+	// AddResponse 记录作答并更新诊断测评状态，题目不是当前待答题目时返回false
+	AddResponse(ctx context.Context, session *entities.DiagnosticSession, response *entities.DiagnosticResponse, answeredQuestionID uuid.UUID) (bool, error)
+}
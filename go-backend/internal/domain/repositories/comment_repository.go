@@ -0,0 +1,63 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+)
+
+// CommentCursor 评论游标分页位置，为上一页最后一条评论的排序键
+type CommentCursor struct {
+	Pinned    bool
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// CommentListOptions 评论列表查询选项
+type CommentListOptions struct {
+	IncludeHidden bool           // 是否包含被隐藏的评论
+	Cursor        *CommentCursor // 为空时从第一页开始
+	Limit         int
+}
+
+// CommentRepository 评论仓储接口
+// 已删除的评论仍有回复时保留在列表中，由上层显示为占位内容
+type CommentRepository interface {
+	// Create 创建评论及其提及，回复会同步增加父评论的回复数
+	Create(ctx context.Context, comment *entities.Comment) error
+
+	// GetByID 根据ID获取评论，包含已删除的评论
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.Comment, error)
+
+	// ListThreads 获取对象下的讨论串，置顶优先，其余按创建时间倒序
+	ListThreads(ctx context.Context, targetType string, targetID uuid.UUID, opts CommentListOptions) ([]*entities.Comment, error)
+
+	// ListReplies 获取讨论串内的全部回复，按创建时间正序
+	ListReplies(ctx context.Context, rootID uuid.UUID, opts CommentListOptions) ([]*entities.Comment, error)
+
+	// UpdateContent 保存编辑前的内容到编辑历史并更新评论内容和提及
+	UpdateContent(ctx context.Context, comment *entities.Comment, edit *entities.CommentEdit) error
+
+	// ListEdits 获取评论的编辑历史，按编辑时间倒序
+	ListEdits(ctx context.Context, commentID uuid.UUID) ([]*entities.CommentEdit, error)
+
+	// Delete 软删除评论，回复会同步减少父评论的回复数
+	Delete(ctx context.Context, comment *entities.Comment) error
+
+	// UpdateModeration 更新评论的隐藏、置顶和锁定状态
+	UpdateModeration(ctx context.Context, comment *entities.Comment) error
+
+	// AddLike 点赞评论，已点赞时返回false
+	AddLike(ctx context.Context, commentID uuid.UUID, userID uint) (bool, error)
+
+	// RemoveLike 取消点赞，未点赞时返回false
+	RemoveLike(ctx context.Context, commentID uuid.UUID, userID uint) (bool, error)
+
+	// LikedBy 返回指定评论中用户已点赞的评论ID
+	LikedBy(ctx context.Context, userID uint, commentIDs []uuid.UUID) (map[uuid.UUID]bool, error)
+
+	// ListMentioning 分页获取提及用户的评论，按创建时间倒序
+	ListMentioning(ctx context.Context, userID uint, offset, limit int) ([]*entities.Comment, int64, error)
+}
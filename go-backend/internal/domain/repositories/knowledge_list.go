@@ -0,0 +1,58 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+)
+
+// KnowledgeSortField 知识点列表的排序字段
+type KnowledgeSortField string
+
+const (
+	KnowledgeSortCreatedAt  KnowledgeSortField = "created_at"
+	KnowledgeSortUpdatedAt  KnowledgeSortField = "updated_at"
+	KnowledgeSortTitle      KnowledgeSortField = "title"
+	KnowledgeSortDifficulty KnowledgeSortField = "difficulty" // 按beginner、intermediate、advanced的顺序
+)
+
+// IsValid 是否为允许的排序字段
+func (f KnowledgeSortField) IsValid() bool {
+	switch f {
+	case KnowledgeSortCreatedAt, KnowledgeSortUpdatedAt, KnowledgeSortTitle, KnowledgeSortDifficulty:
+		return true
+	}
+	return false
+}
+
+// KnowledgeListCursor 知识点列表游标分页位置，为上一页最后一条知识点的排序键
+// 按时间排序时使用Time，按标题排序时使用Text，按难度排序时使用Rank
+type KnowledgeListCursor struct {
+	Time time.Time
+	Text string
+	Rank int
+	ID   uuid.UUID
+}
+
+// KnowledgeListQuery 知识点列表查询条件，各筛选条件为零值时不限
+type KnowledgeListQuery struct {
+	Category         string
+	Difficulty       string
+	Status           string     // 编辑状态
+	Tags             []string   // 须包含全部标签
+	CreatedFrom      *time.Time // 创建时间下限(含)
+	CreatedTo        *time.Time // 创建时间上限(不含)
+	HasPrerequisites *bool
+	SortField        KnowledgeSortField
+	Descending       bool
+	Cursor           *KnowledgeListCursor // 为空时从第一页开始
+	Columns          []string             // 需要读取的列，为空时读取全部列
+	Limit            int
+}
+
+// KnowledgeListResult 知识点列表结果，Total为满足筛选条件的总数
+type KnowledgeListResult struct {
+	Points []*entities.KnowledgePoint
+	Total  int64
+}
@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+)
+
+// KnowledgeCooccurrenceSource 共现的来源
+type KnowledgeCooccurrenceSource string
+
+const (
+	KnowledgeCooccurrenceFromPaths    KnowledgeCooccurrenceSource = "paths"    // 同一学习路径包含的知识点
+	KnowledgeCooccurrenceFromLearners KnowledgeCooccurrenceSource = "learners" // 同一学习者有学习记录的知识点
+)
+
+// KnowledgePairCount 两个知识点的共现次数，每对知识点两个方向各一条
+type KnowledgePairCount struct {
+	KnowledgePointID uuid.UUID
+	RelatedID        uuid.UUID
+	Count            int
+}
+
+// KnowledgeCooccurrenceCounts 一类来源的共现统计
+type KnowledgeCooccurrenceCounts struct {
+	Items map[uuid.UUID]int // 每个知识点出现的路径数或学习者数
+	Pairs []KnowledgePairCount
+}
+
+// KnowledgeRelationRepository 相关知识点仓储接口
+type KnowledgeRelationRepository interface {
+	// CreateRelation 创建双向的相关链接，已存在时返回false
+	CreateRelation(ctx context.Context, relation *entities.KnowledgeRelation) (bool, error)
+
+	// DeleteRelation 删除双向的相关链接，不存在时返回false
+	DeleteRelation(ctx context.Context, pointID, relatedID uuid.UUID) (bool, error)
+
+	// ListRelations 获取知识点的相关链接，预加载相关知识点，已删除的知识点Related为nil
+	ListRelations(ctx context.Context, pointID uuid.UUID) ([]*entities.KnowledgeRelation, error)
+
+	// CountCooccurrences 统计一类来源中共现次数不少于minSupport的知识点对，只统计已发布的知识点
+	CountCooccurrences(ctx context.Context, source KnowledgeCooccurrenceSource, minSupport int) (*KnowledgeCooccurrenceCounts, error)
+
+	// ReplaceCooccurrences 在事务中以新的计算结果替换全部共现记录
+	ReplaceCooccurrences(ctx context.Context, cooccurrences []*entities.KnowledgeCooccurrence) error
+
+	// ListCooccurrences 获取知识点相似度最高的共现记录，预加载相关知识点
+	ListCooccurrences(ctx context.Context, pointID uuid.UUID, limit int) ([]*entities.KnowledgeCooccurrence, error)
+}
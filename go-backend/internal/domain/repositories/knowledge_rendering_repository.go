@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+)
+
+// KnowledgeRenderingRepository 知识点正文渲染缓存仓储接口
+type KnowledgeRenderingRepository interface {
+	// ListByPoints 获取知识点的渲染缓存，没有缓存的知识点不出现在结果中
+	ListByPoints(ctx context.Context, pointIDs []uuid.UUID) ([]*entities.KnowledgePointRendering, error)
+
+	// Save 保存渲染缓存，已存在时覆盖
+	Save(ctx context.Context, rendering *entities.KnowledgePointRendering) error
+}
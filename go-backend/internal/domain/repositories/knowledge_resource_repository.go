@@ -0,0 +1,39 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+)
+
+// KnowledgeResourceRepository 知识点资源仓储接口
+type KnowledgeResourceRepository interface {
+	// Create 创建资源，排在知识点现有资源之后
+	Create(ctx context.Context, resource *entities.KnowledgeResource) error
+
+	// Update 保存资源内容，不修改排序
+	Update(ctx context.Context, resource *entities.KnowledgeResource) error
+
+	// Delete 删除资源
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// GetByID 获取资源，不存在时返回nil
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.KnowledgeResource, error)
+
+	// ListByPoint 获取知识点的全部资源，按排序位置正序
+	ListByPoint(ctx context.Context, pointID uuid.UUID) ([]*entities.KnowledgeResource, error)
+
+	// Reorder 按ids的顺序重写知识点资源的排序位置，ids须为该知识点的全部资源
+	Reorder(ctx context.Context, pointID uuid.UUID, ids []uuid.UUID) error
+
+	// ListLinksDue 获取从未检查或上次检查早于before的外部链接
+	ListLinksDue(ctx context.Context, before time.Time, limit int) ([]*entities.KnowledgeResource, error)
+
+	// UpdateLinkStatus 保存链接检查结果，检查期间链接已被修改时不保存
+	UpdateLinkStatus(ctx context.Context, resource *entities.KnowledgeResource) error
+
+	// ListBroken 分页获取失效的外部链接，按检查时间倒序
+	ListBroken(ctx context.Context, offset, limit int) ([]*entities.KnowledgeResource, int64, error)
+}
@@ -0,0 +1,25 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+)
+
+// KnowledgeRevisionRepository 知识点修订仓储接口
+// 知识点内容的创建和修改都经过该仓储，保证每个版本都有对应的修订记录
+type KnowledgeRevisionRepository interface {
+	// CreatePoint 创建知识点并记录首个修订
+	CreatePoint(ctx context.Context, point *entities.KnowledgePoint, revision *entities.KnowledgePointRevision) error
+
+	// UpdatePoint 在事务中校验版本号，保存知识点内容并记录修订，版本号或编辑状态已变化时返回false
+	// 知识点的当前版本没有修订记录时，先补录修改前的内容
+	UpdatePoint(ctx context.Context, point *entities.KnowledgePoint, expectedVersion int, revision *entities.KnowledgePointRevision) (bool, error)
+
+	// List 获取知识点的修订列表，按版本号倒序
+	List(ctx context.Context, pointID uuid.UUID, offset, limit int) ([]*entities.KnowledgePointRevision, int64, error)
+
+	// GetByVersion 获取知识点指定版本的修订，不存在时返回nil
+	GetByVersion(ctx context.Context, pointID uuid.UUID, version int) (*entities.KnowledgePointRevision, error)
+}
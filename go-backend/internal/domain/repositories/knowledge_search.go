@@ -0,0 +1,53 @@
+package repositories
+
+import (
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+)
+
+// KnowledgeSearchQuery 知识点全文检索条件
+type KnowledgeSearchQuery struct {
+	Keyword    string
+	Category   string // 为空时不限
+	Difficulty string // 为空时不限
+	Offset     int
+	Limit      int
+}
+
+// KnowledgeSearchHit 知识点检索结果，Rank为相关度得分
+type KnowledgeSearchHit struct {
+	Point *entities.KnowledgePoint
+	Rank  float64
+}
+
+// KnowledgeSearchFacet 分面统计项
+type KnowledgeSearchFacet struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+// KnowledgeSearchResult 知识点检索结果页
+// 类别分面不受类别条件限制，难度分面不受难度条件限制，便于切换筛选条件
+type KnowledgeSearchResult struct {
+	Hits         []*KnowledgeSearchHit
+	Total        int64
+	Categories   []KnowledgeSearchFacet
+	Difficulties []KnowledgeSearchFacet
+}
+
+// KnowledgeSuggestQuery 知识点检索建议条件
+type KnowledgeSuggestQuery struct {
+	Text   string // 小写的输入文本
+	Pinyin string // 输入文本的连续全拼，用于拼音和同音字匹配
+	Limit  int
+}
+
+// KnowledgeSuggestion 知识点检索建议，Similarity为标题或拼音的三元组相似度(0-1)
+type KnowledgeSuggestion struct {
+	ID          uuid.UUID
+	Title       string
+	Category    string
+	Difficulty  string
+	Similarity  float64
+	PrefixMatch bool // 标题、全拼或拼音首字母以输入开头
+}
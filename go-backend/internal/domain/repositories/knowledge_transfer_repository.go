@@ -0,0 +1,41 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+)
+
+// KnowledgeImportChange 批量导入时单个知识点的变更
+// ExpectedVersion为0表示新建；Revision为空表示内容没有变化，只更新外部标识
+type KnowledgeImportChange struct {
+	Point           *entities.KnowledgePoint
+	ExpectedVersion int
+	Revision        *entities.KnowledgePointRevision
+}
+
+// KnowledgeExportFilter 知识点导出的筛选条件，为零值时不限
+type KnowledgeExportFilter struct {
+	Category string
+	Status   string
+}
+
+// KnowledgeTransferRepository 知识点批量导入导出仓储接口
+type KnowledgeTransferRepository interface {
+	// FindByExternalKeys 按外部标识获取知识点
+	FindByExternalKeys(ctx context.Context, keys []string) ([]*entities.KnowledgePoint, error)
+
+	// FindByIDs 按ID获取知识点
+	FindByIDs(ctx context.Context, ids []uuid.UUID) ([]*entities.KnowledgePoint, error)
+
+	// FindByTitles 按标题获取知识点，用于解析以标题引用的前置知识点
+	FindByTitles(ctx context.Context, titles []string) ([]*entities.KnowledgePoint, error)
+
+	// ListForExport 获取满足筛选条件的全部知识点，按创建时间正序
+	ListForExport(ctx context.Context, filter KnowledgeExportFilter) ([]*entities.KnowledgePoint, error)
+
+	// ApplyImport 在一个事务中保存全部变更并记录修订
+	// 任一知识点的版本号已变化或不再是草稿时整体回滚并返回false
+	ApplyImport(ctx context.Context, changes []*KnowledgeImportChange) (bool, error)
+}
@@ -0,0 +1,22 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+)
+
+// KnowledgeWorkflowRepository 知识点编辑流程仓储接口
+type KnowledgeWorkflowRepository interface {
+	// Transition 在事务中更新知识点的编辑状态和发布信息并记录流程事件
+	// 知识点当前状态不是fromStatus时不做修改并返回false
+	Transition(ctx context.Context, point *entities.KnowledgePoint, fromStatus string, event *entities.KnowledgeReviewEvent) (bool, error)
+
+	// ListEvents 获取知识点的流程记录，按时间正序
+	ListEvents(ctx context.Context, pointID uuid.UUID) ([]*entities.KnowledgeReviewEvent, error)
+
+	// ListDue 获取定时发布时间已到的已审核知识点
+	ListDue(ctx context.Context, now time.Time, limit int) ([]*entities.KnowledgePoint, error)
+}
@@ -16,7 +16,7 @@ type LearningGoalRepository interface {
 	GetByID(ctx context.Context, id uuid.UUID) (*entities.LearningGoal, error)
 
 	// GetByUserID 根据用户ID获取学习目标列表
-	GetByUserID(ctx context.Context, userID uuid.UUID) ([]*entities.LearningGoal, error)
+	GetByUserID(ctx context.Context, userID uint) ([]*entities.LearningGoal, error)
 
 	// Update 更新学习目标
 	Update(ctx context.Context, goal *entities.LearningGoal) error
@@ -25,7 +25,7 @@ type LearningGoalRepository interface {
 	Delete(ctx context.Context, id uuid.UUID) error
 
 	// GetByStatus 根据状态获取学习目标
-	GetByStatus(ctx context.Context, userID uuid.UUID, status string) ([]*entities.LearningGoal, error)
+	GetByStatus(ctx context.Context, userID uint, status string) ([]*entities.LearningGoal, error)
 
 	// UpdateProgress 更新学习进度
 	UpdateProgress(ctx context.Context, id uuid.UUID, progress float64) error
@@ -45,11 +45,29 @@ type GoalAnalysisRepository interface {
 	// GetLatestByGoalID 获取目标的最新分析记录
 	GetLatestByGoalID(ctx context.Context, goalID uuid.UUID) (*entities.GoalAnalysis, error)
 
+	// GetLatestByGoalIDAndType 获取目标指定类型的最新分析记录
+	GetLatestByGoalIDAndType(ctx context.Context, goalID uuid.UUID, analysisType string) (*entities.GoalAnalysis, error)
+
+	// GetLatestPerType 获取目标每种分析类型的最新记录
+	GetLatestPerType(ctx context.Context, goalID uuid.UUID) ([]*entities.GoalAnalysis, error)
+
+	// GetByJobID 获取异步任务产生的分析记录
+	GetByJobID(ctx context.Context, jobID uuid.UUID) ([]*entities.GoalAnalysis, error)
+
 	// Update 更新分析记录
 	Update(ctx context.Context, analysis *entities.GoalAnalysis) error
 
+	// UpdateRecommendation 更新分析记录中指定位置的推荐
+	UpdateRecommendation(ctx context.Context, analysisID uuid.UUID, index int, recommendation *entities.Recommendation) error
+
 	// Delete 删除分析记录
 	Delete(ctx context.Context, id uuid.UUID) error
+
+	// FindLatestBySkillGap 查询最新分析中标记了指定技能差距的记录（每个目标仅取最新一条）
+	FindLatestBySkillGap(ctx context.Context, skill string, offset, limit int) ([]*entities.GoalAnalysis, int64, error)
+
+	// FindByConfidenceBelow 查询置信度低于阈值的分析记录，latestOnly 为 true 时每个目标仅取最新一条
+	FindByConfidenceBelow(ctx context.Context, threshold float64, latestOnly bool, offset, limit int) ([]*entities.GoalAnalysis, int64, error)
 }
 
 // LearningPathRepository 学习路径仓储接口
@@ -71,6 +89,12 @@ type LearningPathRepository interface {
 
 	// UpdateStatus 更新学习路径状态
 	UpdateStatus(ctx context.Context, id uuid.UUID, status string) error
+
+	// UpdateProgressFields 更新学习路径的状态及开始/完成时间
+	UpdateProgressFields(ctx context.Context, path *entities.LearningPath) error
+
+	// AddTimeSpent 累加学习路径的学习时间(分钟)
+	AddTimeSpent(ctx context.Context, id uuid.UUID, minutes int) error
 }
 
 // KnowledgePointRepository 知识点仓储接口
@@ -78,14 +102,20 @@ type KnowledgePointRepository interface {
 	// Create 创建知识点
 	Create(ctx context.Context, point *entities.KnowledgePoint) error
 
-	// GetByID 根据ID获取知识点
+	// GetByID 根据ID获取知识点，不限编辑状态
 	GetByID(ctx context.Context, id uuid.UUID) (*entities.KnowledgePoint, error)
 
-	// GetByCategory 根据类别获取知识点
+	// GetByCategory 根据类别获取已发布的知识点，供学习者和路径生成使用
 	GetByCategory(ctx context.Context, category string) ([]*entities.KnowledgePoint, error)
 
-	// Search 搜索知识点
-	Search(ctx context.Context, keyword string) ([]*entities.KnowledgePoint, error)
+	// List 按筛选条件获取知识点列表，按排序字段和ID进行游标分页
+	List(ctx context.Context, query KnowledgeListQuery) (*KnowledgeListResult, error)
+
+	// Search 全文检索已发布的知识点，按相关度排序并返回分面统计
+	Search(ctx context.Context, query KnowledgeSearchQuery) (*KnowledgeSearchResult, error)
+
+	// Suggest 按标题前缀、拼音和三元组相似度获取已发布知识点的检索建议，前缀匹配优先
+	Suggest(ctx context.Context, query KnowledgeSuggestQuery) ([]*KnowledgeSuggestion, error)
 
 	// Update 更新知识点
 	Update(ctx context.Context, point *entities.KnowledgePoint) error
@@ -93,6 +123,6 @@ type KnowledgePointRepository interface {
 	// Delete 删除知识点
 	Delete(ctx context.Context, id uuid.UUID) error
 
-	// GetByDifficulty 根据难度获取知识点
+	// GetByDifficulty 根据难度获取已发布的知识点，供学习者和路径生成使用
 	GetByDifficulty(ctx context.Context, difficulty string) ([]*entities.KnowledgePoint, error)
 }
\ No newline at end of file
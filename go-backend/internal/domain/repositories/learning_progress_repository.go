@@ -0,0 +1,42 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+)
+
+// StepTimeLogRepository 步骤学习时间记录仓储接口
+type StepTimeLogRepository interface {
+	// Create 创建学习时间记录
+	Create(ctx context.Context, log *entities.StepTimeLog) error
+
+	// GetByPathID 根据路径ID获取学习时间记录
+	GetByPathID(ctx context.Context, pathID uuid.UUID) ([]*entities.StepTimeLog, error)
+
+	// GetByGoalID 根据目标ID获取学习时间记录
+	GetByGoalID(ctx context.Context, goalID uuid.UUID) ([]*entities.StepTimeLog, error)
+}
+
+// GoalProgressHistoryRepository 目标进度历史仓储接口
+type GoalProgressHistoryRepository interface {
+	// Create 创建进度快照
+	Create(ctx context.Context, history *entities.GoalProgressHistory) error
+
+	// GetByGoalID 获取目标在时间范围内的进度快照（按时间升序）
+	GetByGoalID(ctx context.Context, goalID uuid.UUID, from, to *time.Time) ([]*entities.GoalProgressHistory, error)
+}
+
+// StatusTransitionRepository 状态转换历史仓储接口
+type StatusTransitionRepository interface {
+	// Create 创建状态转换记录
+	Create(ctx context.Context, transition *entities.StatusTransition) error
+
+	// GetByEntity 获取实体的状态转换历史（按时间升序）
+	GetByEntity(ctx context.Context, entityType string, entityID uuid.UUID) ([]*entities.StatusTransition, error)
+
+	// GetByGoalID 获取目标及其全部步骤的状态转换历史（按时间升序）
+	GetByGoalID(ctx context.Context, goalID uuid.UUID) ([]*entities.StatusTransition, error)
+}
@@ -0,0 +1,54 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+)
+
+// ModerationCaseFilter 审核案件筛选条件
+type ModerationCaseFilter struct {
+	Status      string
+	ContentType string
+	Source      string
+}
+
+// ModerationRuleRepository 内容过滤规则仓储接口
+type ModerationRuleRepository interface {
+	// Create 创建过滤规则
+	Create(ctx context.Context, rule *entities.ModerationRule) error
+
+	// GetByID 根据ID获取过滤规则
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.ModerationRule, error)
+
+	// Update 更新过滤规则
+	Update(ctx context.Context, rule *entities.ModerationRule) error
+
+	// Delete 删除过滤规则
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// List 获取过滤规则，activeOnly为true时只返回启用的规则
+	List(ctx context.Context, activeOnly bool) ([]*entities.ModerationRule, error)
+}
+
+// ModerationCaseRepository 审核案件仓储接口
+type ModerationCaseRepository interface {
+	// Create 创建审核案件
+	Create(ctx context.Context, moderationCase *entities.ModerationCase) error
+
+	// GetByID 根据ID获取审核案件及举报记录
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.ModerationCase, error)
+
+	// GetPending 获取内容的待处理案件，不存在时返回nil
+	GetPending(ctx context.Context, contentType, contentID string) (*entities.ModerationCase, error)
+
+	// AddReport 记录举报并累计案件的举报次数，用户已举报过该内容时返回false
+	AddReport(ctx context.Context, report *entities.ContentReport) (bool, error)
+
+	// List 按条件分页获取审核案件，举报多的优先
+	List(ctx context.Context, filter ModerationCaseFilter, offset, limit int) ([]*entities.ModerationCase, int64, error)
+
+	// Resolve 以条件更新处理待处理案件，案件已被处理时返回false
+	Resolve(ctx context.Context, moderationCase *entities.ModerationCase) (bool, error)
+}
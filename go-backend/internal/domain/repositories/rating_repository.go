@@ -0,0 +1,59 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+)
+
+// ReviewOrder 评价列表排序方式
+type ReviewOrder string
+
+const (
+	ReviewOrderHelpful ReviewOrder = "helpful" // 最有帮助优先
+	ReviewOrderRecent  ReviewOrder = "recent"  // 最近更新优先
+)
+
+// RatingRepository 评分仓储接口
+// 评分的创建、修改和删除与评分汇总在同一事务中增量更新
+type RatingRepository interface {
+	// Create 创建评分并计入汇总
+	Create(ctx context.Context, rating *entities.Rating) error
+
+	// Update 更新评分的星级和评价，按新旧星级的差值调整汇总
+	Update(ctx context.Context, rating *entities.Rating) error
+
+	// Delete 删除评分及其投票，并从汇总中扣除
+	Delete(ctx context.Context, rating *entities.Rating) error
+
+	// GetByID 根据ID获取评分
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.Rating, error)
+
+	// GetByUser 获取用户对对象的评分，不存在时返回nil
+	GetByUser(ctx context.Context, targetType string, targetID uuid.UUID, userID uint) (*entities.Rating, error)
+
+	// ListReviews 分页获取对象下附带文字的评价
+	ListReviews(ctx context.Context, targetType string, targetID uuid.UUID, order ReviewOrder, offset, limit int) ([]*entities.Rating, int64, error)
+
+	// AddHelpfulVote 将评价标记为有帮助，已投票时返回false
+	AddHelpfulVote(ctx context.Context, ratingID uuid.UUID, userID uint) (bool, error)
+
+	// RemoveHelpfulVote 取消有帮助投票，未投票时返回false
+	RemoveHelpfulVote(ctx context.Context, ratingID uuid.UUID, userID uint) (bool, error)
+
+	// VotedBy 返回指定评价中用户已投票的评价ID
+	VotedBy(ctx context.Context, userID uint, ratingIDs []uuid.UUID) (map[uuid.UUID]bool, error)
+
+	// GetSummary 获取对象的评分汇总，尚无评分时返回空汇总
+	GetSummary(ctx context.Context, targetType string, targetID uuid.UUID) (*entities.RatingSummary, error)
+
+	// ListSummaries 批量获取对象的评分汇总，尚无评分的对象不在结果中
+	ListSummaries(ctx context.Context, targetType string, targetIDs []uuid.UUID) (map[uuid.UUID]*entities.RatingSummary, error)
+
+	// GlobalMean 获取某类对象全部评分的平均星级及评分总数
+	GlobalMean(ctx context.Context, targetType string) (float64, int64, error)
+
+	// ListTopRated 按贝叶斯加权评分倒序分页获取评分汇总
+	ListTopRated(ctx context.Context, targetType string, priorMean, priorWeight float64, offset, limit int) ([]*entities.RatingSummary, int64, error)
+}
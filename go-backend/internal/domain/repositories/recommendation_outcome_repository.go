@@ -0,0 +1,24 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+)
+
+// RecommendationOutcomeRepository 推荐处理记录仓储接口
+type RecommendationOutcomeRepository interface {
+	// Create 创建处理记录
+	Create(ctx context.Context, outcome *entities.RecommendationOutcome) error
+
+	// Delete 删除处理记录
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// GetByRecommendationID 获取推荐的处理记录，按时间倒序
+	GetByRecommendationID(ctx context.Context, recommendationID uuid.UUID) ([]*entities.RecommendationOutcome, error)
+
+	// CountByAction 统计since之后按分析器、分析类型、推荐类型和处理动作分组的记录数
+	CountByAction(ctx context.Context, since time.Time) ([]*entities.RecommendationOutcomeStat, error)
+}
@@ -0,0 +1,23 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"sical-go-backend/internal/domain/entities"
+)
+
+// QueryPopularity 检索词及其检索次数
+type QueryPopularity struct {
+	Query string `json:"query"`
+	Count int64  `json:"count"`
+}
+
+// SearchLogRepository 检索日志仓储接口
+type SearchLogRepository interface {
+	// Create 记录一次检索
+	Create(ctx context.Context, log *entities.SearchLog) error
+
+	// PopularQueries 获取since之后以prefix开头且有结果的热门检索词，按检索次数倒序
+	PopularQueries(ctx context.Context, prefix string, since time.Time, limit int) ([]QueryPopularity, error)
+}
@@ -0,0 +1,62 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+)
+
+// TaxonomyRepository 类别与技能体系仓储接口
+type TaxonomyRepository interface {
+	// CreateCategory 创建类别
+	CreateCategory(ctx context.Context, category *entities.Category) error
+
+	// GetCategoryByID 根据ID获取类别
+	GetCategoryByID(ctx context.Context, id uuid.UUID) (*entities.Category, error)
+
+	// GetCategoryByName 根据名称获取类别
+	GetCategoryByName(ctx context.Context, name string) (*entities.Category, error)
+
+	// ListCategories 获取类别列表（含子类别和基准时间）
+	ListCategories(ctx context.Context, includeInactive bool) ([]*entities.Category, error)
+
+	// UpdateCategory 更新类别
+	UpdateCategory(ctx context.Context, category *entities.Category) error
+
+	// DeleteCategory 删除类别
+	DeleteCategory(ctx context.Context, id uuid.UUID) error
+
+	// CreateSkill 创建技能
+	CreateSkill(ctx context.Context, skill *entities.Skill) error
+
+	// GetSkillByID 根据ID获取技能
+	GetSkillByID(ctx context.Context, id uuid.UUID) (*entities.Skill, error)
+
+	// GetSkillByName 根据名称获取技能
+	GetSkillByName(ctx context.Context, name string) (*entities.Skill, error)
+
+	// ListSkills 获取技能列表
+	ListSkills(ctx context.Context) ([]*entities.Skill, error)
+
+	// UpdateSkill 更新技能
+	UpdateSkill(ctx context.Context, skill *entities.Skill) error
+
+	// DeleteSkill 删除技能及其类别映射
+	DeleteSkill(ctx context.Context, id uuid.UUID) error
+
+	// CreateCategorySkill 创建类别技能映射
+	CreateCategorySkill(ctx context.Context, mapping *entities.CategorySkill) error
+
+	// GetCategorySkills 获取类别的技能映射（含技能信息）
+	GetCategorySkills(ctx context.Context, categoryID uuid.UUID, kind string) ([]*entities.CategorySkill, error)
+
+	// DeleteCategorySkill 删除类别技能映射
+	DeleteCategorySkill(ctx context.Context, id uuid.UUID) error
+
+	// UpsertCategoryDuration 创建或更新类别难度基准时间
+	UpsertCategoryDuration(ctx context.Context, duration *entities.CategoryDuration) error
+
+	// GetCategoryDurations 获取类别各难度的基准时间
+	GetCategoryDurations(ctx context.Context, categoryID uuid.UUID) ([]*entities.CategoryDuration, error)
+}
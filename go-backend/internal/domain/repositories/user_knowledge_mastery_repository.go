@@ -0,0 +1,24 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+)
+
+// UserKnowledgeMasteryRepository 用户知识点掌握度仓储接口
+type UserKnowledgeMasteryRepository interface {
+	// Get 获取用户对知识点的掌握度，不存在时返回nil
+	Get(ctx context.Context, userID uint, knowledgePointID uuid.UUID) (*entities.UserKnowledgeMastery, error)
+
+	// Save 创建或更新掌握度记录
+	Save(ctx context.Context, mastery *entities.UserKnowledgeMastery) error
+
+	// ListByUser 获取用户全部知识点掌握度，包含知识点信息
+	ListByUser(ctx context.Context, userID uint) ([]*entities.UserKnowledgeMastery, error)
+
+	// ListDue 分页获取before之前到期需要复习的知识点，按到期时间先后排序
+	ListDue(ctx context.Context, userID uint, before time.Time, offset, limit int) ([]*entities.UserKnowledgeMastery, int64, error)
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"math"
+	"strings"
+	"time"
+
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+	apperrors "sical-go-backend/pkg/errors"
+)
+
+// AnalysisInsightService 分析结果洞察服务，供课程团队发现系统性的技能差距
+type AnalysisInsightService struct {
+	analysisRepo repositories.GoalAnalysisRepository
+	outcomeRepo  repositories.RecommendationOutcomeRepository
+}
+
+// NewAnalysisInsightService 创建分析结果洞察服务
+func NewAnalysisInsightService(
+	analysisRepo repositories.GoalAnalysisRepository,
+	outcomeRepo repositories.RecommendationOutcomeRepository,
+) *AnalysisInsightService {
+	return &AnalysisInsightService{
+		analysisRepo: analysisRepo,
+		outcomeRepo:  outcomeRepo,
+	}
+}
+
+// FindGoalsWithSkillGap 查询最新分析中标记了指定技能差距的目标分析
+func (s *AnalysisInsightService) FindGoalsWithSkillGap(ctx context.Context, skill string, offset, limit int) ([]*entities.GoalAnalysis, int64, error) {
+	skill = strings.TrimSpace(skill)
+	if skill == "" {
+		return nil, 0, apperrors.New(apperrors.ErrorTypeValidation, 400, "技能名称不能为空")
+	}
+	return s.analysisRepo.FindLatestBySkillGap(ctx, skill, offset, limit)
+}
+
+// FindLowConfidenceAnalyses 查询置信度低于阈值的分析记录
+func (s *AnalysisInsightService) FindLowConfidenceAnalyses(ctx context.Context, threshold float64, latestOnly bool, offset, limit int) ([]*entities.GoalAnalysis, int64, error) {
+	if threshold <= 0 || threshold > 1 {
+		return nil, 0, apperrors.New(apperrors.ErrorTypeValidation, 400, "置信度阈值必须在(0, 1]范围内")
+	}
+	return s.analysisRepo.FindByConfidenceBelow(ctx, threshold, latestOnly, offset, limit)
+}
+
+// RecommendationQualityStat 推荐质量统计
+type RecommendationQualityStat struct {
+	Analyzer           string  `json:"analyzer"`
+	AnalysisType       string  `json:"analysis_type"`
+	RecommendationType string  `json:"recommendation_type"`
+	Accepted           int64   `json:"accepted"`
+	Dismissed          int64   `json:"dismissed"`
+	Snoozed            int64   `json:"snoozed"`
+	AcceptanceRate     float64 `json:"acceptance_rate"` // 接受数 / (接受数 + 忽略数)
+}
+
+// GetRecommendationQuality 统计since之后各分析器、分析类型和推荐类型的推荐处理结果
+func (s *AnalysisInsightService) GetRecommendationQuality(ctx context.Context, since time.Time) ([]*RecommendationQualityStat, error) {
+	counts, err := s.outcomeRepo.CountByAction(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := []*RecommendationQualityStat{}
+	index := map[string]*RecommendationQualityStat{}
+	for _, count := range counts {
+		key := count.Analyzer + "|" + count.AnalysisType + "|" + count.RecommendationType
+		stat, ok := index[key]
+		if !ok {
+			stat = &RecommendationQualityStat{
+				Analyzer:           count.Analyzer,
+				AnalysisType:       count.AnalysisType,
+				RecommendationType: count.RecommendationType,
+			}
+			index[key] = stat
+			stats = append(stats, stat)
+		}
+
+		switch entities.RecommendationStatus(count.Action) {
+		case entities.RecommendationAccepted:
+			stat.Accepted += count.Count
+		case entities.RecommendationDismissed:
+			stat.Dismissed += count.Count
+		case entities.RecommendationSnoozed:
+			stat.Snoozed += count.Count
+		}
+	}
+
+	for _, stat := range stats {
+		if decided := stat.Accepted + stat.Dismissed; decided > 0 {
+			stat.AcceptanceRate = math.Round(float64(stat.Accepted)/float64(decided)*100) / 100
+		}
+	}
+	return stats, nil
+}
@@ -0,0 +1,250 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+	apperrors "sical-go-backend/pkg/errors"
+	"sical-go-backend/pkg/logger"
+)
+
+// AnalysisJobConfig 分析任务队列配置
+type AnalysisJobConfig struct {
+	Workers      int           // 并发工作协程数
+	PollInterval time.Duration // 无任务时的轮询间隔
+	DedupWindow  time.Duration // 相同请求的去重时间窗口
+	JobTimeout   time.Duration // 单个任务的执行超时
+	MaxAttempts  int           // 任务最大执行次数（执行超时后会被重新领取）
+}
+
+// AnalysisJobService 学习目标异步分析任务服务
+type AnalysisJobService struct {
+	jobRepo         repositories.AnalysisJobRepository
+	goalRepo        repositories.LearningGoalRepository
+	analysisRepo    repositories.GoalAnalysisRepository
+	analysisService *GoalAnalysisService
+	config          AnalysisJobConfig
+
+	wakeup    chan struct{}
+	startOnce sync.Once
+}
+
+// NewAnalysisJobService 创建异步分析任务服务
+func NewAnalysisJobService(
+	jobRepo repositories.AnalysisJobRepository,
+	goalRepo repositories.LearningGoalRepository,
+	analysisRepo repositories.GoalAnalysisRepository,
+	analysisService *GoalAnalysisService,
+	config AnalysisJobConfig,
+) *AnalysisJobService {
+	if config.Workers <= 0 {
+		config.Workers = 2
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = 2 * time.Second
+	}
+	if config.JobTimeout <= 0 {
+		config.JobTimeout = 2 * time.Minute
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = 3
+	}
+
+	return &AnalysisJobService{
+		jobRepo:         jobRepo,
+		goalRepo:        goalRepo,
+		analysisRepo:    analysisRepo,
+		analysisService: analysisService,
+		config:          config,
+		wakeup:          make(chan struct{}, 1),
+	}
+}
+
+// Enqueue 提交目标分析任务，types为空时执行全部单项分析
+// 去重窗口内已有相同请求（目标未修改且分析类型相同）或该请求的任务尚未结束时返回已有任务，deduplicated为true
+func (s *AnalysisJobService) Enqueue(ctx context.Context, goalID uuid.UUID, types []string) (job *entities.AnalysisJob, deduplicated bool, err error) {
+	types, err = NormalizeAnalysisTypes(types)
+	if err != nil {
+		return nil, false, err
+	}
+
+	goal, err := s.goalRepo.GetByID(ctx, goalID)
+	if err != nil {
+		return nil, false, apperrors.New(apperrors.ErrorTypeNotFound, 404, "学习目标不存在").WithCause(err)
+	}
+
+	// 目标内容变化后视为新的请求
+	dedupKey := fmt.Sprintf("%s:%d:%s", goal.ID, goal.UpdatedAt.UnixNano(), strings.Join(types, ","))
+	existing, err := s.jobRepo.FindRecentByDedupKey(ctx, dedupKey, time.Now().Add(-s.config.DedupWindow), s.staleBefore())
+	if err != nil {
+		return nil, false, err
+	}
+	if existing != nil {
+		return existing, true, nil
+	}
+
+	job = &entities.AnalysisJob{
+		GoalID:   goalID,
+		Status:   string(entities.AnalysisJobQueued),
+		Types:    types,
+		DedupKey: dedupKey,
+	}
+	if err := s.jobRepo.Create(ctx, job); err != nil {
+		return nil, false, err
+	}
+
+	// 唤醒空闲的工作协程
+	select {
+	case s.wakeup <- struct{}{}:
+	default:
+	}
+
+	logger.Info("分析任务已入队",
+		logger.String("job_id", job.ID.String()),
+		logger.String("goal_id", goalID.String()),
+		logger.String("types", strings.Join(types, ",")))
+	return job, false, nil
+}
+
+// EnqueueStale 目标的类别、难度或描述变化后，重新执行已过期的单项分析
+// 只重新执行目标已有结果的分析类型，没有需要重新执行的分析时返回nil
+func (s *AnalysisJobService) EnqueueStale(ctx context.Context, previous, current *entities.LearningGoal) (*entities.AnalysisJob, error) {
+	stale := StaleAnalysisTypes(previous, current)
+	if len(stale) == 0 {
+		return nil, nil
+	}
+
+	latest, err := s.analysisRepo.GetLatestPerType(ctx, current.ID)
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]bool, len(latest))
+	for _, analysis := range latest {
+		existing[analysis.AnalysisType] = true
+	}
+
+	types := []string{}
+	for _, analysisType := range stale {
+		// 早期版本的综合分析覆盖全部单项分析
+		if existing[analysisType] || existing[entities.AnalysisTypeComprehensive] {
+			types = append(types, analysisType)
+		}
+	}
+	if len(types) == 0 {
+		return nil, nil
+	}
+
+	job, _, err := s.Enqueue(ctx, current.ID, types)
+	return job, err
+}
+
+// GetJob 获取目标的分析任务
+func (s *AnalysisJobService) GetJob(ctx context.Context, goalID, jobID uuid.UUID) (*entities.AnalysisJob, error) {
+	job, err := s.jobRepo.GetByID(ctx, jobID)
+	if err != nil || job.GoalID != goalID {
+		return nil, apperrors.New(apperrors.ErrorTypeNotFound, 404, "分析任务不存在")
+	}
+	return job, nil
+}
+
+// GetJobAnalyses 获取任务产生的分析结果
+func (s *AnalysisJobService) GetJobAnalyses(ctx context.Context, jobID uuid.UUID) ([]*entities.GoalAnalysis, error) {
+	return s.analysisRepo.GetByJobID(ctx, jobID)
+}
+
+// GetAnalysisHistory 获取目标的历史分析结果，analysisType为空时返回全部类型
+func (s *AnalysisJobService) GetAnalysisHistory(ctx context.Context, goalID uuid.UUID, analysisType string) ([]*entities.GoalAnalysis, error) {
+	analyses, err := s.analysisRepo.GetByGoalID(ctx, goalID)
+	if err != nil || analysisType == "" {
+		return analyses, err
+	}
+
+	filtered := make([]*entities.GoalAnalysis, 0, len(analyses))
+	for _, analysis := range analyses {
+		if analysis.AnalysisType == analysisType {
+			filtered = append(filtered, analysis)
+		}
+	}
+	return filtered, nil
+}
+
+// GetLatestAnalysis 获取目标指定类型的最新分析结果
+func (s *AnalysisJobService) GetLatestAnalysis(ctx context.Context, goalID uuid.UUID, analysisType string) (*entities.GoalAnalysis, error) {
+	if !entities.IsIndividualAnalysisType(analysisType) {
+		return nil, apperrors.New(apperrors.ErrorTypeValidation, 400, fmt.Sprintf("无效的分析类型: %s", analysisType))
+	}
+	analysis, err := s.analysisRepo.GetLatestByGoalIDAndType(ctx, goalID, analysisType)
+	if err != nil {
+		return nil, apperrors.New(apperrors.ErrorTypeNotFound, 404, "暂无分析结果").WithCause(err)
+	}
+	return analysis, nil
+}
+
+// Start 启动工作协程，ctx取消时退出；重复调用无效
+func (s *AnalysisJobService) Start(ctx context.Context) {
+	s.startOnce.Do(func() {
+		for i := 0; i < s.config.Workers; i++ {
+			go s.worker(ctx)
+		}
+		logger.Info("分析任务工作协程已启动", logger.Int("workers", s.config.Workers))
+	})
+}
+
+// worker 循环领取并执行任务
+func (s *AnalysisJobService) worker(ctx context.Context) {
+	for {
+		processed := s.processNext(ctx)
+		if processed {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.wakeup:
+		case <-time.After(s.config.PollInterval):
+		}
+	}
+}
+
+// staleBefore 执行超过两倍超时时间的任务视为工作协程已中断，可被重新领取
+func (s *AnalysisJobService) staleBefore() time.Time {
+	return time.Now().Add(-2 * s.config.JobTimeout)
+}
+
+// processNext 领取并执行一个任务，没有任务时返回false
+func (s *AnalysisJobService) processNext(ctx context.Context) bool {
+	job, err := s.jobRepo.ClaimNext(ctx, s.staleBefore(), s.config.MaxAttempts)
+	if err != nil {
+		logger.Error("领取分析任务失败", logger.String("error", err.Error()))
+		return false
+	}
+	if job == nil {
+		return false
+	}
+
+	jobCtx, cancel := context.WithTimeout(ctx, s.config.JobTimeout)
+	defer cancel()
+
+	_, err = s.analysisService.AnalyzeLearningGoal(jobCtx, job.GoalID, job.Types, &job.ID)
+	if err != nil {
+		logger.Error("分析任务执行失败",
+			logger.String("job_id", job.ID.String()),
+			logger.String("error", err.Error()))
+		if markErr := s.jobRepo.MarkFailed(ctx, job.ID, err.Error()); markErr != nil {
+			logger.Error("更新分析任务状态失败", logger.String("error", markErr.Error()))
+		}
+		return true
+	}
+
+	if err := s.jobRepo.MarkSucceeded(ctx, job.ID); err != nil {
+		logger.Error("更新分析任务状态失败", logger.String("error", err.Error()))
+	}
+	return true
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"context"
+
+	"sical-go-backend/internal/domain/entities"
+)
+
+// 分析器名称
+const (
+	AnalyzerRules = "rules"
+	AnalyzerLLM   = "llm"
+)
+
+// Analyzer 单项分析器，每个实现只负责一种分析类型，单独执行并单独给出置信度
+type Analyzer interface {
+	// Name 分析器名称
+	Name() string
+
+	// Type 负责的分析类型
+	Type() string
+
+	// Analyze 根据目标、用户学习画像和类别知识点生成该类型的分析结果
+	Analyze(ctx context.Context, input *AnalysisInput) (*AnalysisOutput, error)
+}
+
+// Analyzers 按分析类型索引的单项分析器
+type Analyzers map[string]Analyzer
+
+// NewAnalyzers 按各分析器负责的类型建立索引
+func NewAnalyzers(analyzers ...Analyzer) Analyzers {
+	set := make(Analyzers, len(analyzers))
+	for _, analyzer := range analyzers {
+		set[analyzer.Type()] = analyzer
+	}
+	return set
+}
+
+// AnalysisInput 分析器输入
+type AnalysisInput struct {
+	Goal            *entities.LearningGoal
+	Profile         *UserLearningProfile
+	KnowledgePoints []*entities.KnowledgePoint
+}
+
+// Evidence 根据输入数据构建分析证据（必备技能相关字段由分析器填写）
+func (in *AnalysisInput) Evidence() *entities.AnalysisEvidence {
+	return &entities.AnalysisEvidence{
+		KnowledgePoints: len(in.KnowledgePoints),
+		CompletedPaths:  len(in.Profile.CompletedPaths),
+		GoalHistory:     len(in.Profile.Goals),
+		CompletedGoals:  len(in.Profile.CompletedGoals),
+	}
+}
+
+// AnalysisOutput 单项分析器输出，Result只包含该分析类型的字段
+type AnalysisOutput struct {
+	Result          *entities.AnalysisResult
+	Recommendations entities.Recommendations
+	ConfidenceScore float64
+	Analyzer        string // 实际产出结果的分析器（发生降级时为规则分析器）
+}
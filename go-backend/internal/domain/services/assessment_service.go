@@ -0,0 +1,540 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+	apperrors "sical-go-backend/pkg/errors"
+	"sical-go-backend/pkg/logger"
+)
+
+// attemptGracePeriod 作答时限的宽限时间，用于抵消网络延迟
+const attemptGracePeriod = 30 * time.Second
+
+// AssessmentService 测评服务，负责测评的创建发布、作答计时和自动评分
+type AssessmentService struct {
+	assessmentRepo repositories.AssessmentRepository
+	attemptRepo    repositories.AssessmentAttemptRepository
+	knowledgeRepo  repositories.KnowledgePointRepository
+	taxonomy       *TaxonomyService
+	mastery        *MasteryService
+}
+
+// NewAssessmentService 创建测评服务，mastery不为空时评分完成的作答计入用户掌握度
+func NewAssessmentService(
+	assessmentRepo repositories.AssessmentRepository,
+	attemptRepo repositories.AssessmentAttemptRepository,
+	knowledgeRepo repositories.KnowledgePointRepository,
+	taxonomy *TaxonomyService,
+	mastery *MasteryService,
+) *AssessmentService {
+	return &AssessmentService{
+		assessmentRepo: assessmentRepo,
+		attemptRepo:    attemptRepo,
+		knowledgeRepo:  knowledgeRepo,
+		taxonomy:       taxonomy,
+		mastery:        mastery,
+	}
+}
+
+// AnswerInput 单题作答内容
+type AnswerInput struct {
+	QuestionID uuid.UUID `json:"question_id"`
+	Answer     []string  `json:"answer"`
+}
+
+// CreateAssessment 创建测评，校验类别、题目和关联知识点；新建测评为未发布状态
+func (s *AssessmentService) CreateAssessment(ctx context.Context, assessment *entities.Assessment, knowledgePointIDs []uuid.UUID) error {
+	if err := s.taxonomy.ValidateCategory(ctx, assessment.Category); err != nil {
+		return err
+	}
+	for i := range assessment.Questions {
+		if err := validateQuestion(&assessment.Questions[i]); err != nil {
+			return apperrors.New(apperrors.ErrorTypeValidation, 400, fmt.Sprintf("第%d题: %s", i+1, err.Error())).
+				WithDetail("question", fmt.Sprintf("%d", i+1))
+		}
+		if assessment.Questions[i].Order == 0 {
+			assessment.Questions[i].Order = i + 1
+		}
+	}
+
+	points, err := s.resolveKnowledgePoints(ctx, knowledgePointIDs, assessment.Questions)
+	if err != nil {
+		return err
+	}
+	assessment.KnowledgePoints = points
+	assessment.IsPublished = false
+	assessment.PublishedAt = nil
+
+	if err := s.assessmentRepo.Create(ctx, assessment); err != nil {
+		return err
+	}
+
+	logger.Info("测评创建成功",
+		logger.String("assessment_id", assessment.ID.String()),
+		logger.Int("questions", len(assessment.Questions)))
+	return nil
+}
+
+// PublishAssessment 发布测评，发布后学习者才能查看和作答
+func (s *AssessmentService) PublishAssessment(ctx context.Context, id uuid.UUID) (*entities.Assessment, error) {
+	assessment, err := s.getAssessment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if assessment.IsPublished {
+		return assessment, nil
+	}
+	if len(assessment.Questions) == 0 {
+		return nil, apperrors.New(apperrors.ErrorTypeValidation, 400, "测评至少需要一道题目才能发布")
+	}
+
+	now := time.Now()
+	assessment.IsPublished = true
+	assessment.PublishedAt = &now
+	if err := s.assessmentRepo.Update(ctx, assessment); err != nil {
+		return nil, err
+	}
+	return assessment, nil
+}
+
+// GetAssessment 获取测评详情，includeUnpublished为false时未发布的测评视为不存在
+func (s *AssessmentService) GetAssessment(ctx context.Context, id uuid.UUID, includeUnpublished bool) (*entities.Assessment, error) {
+	assessment, err := s.getAssessment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !includeUnpublished && !assessment.IsPublished {
+		return nil, apperrors.New(apperrors.ErrorTypeNotFound, 404, "测评不存在")
+	}
+	return assessment, nil
+}
+
+// ListAssessments 分页获取测评列表
+func (s *AssessmentService) ListAssessments(ctx context.Context, filter repositories.AssessmentFilter, offset, limit int) ([]*entities.Assessment, int64, error) {
+	return s.assessmentRepo.List(ctx, filter, offset, limit)
+}
+
+// StartAttempt 开始作答
+// 已有未超时的进行中作答时直接返回该作答；超时的作答会先按已保存的答案自动提交
+func (s *AssessmentService) StartAttempt(ctx context.Context, assessmentID uuid.UUID, userID uint) (*entities.AssessmentAttempt, error) {
+	assessment, err := s.GetAssessment(ctx, assessmentID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	current, err := s.attemptRepo.GetInProgress(ctx, assessmentID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if current != nil {
+		if !current.IsExpired(now, attemptGracePeriod) {
+			return current, nil
+		}
+		if err := s.finalize(ctx, assessment, current, now); err != nil {
+			return nil, err
+		}
+	}
+
+	count, err := s.attemptRepo.CountByUser(ctx, assessmentID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if assessment.MaxAttempts > 0 && int(count) >= assessment.MaxAttempts {
+		return nil, apperrors.New(apperrors.ErrorTypeConflict, 409, fmt.Sprintf("已达到最大作答次数: %d", assessment.MaxAttempts))
+	}
+
+	attempt := &entities.AssessmentAttempt{
+		AssessmentID:  assessmentID,
+		UserID:        userID,
+		AttemptNumber: int(count) + 1,
+		Status:        string(entities.AttemptInProgress),
+		StartedAt:     now,
+		TotalPoints:   assessment.TotalPoints(),
+	}
+	if assessment.TimeLimit > 0 {
+		expiresAt := now.Add(time.Duration(assessment.TimeLimit) * time.Minute)
+		attempt.ExpiresAt = &expiresAt
+	}
+	// 唯一索引保证并发开始时作答次序不重复
+	if err := s.attemptRepo.Create(ctx, attempt); err != nil {
+		return nil, apperrors.New(apperrors.ErrorTypeConflict, 409, "作答已在进行中，请稍后重试").WithCause(err)
+	}
+
+	logger.Info("开始测评作答",
+		logger.String("assessment_id", assessmentID.String()),
+		logger.String("attempt_id", attempt.ID.String()),
+		logger.Int("attempt_number", attempt.AttemptNumber))
+	return attempt, nil
+}
+
+// SaveAnswers 保存作答中的答案，可多次保存，超时后不再接受
+func (s *AssessmentService) SaveAnswers(ctx context.Context, userID uint, assessmentID, attemptID uuid.UUID, inputs []AnswerInput) (*entities.AssessmentAttempt, error) {
+	attempt, assessment, err := s.loadAttempt(ctx, userID, assessmentID, attemptID)
+	if err != nil {
+		return nil, err
+	}
+	if attempt.Status != string(entities.AttemptInProgress) {
+		return nil, apperrors.New(apperrors.ErrorTypeConflict, 409, "作答已提交")
+	}
+	now := time.Now()
+	if attempt.IsExpired(now, attemptGracePeriod) {
+		if err := s.finalize(ctx, assessment, attempt, now); err != nil {
+			return nil, err
+		}
+		return nil, apperrors.New(apperrors.ErrorTypeConflict, 409, "作答已超时，已按保存的答案自动提交")
+	}
+
+	questions := questionIndex(assessment)
+	answers := make([]*entities.AttemptAnswer, 0, len(inputs))
+	for _, input := range inputs {
+		question, ok := questions[input.QuestionID]
+		if !ok {
+			return nil, apperrors.New(apperrors.ErrorTypeValidation, 400, "题目不属于该测评").
+				WithDetail("question_id", input.QuestionID.String())
+		}
+		answer, err := normalizeAnswer(question, input.Answer)
+		if err != nil {
+			return nil, apperrors.New(apperrors.ErrorTypeValidation, 400, err.Error()).
+				WithDetail("question_id", input.QuestionID.String())
+		}
+		answers = append(answers, &entities.AttemptAnswer{
+			AttemptID:     attempt.ID,
+			QuestionID:    question.ID,
+			Answer:        answer,
+			GradingStatus: string(entities.AnswerUnanswered),
+		})
+	}
+
+	if err := s.attemptRepo.SaveAnswers(ctx, answers); err != nil {
+		return nil, err
+	}
+	return s.attemptRepo.GetByID(ctx, attempt.ID)
+}
+
+// SubmitAttempt 提交作答并自动评分客观题，主观题进入人工评分
+// 超时后提交时只评分超时前保存的答案
+func (s *AssessmentService) SubmitAttempt(ctx context.Context, userID uint, assessmentID, attemptID uuid.UUID) (*entities.AssessmentAttempt, *entities.Assessment, error) {
+	attempt, assessment, err := s.loadAttempt(ctx, userID, assessmentID, attemptID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if attempt.Status != string(entities.AttemptInProgress) {
+		return nil, nil, apperrors.New(apperrors.ErrorTypeConflict, 409, "作答已提交")
+	}
+	if err := s.finalize(ctx, assessment, attempt, time.Now()); err != nil {
+		return nil, nil, err
+	}
+	return attempt, assessment, nil
+}
+
+// GetAttempt 获取作答详情，超时未提交的作答会先自动提交
+func (s *AssessmentService) GetAttempt(ctx context.Context, userID uint, assessmentID, attemptID uuid.UUID) (*entities.AssessmentAttempt, *entities.Assessment, error) {
+	attempt, assessment, err := s.loadAttempt(ctx, userID, assessmentID, attemptID)
+	if err != nil {
+		return nil, nil, err
+	}
+	now := time.Now()
+	if attempt.Status == string(entities.AttemptInProgress) && attempt.IsExpired(now, attemptGracePeriod) {
+		if err := s.finalize(ctx, assessment, attempt, now); err != nil {
+			return nil, nil, err
+		}
+	}
+	return attempt, assessment, nil
+}
+
+// ListAttempts 获取用户在测评上的作答记录
+func (s *AssessmentService) ListAttempts(ctx context.Context, userID uint, assessmentID uuid.UUID) ([]*entities.AssessmentAttempt, error) {
+	return s.attemptRepo.ListByUser(ctx, assessmentID, userID)
+}
+
+// finalize 结束作答：为每道题生成评分结果，汇总成绩，全部评分完成时累计测评统计
+func (s *AssessmentService) finalize(ctx context.Context, assessment *entities.Assessment, attempt *entities.AssessmentAttempt, now time.Time) error {
+	submitted, err := s.attemptRepo.MarkSubmitted(ctx, attempt.ID, now)
+	if err != nil {
+		return err
+	}
+	if !submitted {
+		return apperrors.New(apperrors.ErrorTypeConflict, 409, "作答已提交")
+	}
+
+	saved := make(map[uuid.UUID]entities.AttemptAnswer, len(attempt.Answers))
+	for _, answer := range attempt.Answers {
+		saved[answer.QuestionID] = answer
+	}
+
+	answers := make([]entities.AttemptAnswer, 0, len(assessment.Questions))
+	for i := range assessment.Questions {
+		question := &assessment.Questions[i]
+		answer, ok := saved[question.ID]
+		if !ok {
+			answer = entities.AttemptAnswer{
+				AttemptID:  attempt.ID,
+				QuestionID: question.ID,
+				Answer:     entities.StringList{},
+			}
+		}
+		gradeAnswer(question, &answer)
+		answers = append(answers, answer)
+	}
+
+	attempt.Answers = answers
+	attempt.SubmittedAt = &now
+	attempt.TimedOut = attempt.IsExpired(now, attemptGracePeriod)
+	attempt.Recalculate(assessment.PassingScore, now)
+
+	if err := s.attemptRepo.Update(ctx, attempt); err != nil {
+		return err
+	}
+	if attempt.Status == string(entities.AttemptGraded) {
+		s.recordCompletion(ctx, assessment.ID, attempt.Score)
+		if s.mastery != nil {
+			s.mastery.RecordAttempt(ctx, attempt, assessment)
+		}
+	}
+
+	logger.Info("测评作答已提交",
+		logger.String("attempt_id", attempt.ID.String()),
+		logger.String("status", attempt.Status),
+		logger.Float64("score", attempt.Score),
+		logger.Bool("timed_out", attempt.TimedOut))
+	return nil
+}
+
+// recordCompletion 累计测评完成统计，失败不影响作答结果
+func (s *AssessmentService) recordCompletion(ctx context.Context, assessmentID uuid.UUID, score float64) {
+	if err := s.assessmentRepo.RecordCompletion(ctx, assessmentID, score); err != nil {
+		logger.Error("更新测评统计失败",
+			logger.String("assessment_id", assessmentID.String()),
+			logger.String("error", err.Error()))
+	}
+}
+
+// loadAttempt 加载作答及所属测评，校验作答归属
+func (s *AssessmentService) loadAttempt(ctx context.Context, userID uint, assessmentID, attemptID uuid.UUID) (*entities.AssessmentAttempt, *entities.Assessment, error) {
+	attempt, err := s.attemptRepo.GetByID(ctx, attemptID)
+	if err != nil || attempt.AssessmentID != assessmentID {
+		return nil, nil, apperrors.New(apperrors.ErrorTypeNotFound, 404, "作答记录不存在")
+	}
+	if attempt.UserID != userID {
+		return nil, nil, apperrors.New(apperrors.ErrorTypeForbidden, 403, "无权访问该作答记录")
+	}
+	assessment, err := s.getAssessment(ctx, assessmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return attempt, assessment, nil
+}
+
+// getAssessment 获取测评，不存在时返回404错误
+func (s *AssessmentService) getAssessment(ctx context.Context, id uuid.UUID) (*entities.Assessment, error) {
+	assessment, err := s.assessmentRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, apperrors.New(apperrors.ErrorTypeNotFound, 404, "测评不存在").WithCause(err)
+	}
+	return assessment, nil
+}
+
+// resolveKnowledgePoints 加载测评关联的知识点，题目关联的知识点自动加入
+func (s *AssessmentService) resolveKnowledgePoints(ctx context.Context, ids []uuid.UUID, questions []entities.AssessmentQuestion) ([]entities.KnowledgePoint, error) {
+	seen := make(map[uuid.UUID]bool)
+	var all []uuid.UUID
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			all = append(all, id)
+		}
+	}
+	for _, q := range questions {
+		if q.KnowledgePointID != nil && !seen[*q.KnowledgePointID] {
+			seen[*q.KnowledgePointID] = true
+			all = append(all, *q.KnowledgePointID)
+		}
+	}
+
+	points := make([]entities.KnowledgePoint, 0, len(all))
+	for _, id := range all {
+		point, err := s.knowledgeRepo.GetByID(ctx, id)
+		if err != nil {
+			return nil, apperrors.New(apperrors.ErrorTypeValidation, 400, "知识点不存在").
+				WithDetail("knowledge_point_id", id.String())
+		}
+		points = append(points, *point)
+	}
+	return points, nil
+}
+
+// questionIndex 按ID索引测评题目
+func questionIndex(assessment *entities.Assessment) map[uuid.UUID]*entities.AssessmentQuestion {
+	index := make(map[uuid.UUID]*entities.AssessmentQuestion, len(assessment.Questions))
+	for i := range assessment.Questions {
+		index[assessment.Questions[i].ID] = &assessment.Questions[i]
+	}
+	return index
+}
+
+// validateQuestion 校验题目配置，判断题未给出选项时使用true/false
+func validateQuestion(q *entities.AssessmentQuestion) error {
+	if strings.TrimSpace(q.Content) == "" {
+		return fmt.Errorf("题目内容不能为空")
+	}
+	if q.Points <= 0 {
+		return fmt.Errorf("题目分值必须大于0")
+	}
+
+	switch entities.QuestionType(q.Type) {
+	case entities.QuestionTypeMultipleChoice:
+		if len(q.Options) < 2 {
+			return fmt.Errorf("选择题至少需要两个选项")
+		}
+	case entities.QuestionTypeTrueFalse:
+		if len(q.Options) == 0 {
+			q.Options = entities.StringList{"true", "false"}
+		}
+		if len(q.Options) != 2 {
+			return fmt.Errorf("判断题必须恰好有两个选项")
+		}
+		if len(q.CorrectAnswer) != 1 {
+			return fmt.Errorf("判断题必须有且只有一个正确答案")
+		}
+	case entities.QuestionTypeShortAnswer, entities.QuestionTypeEssay:
+		return validateRubric(q)
+	default:
+		return fmt.Errorf("无效的题目类型: %s", q.Type)
+	}
+
+	if len(q.Rubric) > 0 {
+		return fmt.Errorf("客观题不支持评分细则")
+	}
+	if len(q.CorrectAnswer) == 0 {
+		return fmt.Errorf("客观题必须设置正确答案")
+	}
+	for _, answer := range q.CorrectAnswer {
+		if !containsOption(q.Options, answer) {
+			return fmt.Errorf("正确答案不在选项中: %s", answer)
+		}
+	}
+	return nil
+}
+
+// validateRubric 校验主观题评分细则，各项标准名称唯一且分值合计等于题目分值
+func validateRubric(q *entities.AssessmentQuestion) error {
+	if len(q.Rubric) == 0 {
+		return nil
+	}
+	seen := make(map[string]bool, len(q.Rubric))
+	for i := range q.Rubric {
+		q.Rubric[i].Name = strings.TrimSpace(q.Rubric[i].Name)
+		if q.Rubric[i].Name == "" {
+			return fmt.Errorf("评分标准名称不能为空")
+		}
+		if seen[q.Rubric[i].Name] {
+			return fmt.Errorf("评分标准名称重复: %s", q.Rubric[i].Name)
+		}
+		seen[q.Rubric[i].Name] = true
+		if q.Rubric[i].Points <= 0 {
+			return fmt.Errorf("评分标准分值必须大于0: %s", q.Rubric[i].Name)
+		}
+	}
+	if math.Abs(q.Rubric.TotalPoints()-q.Points) > 0.001 {
+		return fmt.Errorf("评分细则总分(%.2f)必须等于题目分值(%.2f)", q.Rubric.TotalPoints(), q.Points)
+	}
+	return nil
+}
+
+// normalizeAnswer 校验并整理作答内容，客观题答案必须为题目选项
+func normalizeAnswer(q *entities.AssessmentQuestion, answer []string) (entities.StringList, error) {
+	normalized := entities.StringList{}
+	for _, a := range answer {
+		if a = strings.TrimSpace(a); a != "" {
+			normalized = append(normalized, a)
+		}
+	}
+	if !entities.QuestionType(q.Type).IsObjective() {
+		return normalized, nil
+	}
+
+	if q.Type == string(entities.QuestionTypeTrueFalse) && len(normalized) > 1 {
+		return nil, fmt.Errorf("判断题只能选择一个答案")
+	}
+	for _, a := range normalized {
+		if !containsOption(q.Options, a) {
+			return nil, fmt.Errorf("答案不在选项中: %s", a)
+		}
+	}
+	return normalized, nil
+}
+
+// gradeAnswer 评分单题：客观题按选项集合完全匹配自动评分，已作答的主观题等待人工评分，未作答计0分
+func gradeAnswer(q *entities.AssessmentQuestion, answer *entities.AttemptAnswer) {
+	if len(answer.Answer) == 0 {
+		correct := false
+		earned := 0.0
+		answer.IsCorrect = &correct
+		answer.EarnedPoints = &earned
+		answer.GradingStatus = string(entities.AnswerUnanswered)
+		return
+	}
+
+	if !entities.QuestionType(q.Type).IsObjective() {
+		answer.IsCorrect = nil
+		answer.EarnedPoints = nil
+		answer.GradingStatus = string(entities.AnswerNeedsReview)
+		return
+	}
+
+	correct := sameOptions(answer.Answer, q.CorrectAnswer)
+	earned := 0.0
+	if correct {
+		earned = q.Points
+	}
+	answer.IsCorrect = &correct
+	answer.EarnedPoints = &earned
+	answer.GradingStatus = string(entities.AnswerAutoGraded)
+}
+
+// containsOption 判断选项列表中是否包含指定选项，忽略大小写和首尾空白
+func containsOption(options []string, value string) bool {
+	for _, option := range options {
+		if strings.EqualFold(strings.TrimSpace(option), strings.TrimSpace(value)) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameOptions 判断两组选项是否相同，不考虑顺序和大小写
+func sameOptions(a, b []string) bool {
+	normalize := func(values []string) []string {
+		seen := make(map[string]bool, len(values))
+		result := make([]string, 0, len(values))
+		for _, v := range values {
+			v = strings.ToLower(strings.TrimSpace(v))
+			if !seen[v] {
+				seen[v] = true
+				result = append(result, v)
+			}
+		}
+		sort.Strings(result)
+		return result
+	}
+
+	left, right := normalize(a), normalize(b)
+	if len(left) != len(right) {
+		return false
+	}
+	for i := range left {
+		if left[i] != right[i] {
+			return false
+		}
+	}
+	return true
+}
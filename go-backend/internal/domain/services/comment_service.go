@@ -0,0 +1,526 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+	apperrors "sical-go-backend/pkg/errors"
+	"sical-go-backend/pkg/logger"
+)
+
+// maxCommentMentions 单条评论最多解析的@提及数量
+const maxCommentMentions = 10
+
+// mentionPattern 匹配评论中的@用户名
+var mentionPattern = regexp.MustCompile(`@([\p{L}\p{N}_.-]+)`)
+
+// CommentViewer 查看或操作评论的当前用户
+type CommentViewer struct {
+	UserID    uint
+	Moderator bool
+}
+
+// CreateCommentInput 发表评论内容，ParentID不为空时为回复，评论对象取自父评论
+type CreateCommentInput struct {
+	TargetType string
+	TargetID   uuid.UUID
+	ParentID   *uuid.UUID
+	Content    string
+}
+
+// ModerateCommentInput 评论管理操作，字段为空时保持不变
+type ModerateCommentInput struct {
+	Hidden *bool
+	Reason string
+	Pinned *bool
+	Locked *bool
+}
+
+// CommentPage 游标分页的评论列表
+type CommentPage struct {
+	Comments   []*entities.Comment
+	Liked      map[uuid.UUID]bool // 当前用户已点赞的评论
+	NextCursor string             // 为空表示没有更多
+}
+
+// CommentService 评论服务，负责知识点和学习路径的讨论串、回复、点赞、编辑历史和管理操作
+type CommentService struct {
+	commentRepo   repositories.CommentRepository
+	userRepo      repositories.UserRepository
+	knowledgeRepo repositories.KnowledgePointRepository
+	pathRepo      repositories.LearningPathRepository
+	moderation    *ModerationService
+}
+
+// NewCommentService 创建评论服务，moderation不为空时发布和编辑的内容需经过审核
+func NewCommentService(
+	commentRepo repositories.CommentRepository,
+	userRepo repositories.UserRepository,
+	knowledgeRepo repositories.KnowledgePointRepository,
+	pathRepo repositories.LearningPathRepository,
+	moderation *ModerationService,
+) *CommentService {
+	return &CommentService{
+		commentRepo:   commentRepo,
+		userRepo:      userRepo,
+		knowledgeRepo: knowledgeRepo,
+		pathRepo:      pathRepo,
+		moderation:    moderation,
+	}
+}
+
+// Create 发表评论或回复，讨论串锁定后只有审核员可以回复
+func (s *CommentService) Create(ctx context.Context, viewer CommentViewer, input CreateCommentInput) (*entities.Comment, error) {
+	content, err := normalizeCommentContent(input.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	comment := &entities.Comment{
+		UserID:  viewer.UserID,
+		Content: content,
+	}
+
+	if input.ParentID != nil {
+		parent, err := s.loadVisible(ctx, viewer, *input.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		if parent.IsDeleted() {
+			return nil, apperrors.New(apperrors.ErrorTypeValidation, 400, "不能回复已删除的评论")
+		}
+		if err := s.checkThreadOpen(ctx, viewer, parent); err != nil {
+			return nil, err
+		}
+		rootID := parent.ThreadID()
+		comment.TargetType = parent.TargetType
+		comment.TargetID = parent.TargetID
+		comment.ParentID = &parent.ID
+		comment.RootID = &rootID
+	} else {
+		if err := s.validateTarget(ctx, input.TargetType, input.TargetID); err != nil {
+			return nil, err
+		}
+		comment.TargetType = input.TargetType
+		comment.TargetID = input.TargetID
+	}
+
+	verdict, err := s.screen(ctx, viewer.UserID, "", content)
+	if err != nil {
+		return nil, err
+	}
+	if verdict != nil && verdict.Action == entities.ModerationReview {
+		comment.IsHidden = true
+		comment.HiddenReason = pendingReviewReason
+	}
+
+	comment.Mentions = s.resolveMentions(ctx, viewer.UserID, content)
+	if err := s.commentRepo.Create(ctx, comment); err != nil {
+		return nil, err
+	}
+	s.flagForReview(ctx, comment, verdict)
+
+	logger.Info("评论已发表",
+		logger.String("comment_id", comment.ID.String()),
+		logger.String("target_type", comment.TargetType),
+		logger.Int("mentions", len(comment.Mentions)))
+	return s.commentRepo.GetByID(ctx, comment.ID)
+}
+
+// Get 获取单条评论
+func (s *CommentService) Get(ctx context.Context, viewer CommentViewer, id uuid.UUID) (*entities.Comment, bool, error) {
+	comment, err := s.loadVisible(ctx, viewer, id)
+	if err != nil {
+		return nil, false, err
+	}
+	liked, err := s.commentRepo.LikedBy(ctx, viewer.UserID, []uuid.UUID{comment.ID})
+	if err != nil {
+		return nil, false, err
+	}
+	return comment, liked[comment.ID], nil
+}
+
+// ListThreads 游标分页获取对象下的讨论串
+func (s *CommentService) ListThreads(ctx context.Context, viewer CommentViewer, targetType string, targetID uuid.UUID, cursor string, limit int) (*CommentPage, error) {
+	if !entities.CommentTargetType(targetType).IsValid() {
+		return nil, apperrors.New(apperrors.ErrorTypeValidation, 400, "评论对象类型无效")
+	}
+	opts, err := s.listOptions(viewer, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	comments, err := s.commentRepo.ListThreads(ctx, targetType, targetID, opts)
+	if err != nil {
+		return nil, err
+	}
+	return s.page(ctx, viewer, comments, limit)
+}
+
+// ListReplies 游标分页获取讨论串内的回复，回复按时间正序，通过parent_id还原层级
+func (s *CommentService) ListReplies(ctx context.Context, viewer CommentViewer, threadID uuid.UUID, cursor string, limit int) (*CommentPage, error) {
+	root, err := s.loadVisible(ctx, viewer, threadID)
+	if err != nil {
+		return nil, err
+	}
+	if !root.IsRoot() {
+		return nil, apperrors.New(apperrors.ErrorTypeValidation, 400, "只能获取讨论串的回复")
+	}
+	opts, err := s.listOptions(viewer, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	comments, err := s.commentRepo.ListReplies(ctx, root.ID, opts)
+	if err != nil {
+		return nil, err
+	}
+	return s.page(ctx, viewer, comments, limit)
+}
+
+// Update 编辑自己的评论，编辑前的内容记入编辑历史
+func (s *CommentService) Update(ctx context.Context, viewer CommentViewer, id uuid.UUID, content string) (*entities.Comment, error) {
+	content, err := normalizeCommentContent(content)
+	if err != nil {
+		return nil, err
+	}
+	comment, err := s.loadVisible(ctx, viewer, id)
+	if err != nil {
+		return nil, err
+	}
+	if comment.IsDeleted() {
+		return nil, apperrors.New(apperrors.ErrorTypeNotFound, 404, "评论不存在")
+	}
+	if comment.UserID != viewer.UserID {
+		return nil, apperrors.New(apperrors.ErrorTypeForbidden, 403, "只能编辑自己的评论")
+	}
+	if err := s.checkThreadOpen(ctx, viewer, comment); err != nil {
+		return nil, err
+	}
+	if content == comment.Content {
+		return comment, nil
+	}
+	verdict, err := s.screen(ctx, viewer.UserID, comment.ID.String(), content)
+	if err != nil {
+		return nil, err
+	}
+
+	edit := &entities.CommentEdit{
+		CommentID:       comment.ID,
+		PreviousContent: comment.Content,
+		EditedBy:        viewer.UserID,
+	}
+	now := time.Now()
+	comment.Content = content
+	comment.EditedAt = &now
+	comment.Mentions = s.resolveMentions(ctx, viewer.UserID, content)
+	for i := range comment.Mentions {
+		comment.Mentions[i].CommentID = comment.ID
+	}
+	if err := s.commentRepo.UpdateContent(ctx, comment, edit); err != nil {
+		return nil, err
+	}
+	if verdict != nil && verdict.Action == entities.ModerationReview && !comment.IsHidden {
+		comment.IsHidden = true
+		comment.HiddenReason = pendingReviewReason
+		if err := s.commentRepo.UpdateModeration(ctx, comment); err != nil {
+			return nil, err
+		}
+	}
+	s.flagForReview(ctx, comment, verdict)
+	return s.commentRepo.GetByID(ctx, comment.ID)
+}
+
+// ListEdits 获取评论的编辑历史
+func (s *CommentService) ListEdits(ctx context.Context, viewer CommentViewer, id uuid.UUID) ([]*entities.CommentEdit, error) {
+	comment, err := s.loadVisible(ctx, viewer, id)
+	if err != nil {
+		return nil, err
+	}
+	if comment.IsDeleted() && !viewer.Moderator {
+		return nil, apperrors.New(apperrors.ErrorTypeNotFound, 404, "评论不存在")
+	}
+	return s.commentRepo.ListEdits(ctx, comment.ID)
+}
+
+// Delete 删除评论，作者或审核员可以删除；仍有回复的评论在讨论串中显示为已删除
+func (s *CommentService) Delete(ctx context.Context, viewer CommentViewer, id uuid.UUID) error {
+	comment, err := s.loadVisible(ctx, viewer, id)
+	if err != nil {
+		return err
+	}
+	if comment.IsDeleted() {
+		return apperrors.New(apperrors.ErrorTypeNotFound, 404, "评论不存在")
+	}
+	if comment.UserID != viewer.UserID && !viewer.Moderator {
+		return apperrors.New(apperrors.ErrorTypeForbidden, 403, "只能删除自己的评论")
+	}
+	return s.commentRepo.Delete(ctx, comment)
+}
+
+// Like 点赞评论，重复点赞不报错
+func (s *CommentService) Like(ctx context.Context, viewer CommentViewer, id uuid.UUID) (*entities.Comment, error) {
+	comment, err := s.loadVisible(ctx, viewer, id)
+	if err != nil {
+		return nil, err
+	}
+	if comment.IsDeleted() {
+		return nil, apperrors.New(apperrors.ErrorTypeNotFound, 404, "评论不存在")
+	}
+	if added, err := s.commentRepo.AddLike(ctx, comment.ID, viewer.UserID); err != nil {
+		return nil, err
+	} else if added {
+		comment.LikeCount++
+	}
+	return comment, nil
+}
+
+// Unlike 取消点赞，未点赞时不报错
+func (s *CommentService) Unlike(ctx context.Context, viewer CommentViewer, id uuid.UUID) (*entities.Comment, error) {
+	comment, err := s.loadVisible(ctx, viewer, id)
+	if err != nil {
+		return nil, err
+	}
+	if removed, err := s.commentRepo.RemoveLike(ctx, comment.ID, viewer.UserID); err != nil {
+		return nil, err
+	} else if removed && comment.LikeCount > 0 {
+		comment.LikeCount--
+	}
+	return comment, nil
+}
+
+// Moderate 审核员隐藏、置顶或锁定评论，置顶和锁定只能作用于讨论串
+func (s *CommentService) Moderate(ctx context.Context, moderatorID uint, id uuid.UUID, input ModerateCommentInput) (*entities.Comment, error) {
+	comment, err := s.commentRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, apperrors.New(apperrors.ErrorTypeNotFound, 404, "评论不存在").WithCause(err)
+	}
+	if (input.Pinned != nil || input.Locked != nil) && !comment.IsRoot() {
+		return nil, apperrors.New(apperrors.ErrorTypeValidation, 400, "只能置顶或锁定讨论串")
+	}
+
+	if input.Hidden != nil {
+		comment.IsHidden = *input.Hidden
+		if comment.IsHidden {
+			comment.HiddenBy = &moderatorID
+			comment.HiddenReason = strings.TrimSpace(input.Reason)
+		} else {
+			comment.HiddenBy = nil
+			comment.HiddenReason = ""
+		}
+	}
+	if input.Pinned != nil {
+		comment.IsPinned = *input.Pinned
+	}
+	if input.Locked != nil {
+		comment.IsLocked = *input.Locked
+	}
+
+	if err := s.commentRepo.UpdateModeration(ctx, comment); err != nil {
+		return nil, err
+	}
+
+	logger.Info("评论管理状态已更新",
+		logger.String("comment_id", comment.ID.String()),
+		logger.Int("moderator_id", int(moderatorID)),
+		logger.Bool("hidden", comment.IsHidden),
+		logger.Bool("pinned", comment.IsPinned),
+		logger.Bool("locked", comment.IsLocked))
+	return comment, nil
+}
+
+// ListMentions 分页获取提及当前用户的评论
+func (s *CommentService) ListMentions(ctx context.Context, userID uint, offset, limit int) ([]*entities.Comment, int64, error) {
+	return s.commentRepo.ListMentioning(ctx, userID, offset, limit)
+}
+
+// loadVisible 加载当前用户可见的评论，隐藏评论只对审核员和作者可见
+func (s *CommentService) loadVisible(ctx context.Context, viewer CommentViewer, id uuid.UUID) (*entities.Comment, error) {
+	comment, err := s.commentRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, apperrors.New(apperrors.ErrorTypeNotFound, 404, "评论不存在").WithCause(err)
+	}
+	if comment.IsHidden && !viewer.Moderator && comment.UserID != viewer.UserID {
+		return nil, apperrors.New(apperrors.ErrorTypeNotFound, 404, "评论不存在")
+	}
+	if comment.IsDeleted() && comment.ReplyCount == 0 && !viewer.Moderator {
+		return nil, apperrors.New(apperrors.ErrorTypeNotFound, 404, "评论不存在")
+	}
+	return comment, nil
+}
+
+// screen 检查作者是否可以发布并自动审核内容，未启用审核时返回nil
+func (s *CommentService) screen(ctx context.Context, authorID uint, commentID, content string) (*ModerationVerdict, error) {
+	if s.moderation == nil {
+		return nil, nil
+	}
+	if err := s.moderation.CheckAuthor(ctx, authorID); err != nil {
+		return nil, err
+	}
+	return s.moderation.Screen(ctx, entities.ModerationContentComment, commentID, authorID, content)
+}
+
+// flagForReview 将需要人工审核的评论加入审核队列
+func (s *CommentService) flagForReview(ctx context.Context, comment *entities.Comment, verdict *ModerationVerdict) {
+	if s.moderation == nil {
+		return
+	}
+	s.moderation.FlagForReview(ctx, entities.ModerationContentComment, comment.ID.String(), comment.UserID, comment.Content, verdict)
+}
+
+// checkThreadOpen 检查评论所在讨论串是否已锁定，审核员不受限制
+func (s *CommentService) checkThreadOpen(ctx context.Context, viewer CommentViewer, comment *entities.Comment) error {
+	if viewer.Moderator {
+		return nil
+	}
+	root := comment
+	if !comment.IsRoot() {
+		var err error
+		if root, err = s.commentRepo.GetByID(ctx, comment.ThreadID()); err != nil {
+			return err
+		}
+	}
+	if root.IsLocked {
+		return apperrors.New(apperrors.ErrorTypeForbidden, 403, "讨论已锁定")
+	}
+	return nil
+}
+
+// validateTarget 校验评论对象存在
+func (s *CommentService) validateTarget(ctx context.Context, targetType string, targetID uuid.UUID) error {
+	switch entities.CommentTargetType(targetType) {
+	case entities.CommentTargetKnowledgePoint:
+		if _, err := s.knowledgeRepo.GetByID(ctx, targetID); err != nil {
+			return apperrors.New(apperrors.ErrorTypeNotFound, 404, "知识点不存在").WithCause(err)
+		}
+	case entities.CommentTargetLearningPath:
+		if _, err := s.pathRepo.GetByID(ctx, targetID); err != nil {
+			return apperrors.New(apperrors.ErrorTypeNotFound, 404, "学习路径不存在").WithCause(err)
+		}
+	default:
+		return apperrors.New(apperrors.ErrorTypeValidation, 400, "评论对象类型无效").
+			WithDetail("target_type", targetType)
+	}
+	return nil
+}
+
+// resolveMentions 解析评论中@提及的已存在用户，忽略自己和不存在的用户名
+func (s *CommentService) resolveMentions(ctx context.Context, authorID uint, content string) []entities.CommentMention {
+	var mentions []entities.CommentMention
+	seen := make(map[string]bool)
+	for _, match := range mentionPattern.FindAllStringSubmatch(content, -1) {
+		username := strings.TrimRight(match[1], ".-")
+		if username == "" || seen[username] {
+			continue
+		}
+		seen[username] = true
+
+		user, err := s.userRepo.GetByUsername(ctx, username)
+		if err != nil || user == nil || user.ID == authorID {
+			continue
+		}
+		mentions = append(mentions, entities.CommentMention{UserID: user.ID, Username: user.Username})
+		if len(mentions) >= maxCommentMentions {
+			break
+		}
+	}
+	return mentions
+}
+
+// listOptions 构建列表查询选项，多取一条用于判断是否还有下一页
+func (s *CommentService) listOptions(viewer CommentViewer, cursor string, limit int) (repositories.CommentListOptions, error) {
+	opts := repositories.CommentListOptions{
+		IncludeHidden: viewer.Moderator,
+		Limit:         limit + 1,
+	}
+	if cursor != "" {
+		decoded, err := decodeCommentCursor(cursor)
+		if err != nil {
+			return opts, apperrors.New(apperrors.ErrorTypeValidation, 400, "分页游标无效")
+		}
+		opts.Cursor = decoded
+	}
+	return opts, nil
+}
+
+// page 截取当前页并生成下一页游标
+func (s *CommentService) page(ctx context.Context, viewer CommentViewer, comments []*entities.Comment, limit int) (*CommentPage, error) {
+	page := &CommentPage{}
+	if len(comments) > limit {
+		comments = comments[:limit]
+		last := comments[len(comments)-1]
+		page.NextCursor = encodeCommentCursor(&repositories.CommentCursor{
+			Pinned:    last.IsPinned,
+			CreatedAt: last.CreatedAt,
+			ID:        last.ID,
+		})
+	}
+
+	ids := make([]uuid.UUID, 0, len(comments))
+	for _, comment := range comments {
+		ids = append(ids, comment.ID)
+	}
+	liked, err := s.commentRepo.LikedBy(ctx, viewer.UserID, ids)
+	if err != nil {
+		return nil, err
+	}
+	page.Comments = comments
+	page.Liked = liked
+	return page, nil
+}
+
+// normalizeCommentContent 去除首尾空白并校验评论长度
+func normalizeCommentContent(content string) (string, error) {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return "", apperrors.New(apperrors.ErrorTypeValidation, 400, "请输入评论内容")
+	}
+	if utf8.RuneCountInString(content) > entities.MaxCommentLength {
+		return "", apperrors.New(apperrors.ErrorTypeValidation, 400, fmt.Sprintf("评论内容不能超过%d个字符", entities.MaxCommentLength))
+	}
+	return content, nil
+}
+
+// encodeCommentCursor 将分页位置编码为不透明的游标字符串
+func encodeCommentCursor(cursor *repositories.CommentCursor) string {
+	pinned := "0"
+	if cursor.Pinned {
+		pinned = "1"
+	}
+	raw := fmt.Sprintf("%s|%d|%s", pinned, cursor.CreatedAt.UnixNano(), cursor.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeCommentCursor 解析游标字符串
+func decodeCommentCursor(value string) (*repositories.CommentCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	parts := strings.Split(string(raw), "|")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("游标格式无效")
+	}
+	nanos, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	id, err := uuid.Parse(parts[2])
+	if err != nil {
+		return nil, err
+	}
+	return &repositories.CommentCursor{
+		Pinned:    parts[0] == "1",
+		CreatedAt: time.Unix(0, nanos),
+		ID:        id,
+	}, nil
+}
@@ -0,0 +1,435 @@
+package services
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+	apperrors "sical-go-backend/pkg/errors"
+	"sical-go-backend/pkg/logger"
+)
+
+// 自适应诊断参数
+const (
+	defaultDiagnosticItems = 20  // 单次诊断最多题数
+	maxItemsPerPoint       = 4   // 单个知识点最多题数
+	minItemsPerPoint       = 2   // 知识点估计收敛前至少作答的题数
+	targetStdError         = 0.5 // 能力估计标准误低于该值视为收敛
+)
+
+// difficultyLogits 难度等级对应的题目难度参数(logit)
+var difficultyLogits = map[string]float64{
+	"beginner":     -1.0,
+	"intermediate": 0.0,
+	"advanced":     1.0,
+}
+
+// DiagnosticService 自适应诊断服务
+// 从目标类别知识点关联的客观题中逐题选题，按Rasch模型估计各知识点能力，每次选取与当前能力最接近的题目
+type DiagnosticService struct {
+	goalRepo       repositories.LearningGoalRepository
+	knowledgeRepo  repositories.KnowledgePointRepository
+	assessmentRepo repositories.AssessmentRepository
+	sessionRepo    repositories.DiagnosticSessionRepository
+	jobService     *AnalysisJobService
+	mastery        *MasteryService
+}
+
+// NewDiagnosticService 创建自适应诊断服务
+// jobService不为空时诊断完成后自动重新分析目标，mastery不为空时将诊断结果计入用户掌握度
+func NewDiagnosticService(
+	goalRepo repositories.LearningGoalRepository,
+	knowledgeRepo repositories.KnowledgePointRepository,
+	assessmentRepo repositories.AssessmentRepository,
+	sessionRepo repositories.DiagnosticSessionRepository,
+	jobService *AnalysisJobService,
+	mastery *MasteryService,
+) *DiagnosticService {
+	return &DiagnosticService{
+		goalRepo:       goalRepo,
+		knowledgeRepo:  knowledgeRepo,
+		assessmentRepo: assessmentRepo,
+		sessionRepo:    sessionRepo,
+		jobService:     jobService,
+		mastery:        mastery,
+	}
+}
+
+// DiagnosticView 诊断测评当前状态及下一道题目
+type DiagnosticView struct {
+	Session      *entities.DiagnosticSession
+	NextQuestion *entities.AssessmentQuestion // 诊断完成后为空
+}
+
+// DiagnosticAnswerResult 诊断作答结果
+type DiagnosticAnswerResult struct {
+	DiagnosticView
+	Correct       bool
+	CorrectAnswer []string
+	Explanation   string
+	AnalysisJob   *entities.AnalysisJob // 诊断完成后触发的分析任务
+}
+
+// diagnosticPool 诊断题库：目标类别的知识点及其关联的客观题
+type diagnosticPool struct {
+	points    map[uuid.UUID]*entities.KnowledgePoint
+	questions []*entities.AssessmentQuestion
+}
+
+// Start 为目标开始诊断测评，已有进行中的诊断时继续该诊断
+func (s *DiagnosticService) Start(ctx context.Context, userID uint, goalID uuid.UUID) (*DiagnosticView, error) {
+	goal, err := s.loadGoal(ctx, userID, goalID)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := s.sessionRepo.GetInProgress(ctx, goalID)
+	if err != nil {
+		return nil, err
+	}
+	if current != nil {
+		return s.view(ctx, current)
+	}
+
+	pool, err := s.loadPool(ctx, goal.Category)
+	if err != nil {
+		return nil, err
+	}
+	if len(pool.questions) == 0 {
+		return nil, apperrors.New(apperrors.ErrorTypeConflict, 409, "该类别暂无可用于诊断的题目").
+			WithDetail("category", goal.Category)
+	}
+
+	session := &entities.DiagnosticSession{
+		GoalID:           goalID,
+		UserID:           userID,
+		Status:           string(entities.DiagnosticInProgress),
+		TargetDifficulty: goal.Difficulty,
+		MaxItems:         defaultDiagnosticItems,
+	}
+	if len(pool.questions) < session.MaxItems {
+		session.MaxItems = len(pool.questions)
+	}
+	for _, q := range pool.questions {
+		if _, ok := session.Estimates.Find(*q.KnowledgePointID); ok {
+			continue
+		}
+		point := pool.points[*q.KnowledgePointID]
+		session.Estimates = append(session.Estimates, entities.KnowledgeEstimate{
+			KnowledgePointID: point.ID,
+			Title:            point.Title,
+			StdError:         1, // 先验分布N(0,1)
+			Mastery:          masteryAt(0, goal.Difficulty),
+		})
+	}
+
+	next := selectNextQuestion(pool, session.Estimates, map[uuid.UUID]bool{})
+	session.CurrentQuestionID = &next.ID
+	if err := s.sessionRepo.Create(ctx, session); err != nil {
+		return nil, err
+	}
+
+	logger.Info("开始自适应诊断",
+		logger.String("goal_id", goalID.String()),
+		logger.String("session_id", session.ID.String()),
+		logger.Int("knowledge_points", len(session.Estimates)),
+		logger.Int("max_items", session.MaxItems))
+	return &DiagnosticView{Session: session, NextQuestion: next}, nil
+}
+
+// Get 获取诊断测评状态
+func (s *DiagnosticService) Get(ctx context.Context, userID uint, goalID, sessionID uuid.UUID) (*DiagnosticView, error) {
+	session, err := s.loadSession(ctx, userID, goalID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	return s.view(ctx, session)
+}
+
+// Answer 回答当前题目，更新该知识点的能力估计并选出下一道题；达到题数上限或题目用尽时诊断完成
+func (s *DiagnosticService) Answer(ctx context.Context, userID uint, goalID, sessionID, questionID uuid.UUID, answer []string) (*DiagnosticAnswerResult, error) {
+	session, err := s.loadSession(ctx, userID, goalID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status != string(entities.DiagnosticInProgress) {
+		return nil, apperrors.New(apperrors.ErrorTypeConflict, 409, "诊断测评已完成")
+	}
+	if session.CurrentQuestionID == nil || *session.CurrentQuestionID != questionID {
+		return nil, apperrors.New(apperrors.ErrorTypeConflict, 409, "只能回答当前题目")
+	}
+
+	goal, err := s.goalRepo.GetByID(ctx, goalID)
+	if err != nil {
+		return nil, apperrors.New(apperrors.ErrorTypeNotFound, 404, "学习目标不存在").WithCause(err)
+	}
+	pool, err := s.loadPool(ctx, goal.Category)
+	if err != nil {
+		return nil, err
+	}
+	question, err := s.assessmentRepo.GetQuestionByID(ctx, questionID)
+	if err != nil || question.KnowledgePointID == nil {
+		return nil, apperrors.New(apperrors.ErrorTypeNotFound, 404, "题目不存在")
+	}
+	normalized, err := normalizeAnswer(question, answer)
+	if err != nil {
+		return nil, apperrors.New(apperrors.ErrorTypeValidation, 400, err.Error())
+	}
+
+	response := &entities.DiagnosticResponse{
+		SessionID:        session.ID,
+		QuestionID:       question.ID,
+		KnowledgePointID: *question.KnowledgePointID,
+		ItemDifficulty:   itemDifficulty(question, pool.points[*question.KnowledgePointID]),
+		Answer:           normalized,
+		Correct:          len(normalized) > 0 && sameOptions(normalized, question.CorrectAnswer),
+		Sequence:         len(session.Responses) + 1,
+	}
+	session.Responses = append(session.Responses, *response)
+	updateEstimate(session, response.KnowledgePointID)
+
+	next := nextDiagnosticQuestion(pool, session)
+	if next != nil {
+		session.CurrentQuestionID = &next.ID
+	} else {
+		now := time.Now()
+		session.CurrentQuestionID = nil
+		session.Status = string(entities.DiagnosticCompleted)
+		session.CompletedAt = &now
+	}
+
+	applied, err := s.sessionRepo.AddResponse(ctx, session, response, questionID)
+	if err != nil {
+		return nil, err
+	}
+	if !applied {
+		return nil, apperrors.New(apperrors.ErrorTypeConflict, 409, "该题目已作答")
+	}
+
+	result := &DiagnosticAnswerResult{
+		DiagnosticView: DiagnosticView{Session: session, NextQuestion: next},
+		Correct:        response.Correct,
+		CorrectAnswer:  question.CorrectAnswer,
+		Explanation:    question.Explanation,
+	}
+	if session.Status == string(entities.DiagnosticCompleted) {
+		if s.mastery != nil {
+			s.mastery.RecordDiagnostic(ctx, session)
+		}
+		result.AnalysisJob = s.reanalyze(ctx, session)
+	}
+	return result, nil
+}
+
+// reanalyze 诊断完成后重新执行依赖掌握度的分析，失败不影响诊断结果
+func (s *DiagnosticService) reanalyze(ctx context.Context, session *entities.DiagnosticSession) *entities.AnalysisJob {
+	logger.Info("自适应诊断完成",
+		logger.String("session_id", session.ID.String()),
+		logger.Int("answered", len(session.Responses)))
+	if s.jobService == nil {
+		return nil
+	}
+	job, _, err := s.jobService.Enqueue(ctx, session.GoalID, []string{entities.AnalysisTypeSkillGap, entities.AnalysisTypePrerequisite})
+	if err != nil {
+		logger.Error("诊断完成后提交分析任务失败",
+			logger.String("goal_id", session.GoalID.String()),
+			logger.String("error", err.Error()))
+		return nil
+	}
+	return job
+}
+
+// view 构建诊断测评状态，进行中的诊断附带当前题目
+func (s *DiagnosticService) view(ctx context.Context, session *entities.DiagnosticSession) (*DiagnosticView, error) {
+	view := &DiagnosticView{Session: session}
+	if session.Status == string(entities.DiagnosticInProgress) && session.CurrentQuestionID != nil {
+		question, err := s.assessmentRepo.GetQuestionByID(ctx, *session.CurrentQuestionID)
+		if err != nil {
+			return nil, err
+		}
+		view.NextQuestion = question
+	}
+	return view, nil
+}
+
+// loadGoal 加载学习目标并校验归属
+func (s *DiagnosticService) loadGoal(ctx context.Context, userID uint, goalID uuid.UUID) (*entities.LearningGoal, error) {
+	goal, err := s.goalRepo.GetByID(ctx, goalID)
+	if err != nil {
+		return nil, apperrors.New(apperrors.ErrorTypeNotFound, 404, "学习目标不存在").WithCause(err)
+	}
+	if goal.UserID != userID {
+		return nil, apperrors.New(apperrors.ErrorTypeForbidden, 403, "无权访问该学习目标")
+	}
+	return goal, nil
+}
+
+// loadSession 加载诊断测评并校验归属
+func (s *DiagnosticService) loadSession(ctx context.Context, userID uint, goalID, sessionID uuid.UUID) (*entities.DiagnosticSession, error) {
+	session, err := s.sessionRepo.GetByID(ctx, sessionID)
+	if err != nil || session.GoalID != goalID {
+		return nil, apperrors.New(apperrors.ErrorTypeNotFound, 404, "诊断测评不存在")
+	}
+	if session.UserID != userID {
+		return nil, apperrors.New(apperrors.ErrorTypeForbidden, 403, "无权访问该诊断测评")
+	}
+	return session, nil
+}
+
+// loadPool 加载类别知识点及其关联的已发布客观题
+func (s *DiagnosticService) loadPool(ctx context.Context, category string) (*diagnosticPool, error) {
+	points, err := s.knowledgeRepo.GetByCategory(ctx, category)
+	if err != nil {
+		return nil, err
+	}
+	pool := &diagnosticPool{points: make(map[uuid.UUID]*entities.KnowledgePoint, len(points))}
+	ids := make([]uuid.UUID, 0, len(points))
+	for _, point := range points {
+		pool.points[point.ID] = point
+		ids = append(ids, point.ID)
+	}
+
+	pool.questions, err = s.assessmentRepo.ListQuestionsByKnowledgePoints(ctx, ids, []string{
+		string(entities.QuestionTypeMultipleChoice),
+		string(entities.QuestionTypeTrueFalse),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pool, nil
+}
+
+// nextDiagnosticQuestion 选取诊断的下一道题，达到题数上限、各知识点均已收敛或题目用尽时返回nil
+func nextDiagnosticQuestion(pool *diagnosticPool, session *entities.DiagnosticSession) *entities.AssessmentQuestion {
+	if len(session.Responses) >= session.MaxItems {
+		return nil
+	}
+	answered := make(map[uuid.UUID]bool, len(session.Responses))
+	for _, r := range session.Responses {
+		answered[r.QuestionID] = true
+	}
+	return selectNextQuestion(pool, session.Estimates, answered)
+}
+
+// selectNextQuestion 选取下一道题
+// 优先选择估计最不确定的知识点，再从中选取难度最接近当前能力估计的题目（Rasch模型下信息量最大）
+func selectNextQuestion(pool *diagnosticPool, estimates entities.KnowledgeEstimates, answered map[uuid.UUID]bool) *entities.AssessmentQuestion {
+	candidates := make(map[uuid.UUID][]*entities.AssessmentQuestion)
+	for _, q := range pool.questions {
+		if !answered[q.ID] {
+			candidates[*q.KnowledgePointID] = append(candidates[*q.KnowledgePointID], q)
+		}
+	}
+
+	var target *entities.KnowledgeEstimate
+	for i := range estimates {
+		e := &estimates[i]
+		if len(candidates[e.KnowledgePointID]) == 0 || e.Answered >= maxItemsPerPoint {
+			continue
+		}
+		if e.Answered >= minItemsPerPoint && e.StdError <= targetStdError {
+			continue
+		}
+		if target == nil || e.StdError > target.StdError ||
+			(e.StdError == target.StdError && e.Answered < target.Answered) {
+			target = e
+		}
+	}
+	if target == nil {
+		return nil
+	}
+
+	questions := candidates[target.KnowledgePointID]
+	rand.Shuffle(len(questions), func(i, j int) { questions[i], questions[j] = questions[j], questions[i] })
+	point := pool.points[target.KnowledgePointID]
+	var best *entities.AssessmentQuestion
+	bestDistance := math.Inf(1)
+	for _, q := range questions {
+		if distance := math.Abs(itemDifficulty(q, point) - target.Ability); distance < bestDistance {
+			best, bestDistance = q, distance
+		}
+	}
+	return best
+}
+
+// updateEstimate 根据知识点的全部作答重新估计能力
+func updateEstimate(session *entities.DiagnosticSession, pointID uuid.UUID) {
+	estimate, ok := session.Estimates.Find(pointID)
+	if !ok {
+		return
+	}
+
+	var difficulties []float64
+	var correct []bool
+	estimate.Correct = 0
+	for _, r := range session.Responses {
+		if r.KnowledgePointID != pointID {
+			continue
+		}
+		difficulties = append(difficulties, r.ItemDifficulty)
+		correct = append(correct, r.Correct)
+		if r.Correct {
+			estimate.Correct++
+		}
+	}
+
+	ability, stdError := estimateAbility(difficulties, correct)
+	estimate.Answered = len(difficulties)
+	estimate.Ability = math.Round(ability*1000) / 1000
+	estimate.StdError = math.Round(stdError*1000) / 1000
+	estimate.Mastery = masteryAt(ability, session.TargetDifficulty)
+}
+
+// estimateAbility 以标准正态先验计算能力的期望后验估计(EAP)及其后验标准差
+// 少量作答时先验可避免全对或全错导致估计发散
+func estimateAbility(difficulties []float64, correct []bool) (float64, float64) {
+	const (
+		gridMin  = -4.0
+		gridMax  = 4.0
+		gridStep = 0.05
+	)
+	var sumWeight, sumTheta, sumTheta2 float64
+	for theta := gridMin; theta <= gridMax+1e-9; theta += gridStep {
+		weight := math.Exp(-theta * theta / 2)
+		for i, b := range difficulties {
+			p := 1 / (1 + math.Exp(-(theta - b)))
+			if correct[i] {
+				weight *= p
+			} else {
+				weight *= 1 - p
+			}
+		}
+		sumWeight += weight
+		sumTheta += weight * theta
+		sumTheta2 += weight * theta * theta
+	}
+	mean := sumTheta / sumWeight
+	variance := sumTheta2/sumWeight - mean*mean
+	if variance < 0 {
+		variance = 0
+	}
+	return mean, math.Sqrt(variance)
+}
+
+// itemDifficulty 题目难度参数，题目未设置难度时使用知识点难度
+func itemDifficulty(q *entities.AssessmentQuestion, point *entities.KnowledgePoint) float64 {
+	if b, ok := difficultyLogits[q.Difficulty]; ok {
+		return b
+	}
+	if point != nil {
+		if b, ok := difficultyLogits[point.Difficulty]; ok {
+			return b
+		}
+	}
+	return 0
+}
+
+// masteryAt 按目标难度换算掌握度：能力估计下答对目标难度题目的概率
+func masteryAt(ability float64, targetDifficulty string) float64 {
+	p := 1 / (1 + math.Exp(-(ability - difficultyLogits[targetDifficulty])))
+	return math.Round(p*100) / 100
+}
@@ -0,0 +1,271 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+)
+
+// newTestDiagnosticPool 每个知识点在三个难度各有perLevel道题
+func newTestDiagnosticPool(points, perLevel int) *diagnosticPool {
+	pool := &diagnosticPool{points: map[uuid.UUID]*entities.KnowledgePoint{}}
+	for i := 0; i < points; i++ {
+		point := &entities.KnowledgePoint{ID: uuid.New(), Title: "知识点", Difficulty: "intermediate"}
+		pool.points[point.ID] = point
+		for _, difficulty := range []string{"beginner", "intermediate", "advanced"} {
+			for j := 0; j < perLevel; j++ {
+				pool.questions = append(pool.questions, &entities.AssessmentQuestion{
+					ID:               uuid.New(),
+					KnowledgePointID: &point.ID,
+					Difficulty:       difficulty,
+				})
+			}
+		}
+	}
+	return pool
+}
+
+// newTestDiagnosticSession 与Start相同的初始估计
+func newTestDiagnosticSession(pool *diagnosticPool, maxItems int) *entities.DiagnosticSession {
+	session := &entities.DiagnosticSession{TargetDifficulty: "intermediate", MaxItems: maxItems}
+	for _, q := range pool.questions {
+		if _, ok := session.Estimates.Find(*q.KnowledgePointID); ok {
+			continue
+		}
+		session.Estimates = append(session.Estimates, entities.KnowledgeEstimate{
+			KnowledgePointID: *q.KnowledgePointID,
+			StdError:         1,
+			Mastery:          masteryAt(0, session.TargetDifficulty),
+		})
+	}
+	return session
+}
+
+// runTestDiagnostic 按answer作答直到诊断结束
+func runTestDiagnostic(pool *diagnosticPool, session *entities.DiagnosticSession, answer func(q *entities.AssessmentQuestion) bool) {
+	for q := nextDiagnosticQuestion(pool, session); q != nil; q = nextDiagnosticQuestion(pool, session) {
+		session.Responses = append(session.Responses, entities.DiagnosticResponse{
+			QuestionID:       q.ID,
+			KnowledgePointID: *q.KnowledgePointID,
+			ItemDifficulty:   itemDifficulty(q, pool.points[*q.KnowledgePointID]),
+			Correct:          answer(q),
+			Sequence:         len(session.Responses) + 1,
+		})
+		updateEstimate(session, *q.KnowledgePointID)
+	}
+}
+
+func TestEstimateAbility(t *testing.T) {
+	prior, priorSE := estimateAbility(nil, nil)
+	if prior > 1e-9 || prior < -1e-9 || priorSE < 0.99 || priorSE > 1.01 {
+		t.Fatalf("prior = %v ± %v, want 0 ± 1", prior, priorSE)
+	}
+
+	tests := []struct {
+		name         string
+		difficulties []float64
+		correct      []bool
+		wantHigher   bool // 能力估计高于先验
+	}{
+		{"one correct", []float64{0}, []bool{true}, true},
+		{"one incorrect", []float64{0}, []bool{false}, false},
+		{"correct on a hard item", []float64{1}, []bool{true}, true},
+		{"incorrect on an easy item", []float64{-1}, []bool{false}, false},
+		{"all correct", []float64{-1, 0, 1, 1}, []bool{true, true, true, true}, true},
+		{"all incorrect", []float64{1, 0, -1, -1}, []bool{false, false, false, false}, false},
+	}
+	for _, tt := range tests {
+		ability, stdError := estimateAbility(tt.difficulties, tt.correct)
+		if (ability > 0) != tt.wantHigher {
+			t.Errorf("%s: ability = %v, want higher = %v", tt.name, ability, tt.wantHigher)
+		}
+		// 先验避免全对或全错时估计发散
+		if ability < -4 || ability > 4 || stdError <= 0 || stdError >= priorSE {
+			t.Errorf("%s: ability = %v ± %v, want bounded with shrinking error", tt.name, ability, stdError)
+		}
+	}
+
+	oneCorrect, oneSE := estimateAbility([]float64{0}, []bool{true})
+	twoCorrect, twoSE := estimateAbility([]float64{0, 0}, []bool{true, true})
+	if twoCorrect <= oneCorrect || twoSE >= oneSE {
+		t.Errorf("two correct = %v ± %v, one correct = %v ± %v, want higher and more certain", twoCorrect, twoSE, oneCorrect, oneSE)
+	}
+	easy, _ := estimateAbility([]float64{-1}, []bool{true})
+	hard, _ := estimateAbility([]float64{1}, []bool{true})
+	if hard <= easy {
+		t.Errorf("correct on hard = %v, on easy = %v, want hard higher", hard, easy)
+	}
+}
+
+func TestMasteryAt(t *testing.T) {
+	tests := []struct {
+		ability    float64
+		difficulty string
+		want       float64
+	}{
+		{0, "intermediate", 0.5},
+		{1, "advanced", 0.5},
+		{-1, "beginner", 0.5},
+		{0, "beginner", 0.73},
+		{0, "advanced", 0.27},
+		{2, "intermediate", 0.88},
+		{-2, "intermediate", 0.12},
+		{0, "unknown", 0.5}, // 未知难度按中等计算
+	}
+	for _, tt := range tests {
+		if got := masteryAt(tt.ability, tt.difficulty); got != tt.want {
+			t.Errorf("masteryAt(%v, %s) = %v, want %v", tt.ability, tt.difficulty, got, tt.want)
+		}
+	}
+}
+
+func TestSelectNextQuestionTracksAbility(t *testing.T) {
+	tests := []struct {
+		ability float64
+		want    string
+	}{
+		{-1.2, "beginner"},
+		{-0.4, "intermediate"},
+		{0, "intermediate"},
+		{0.6, "advanced"},
+		{2, "advanced"},
+	}
+	for _, tt := range tests {
+		pool := newTestDiagnosticPool(1, 2)
+		session := newTestDiagnosticSession(pool, defaultDiagnosticItems)
+		session.Estimates[0].Ability = tt.ability
+		next := selectNextQuestion(pool, session.Estimates, map[uuid.UUID]bool{})
+		if next == nil || next.Difficulty != tt.want {
+			t.Errorf("ability %v: next = %+v, want %s question", tt.ability, next, tt.want)
+		}
+	}
+}
+
+func TestSelectNextQuestionStoppingRules(t *testing.T) {
+	tests := []struct {
+		name      string
+		estimates []entities.KnowledgeEstimate // 依次对应三个知识点
+		want      int                          // 期望选中的知识点序号，-1表示结束
+	}{
+		{
+			name: "most uncertain point first",
+			estimates: []entities.KnowledgeEstimate{
+				{Answered: 1, StdError: 0.8},
+				{Answered: 1, StdError: 0.9},
+				{Answered: 1, StdError: 0.7},
+			},
+			want: 1,
+		},
+		{
+			name: "fewer answers break ties",
+			estimates: []entities.KnowledgeEstimate{
+				{Answered: 2, StdError: 0.8},
+				{Answered: 1, StdError: 0.8},
+				{Answered: 3, StdError: 0.8},
+			},
+			want: 1,
+		},
+		{
+			name: "converged points are skipped",
+			estimates: []entities.KnowledgeEstimate{
+				{Answered: 2, StdError: 0.5},
+				{Answered: 3, StdError: 0.3},
+				{Answered: 2, StdError: 0.6},
+			},
+			want: 2,
+		},
+		{
+			name: "low error needs the minimum number of items",
+			estimates: []entities.KnowledgeEstimate{
+				{Answered: 2, StdError: 0.4},
+				{Answered: 1, StdError: 0.4},
+				{Answered: 3, StdError: 0.2},
+			},
+			want: 1,
+		},
+		{
+			name: "points at the item limit are skipped",
+			estimates: []entities.KnowledgeEstimate{
+				{Answered: maxItemsPerPoint, StdError: 0.9},
+				{Answered: 2, StdError: 0.6},
+				{Answered: 2, StdError: 0.4},
+			},
+			want: 1,
+		},
+		{
+			name: "all converged or exhausted",
+			estimates: []entities.KnowledgeEstimate{
+				{Answered: maxItemsPerPoint, StdError: 0.9},
+				{Answered: minItemsPerPoint, StdError: targetStdError},
+				{Answered: 3, StdError: 0.1},
+			},
+			want: -1,
+		},
+	}
+	for _, tt := range tests {
+		pool := newTestDiagnosticPool(3, 2)
+		session := newTestDiagnosticSession(pool, defaultDiagnosticItems)
+		for i := range session.Estimates {
+			session.Estimates[i].Answered = tt.estimates[i].Answered
+			session.Estimates[i].StdError = tt.estimates[i].StdError
+		}
+		next := selectNextQuestion(pool, session.Estimates, map[uuid.UUID]bool{})
+		switch {
+		case tt.want < 0 && next != nil:
+			t.Errorf("%s: next = %+v, want nil", tt.name, next)
+		case tt.want >= 0 && (next == nil || *next.KnowledgePointID != session.Estimates[tt.want].KnowledgePointID):
+			t.Errorf("%s: next = %+v, want question of point %d", tt.name, next, tt.want)
+		}
+	}
+
+	// 知识点题目用尽
+	pool := newTestDiagnosticPool(1, 1)
+	session := newTestDiagnosticSession(pool, defaultDiagnosticItems)
+	answered := map[uuid.UUID]bool{}
+	for _, q := range pool.questions {
+		answered[q.ID] = true
+	}
+	if next := selectNextQuestion(pool, session.Estimates, answered); next != nil {
+		t.Errorf("next = %+v, want nil when every question is answered", next)
+	}
+}
+
+func TestDiagnosticSessionStops(t *testing.T) {
+	// 达到题数上限
+	pool := newTestDiagnosticPool(3, 4)
+	session := newTestDiagnosticSession(pool, 5)
+	runTestDiagnostic(pool, session, func(q *entities.AssessmentQuestion) bool { return true })
+	if len(session.Responses) != 5 {
+		t.Fatalf("responses = %d, want MaxItems 5", len(session.Responses))
+	}
+
+	// 未达上限时各知识点作答到收敛或单点上限
+	tests := []struct {
+		name        string
+		answer      func(q *entities.AssessmentQuestion) bool
+		wantHigher  bool
+		wantMastery func(float64) bool
+	}{
+		{"strong", func(q *entities.AssessmentQuestion) bool { return true }, true, func(m float64) bool { return m > 0.5 }},
+		{"weak", func(q *entities.AssessmentQuestion) bool { return false }, false, func(m float64) bool { return m < 0.5 }},
+		{"beginner only", func(q *entities.AssessmentQuestion) bool { return q.Difficulty == "beginner" }, false, func(m float64) bool { return m < 0.5 }},
+	}
+	for _, tt := range tests {
+		pool := newTestDiagnosticPool(3, 4)
+		session := newTestDiagnosticSession(pool, defaultDiagnosticItems)
+		runTestDiagnostic(pool, session, tt.answer)
+		if len(session.Responses) >= session.MaxItems {
+			t.Fatalf("%s: responses = %d, want to stop before MaxItems", tt.name, len(session.Responses))
+		}
+		for _, e := range session.Estimates {
+			converged := e.Answered >= minItemsPerPoint && e.StdError <= targetStdError
+			if !converged && e.Answered != maxItemsPerPoint {
+				t.Errorf("%s: estimate = %+v, stopped before converging", tt.name, e)
+			}
+			if (e.Ability > 0) != tt.wantHigher || !tt.wantMastery(e.Mastery) {
+				t.Errorf("%s: estimate = %+v, unexpected ability or mastery", tt.name, e)
+			}
+		}
+	}
+}
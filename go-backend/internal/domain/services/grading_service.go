@@ -0,0 +1,341 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+	apperrors "sical-go-backend/pkg/errors"
+	"sical-go-backend/pkg/logger"
+)
+
+// scoreUpdateRetries 并发评分导致作答状态变化时重新计算成绩的次数
+const scoreUpdateRetries = 3
+
+// GradingService 人工评分服务，负责主观题评分队列、评分细则打分和复核申请
+type GradingService struct {
+	assessmentRepo repositories.AssessmentRepository
+	attemptRepo    repositories.AssessmentAttemptRepository
+	regradeRepo    repositories.RegradeRequestRepository
+	mastery        *MasteryService
+}
+
+// NewGradingService 创建人工评分服务，mastery不为空时人工评分完成的作答计入用户掌握度
+func NewGradingService(
+	assessmentRepo repositories.AssessmentRepository,
+	attemptRepo repositories.AssessmentAttemptRepository,
+	regradeRepo repositories.RegradeRequestRepository,
+	mastery *MasteryService,
+) *GradingService {
+	return &GradingService{
+		assessmentRepo: assessmentRepo,
+		attemptRepo:    attemptRepo,
+		regradeRepo:    regradeRepo,
+		mastery:        mastery,
+	}
+}
+
+// GradeInput 单题评分内容
+// 题目配置了评分细则时按细则逐项给分，否则直接给出得分，允许部分得分
+type GradeInput struct {
+	Points       *float64              `json:"points"`
+	RubricScores entities.RubricScores `json:"rubric_scores"`
+	Feedback     string                `json:"feedback"`
+}
+
+// Submission 待评分的作答及其所属测评和复核申请
+type Submission struct {
+	Attempt    *entities.AssessmentAttempt `json:"attempt"`
+	Assessment *entities.Assessment        `json:"assessment"`
+	Regrades   []*entities.RegradeRequest  `json:"regrades"`
+}
+
+// ListQueue 分页获取待人工评分的作答，assessmentID不为空时只返回该测评的作答
+func (s *GradingService) ListQueue(ctx context.Context, assessmentID *uuid.UUID, offset, limit int) ([]*entities.AssessmentAttempt, int64, error) {
+	return s.attemptRepo.ListPendingReview(ctx, assessmentID, offset, limit)
+}
+
+// GetSubmission 获取作答详情供评分人查看
+func (s *GradingService) GetSubmission(ctx context.Context, attemptID uuid.UUID) (*Submission, error) {
+	attempt, assessment, err := s.loadSubmission(ctx, attemptID)
+	if err != nil {
+		return nil, err
+	}
+	regrades, err := s.regradeRepo.ListByAttempt(ctx, attemptID)
+	if err != nil {
+		return nil, err
+	}
+	return &Submission{Attempt: attempt, Assessment: assessment, Regrades: regrades}, nil
+}
+
+// GradeAnswer 人工评分待评分的主观题答案，所有答案评分完成后作答成绩和是否通过随之确定
+func (s *GradingService) GradeAnswer(ctx context.Context, graderID uint, attemptID, answerID uuid.UUID, input GradeInput) (*entities.AssessmentAttempt, error) {
+	attempt, assessment, err := s.loadSubmission(ctx, attemptID)
+	if err != nil {
+		return nil, err
+	}
+	answer, question, err := findAnswer(attempt, assessment, answerID)
+	if err != nil {
+		return nil, err
+	}
+	if answer.GradingStatus != string(entities.AnswerNeedsReview) {
+		return nil, apperrors.New(apperrors.ErrorTypeConflict, 409, "该答案无需人工评分，如需修改请通过复核申请处理")
+	}
+
+	if err := applyGrade(answer, question, graderID, input, time.Now()); err != nil {
+		return nil, err
+	}
+	if err := s.attemptRepo.UpdateAnswer(ctx, answer); err != nil {
+		return nil, err
+	}
+
+	logger.Info("答案人工评分完成",
+		logger.String("attempt_id", attemptID.String()),
+		logger.String("answer_id", answerID.String()),
+		logger.Uint("grader_id", graderID))
+	return s.refreshScore(ctx, assessment, attemptID)
+}
+
+// RequestRegrade 学习者对已评分答案申请复核
+func (s *GradingService) RequestRegrade(ctx context.Context, userID uint, assessmentID, attemptID, answerID uuid.UUID, reason string) (*entities.RegradeRequest, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, apperrors.New(apperrors.ErrorTypeValidation, 400, "请填写复核原因")
+	}
+
+	attempt, assessment, err := s.loadSubmission(ctx, attemptID)
+	if err != nil {
+		return nil, err
+	}
+	if attempt.AssessmentID != assessmentID {
+		return nil, apperrors.New(apperrors.ErrorTypeNotFound, 404, "作答记录不存在")
+	}
+	if attempt.UserID != userID {
+		return nil, apperrors.New(apperrors.ErrorTypeForbidden, 403, "无权访问该作答记录")
+	}
+	if attempt.Status != string(entities.AttemptGraded) {
+		return nil, apperrors.New(apperrors.ErrorTypeConflict, 409, "作答评分完成后才能申请复核")
+	}
+
+	answer, _, err := findAnswer(attempt, assessment, answerID)
+	if err != nil {
+		return nil, err
+	}
+	previous := 0.0
+	if answer.EarnedPoints != nil {
+		previous = *answer.EarnedPoints
+	}
+
+	request := &entities.RegradeRequest{
+		AttemptID:      attempt.ID,
+		AnswerID:       answer.ID,
+		QuestionID:     answer.QuestionID,
+		UserID:         userID,
+		Reason:         reason,
+		Status:         string(entities.RegradePending),
+		PreviousPoints: previous,
+	}
+	// 部分唯一索引保证同一答案同时只有一个待处理申请
+	if err := s.regradeRepo.Create(ctx, request); err != nil {
+		return nil, apperrors.New(apperrors.ErrorTypeConflict, 409, "该答案已有待处理的复核申请").WithCause(err)
+	}
+	return request, nil
+}
+
+// ListAttemptRegrades 获取学习者在作答上的复核申请
+func (s *GradingService) ListAttemptRegrades(ctx context.Context, userID uint, assessmentID, attemptID uuid.UUID) ([]*entities.RegradeRequest, error) {
+	attempt, err := s.attemptRepo.GetByID(ctx, attemptID)
+	if err != nil || attempt.AssessmentID != assessmentID {
+		return nil, apperrors.New(apperrors.ErrorTypeNotFound, 404, "作答记录不存在")
+	}
+	if attempt.UserID != userID {
+		return nil, apperrors.New(apperrors.ErrorTypeForbidden, 403, "无权访问该作答记录")
+	}
+	return s.regradeRepo.ListByAttempt(ctx, attemptID)
+}
+
+// ListRegrades 分页获取复核申请
+func (s *GradingService) ListRegrades(ctx context.Context, status string, offset, limit int) ([]*entities.RegradeRequest, int64, error) {
+	return s.regradeRepo.ListByStatus(ctx, status, offset, limit)
+}
+
+// ResolveRegrade 处理复核申请：accept为true时按grade重新评分并更新作答成绩，否则维持原评分
+func (s *GradingService) ResolveRegrade(ctx context.Context, graderID uint, regradeID uuid.UUID, accept bool, grade GradeInput, response string) (*entities.RegradeRequest, error) {
+	request, err := s.regradeRepo.GetByID(ctx, regradeID)
+	if err != nil {
+		return nil, apperrors.New(apperrors.ErrorTypeNotFound, 404, "复核申请不存在").WithCause(err)
+	}
+	if request.Status != string(entities.RegradePending) {
+		return nil, apperrors.New(apperrors.ErrorTypeConflict, 409, "复核申请已处理")
+	}
+	response = strings.TrimSpace(response)
+	if !accept && response == "" {
+		return nil, apperrors.New(apperrors.ErrorTypeValidation, 400, "驳回复核申请时请填写答复")
+	}
+
+	now := time.Now()
+	request.Status = string(entities.RegradeRejected)
+	request.Response = response
+	request.ResolvedBy = &graderID
+	request.ResolvedAt = &now
+
+	var answer *entities.AttemptAnswer
+	var assessment *entities.Assessment
+	if accept {
+		var attempt *entities.AssessmentAttempt
+		var question *entities.AssessmentQuestion
+		attempt, assessment, err = s.loadSubmission(ctx, request.AttemptID)
+		if err != nil {
+			return nil, err
+		}
+		answer, question, err = findAnswer(attempt, assessment, request.AnswerID)
+		if err != nil {
+			return nil, err
+		}
+		if err := applyGrade(answer, question, graderID, grade, now); err != nil {
+			return nil, err
+		}
+		request.Status = string(entities.RegradeAccepted)
+		request.NewPoints = answer.EarnedPoints
+	}
+
+	resolved, err := s.regradeRepo.Resolve(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	if !resolved {
+		return nil, apperrors.New(apperrors.ErrorTypeConflict, 409, "复核申请已处理")
+	}
+
+	if answer != nil {
+		if err := s.attemptRepo.UpdateAnswer(ctx, answer); err != nil {
+			return nil, err
+		}
+		if _, err := s.refreshScore(ctx, assessment, request.AttemptID); err != nil {
+			return nil, err
+		}
+	}
+
+	logger.Info("复核申请已处理",
+		logger.String("regrade_id", request.ID.String()),
+		logger.String("status", request.Status),
+		logger.Uint("grader_id", graderID))
+	return request, nil
+}
+
+// refreshScore 根据最新的答案评分重新汇总作答成绩
+// 作答首次评分完成时累计测评统计，已计入统计的成绩变化时修正平均分
+func (s *GradingService) refreshScore(ctx context.Context, assessment *entities.Assessment, attemptID uuid.UUID) (*entities.AssessmentAttempt, error) {
+	for i := 0; i < scoreUpdateRetries; i++ {
+		attempt, err := s.attemptRepo.GetByID(ctx, attemptID)
+		if err != nil {
+			return nil, err
+		}
+		fromStatus := attempt.Status
+		previousScore := attempt.Score
+
+		attempt.Recalculate(assessment.PassingScore, time.Now())
+		updated, err := s.attemptRepo.UpdateScore(ctx, attempt, fromStatus)
+		if err != nil {
+			return nil, err
+		}
+		if !updated {
+			continue
+		}
+
+		switch {
+		case fromStatus != string(entities.AttemptGraded) && attempt.Status == string(entities.AttemptGraded):
+			if err := s.assessmentRepo.RecordCompletion(ctx, assessment.ID, attempt.Score); err != nil {
+				logger.Error("更新测评统计失败", logger.String("error", err.Error()))
+			}
+			if s.mastery != nil {
+				s.mastery.RecordAttempt(ctx, attempt, assessment)
+			}
+		case fromStatus == string(entities.AttemptGraded) && attempt.Score != previousScore:
+			if err := s.assessmentRepo.AdjustAverageScore(ctx, assessment.ID, attempt.Score-previousScore); err != nil {
+				logger.Error("更新测评统计失败", logger.String("error", err.Error()))
+			}
+		}
+		return attempt, nil
+	}
+	return nil, apperrors.New(apperrors.ErrorTypeConflict, 409, "作答正在被其他评分人更新，请稍后重试")
+}
+
+// loadSubmission 加载已提交的作答及其测评
+func (s *GradingService) loadSubmission(ctx context.Context, attemptID uuid.UUID) (*entities.AssessmentAttempt, *entities.Assessment, error) {
+	attempt, err := s.attemptRepo.GetByID(ctx, attemptID)
+	if err != nil {
+		return nil, nil, apperrors.New(apperrors.ErrorTypeNotFound, 404, "作答记录不存在").WithCause(err)
+	}
+	if attempt.Status == string(entities.AttemptInProgress) {
+		return nil, nil, apperrors.New(apperrors.ErrorTypeConflict, 409, "作答尚未提交")
+	}
+	assessment, err := s.assessmentRepo.GetByID(ctx, attempt.AssessmentID)
+	if err != nil {
+		return nil, nil, apperrors.New(apperrors.ErrorTypeNotFound, 404, "测评不存在").WithCause(err)
+	}
+	return attempt, assessment, nil
+}
+
+// findAnswer 在作答中查找答案及对应题目
+func findAnswer(attempt *entities.AssessmentAttempt, assessment *entities.Assessment, answerID uuid.UUID) (*entities.AttemptAnswer, *entities.AssessmentQuestion, error) {
+	for i := range attempt.Answers {
+		if attempt.Answers[i].ID != answerID {
+			continue
+		}
+		question, ok := questionIndex(assessment)[attempt.Answers[i].QuestionID]
+		if !ok {
+			return nil, nil, apperrors.New(apperrors.ErrorTypeNotFound, 404, "题目不存在")
+		}
+		return &attempt.Answers[i], question, nil
+	}
+	return nil, nil, apperrors.New(apperrors.ErrorTypeNotFound, 404, "答案不存在")
+}
+
+// applyGrade 校验评分内容并写入答案，得分不能超过题目或评分标准的分值
+func applyGrade(answer *entities.AttemptAnswer, question *entities.AssessmentQuestion, graderID uint, input GradeInput, now time.Time) error {
+	earned := 0.0
+	if len(question.Rubric) > 0 {
+		if len(input.RubricScores) != len(question.Rubric) {
+			return apperrors.New(apperrors.ErrorTypeValidation, 400, "请按评分细则逐项给分")
+		}
+		seen := make(map[string]bool, len(input.RubricScores))
+		for _, score := range input.RubricScores {
+			criterion, ok := question.Rubric.Find(score.Criterion)
+			if !ok || seen[score.Criterion] {
+				return apperrors.New(apperrors.ErrorTypeValidation, 400, fmt.Sprintf("无效的评分标准: %s", score.Criterion)).
+					WithDetail("criterion", score.Criterion)
+			}
+			seen[score.Criterion] = true
+			if score.Points < 0 || score.Points > criterion.Points {
+				return apperrors.New(apperrors.ErrorTypeValidation, 400, fmt.Sprintf("评分标准得分必须在0到%.2f之间: %s", criterion.Points, score.Criterion)).
+					WithDetail("criterion", score.Criterion)
+			}
+			earned += score.Points
+		}
+	} else {
+		if input.Points == nil {
+			return apperrors.New(apperrors.ErrorTypeValidation, 400, "请填写得分")
+		}
+		if *input.Points < 0 || *input.Points > question.Points {
+			return apperrors.New(apperrors.ErrorTypeValidation, 400, fmt.Sprintf("得分必须在0到%.2f之间", question.Points))
+		}
+		earned = *input.Points
+	}
+
+	earned = math.Round(earned*100) / 100
+	correct := earned >= question.Points
+	answer.EarnedPoints = &earned
+	answer.IsCorrect = &correct
+	answer.RubricScores = input.RubricScores
+	answer.Feedback = strings.TrimSpace(input.Feedback)
+	answer.GradingStatus = string(entities.AnswerGraded)
+	answer.GradedBy = &graderID
+	answer.GradedAt = &now
+	return nil
+}
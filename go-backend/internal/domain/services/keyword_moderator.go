@@ -0,0 +1,190 @@
+package services
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+	"sical-go-backend/pkg/logger"
+)
+
+// Moderator 内容审核器接口，内置关键词过滤器，也可替换为第三方审核服务
+type Moderator interface {
+	// Check 检查文本，未命中任何规则时返回allow
+	Check(ctx context.Context, text string) (*ModerationVerdict, error)
+}
+
+// ModerationVerdict 审核判定结果及命中的规则
+type ModerationVerdict struct {
+	Action   entities.ModerationAction `json:"action"`
+	RuleID   *uuid.UUID                `json:"rule_id,omitempty"`
+	RuleName string                    `json:"rule_name,omitempty"`
+	Category string                    `json:"category,omitempty"`
+	Matched  string                    `json:"matched,omitempty"` // 命中的文本片段
+}
+
+// ruleReloader 支持重新加载规则的审核器
+type ruleReloader interface {
+	Reload(ctx context.Context) error
+}
+
+// compiledRule 预处理后的过滤规则
+type compiledRule struct {
+	rule    *entities.ModerationRule
+	keyword string         // 归一化后的关键词
+	regex   *regexp.Regexp // 正则规则
+}
+
+// KeywordModerator 基于关键词和正则规则的内置审核器
+// 规则保存在数据库中，修改后调用Reload立即生效，并定期刷新以同步其他实例的修改
+// 关键词匹配前会统一大小写和全半角，并去除空白、标点和零宽字符，以识别“违 规”“违.规”等变体
+type KeywordModerator struct {
+	ruleRepo repositories.ModerationRuleRepository
+
+	mu        sync.RWMutex
+	rules     []compiledRule
+	startOnce sync.Once
+}
+
+// NewKeywordModerator 创建关键词审核器，需调用Reload加载规则
+func NewKeywordModerator(ruleRepo repositories.ModerationRuleRepository) *KeywordModerator {
+	return &KeywordModerator{
+		ruleRepo: ruleRepo,
+	}
+}
+
+// Start 启动后台定期刷新规则，重复调用只启动一次
+func (m *KeywordModerator) Start(ctx context.Context, interval time.Duration) {
+	m.startOnce.Do(func() {
+		go func() {
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					if err := m.Reload(ctx); err != nil {
+						logger.Error("刷新内容过滤规则失败", logger.String("error", err.Error()))
+					}
+				}
+			}
+		}()
+	})
+}
+
+// Reload 从数据库重新加载启用的规则，无效的正则规则会被跳过
+func (m *KeywordModerator) Reload(ctx context.Context) error {
+	rules, err := m.ruleRepo.List(ctx, true)
+	if err != nil {
+		return err
+	}
+
+	compiled := make([]compiledRule, 0, len(rules))
+	for _, rule := range rules {
+		c, err := compileModerationRule(rule)
+		if err != nil {
+			logger.Warn("跳过无效的内容过滤规则",
+				logger.String("rule_id", rule.ID.String()),
+				logger.String("error", err.Error()))
+			continue
+		}
+		compiled = append(compiled, c)
+	}
+
+	m.mu.Lock()
+	m.rules = compiled
+	m.mu.Unlock()
+	return nil
+}
+
+// Check 检查文本，拦截规则优先于人工审核规则
+func (m *KeywordModerator) Check(ctx context.Context, text string) (*ModerationVerdict, error) {
+	folded := foldModerationText(text)
+	compact := compactModerationText(folded)
+
+	m.mu.RLock()
+	rules := m.rules
+	m.mu.RUnlock()
+
+	var review *ModerationVerdict
+	for _, c := range rules {
+		matched := c.match(folded, compact)
+		if matched == "" {
+			continue
+		}
+		verdict := &ModerationVerdict{
+			Action:   entities.ModerationAction(c.rule.Action),
+			RuleID:   &c.rule.ID,
+			RuleName: c.rule.Name,
+			Category: c.rule.Category,
+			Matched:  matched,
+		}
+		if verdict.Action == entities.ModerationBlock {
+			return verdict, nil
+		}
+		if review == nil {
+			review = verdict
+		}
+	}
+	if review != nil {
+		return review, nil
+	}
+	return &ModerationVerdict{Action: entities.ModerationAllow}, nil
+}
+
+// match 返回命中的文本片段，未命中时返回空字符串
+func (c compiledRule) match(folded, compact string) string {
+	if c.regex != nil {
+		return c.regex.FindString(folded)
+	}
+	if c.keyword != "" && strings.Contains(compact, c.keyword) {
+		return c.keyword
+	}
+	return ""
+}
+
+// compileModerationRule 预处理过滤规则
+func compileModerationRule(rule *entities.ModerationRule) (compiledRule, error) {
+	c := compiledRule{rule: rule}
+	switch entities.ModerationMatchType(rule.MatchType) {
+	case entities.ModerationMatchRegex:
+		re, err := regexp.Compile("(?i)" + rule.Pattern)
+		if err != nil {
+			return c, err
+		}
+		c.regex = re
+	default:
+		c.keyword = compactModerationText(foldModerationText(rule.Pattern))
+	}
+	return c, nil
+}
+
+// foldModerationText 统一大小写，并将全角字符转换为半角
+func foldModerationText(text string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '　':
+			r = ' '
+		case r >= '！' && r <= '～':
+			r -= 0xFEE0
+		}
+		return unicode.ToLower(r)
+	}, text)
+}
+
+// compactModerationText 去除空白、标点、符号和零宽等格式字符
+func compactModerationText(text string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.Is(unicode.Cf, r) {
+			return -1
+		}
+		return r
+	}, text)
+}
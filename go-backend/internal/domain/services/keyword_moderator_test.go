@@ -0,0 +1,198 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+)
+
+// fakeModerationRuleRepository 内存中的过滤规则仓储
+type fakeModerationRuleRepository struct {
+	repositories.ModerationRuleRepository
+	mu    sync.Mutex
+	rules []*entities.ModerationRule
+	err   error
+}
+
+func (r *fakeModerationRuleRepository) List(ctx context.Context, activeOnly bool) ([]*entities.ModerationRule, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return nil, r.err
+	}
+	var rules []*entities.ModerationRule
+	for _, rule := range r.rules {
+		if rule.IsActive || !activeOnly {
+			rules = append(rules, rule)
+		}
+	}
+	return rules, nil
+}
+
+func (r *fakeModerationRuleRepository) set(rules ...*entities.ModerationRule) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rules = rules
+}
+
+func (r *fakeModerationRuleRepository) fail(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.err = err
+}
+
+func newTestModerationRule(name, pattern string, matchType entities.ModerationMatchType, action entities.ModerationAction) *entities.ModerationRule {
+	return &entities.ModerationRule{
+		ID:        uuid.New(),
+		Name:      name,
+		Pattern:   pattern,
+		MatchType: string(matchType),
+		Action:    string(action),
+		IsActive:  true,
+	}
+}
+
+// newTestKeywordModerator 加载给定规则的审核器
+func newTestKeywordModerator(t *testing.T, rules ...*entities.ModerationRule) (*KeywordModerator, *fakeModerationRuleRepository) {
+	t.Helper()
+	repo := &fakeModerationRuleRepository{}
+	repo.set(rules...)
+	moderator := NewKeywordModerator(repo)
+	if err := moderator.Reload(context.Background()); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	return moderator, repo
+}
+
+// checkModeration 检查文本并返回判定结果
+func checkModeration(t *testing.T, moderator *KeywordModerator, text string) *ModerationVerdict {
+	t.Helper()
+	verdict, err := moderator.Check(context.Background(), text)
+	if err != nil {
+		t.Fatalf("Check(%q) error = %v", text, err)
+	}
+	return verdict
+}
+
+func TestKeywordModeratorNormalization(t *testing.T) {
+	moderator, _ := newTestKeywordModerator(t,
+		newTestModerationRule("spam", "Spam", entities.ModerationMatchKeyword, entities.ModerationBlock),
+		newTestModerationRule("违规", "违规", entities.ModerationMatchKeyword, entities.ModerationBlock),
+		newTestModerationRule("full-width pattern", "Ｆｒｅｅ Ｍｏｎｅｙ", entities.ModerationMatchKeyword, entities.ModerationReview),
+	)
+
+	tests := []struct {
+		text        string
+		wantAction  entities.ModerationAction
+		wantMatched string
+	}{
+		{"this is SPAM", entities.ModerationBlock, "spam"},
+		{"ＳＰＡＭ", entities.ModerationBlock, "spam"},
+		{"s p a m", entities.ModerationBlock, "spam"},
+		{"s.p-a_m!", entities.ModerationBlock, "spam"},
+		{"s​pam", entities.ModerationBlock, "spam"},
+		{"违 规内容", entities.ModerationBlock, "违规"},
+		{"违　规", entities.ModerationBlock, "违规"},
+		{"违。规", entities.ModerationBlock, "违规"},
+		{"free-money now", entities.ModerationReview, "freemoney"},
+		{"FREE MONEY", entities.ModerationReview, "freemoney"},
+		{"sparm", entities.ModerationAllow, ""},
+		{"违反规定", entities.ModerationAllow, ""},
+		{"", entities.ModerationAllow, ""},
+	}
+	for _, tt := range tests {
+		verdict := checkModeration(t, moderator, tt.text)
+		if verdict.Action != tt.wantAction || verdict.Matched != tt.wantMatched {
+			t.Errorf("Check(%q) = %s %q, want %s %q", tt.text, verdict.Action, verdict.Matched, tt.wantAction, tt.wantMatched)
+		}
+	}
+}
+
+func TestKeywordModeratorRegexRules(t *testing.T) {
+	phone := newTestModerationRule("phone", `1[3-9]\d{9}`, entities.ModerationMatchRegex, entities.ModerationReview)
+	contact := newTestModerationRule("contact", `(wx|vx)[:：]?\s*\w+`, entities.ModerationMatchRegex, entities.ModerationBlock)
+	invalid := newTestModerationRule("invalid", `([`, entities.ModerationMatchRegex, entities.ModerationBlock)
+	inactive := newTestModerationRule("inactive", `hello`, entities.ModerationMatchRegex, entities.ModerationBlock)
+	inactive.IsActive = false
+	moderator, _ := newTestKeywordModerator(t, phone, invalid, contact, inactive)
+
+	tests := []struct {
+		text        string
+		wantRule    *entities.ModerationRule
+		wantMatched string
+	}{
+		{"请拨打13800138000咨询", phone, "13800138000"},
+		{"加VX: abc123", contact, "vx: abc123"},
+		{"加ＷＸ：abc", contact, "wx:abc"},
+		// 正则匹配保留空白和标点
+		{"1380 0138 000", nil, ""},
+		// 拦截规则优先于人工审核规则
+		{"13800138000 vx abc", contact, "vx abc"},
+		{"hello", nil, ""},
+	}
+	for _, tt := range tests {
+		verdict := checkModeration(t, moderator, tt.text)
+		if tt.wantRule == nil {
+			if verdict.Action != entities.ModerationAllow {
+				t.Errorf("Check(%q) = %+v, want allow", tt.text, verdict)
+			}
+			continue
+		}
+		if verdict.RuleID == nil || *verdict.RuleID != tt.wantRule.ID ||
+			verdict.Action != entities.ModerationAction(tt.wantRule.Action) || verdict.Matched != tt.wantMatched {
+			t.Errorf("Check(%q) = %+v, want rule %s matching %q", tt.text, verdict, tt.wantRule.Name, tt.wantMatched)
+		}
+	}
+}
+
+func TestKeywordModeratorReload(t *testing.T) {
+	ctx := context.Background()
+	moderator, repo := newTestKeywordModerator(t)
+	if verdict := checkModeration(t, moderator, "spam"); verdict.Action != entities.ModerationAllow {
+		t.Fatalf("verdict = %+v, want allow without rules", verdict)
+	}
+
+	// 规则修改后调用Reload立即生效
+	repo.set(newTestModerationRule("spam", "spam", entities.ModerationMatchKeyword, entities.ModerationBlock))
+	if err := moderator.Reload(ctx); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if verdict := checkModeration(t, moderator, "spam"); verdict.Action != entities.ModerationBlock {
+		t.Fatalf("verdict = %+v, want block after reload", verdict)
+	}
+
+	// 加载失败时保留原有规则
+	repo.fail(errors.New("数据库不可用"))
+	if err := moderator.Reload(ctx); err == nil {
+		t.Fatal("Reload() error = nil, want repository error")
+	}
+	if verdict := checkModeration(t, moderator, "spam"); verdict.Action != entities.ModerationBlock {
+		t.Fatalf("verdict = %+v, want previous rules kept", verdict)
+	}
+	repo.fail(nil)
+
+	// 后台定期刷新同步其他实例的修改
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	moderator.Start(runCtx, 5*time.Millisecond)
+	repo.set(newTestModerationRule("ads", "广告", entities.ModerationMatchKeyword, entities.ModerationReview))
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		ads := checkModeration(t, moderator, "广 告")
+		spam := checkModeration(t, moderator, "spam")
+		if ads.Action == entities.ModerationReview && spam.Action == entities.ModerationAllow {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("ads = %+v, spam = %+v, rules not refreshed in background", ads, spam)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
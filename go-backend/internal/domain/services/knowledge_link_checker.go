@@ -0,0 +1,180 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"syscall"
+	"time"
+
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+	"sical-go-backend/pkg/logger"
+)
+
+// errPrivateAddress 链接指向内网地址
+var errPrivateAddress = errors.New("不允许访问内网地址")
+
+// KnowledgeLinkCheckerConfig 外部链接检查配置
+type KnowledgeLinkCheckerConfig struct {
+	RecheckAfter     time.Duration // 同一链接两次检查的最小间隔
+	BatchSize        int           // 每轮最多检查的链接数
+	Timeout          time.Duration // 单个链接的请求超时
+	FailureThreshold int           // 连续失败多少次后标记为失效，避免偶发故障误报
+}
+
+// KnowledgeLinkChecker 定期检查知识点资源的外部链接，连续失败的链接标记为失效
+type KnowledgeLinkChecker struct {
+	resourceRepo repositories.KnowledgeResourceRepository
+	client       *http.Client
+	config       KnowledgeLinkCheckerConfig
+	startOnce    sync.Once
+}
+
+// NewKnowledgeLinkChecker 创建外部链接检查器，请求只允许访问公网地址
+func NewKnowledgeLinkChecker(resourceRepo repositories.KnowledgeResourceRepository, config KnowledgeLinkCheckerConfig) *KnowledgeLinkChecker {
+	if config.RecheckAfter <= 0 {
+		config.RecheckAfter = 24 * time.Hour
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 50
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 10 * time.Second
+	}
+	if config.FailureThreshold <= 0 {
+		config.FailureThreshold = 2
+	}
+
+	dialer := &net.Dialer{
+		Timeout: config.Timeout,
+		// 在解析后的地址上检查，防止通过域名或重定向访问内网
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !isPublicIP(ip) {
+				return errPrivateAddress
+			}
+			return nil
+		},
+	}
+	transport := &http.Transport{
+		DialContext:           dialer.DialContext,
+		TLSHandshakeTimeout:   config.Timeout,
+		ResponseHeaderTimeout: config.Timeout,
+		MaxIdleConnsPerHost:   2,
+		IdleConnTimeout:       time.Minute,
+	}
+
+	return &KnowledgeLinkChecker{
+		resourceRepo: resourceRepo,
+		client:       &http.Client{Transport: transport, Timeout: config.Timeout},
+		config:       config,
+	}
+}
+
+// Start 启动后台定期检查到期的链接，重复调用只启动一次
+func (c *KnowledgeLinkChecker) Start(ctx context.Context, interval time.Duration) {
+	c.startOnce.Do(func() {
+		go func() {
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					if _, err := c.CheckDue(ctx); err != nil {
+						logger.Error("检查外部链接失败", logger.String("error", err.Error()))
+					}
+				}
+			}
+		}()
+	})
+}
+
+// CheckDue 检查一批到期的链接，返回检查的链接数
+func (c *KnowledgeLinkChecker) CheckDue(ctx context.Context) (int, error) {
+	resources, err := c.resourceRepo.ListLinksDue(ctx, time.Now().Add(-c.config.RecheckAfter), c.config.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, resource := range resources {
+		if ctx.Err() != nil {
+			return i, ctx.Err()
+		}
+		c.record(resource, c.probe(ctx, resource.URL))
+		if err := c.resourceRepo.UpdateLinkStatus(ctx, resource); err != nil {
+			return i, err
+		}
+	}
+	return len(resources), nil
+}
+
+// record 记录检查结果，成功时恢复为正常，连续失败达到阈值时标记为失效
+func (c *KnowledgeLinkChecker) record(resource *entities.KnowledgeResource, err error) {
+	now := time.Now()
+	resource.LinkCheckedAt = &now
+	if err == nil {
+		resource.LinkStatus = string(entities.KnowledgeLinkOK)
+		resource.LinkFailures = 0
+		resource.LinkError = ""
+		return
+	}
+
+	resource.LinkFailures++
+	resource.LinkError = truncateRunes(err.Error(), 500)
+	if resource.LinkFailures >= c.config.FailureThreshold {
+		resource.LinkStatus = string(entities.KnowledgeLinkBroken)
+	}
+}
+
+// probe 请求链接，先用HEAD，服务端不支持时改用只取首字节的GET
+func (c *KnowledgeLinkChecker) probe(ctx context.Context, link string) error {
+	status, err := c.request(ctx, http.MethodHead, link)
+	if err == nil && (status == http.StatusMethodNotAllowed || status == http.StatusNotImplemented) {
+		status, err = c.request(ctx, http.MethodGet, link)
+	}
+	if err != nil {
+		return err
+	}
+	// 被限流不能说明链接失效
+	if status >= 400 && status != http.StatusTooManyRequests {
+		return fmt.Errorf("HTTP %d", status)
+	}
+	return nil
+}
+
+// request 发送请求并返回状态码，不读取响应体
+func (c *KnowledgeLinkChecker) request(ctx context.Context, method, link string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, method, link, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("User-Agent", "SiCal-LinkChecker/1.0")
+	if method == http.MethodGet {
+		req.Header.Set("Range", "bytes=0-0")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1024))
+	return resp.StatusCode, nil
+}
+
+// isPublicIP 是否为公网地址
+func isPublicIP(ip net.IP) bool {
+	return !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsUnspecified() &&
+		!ip.IsLinkLocalUnicast() && !ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() && !ip.IsMulticast()
+}
@@ -0,0 +1,258 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+	apperrors "sical-go-backend/pkg/errors"
+)
+
+const (
+	// defaultKnowledgeSort 默认按创建时间倒序
+	defaultKnowledgeSort = "-created_at"
+	// maxKnowledgeListTags 单次筛选最多使用的标签数
+	maxKnowledgeListTags = 10
+)
+
+// knowledgeListFields 列表允许返回的字段，与数据库列名一致
+var knowledgeListFields = []string{
+	"id", "title", "description", "content", "category", "difficulty",
+	"resources", "prerequisites", "tags", "status", "created_at", "updated_at",
+}
+
+// knowledgeDifficultyRanks 按难度排序时各难度的序号，与仓储的排序表达式一致
+var knowledgeDifficultyRanks = map[string]int{
+	"beginner":     1,
+	"intermediate": 2,
+	"advanced":     3,
+}
+
+// KnowledgeListInput 知识点列表请求
+// Sort为排序字段，前缀"-"表示倒序；Fields为空时返回除正文外的全部字段
+// Editorial为false时只返回已发布的知识点，Status仅对作者和审核员生效
+type KnowledgeListInput struct {
+	Category         string
+	Difficulty       string
+	Status           string
+	Editorial        bool
+	Tags             []string
+	CreatedFrom      *time.Time
+	CreatedTo        *time.Time
+	HasPrerequisites *bool
+	Sort             string
+	Fields           []string
+	Cursor           string
+	Limit            int
+}
+
+// KnowledgeListPage 知识点列表分页结果，Page为当前页码，从1开始
+type KnowledgeListPage struct {
+	Points     []*entities.KnowledgePoint
+	Fields     []string // 实际返回的字段
+	Total      int64
+	Page       int
+	Limit      int
+	NextCursor string // 为空表示没有更多
+}
+
+// KnowledgeListService 知识点列表服务
+type KnowledgeListService struct {
+	knowledgeRepo repositories.KnowledgePointRepository
+}
+
+// NewKnowledgeListService 创建知识点列表服务
+func NewKnowledgeListService(knowledgeRepo repositories.KnowledgePointRepository) *KnowledgeListService {
+	return &KnowledgeListService{
+		knowledgeRepo: knowledgeRepo,
+	}
+}
+
+// List 按组合筛选条件分页获取知识点，游标与排序方式绑定，排序变化时须从第一页开始
+func (s *KnowledgeListService) List(ctx context.Context, input KnowledgeListInput) (*KnowledgeListPage, error) {
+	if input.Difficulty != "" && !isValidDifficulty(input.Difficulty) {
+		return nil, apperrors.New(apperrors.ErrorTypeValidation, 400, "无效的难度值").WithDetail("difficulty", input.Difficulty)
+	}
+	status := string(entities.KnowledgeStatusPublished)
+	if input.Editorial {
+		if input.Status != "" && !entities.KnowledgeStatus(input.Status).IsValid() {
+			return nil, apperrors.New(apperrors.ErrorTypeValidation, 400, "无效的编辑状态").WithDetail("status", input.Status)
+		}
+		status = input.Status
+	}
+	if len(input.Tags) > maxKnowledgeListTags {
+		return nil, apperrors.New(apperrors.ErrorTypeValidation, 400, fmt.Sprintf("最多按%d个标签筛选", maxKnowledgeListTags))
+	}
+	if input.CreatedFrom != nil && input.CreatedTo != nil && !input.CreatedFrom.Before(*input.CreatedTo) {
+		return nil, apperrors.New(apperrors.ErrorTypeValidation, 400, "创建时间范围无效")
+	}
+
+	sort := input.Sort
+	if sort == "" {
+		sort = defaultKnowledgeSort
+	}
+	sortField := repositories.KnowledgeSortField(strings.TrimPrefix(sort, "-"))
+	if !sortField.IsValid() {
+		return nil, apperrors.New(apperrors.ErrorTypeValidation, 400, "不支持的排序字段").WithDetail("sort", input.Sort)
+	}
+
+	fields, err := knowledgeListColumns(input.Fields, sortField)
+	if err != nil {
+		return nil, err
+	}
+
+	query := repositories.KnowledgeListQuery{
+		Category:         input.Category,
+		Difficulty:       input.Difficulty,
+		Status:           status,
+		Tags:             input.Tags,
+		CreatedFrom:      input.CreatedFrom,
+		CreatedTo:        input.CreatedTo,
+		HasPrerequisites: input.HasPrerequisites,
+		SortField:        sortField,
+		Descending:       strings.HasPrefix(sort, "-"),
+		Columns:          fields,
+		Limit:            input.Limit + 1,
+	}
+	page := 1
+	if input.Cursor != "" {
+		cursor, cursorPage, err := decodeKnowledgeListCursor(input.Cursor, sort)
+		if err != nil {
+			return nil, apperrors.New(apperrors.ErrorTypeValidation, 400, "分页游标无效")
+		}
+		query.Cursor = cursor
+		page = cursorPage
+	}
+
+	result, err := s.knowledgeRepo.List(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	listPage := &KnowledgeListPage{
+		Points: result.Points,
+		Fields: fields,
+		Total:  result.Total,
+		Page:   page,
+		Limit:  input.Limit,
+	}
+	if len(result.Points) > input.Limit {
+		listPage.Points = result.Points[:input.Limit]
+		last := listPage.Points[len(listPage.Points)-1]
+		listPage.NextCursor = encodeKnowledgeListCursor(sort, page+1, last)
+	}
+	return listPage, nil
+}
+
+// knowledgeListColumns 校验稀疏字段集并补充游标分页所需的ID和排序列
+func knowledgeListColumns(requested []string, sortField repositories.KnowledgeSortField) ([]string, error) {
+	if len(requested) == 0 {
+		fields := make([]string, 0, len(knowledgeListFields))
+		for _, field := range knowledgeListFields {
+			if field != "content" {
+				fields = append(fields, field)
+			}
+		}
+		return fields, nil
+	}
+
+	wanted := make(map[string]bool, len(requested)+2)
+	for _, field := range requested {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		if !isKnowledgeListField(field) {
+			return nil, apperrors.New(apperrors.ErrorTypeValidation, 400, "不支持的字段").WithDetail("field", field)
+		}
+		wanted[field] = true
+	}
+	wanted["id"] = true
+	wanted[string(sortField)] = true
+
+	fields := make([]string, 0, len(wanted))
+	for _, field := range knowledgeListFields {
+		if wanted[field] {
+			fields = append(fields, field)
+		}
+	}
+	return fields, nil
+}
+
+// isKnowledgeListField 是否为列表允许返回的字段
+func isKnowledgeListField(field string) bool {
+	for _, allowed := range knowledgeListFields {
+		if field == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// encodeKnowledgeListCursor 将排序方式、下一页页码和最后一条知识点的排序键编码为不透明的游标字符串
+func encodeKnowledgeListCursor(sort string, page int, last *entities.KnowledgePoint) string {
+	var value string
+	switch repositories.KnowledgeSortField(strings.TrimPrefix(sort, "-")) {
+	case repositories.KnowledgeSortTitle:
+		value = last.Title
+	case repositories.KnowledgeSortDifficulty:
+		value = strconv.Itoa(knowledgeDifficultyRank(last.Difficulty))
+	case repositories.KnowledgeSortUpdatedAt:
+		value = strconv.FormatInt(last.UpdatedAt.UnixNano(), 10)
+	default:
+		value = strconv.FormatInt(last.CreatedAt.UnixNano(), 10)
+	}
+	raw := fmt.Sprintf("%s|%d|%s|%s", sort, page, last.ID, value)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeKnowledgeListCursor 解析游标字符串，游标的排序方式须与当前请求一致
+func decodeKnowledgeListCursor(value, sort string) (*repositories.KnowledgeListCursor, int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, 0, err
+	}
+	parts := strings.SplitN(string(raw), "|", 4)
+	if len(parts) != 4 || parts[0] != sort {
+		return nil, 0, fmt.Errorf("游标格式无效")
+	}
+	page, err := strconv.Atoi(parts[1])
+	if err != nil || page < 2 {
+		return nil, 0, fmt.Errorf("游标页码无效")
+	}
+	id, err := uuid.Parse(parts[2])
+	if err != nil {
+		return nil, 0, err
+	}
+
+	cursor := &repositories.KnowledgeListCursor{ID: id}
+	switch repositories.KnowledgeSortField(strings.TrimPrefix(sort, "-")) {
+	case repositories.KnowledgeSortTitle:
+		cursor.Text = parts[3]
+	case repositories.KnowledgeSortDifficulty:
+		if cursor.Rank, err = strconv.Atoi(parts[3]); err != nil {
+			return nil, 0, err
+		}
+	default:
+		nanos, err := strconv.ParseInt(parts[3], 10, 64)
+		if err != nil {
+			return nil, 0, err
+		}
+		cursor.Time = time.Unix(0, nanos)
+	}
+	return cursor, page, nil
+}
+
+// knowledgeDifficultyRank 难度的排序序号，未知难度排在最后
+func knowledgeDifficultyRank(difficulty string) int {
+	if rank, ok := knowledgeDifficultyRanks[difficulty]; ok {
+		return rank
+	}
+	return len(knowledgeDifficultyRanks) + 1
+}
@@ -0,0 +1,593 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/pkg/pinyin"
+)
+
+// 阅读时间估算参数，单位为秒
+const (
+	secondsPerCJKChar       = 0.2 // 约每分钟300字
+	secondsPerWord          = 0.3 // 约每分钟200词
+	secondsPerInlineFormula = 3
+	secondsPerDisplayMath   = 15
+	secondsPerCodeLine      = 2
+	secondsPerImage         = 12
+)
+
+// maxTOCLevel 目录收录的最深标题层级
+const maxTOCLevel = 4
+
+// maxAnchorLength 标题锚点的最大字符数
+const maxAnchorLength = 60
+
+// knowledgeCalloutTitles 提示框类型及默认标题，语法同GitHub："> [!NOTE] 可选标题"
+var knowledgeCalloutTitles = map[string]string{
+	"note":      "说明",
+	"tip":       "提示",
+	"important": "重要",
+	"warning":   "警告",
+	"caution":   "注意",
+}
+
+var (
+	calloutMarkerPattern = regexp.MustCompile(`^\[!([A-Za-z]+)\][ \t]*(.*)$`)
+
+	// knowledgeMarkdown 正文的Markdown解析器，允许原始HTML，输出统一经knowledgeHTMLPolicy清洗
+	knowledgeMarkdown = goldmark.New(
+		goldmark.WithExtensions(extension.Table, extension.Strikethrough, extension.Linkify, &knowledgeMarkdownExtension{}),
+		goldmark.WithRendererOptions(html.WithUnsafe()),
+	)
+
+	// knowledgeHTMLPolicy 渲染结果的HTML白名单，在UGC策略基础上允许公式、化学结构和提示框使用的class和属性
+	knowledgeHTMLPolicy = newKnowledgeHTMLPolicy()
+)
+
+// KnowledgeRenderedContent 正文的渲染结果
+type KnowledgeRenderedContent struct {
+	HTML           string
+	TOC            []entities.KnowledgeTOCEntry
+	WordCount      int
+	ReadingMinutes int
+}
+
+// RenderKnowledgeContent 将Markdown正文渲染为清洗后的HTML，同时提取目录并估算阅读时间
+// 支持的扩展：$行内公式$、$$独立公式$$和math代码块(由前端以KaTeX等渲染)；smiles代码块表示化学结构式，
+// chem代码块为mhchem化学方程式；"> [!NOTE]"等提示框
+func RenderKnowledgeContent(content string) (*KnowledgeRenderedContent, error) {
+	source := []byte(content)
+	doc := knowledgeMarkdown.Parser().Parse(text.NewReader(source))
+
+	result := &KnowledgeRenderedContent{TOC: assignHeadingAnchors(doc, source)}
+	var seconds float64
+	result.WordCount, seconds = measureKnowledgeContent(doc, source)
+	if result.WordCount > 0 || seconds > 0 {
+		result.ReadingMinutes = max(1, int(math.Ceil(seconds/60)))
+	}
+
+	var buf bytes.Buffer
+	if err := knowledgeMarkdown.Renderer().Render(&buf, source, doc); err != nil {
+		return nil, fmt.Errorf("渲染知识点正文失败: %w", err)
+	}
+	result.HTML = knowledgeHTMLPolicy.Sanitize(buf.String())
+	return result, nil
+}
+
+// newKnowledgeHTMLPolicy 创建渲染结果的HTML白名单
+func newKnowledgeHTMLPolicy() *bluemonday.Policy {
+	policy := bluemonday.UGCPolicy()
+	policy.AllowAttrs("class").Matching(regexp.MustCompile(
+		`^(math math-inline|math math-display|chem-structure|callout callout-(note|tip|important|warning|caution)|callout-title)$`,
+	)).OnElements("span", "div", "figure", "p")
+	policy.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+#-]+$`)).OnElements("code")
+	policy.AllowAttrs("data-smiles").OnElements("figure")
+	return policy
+}
+
+// assignHeadingAnchors 为标题生成锚点并返回目录，重复时追加序号
+func assignHeadingAnchors(doc ast.Node, source []byte) []entities.KnowledgeTOCEntry {
+	toc := []entities.KnowledgeTOCEntry{}
+	used := map[string]int{}
+	_ = ast.Walk(doc, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		heading, ok := node.(*ast.Heading)
+		if !entering || !ok {
+			return ast.WalkContinue, nil
+		}
+		title := strings.TrimSpace(knowledgeNodeText(heading, source))
+		anchor := knowledgeAnchor(title)
+		used[anchor]++
+		if n := used[anchor]; n > 1 {
+			anchor = fmt.Sprintf("%s-%d", anchor, n)
+		}
+		heading.SetAttributeString("id", []byte(anchor))
+		if heading.Level <= maxTOCLevel {
+			toc = append(toc, entities.KnowledgeTOCEntry{Level: heading.Level, Title: title, Anchor: anchor})
+		}
+		return ast.WalkSkipChildren, nil
+	})
+	return toc
+}
+
+// knowledgeAnchor 由标题的拼音生成锚点，只保留HTML清洗时id允许的ASCII字母和数字，其他文字的标题使用section
+func knowledgeAnchor(title string) string {
+	full, _ := pinyin.Convert(title)
+	var sb strings.Builder
+	for _, r := range full {
+		if r > unicode.MaxASCII || !(unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			continue
+		}
+		sb.WriteRune(r)
+		if sb.Len() >= maxAnchorLength {
+			break
+		}
+	}
+	if sb.Len() == 0 {
+		return "section"
+	}
+	return sb.String()
+}
+
+// measureKnowledgeContent 统计字数并估算阅读秒数，汉字按字、其他文字按词计数，公式、代码和图片按个数或行数计时
+func measureKnowledgeContent(doc ast.Node, source []byte) (int, float64) {
+	var words int
+	var seconds float64
+	_ = ast.Walk(doc, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n := node.(type) {
+		case *ast.Text:
+			cjk, latin := countKnowledgeWords(n.Segment.Value(source))
+			words += cjk + latin
+			seconds += float64(cjk)*secondsPerCJKChar + float64(latin)*secondsPerWord
+		case *ast.String:
+			cjk, latin := countKnowledgeWords(n.Value)
+			words += cjk + latin
+			seconds += float64(cjk)*secondsPerCJKChar + float64(latin)*secondsPerWord
+		case *ast.Image:
+			seconds += secondsPerImage
+			return ast.WalkSkipChildren, nil
+		case *knowledgeMath:
+			if n.Display {
+				seconds += secondsPerDisplayMath
+			} else {
+				seconds += secondsPerInlineFormula
+			}
+		case *knowledgeMathBlock, *knowledgeChemStructure:
+			seconds += secondsPerDisplayMath
+		case *ast.FencedCodeBlock, *ast.CodeBlock:
+			seconds += float64(node.Lines().Len()) * secondsPerCodeLine
+		}
+		return ast.WalkContinue, nil
+	})
+	return words, seconds
+}
+
+// countKnowledgeWords 统计汉字(含日文、韩文)数和其他文字的词数
+func countKnowledgeWords(value []byte) (int, int) {
+	var cjk, latin int
+	inWord := false
+	for _, r := range string(value) {
+		switch {
+		case unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul):
+			cjk++
+			inWord = false
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if !inWord {
+				latin++
+			}
+			inWord = true
+		default:
+			inWord = false
+		}
+	}
+	return cjk, latin
+}
+
+// knowledgeNodeText 提取节点下的纯文本，公式保留原文
+func knowledgeNodeText(node ast.Node, source []byte) string {
+	var sb strings.Builder
+	_ = ast.Walk(node, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch v := n.(type) {
+		case *ast.Text:
+			sb.Write(v.Segment.Value(source))
+			if v.SoftLineBreak() || v.HardLineBreak() {
+				sb.WriteByte(' ')
+			}
+		case *ast.String:
+			sb.Write(v.Value)
+		case *knowledgeMath:
+			sb.Write(v.Formula)
+		}
+		return ast.WalkContinue, nil
+	})
+	return sb.String()
+}
+
+// knowledgeMarkdownExtension 知识点正文的Markdown扩展：公式、化学结构式和提示框
+type knowledgeMarkdownExtension struct{}
+
+// Extend 实现goldmark.Extender接口
+func (e *knowledgeMarkdownExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(
+		parser.WithBlockParsers(util.Prioritized(&knowledgeMathBlockParser{}, 90)),
+		parser.WithInlineParsers(util.Prioritized(&knowledgeMathParser{}, 90)),
+		parser.WithASTTransformers(util.Prioritized(&knowledgeBlockTransformer{}, 100)),
+	)
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(util.Prioritized(&knowledgeNodeRenderer{}, 100)))
+}
+
+var (
+	kindKnowledgeMath          = ast.NewNodeKind("KnowledgeMath")
+	kindKnowledgeMathBlock     = ast.NewNodeKind("KnowledgeMathBlock")
+	kindKnowledgeChemStructure = ast.NewNodeKind("KnowledgeChemStructure")
+	kindKnowledgeCallout       = ast.NewNodeKind("KnowledgeCallout")
+)
+
+// knowledgeMath 行内公式，$...$或同一行内的$$...$$
+type knowledgeMath struct {
+	ast.BaseInline
+	Formula []byte
+	Display bool
+}
+
+// Kind 实现ast.Node接口
+func (n *knowledgeMath) Kind() ast.NodeKind { return kindKnowledgeMath }
+
+// Dump 实现ast.Node接口
+func (n *knowledgeMath) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"Formula": string(n.Formula)}, nil)
+}
+
+// knowledgeMathBlock 独立公式，由$$包围的多行内容或math、chem代码块
+type knowledgeMathBlock struct {
+	ast.BaseBlock
+	Formula []byte
+	closed  bool
+}
+
+// Kind 实现ast.Node接口
+func (n *knowledgeMathBlock) Kind() ast.NodeKind { return kindKnowledgeMathBlock }
+
+// IsRaw 实现ast.Node接口
+func (n *knowledgeMathBlock) IsRaw() bool { return true }
+
+// Dump 实现ast.Node接口
+func (n *knowledgeMathBlock) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"Formula": string(n.Formula)}, nil)
+}
+
+// knowledgeChemStructure SMILES化学结构式，由前端绘制
+type knowledgeChemStructure struct {
+	ast.BaseBlock
+	SMILES []byte
+}
+
+// Kind 实现ast.Node接口
+func (n *knowledgeChemStructure) Kind() ast.NodeKind { return kindKnowledgeChemStructure }
+
+// IsRaw 实现ast.Node接口
+func (n *knowledgeChemStructure) IsRaw() bool { return true }
+
+// Dump 实现ast.Node接口
+func (n *knowledgeChemStructure) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"SMILES": string(n.SMILES)}, nil)
+}
+
+// knowledgeCallout 提示框，第一个子节点为标题段落
+type knowledgeCallout struct {
+	ast.BaseBlock
+	CalloutType string
+}
+
+// Kind 实现ast.Node接口
+func (n *knowledgeCallout) Kind() ast.NodeKind { return kindKnowledgeCallout }
+
+// Dump 实现ast.Node接口
+func (n *knowledgeCallout) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"Type": n.CalloutType}, nil)
+}
+
+// knowledgeMathParser 解析行内公式，按Pandoc的规则：$后和结束的$前不能是空白，结束的$后不能紧跟数字，避免误判金额
+type knowledgeMathParser struct{}
+
+// Trigger 实现parser.InlineParser接口
+func (p *knowledgeMathParser) Trigger() []byte {
+	return []byte{'$'}
+}
+
+// Parse 实现parser.InlineParser接口
+func (p *knowledgeMathParser) Parse(parent ast.Node, block text.Reader, pc parser.Context) ast.Node {
+	line, _ := block.PeekLine()
+	delimiter := []byte("$")
+	if len(line) > 1 && line[1] == '$' {
+		delimiter = []byte("$$")
+	}
+	rest := line[len(delimiter):]
+	end := -1
+	for i := 0; i < len(rest); i++ {
+		if rest[i] == '\\' {
+			i++
+			continue
+		}
+		if bytes.HasPrefix(rest[i:], delimiter) {
+			end = i
+			break
+		}
+	}
+	if end <= 0 {
+		return nil
+	}
+	formula := rest[:end]
+	if len(delimiter) == 1 {
+		after := len(delimiter) + end + 1
+		if util.IsSpace(formula[0]) || util.IsSpace(formula[len(formula)-1]) ||
+			(after < len(line) && line[after] >= '0' && line[after] <= '9') {
+			return nil
+		}
+	}
+	block.Advance(len(delimiter)*2 + end)
+	return &knowledgeMath{
+		Formula: bytes.Clone(formula),
+		Display: len(delimiter) == 2,
+	}
+}
+
+// knowledgeMathBlockParser 解析以$$开头的独立公式，至以$$结尾的行结束
+type knowledgeMathBlockParser struct{}
+
+// Trigger 实现parser.BlockParser接口
+func (p *knowledgeMathBlockParser) Trigger() []byte {
+	return []byte{'$'}
+}
+
+// Open 实现parser.BlockParser接口
+func (p *knowledgeMathBlockParser) Open(parent ast.Node, reader text.Reader, pc parser.Context) (ast.Node, parser.State) {
+	line, _ := reader.PeekLine()
+	pos := pc.BlockOffset()
+	if pos < 0 || !bytes.HasPrefix(line[pos:], []byte("$$")) {
+		return nil, parser.NoChildren
+	}
+	rest := bytes.TrimSpace(line[pos+2:])
+	node := &knowledgeMathBlock{}
+	if i := bytes.Index(rest, []byte("$$")); i >= 0 {
+		// 同一行内结束，$$之后不能再有内容，否则按行内公式处理
+		if len(bytes.TrimSpace(rest[i+2:])) > 0 {
+			return nil, parser.NoChildren
+		}
+		node.Formula = bytes.Clone(bytes.TrimSpace(rest[:i]))
+		node.closed = true
+	} else if len(rest) > 0 {
+		node.Formula = append(bytes.Clone(rest), '\n')
+	}
+	reader.AdvanceToEOL()
+	return node, parser.NoChildren
+}
+
+// Continue 实现parser.BlockParser接口
+func (p *knowledgeMathBlockParser) Continue(node ast.Node, reader text.Reader, pc parser.Context) parser.State {
+	block := node.(*knowledgeMathBlock)
+	if block.closed {
+		return parser.Close
+	}
+	line, _ := reader.PeekLine()
+	trimmed := bytes.TrimSpace(line)
+	if bytes.HasSuffix(trimmed, []byte("$$")) {
+		block.Formula = append(block.Formula, bytes.TrimSpace(trimmed[:len(trimmed)-2])...)
+		block.closed = true
+		reader.AdvanceToEOL()
+		return parser.Close
+	}
+	block.Formula = append(block.Formula, line...)
+	reader.AdvanceToEOL()
+	return parser.Continue | parser.NoChildren
+}
+
+// Close 实现parser.BlockParser接口
+func (p *knowledgeMathBlockParser) Close(node ast.Node, reader text.Reader, pc parser.Context) {
+	block := node.(*knowledgeMathBlock)
+	block.Formula = bytes.TrimSpace(block.Formula)
+}
+
+// CanInterruptParagraph 实现parser.BlockParser接口
+func (p *knowledgeMathBlockParser) CanInterruptParagraph() bool {
+	return true
+}
+
+// CanAcceptIndentedLine 实现parser.BlockParser接口
+func (p *knowledgeMathBlockParser) CanAcceptIndentedLine() bool {
+	return false
+}
+
+// knowledgeBlockTransformer 将math、chem、smiles代码块转换为公式和化学结构式，将带[!TYPE]标记的引用块转换为提示框
+type knowledgeBlockTransformer struct{}
+
+// Transform 实现parser.ASTTransformer接口
+func (t *knowledgeBlockTransformer) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	source := reader.Source()
+	var replacements [][2]ast.Node
+	_ = ast.Walk(doc, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n := node.(type) {
+		case *ast.FencedCodeBlock:
+			if replacement := convertKnowledgeCodeBlock(n, source); replacement != nil {
+				replacements = append(replacements, [2]ast.Node{n, replacement})
+			}
+			return ast.WalkSkipChildren, nil
+		case *ast.Blockquote:
+			if callout := convertKnowledgeCallout(n, source); callout != nil {
+				replacements = append(replacements, [2]ast.Node{n, callout})
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+	for _, pair := range replacements {
+		pair[0].Parent().ReplaceChild(pair[0].Parent(), pair[0], pair[1])
+	}
+}
+
+// convertKnowledgeCodeBlock 转换math、chem、smiles代码块，其他语言返回nil
+func convertKnowledgeCodeBlock(block *ast.FencedCodeBlock, source []byte) ast.Node {
+	var code bytes.Buffer
+	lines := block.Lines()
+	for i := 0; i < lines.Len(); i++ {
+		segment := lines.At(i)
+		code.Write(segment.Value(source))
+	}
+	value := bytes.TrimSpace(code.Bytes())
+
+	switch strings.ToLower(string(block.Language(source))) {
+	case "math", "latex", "tex":
+		return &knowledgeMathBlock{Formula: value, closed: true}
+	case "chem", "mhchem":
+		return &knowledgeMathBlock{Formula: []byte(`\ce{` + string(value) + `}`), closed: true}
+	case "smiles":
+		return &knowledgeChemStructure{SMILES: value}
+	}
+	return nil
+}
+
+// convertKnowledgeCallout 转换首行为[!TYPE]标记的引用块，标记后的行内内容作为标题，不是提示框时返回nil
+func convertKnowledgeCallout(quote *ast.Blockquote, source []byte) ast.Node {
+	paragraph, ok := quote.FirstChild().(*ast.Paragraph)
+	if !ok || paragraph.Lines().Len() == 0 {
+		return nil
+	}
+	firstLine := paragraph.Lines().At(0)
+	line := bytes.TrimRight(firstLine.Value(source), "\r\n")
+	match := calloutMarkerPattern.FindSubmatchIndex(line)
+	if match == nil {
+		return nil
+	}
+	calloutType := strings.ToLower(string(line[match[2]:match[3]]))
+	defaultTitle, ok := knowledgeCalloutTitles[calloutType]
+	if !ok {
+		return nil
+	}
+	titleStart := firstLine.Start + match[4]
+
+	// 首行的行内节点移入标题段落并去掉其中的标记，只剩标记行时移除整个段落
+	title := ast.NewParagraph()
+	title.SetAttributeString("class", []byte("callout-title"))
+	for child := paragraph.FirstChild(); child != nil; {
+		next := child.NextSibling()
+		paragraph.RemoveChild(paragraph, child)
+		lineEnd := false
+		if textNode, isText := child.(*ast.Text); isText {
+			lineEnd = textNode.SoftLineBreak() || textNode.HardLineBreak()
+			textNode.SetSoftLineBreak(false)
+			textNode.SetHardLineBreak(false)
+			if textNode.Segment.Start < titleStart {
+				textNode.Segment = textNode.Segment.WithStart(min(titleStart, textNode.Segment.Stop))
+			}
+			if !textNode.Segment.IsEmpty() {
+				title.AppendChild(title, textNode)
+			}
+		} else {
+			title.AppendChild(title, child)
+		}
+		if lineEnd {
+			break
+		}
+		child = next
+	}
+	if !title.HasChildren() {
+		title.AppendChild(title, ast.NewString([]byte(defaultTitle)))
+	}
+	if !paragraph.HasChildren() {
+		quote.RemoveChild(quote, paragraph)
+	}
+
+	callout := &knowledgeCallout{CalloutType: calloutType}
+	callout.AppendChild(callout, title)
+	for child := quote.FirstChild(); child != nil; {
+		next := child.NextSibling()
+		callout.AppendChild(callout, child)
+		child = next
+	}
+	return callout
+}
+
+// knowledgeNodeRenderer 渲染公式、化学结构式和提示框
+// 公式以\(...\)、\[...\]包围后原样输出，由前端的KaTeX或MathJax排版
+type knowledgeNodeRenderer struct{}
+
+// RegisterFuncs 实现renderer.NodeRenderer接口
+func (r *knowledgeNodeRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(kindKnowledgeMath, r.renderMath)
+	reg.Register(kindKnowledgeMathBlock, r.renderMathBlock)
+	reg.Register(kindKnowledgeChemStructure, r.renderChemStructure)
+	reg.Register(kindKnowledgeCallout, r.renderCallout)
+}
+
+// renderMath 渲染行内公式
+func (r *knowledgeNodeRenderer) renderMath(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		n := node.(*knowledgeMath)
+		if n.Display {
+			_, _ = w.WriteString(`<span class="math math-display">\[`)
+			_, _ = w.Write(util.EscapeHTML(n.Formula))
+			_, _ = w.WriteString(`\]</span>`)
+		} else {
+			_, _ = w.WriteString(`<span class="math math-inline">\(`)
+			_, _ = w.Write(util.EscapeHTML(n.Formula))
+			_, _ = w.WriteString(`\)</span>`)
+		}
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+// renderMathBlock 渲染独立公式
+func (r *knowledgeNodeRenderer) renderMathBlock(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		_, _ = w.WriteString(`<div class="math math-display">\[`)
+		_, _ = w.Write(util.EscapeHTML(node.(*knowledgeMathBlock).Formula))
+		_, _ = w.WriteString("\\]</div>\n")
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+// renderChemStructure 渲染化学结构式，前端读取data-smiles绘制，未绘制时显示SMILES原文
+func (r *knowledgeNodeRenderer) renderChemStructure(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		smiles := util.EscapeHTML(node.(*knowledgeChemStructure).SMILES)
+		_, _ = w.WriteString(`<figure class="chem-structure" data-smiles="`)
+		_, _ = w.Write(smiles)
+		_, _ = w.WriteString(`"><code>`)
+		_, _ = w.Write(smiles)
+		_, _ = w.WriteString("</code></figure>\n")
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+// renderCallout 渲染提示框
+func (r *knowledgeNodeRenderer) renderCallout(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	n := node.(*knowledgeCallout)
+	if entering {
+		_, _ = fmt.Fprintf(w, `<div class="callout callout-%s">`+"\n", n.CalloutType)
+	} else {
+		_, _ = w.WriteString("</div>\n")
+	}
+	return ast.WalkContinue, nil
+}
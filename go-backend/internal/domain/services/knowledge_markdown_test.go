@@ -0,0 +1,154 @@
+package services
+
+import (
+	"strings"
+	"testing"
+)
+
+func renderKnowledgeForTest(t *testing.T, content string) *KnowledgeRenderedContent {
+	t.Helper()
+	result, err := RenderKnowledgeContent(content)
+	if err != nil {
+		t.Fatalf("RenderKnowledgeContent() error = %v", err)
+	}
+	return result
+}
+
+func TestRenderKnowledgeContentSanitizes(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+		reject  []string
+	}{
+		{
+			name:    "script and handlers",
+			content: "<script>alert(1)</script><a href=\"javascript:alert(1)\" onclick=\"steal()\">链接</a>",
+			want:    []string{"链接"},
+			reject:  []string{"<script", "alert(1)", "onclick", "javascript:"},
+		},
+		{
+			name:    "class whitelist",
+			content: `<span class="evil">x</span> <span class="math math-inline">y</span>`,
+			want:    []string{`<span>x</span>`, `<span class="math math-inline">y</span>`},
+			reject:  []string{"evil"},
+		},
+		{
+			name:    "extensions survive",
+			content: "质量 $E=mc^2$\n\n```smiles\nC1=CC=CC=C1\n```\n\n```go\nfmt.Println()\n```",
+			want: []string{
+				`<span class="math math-inline">\(E=mc^2\)</span>`,
+				`<figure class="chem-structure" data-smiles="C1=CC=CC=C1">`,
+				`<code class="language-go">`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		html := renderKnowledgeForTest(t, tt.content).HTML
+		for _, want := range tt.want {
+			if !strings.Contains(html, want) {
+				t.Errorf("%s: html = %q, want %q", tt.name, html, want)
+			}
+		}
+		for _, reject := range tt.reject {
+			if strings.Contains(html, reject) {
+				t.Errorf("%s: html = %q, must not contain %q", tt.name, html, reject)
+			}
+		}
+	}
+}
+
+func TestRenderKnowledgeContentCallouts(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "default title",
+			content: "> [!NOTE]\n> 只有正文",
+			want:    "<div class=\"callout callout-note\">\n<p class=\"callout-title\">说明</p>\n<p>只有正文</p>\n</div>\n",
+		},
+		{
+			name:    "marker only",
+			content: "> [!WARNING]",
+			want:    "<div class=\"callout callout-warning\">\n<p class=\"callout-title\">警告</p>\n</div>\n",
+		},
+		{
+			name:    "inline formatting in title",
+			content: "> [!TIP] 使用 **β受体阻滞剂** 时注意\n> 正文内容",
+			want:    "<div class=\"callout callout-tip\">\n<p class=\"callout-title\">使用 <strong>β受体阻滞剂</strong> 时注意</p>\n<p>正文内容</p>\n</div>\n",
+		},
+		{
+			name:    "lower case type and code span",
+			content: "> [!caution] 剂量 `mg/kg`\n>\n> 第二段",
+			want:    "<div class=\"callout callout-caution\">\n<p class=\"callout-title\">剂量 <code>mg/kg</code></p>\n<p>第二段</p>\n</div>\n",
+		},
+		{
+			name:    "unknown type stays a quote",
+			content: "> [!OTHER] 标题",
+			want:    "<blockquote>\n<p>[!OTHER] 标题</p>\n</blockquote>\n",
+		},
+	}
+	for _, tt := range tests {
+		if got := renderKnowledgeForTest(t, tt.content).HTML; got != tt.want {
+			t.Errorf("%s: html = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRenderKnowledgeContentTOC(t *testing.T) {
+	content := "# 感冒灵 2号\n\n## 感冒灵 2号\n\n## Ωμέγα\n\n### Dose *adjustment*\n\n##### 五级标题\n\n## " + strings.Repeat("长", 40)
+	result := renderKnowledgeForTest(t, content)
+
+	want := []struct {
+		level  int
+		title  string
+		anchor string
+	}{
+		{1, "感冒灵 2号", "ganmaoling2hao"},
+		{2, "感冒灵 2号", "ganmaoling2hao-2"},
+		{2, "Ωμέγα", "section"},
+		{3, "Dose adjustment", "doseadjustment"},
+		{2, strings.Repeat("长", 40), strings.Repeat("zhang", 12)},
+	}
+	if len(result.TOC) != len(want) {
+		t.Fatalf("toc = %+v, want %d entries", result.TOC, len(want))
+	}
+	for i, entry := range result.TOC {
+		if entry.Level != want[i].level || entry.Title != want[i].title || entry.Anchor != want[i].anchor {
+			t.Errorf("toc[%d] = %+v, want %+v", i, entry, want[i])
+		}
+		// 目录锚点须与清洗后标题的id一致
+		if !strings.Contains(result.HTML, `id="`+entry.Anchor+`"`) {
+			t.Errorf("html has no heading with id %q", entry.Anchor)
+		}
+	}
+	// 超出目录层级的标题仍有锚点
+	if !strings.Contains(result.HTML, `<h5 id="wujibiaoti">`) {
+		t.Errorf("html = %q, want anchored h5", result.HTML)
+	}
+}
+
+func TestRenderKnowledgeContentReadingTime(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		wantWords int
+		wantMins  int
+	}{
+		{"empty", "", 0, 0},
+		{"cjk at 300 per minute", strings.Repeat("字", 600), 600, 2},
+		{"words at 200 per minute", strings.Repeat("word ", 401), 401, 3},
+		{"mixed", "阿司匹林 aspirin 100 mg", 7, 1},
+		// 4字0.8秒 + 行内公式3秒 + 独立公式15秒 + 结构式15秒 + 2行代码4秒
+		{"formulas and code", "质量公式 $E=mc^2$\n\n$$\n\\int x\n$$\n\n```smiles\nCCO\n```\n\n```go\na\nb\n```", 4, 1},
+		{"formula only", "$$\n\\int x\n$$", 0, 1},
+	}
+	for _, tt := range tests {
+		result := renderKnowledgeForTest(t, tt.content)
+		if result.WordCount != tt.wantWords || result.ReadingMinutes != tt.wantMins {
+			t.Errorf("%s: words = %d, minutes = %d, want %d, %d", tt.name, result.WordCount, result.ReadingMinutes, tt.wantWords, tt.wantMins)
+		}
+	}
+}
@@ -0,0 +1,294 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+	apperrors "sical-go-backend/pkg/errors"
+	"sical-go-backend/pkg/logger"
+)
+
+// knowledgeCooccurrenceWeights 各共现来源在相似度中的权重，学习者的实际学习记录比生成的路径更能反映关联
+var knowledgeCooccurrenceWeights = []struct {
+	source repositories.KnowledgeCooccurrenceSource
+	weight float64
+}{
+	{repositories.KnowledgeCooccurrenceFromPaths, 0.4},
+	{repositories.KnowledgeCooccurrenceFromLearners, 0.6},
+}
+
+const (
+	// maxKnowledgeRelationNoteLength 相关链接说明的最大长度
+	maxKnowledgeRelationNoteLength = 255
+	// maxKnowledgeRelatedLimit 单次返回的最大推荐数
+	maxKnowledgeRelatedLimit = 50
+)
+
+// KnowledgeRelatedReason 推荐理由类型
+type KnowledgeRelatedReason string
+
+const (
+	KnowledgeRelatedCurated  KnowledgeRelatedReason = "curated"               // 作者维护的相关链接
+	KnowledgeRelatedPaths    KnowledgeRelatedReason = "learning_paths"        // 经常出现在同一学习路径中
+	KnowledgeRelatedLearners KnowledgeRelatedReason = "learners_also_studied" // 学习过该知识点的学习者也学习了
+)
+
+// KnowledgeRelationConfig 相关知识点推荐配置
+type KnowledgeRelationConfig struct {
+	MinSupport int // 共现次数少于该值的知识点对不参与推荐，避免个别记录产生噪声
+	TopK       int // 每个知识点保留的计算推荐数
+}
+
+// KnowledgeRelatedItem 一条相关知识点推荐
+type KnowledgeRelatedItem struct {
+	Point        *entities.KnowledgePoint
+	Reason       KnowledgeRelatedReason
+	ReasonText   string
+	Score        float64
+	PathCount    int
+	LearnerCount int
+}
+
+// KnowledgeRelationService 相关知识点服务
+// 作者维护的相关链接优先，其后是由学习路径和学习记录共现计算的推荐，计算由后台定期执行
+type KnowledgeRelationService struct {
+	knowledgeRepo repositories.KnowledgePointRepository
+	relationRepo  repositories.KnowledgeRelationRepository
+	config        KnowledgeRelationConfig
+	startOnce     sync.Once
+}
+
+// NewKnowledgeRelationService 创建相关知识点服务
+func NewKnowledgeRelationService(
+	knowledgeRepo repositories.KnowledgePointRepository,
+	relationRepo repositories.KnowledgeRelationRepository,
+	config KnowledgeRelationConfig,
+) *KnowledgeRelationService {
+	if config.MinSupport <= 0 {
+		config.MinSupport = 2
+	}
+	if config.TopK <= 0 {
+		config.TopK = 20
+	}
+
+	return &KnowledgeRelationService{
+		knowledgeRepo: knowledgeRepo,
+		relationRepo:  relationRepo,
+		config:        config,
+	}
+}
+
+// Start 启动后台定期重新计算共现推荐，启动时先计算一次，重复调用只启动一次
+func (s *KnowledgeRelationService) Start(ctx context.Context, interval time.Duration) {
+	s.startOnce.Do(func() {
+		go func() {
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			for {
+				if _, err := s.Recompute(ctx); err != nil {
+					logger.Error("计算相关知识点推荐失败", logger.String("error", err.Error()))
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+				}
+			}
+		}()
+	})
+}
+
+// Related 获取知识点的相关推荐，未发布的知识点只有作者和审核员可以查看，推荐中也只对其包含未发布的知识点
+func (s *KnowledgeRelationService) Related(ctx context.Context, pointID uuid.UUID, editor KnowledgeEditor, limit int) ([]*KnowledgeRelatedItem, error) {
+	point, err := s.knowledgeRepo.GetByID(ctx, pointID)
+	if err != nil || (!point.IsPublished() && !editor.IsEditorial()) {
+		return nil, apperrors.New(apperrors.ErrorTypeNotFound, 404, "知识点不存在").WithCause(err)
+	}
+	if limit <= 0 || limit > maxKnowledgeRelatedLimit {
+		limit = maxKnowledgeRelatedLimit
+	}
+
+	visible := func(related *entities.KnowledgePoint) bool {
+		return related != nil && related.ID != pointID && (related.IsPublished() || editor.IsEditorial())
+	}
+	seen := map[uuid.UUID]bool{}
+	items := []*KnowledgeRelatedItem{}
+
+	relations, err := s.relationRepo.ListRelations(ctx, pointID)
+	if err != nil {
+		return nil, err
+	}
+	for _, relation := range relations {
+		if len(items) >= limit || !visible(relation.Related) || seen[relation.RelatedID] {
+			continue
+		}
+		seen[relation.RelatedID] = true
+		reasonText := relation.Note
+		if reasonText == "" {
+			reasonText = "作者推荐的相关知识点"
+		}
+		items = append(items, &KnowledgeRelatedItem{
+			Point:      relation.Related,
+			Reason:     KnowledgeRelatedCurated,
+			ReasonText: reasonText,
+		})
+	}
+	if len(items) >= limit {
+		return items, nil
+	}
+
+	cooccurrences, err := s.relationRepo.ListCooccurrences(ctx, pointID, limit+len(items))
+	if err != nil {
+		return nil, err
+	}
+	for _, cooccurrence := range cooccurrences {
+		if len(items) >= limit {
+			break
+		}
+		if !visible(cooccurrence.Related) || seen[cooccurrence.RelatedID] {
+			continue
+		}
+		seen[cooccurrence.RelatedID] = true
+		item := &KnowledgeRelatedItem{
+			Point:        cooccurrence.Related,
+			Score:        cooccurrence.Score,
+			PathCount:    cooccurrence.PathCount,
+			LearnerCount: cooccurrence.LearnerCount,
+		}
+		if cooccurrence.LearnerCount >= cooccurrence.PathCount {
+			item.Reason = KnowledgeRelatedLearners
+			item.ReasonText = fmt.Sprintf("%d位学习过该知识点的学习者也学习了它", cooccurrence.LearnerCount)
+		} else {
+			item.Reason = KnowledgeRelatedPaths
+			item.ReasonText = fmt.Sprintf("%d条学习路径同时包含这两个知识点", cooccurrence.PathCount)
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// AddRelation 添加作者维护的双向相关链接，需要作者权限
+func (s *KnowledgeRelationService) AddRelation(ctx context.Context, pointID, relatedID uuid.UUID, note string, editor KnowledgeEditor) (*entities.KnowledgeRelation, error) {
+	if !editor.Author {
+		return nil, apperrors.New(apperrors.ErrorTypeForbidden, 403, "只有作者可以维护相关知识点")
+	}
+	if pointID == relatedID {
+		return nil, apperrors.New(apperrors.ErrorTypeValidation, 400, "不能将知识点关联到自身")
+	}
+	note = strings.TrimSpace(note)
+	if utf8.RuneCountInString(note) > maxKnowledgeRelationNoteLength {
+		return nil, apperrors.New(apperrors.ErrorTypeValidation, 400,
+			fmt.Sprintf("关联说明不能超过%d个字符", maxKnowledgeRelationNoteLength))
+	}
+	if _, err := s.knowledgeRepo.GetByID(ctx, pointID); err != nil {
+		return nil, apperrors.New(apperrors.ErrorTypeNotFound, 404, "知识点不存在").WithCause(err)
+	}
+	related, err := s.knowledgeRepo.GetByID(ctx, relatedID)
+	if err != nil {
+		return nil, apperrors.New(apperrors.ErrorTypeNotFound, 404, "相关知识点不存在").WithCause(err)
+	}
+
+	relation := &entities.KnowledgeRelation{
+		ID:               uuid.New(),
+		KnowledgePointID: pointID,
+		RelatedID:        relatedID,
+		Note:             note,
+		CreatedBy:        editor.UserID,
+	}
+	created, err := s.relationRepo.CreateRelation(ctx, relation)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, apperrors.New(apperrors.ErrorTypeConflict, 409, "相关链接已存在")
+	}
+	relation.Related = related
+	return relation, nil
+}
+
+// RemoveRelation 删除作者维护的双向相关链接，需要作者权限
+func (s *KnowledgeRelationService) RemoveRelation(ctx context.Context, pointID, relatedID uuid.UUID, editor KnowledgeEditor) error {
+	if !editor.Author {
+		return apperrors.New(apperrors.ErrorTypeForbidden, 403, "只有作者可以维护相关知识点")
+	}
+	deleted, err := s.relationRepo.DeleteRelation(ctx, pointID, relatedID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return apperrors.New(apperrors.ErrorTypeNotFound, 404, "相关链接不存在")
+	}
+	return nil
+}
+
+// Recompute 重新计算全部共现推荐并替换已有结果，返回保存的推荐数
+// 相似度为物品协同过滤的余弦相似度：共现次数 / sqrt(知识点A出现次数 × 知识点B出现次数)，各来源按权重相加
+func (s *KnowledgeRelationService) Recompute(ctx context.Context) (int, error) {
+	type pairKey struct{ point, related uuid.UUID }
+	scores := map[pairKey]*entities.KnowledgeCooccurrence{}
+	computedAt := time.Now()
+
+	for _, source := range knowledgeCooccurrenceWeights {
+		counts, err := s.relationRepo.CountCooccurrences(ctx, source.source, s.config.MinSupport)
+		if err != nil {
+			return 0, err
+		}
+		for _, pair := range counts.Pairs {
+			denominator := math.Sqrt(float64(counts.Items[pair.KnowledgePointID]) * float64(counts.Items[pair.RelatedID]))
+			if denominator == 0 {
+				continue
+			}
+			key := pairKey{pair.KnowledgePointID, pair.RelatedID}
+			entry, ok := scores[key]
+			if !ok {
+				entry = &entities.KnowledgeCooccurrence{
+					KnowledgePointID: pair.KnowledgePointID,
+					RelatedID:        pair.RelatedID,
+					ComputedAt:       computedAt,
+				}
+				scores[key] = entry
+			}
+			entry.Score += source.weight * float64(pair.Count) / denominator
+			if source.source == repositories.KnowledgeCooccurrenceFromPaths {
+				entry.PathCount = pair.Count
+			} else {
+				entry.LearnerCount = pair.Count
+			}
+		}
+	}
+
+	byPoint := map[uuid.UUID][]*entities.KnowledgeCooccurrence{}
+	for _, entry := range scores {
+		byPoint[entry.KnowledgePointID] = append(byPoint[entry.KnowledgePointID], entry)
+	}
+	var cooccurrences []*entities.KnowledgeCooccurrence
+	for _, entries := range byPoint {
+		sort.Slice(entries, func(i, j int) bool {
+			if entries[i].Score != entries[j].Score {
+				return entries[i].Score > entries[j].Score
+			}
+			return entries[i].RelatedID.String() < entries[j].RelatedID.String()
+		})
+		if len(entries) > s.config.TopK {
+			entries = entries[:s.config.TopK]
+		}
+		cooccurrences = append(cooccurrences, entries...)
+	}
+
+	if err := s.relationRepo.ReplaceCooccurrences(ctx, cooccurrences); err != nil {
+		return 0, err
+	}
+	logger.Info("相关知识点推荐计算完成",
+		logger.Int("points", len(byPoint)),
+		logger.Int("recommendations", len(cooccurrences)))
+	return len(cooccurrences), nil
+}
@@ -0,0 +1,118 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+	apperrors "sical-go-backend/pkg/errors"
+	"sical-go-backend/pkg/logger"
+)
+
+// knowledgeRendererVersion 渲染规则版本，修改Markdown扩展或HTML白名单后递增，使已有缓存失效
+const knowledgeRendererVersion = 1
+
+// KnowledgeRenderService 知识点正文渲染服务
+// 渲染结果按正文哈希缓存，正文修改后首次读取时重新渲染，不需要在各个修改入口维护缓存
+type KnowledgeRenderService struct {
+	knowledgeRepo repositories.KnowledgePointRepository
+	renderingRepo repositories.KnowledgeRenderingRepository
+}
+
+// NewKnowledgeRenderService 创建知识点正文渲染服务
+func NewKnowledgeRenderService(
+	knowledgeRepo repositories.KnowledgePointRepository,
+	renderingRepo repositories.KnowledgeRenderingRepository,
+) *KnowledgeRenderService {
+	return &KnowledgeRenderService{
+		knowledgeRepo: knowledgeRepo,
+		renderingRepo: renderingRepo,
+	}
+}
+
+// Rendered 获取知识点正文的渲染结果，未发布的知识点只有作者和审核员可以查看
+func (s *KnowledgeRenderService) Rendered(ctx context.Context, pointID uuid.UUID, editor KnowledgeEditor) (*entities.KnowledgePoint, *entities.KnowledgePointRendering, error) {
+	point, err := s.knowledgeRepo.GetByID(ctx, pointID)
+	if err != nil || (!point.IsPublished() && !editor.IsEditorial()) {
+		return nil, nil, apperrors.New(apperrors.ErrorTypeNotFound, 404, "知识点不存在").WithCause(err)
+	}
+	renderings, err := s.Renderings(ctx, []*entities.KnowledgePoint{point})
+	if err != nil {
+		return nil, nil, err
+	}
+	return point, renderings[point.ID], nil
+}
+
+// Renderings 批量获取知识点正文的渲染结果，缓存缺失或过期的重新渲染并保存
+// 保存缓存失败只记录日志，不影响返回的渲染结果
+func (s *KnowledgeRenderService) Renderings(ctx context.Context, points []*entities.KnowledgePoint) (map[uuid.UUID]*entities.KnowledgePointRendering, error) {
+	ids := make([]uuid.UUID, 0, len(points))
+	for _, point := range points {
+		ids = append(ids, point.ID)
+	}
+	cached, err := s.renderingRepo.ListByPoints(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byPoint := make(map[uuid.UUID]*entities.KnowledgePointRendering, len(points))
+	for _, rendering := range cached {
+		byPoint[rendering.KnowledgePointID] = rendering
+	}
+
+	for _, point := range points {
+		hash := knowledgeContentHash(point.Content)
+		if rendering, ok := byPoint[point.ID]; ok && rendering.ContentHash == hash && rendering.RendererVersion == knowledgeRendererVersion {
+			continue
+		}
+
+		content, err := RenderKnowledgeContent(point.Content)
+		if err != nil {
+			return nil, err
+		}
+		toc, err := json.Marshal(content.TOC)
+		if err != nil {
+			return nil, err
+		}
+		rendering := &entities.KnowledgePointRendering{
+			KnowledgePointID: point.ID,
+			ContentHash:      hash,
+			RendererVersion:  knowledgeRendererVersion,
+			HTML:             content.HTML,
+			TOC:              string(toc),
+			WordCount:        content.WordCount,
+			ReadingMinutes:   content.ReadingMinutes,
+			RenderedAt:       time.Now(),
+		}
+		if err := s.renderingRepo.Save(ctx, rendering); err != nil {
+			logger.Warn("保存知识点渲染缓存失败",
+				logger.String("knowledge_point_id", point.ID.String()),
+				logger.String("error", err.Error()))
+		}
+		byPoint[point.ID] = rendering
+	}
+	return byPoint, nil
+}
+
+// ReadingMinutes 批量获取知识点正文的预计阅读分钟数，正文为空的知识点为0
+func (s *KnowledgeRenderService) ReadingMinutes(ctx context.Context, points []*entities.KnowledgePoint) (map[uuid.UUID]int, error) {
+	renderings, err := s.Renderings(ctx, points)
+	if err != nil {
+		return nil, err
+	}
+	minutes := make(map[uuid.UUID]int, len(renderings))
+	for id, rendering := range renderings {
+		minutes[id] = rendering.ReadingMinutes
+	}
+	return minutes, nil
+}
+
+// knowledgeContentHash 计算正文的SHA-256
+func knowledgeContentHash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
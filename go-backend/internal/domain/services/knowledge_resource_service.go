@@ -0,0 +1,450 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+	apperrors "sical-go-backend/pkg/errors"
+	"sical-go-backend/pkg/logger"
+)
+
+// knowledgeUploadTypes 允许上传的文件类型，按文件内容识别，值为对应的资源类型和扩展名
+var knowledgeUploadTypes = map[string][2]string{
+	"image/png":       {string(entities.KnowledgeResourceImage), ".png"},
+	"image/jpeg":      {string(entities.KnowledgeResourceImage), ".jpg"},
+	"image/gif":       {string(entities.KnowledgeResourceImage), ".gif"},
+	"image/webp":      {string(entities.KnowledgeResourceImage), ".webp"},
+	"application/pdf": {string(entities.KnowledgeResourceDocument), ".pdf"},
+}
+
+// BlobStorage 文件存储，storage.Local实现了该接口
+type BlobStorage interface {
+	Put(ctx context.Context, key string, r io.Reader) error
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// KnowledgeResourceConfig 知识点资源配置
+type KnowledgeResourceConfig struct {
+	SigningKey    []byte        // 下载链接的签名密钥，为空时使用随机密钥，重启后已签发的链接失效
+	URLExpiration time.Duration // 下载链接有效期
+	DownloadPath  string        // 下载接口的路径前缀，后接资源ID
+	MaxUploadSize int64         // 上传文件的最大字节数
+}
+
+// KnowledgeResourceInput 创建或修改资源的字段
+type KnowledgeResourceInput struct {
+	Type        string
+	Title       string
+	Description string
+	URL         string
+	Data        json.RawMessage // 图表数据、3D模型参数等，须为JSON对象或数组
+	Author      string
+	Source      string
+	Year        *int
+}
+
+// KnowledgeUpload 上传的文件
+type KnowledgeUpload struct {
+	FileName string
+	Reader   io.Reader
+}
+
+// KnowledgeResourceService 知识点结构化资源服务
+// 资源属于知识点内容，只有作者可以修改草稿的资源；上传的文件通过带签名、会过期的链接下载
+type KnowledgeResourceService struct {
+	knowledgeRepo repositories.KnowledgePointRepository
+	resourceRepo  repositories.KnowledgeResourceRepository
+	storage       BlobStorage
+	config        KnowledgeResourceConfig
+}
+
+// NewKnowledgeResourceService 创建知识点资源服务，storage为空时不支持上传文件
+func NewKnowledgeResourceService(
+	knowledgeRepo repositories.KnowledgePointRepository,
+	resourceRepo repositories.KnowledgeResourceRepository,
+	storage BlobStorage,
+	config KnowledgeResourceConfig,
+) *KnowledgeResourceService {
+	if len(config.SigningKey) == 0 {
+		config.SigningKey = make([]byte, 32)
+		if _, err := rand.Read(config.SigningKey); err != nil {
+			panic(fmt.Sprintf("生成下载链接签名密钥失败: %v", err))
+		}
+		logger.Warn("未配置下载链接签名密钥，使用随机密钥，重启后已签发的下载链接失效")
+	}
+	if config.URLExpiration <= 0 {
+		config.URLExpiration = 15 * time.Minute
+	}
+	if config.MaxUploadSize <= 0 {
+		config.MaxUploadSize = 10 << 20
+	}
+
+	return &KnowledgeResourceService{
+		knowledgeRepo: knowledgeRepo,
+		resourceRepo:  resourceRepo,
+		storage:       storage,
+		config:        config,
+	}
+}
+
+// MaxUploadSize 上传文件的最大字节数
+func (s *KnowledgeResourceService) MaxUploadSize() int64 {
+	return s.config.MaxUploadSize
+}
+
+// List 获取知识点的资源，未发布的知识点只有作者和审核员可以查看
+func (s *KnowledgeResourceService) List(ctx context.Context, pointID uuid.UUID, editor KnowledgeEditor) ([]*entities.KnowledgeResource, error) {
+	point, err := s.knowledgeRepo.GetByID(ctx, pointID)
+	if err != nil || (!point.IsPublished() && !editor.IsEditorial()) {
+		return nil, apperrors.New(apperrors.ErrorTypeNotFound, 404, "知识点不存在").WithCause(err)
+	}
+	return s.resourceRepo.ListByPoint(ctx, pointID)
+}
+
+// Create 为知识点添加外部链接、参考文献或内嵌数据类资源
+func (s *KnowledgeResourceService) Create(ctx context.Context, pointID uuid.UUID, editor KnowledgeEditor, input KnowledgeResourceInput) (*entities.KnowledgeResource, error) {
+	if _, err := s.loadEditablePoint(ctx, pointID, editor); err != nil {
+		return nil, err
+	}
+
+	resource := &entities.KnowledgeResource{
+		ID:               uuid.New(),
+		KnowledgePointID: pointID,
+		LinkStatus:       string(entities.KnowledgeLinkUnchecked),
+		CreatedBy:        editor.UserID,
+	}
+	if err := applyKnowledgeResourceInput(resource, input); err != nil {
+		return nil, err
+	}
+	if err := s.resourceRepo.Create(ctx, resource); err != nil {
+		return nil, err
+	}
+	return resource, nil
+}
+
+// Upload 上传图片或PDF并添加为资源，文件类型按内容识别，与声明的资源类型不符时拒绝
+func (s *KnowledgeResourceService) Upload(ctx context.Context, pointID uuid.UUID, editor KnowledgeEditor, input KnowledgeResourceInput, upload KnowledgeUpload) (*entities.KnowledgeResource, error) {
+	if s.storage == nil {
+		return nil, apperrors.New(apperrors.ErrorTypeBusiness, 400, "未配置文件存储，暂不支持上传")
+	}
+	if _, err := s.loadEditablePoint(ctx, pointID, editor); err != nil {
+		return nil, err
+	}
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(upload.Reader, head)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return nil, apperrors.New(apperrors.ErrorTypeValidation, 400, "上传文件为空").WithCause(err)
+	}
+	head = head[:n]
+	contentType, _, _ := mime.ParseMediaType(http.DetectContentType(head))
+	kind, ok := knowledgeUploadTypes[contentType]
+	if !ok {
+		return nil, apperrors.New(apperrors.ErrorTypeValidation, 400, "只支持上传PNG、JPEG、GIF、WebP图片和PDF文档").
+			WithDetail("content_type", contentType)
+	}
+	if input.Type == "" {
+		input.Type = kind[0]
+	} else if input.Type != kind[0] {
+		return nil, apperrors.New(apperrors.ErrorTypeValidation, 400, "上传的文件与资源类型不符").
+			WithDetail("type", input.Type).
+			WithDetail("content_type", contentType)
+	}
+	fileName := path.Base(strings.ReplaceAll(upload.FileName, `\`, "/"))
+	if input.Title == "" {
+		input.Title = strings.TrimSuffix(fileName, path.Ext(fileName))
+	}
+	if input.URL != "" {
+		return nil, apperrors.New(apperrors.ErrorTypeValidation, 400, "上传文件的资源不能同时设置外部链接")
+	}
+
+	resource := &entities.KnowledgeResource{
+		ID:               uuid.New(),
+		KnowledgePointID: pointID,
+		StorageKey:       fmt.Sprintf("knowledge/%s/%s%s", pointID, uuid.New(), kind[1]),
+		FileName:         truncateRunes(fileName, 255),
+		ContentType:      contentType,
+		LinkStatus:       string(entities.KnowledgeLinkUnchecked),
+		CreatedBy:        editor.UserID,
+	}
+	if err := applyKnowledgeResourceInput(resource, input); err != nil {
+		return nil, err
+	}
+
+	reader := &countingReader{r: io.LimitReader(io.MultiReader(bytes.NewReader(head), upload.Reader), s.config.MaxUploadSize+1)}
+	if err := s.storage.Put(ctx, resource.StorageKey, reader); err != nil {
+		return nil, fmt.Errorf("保存上传文件失败: %w", err)
+	}
+	if reader.n > s.config.MaxUploadSize {
+		s.deleteBlob(ctx, resource.StorageKey)
+		return nil, apperrors.New(apperrors.ErrorTypeValidation, 413, fmt.Sprintf("上传文件不能超过%dMB", s.config.MaxUploadSize>>20))
+	}
+	resource.Size = reader.n
+
+	if err := s.resourceRepo.Create(ctx, resource); err != nil {
+		s.deleteBlob(ctx, resource.StorageKey)
+		return nil, err
+	}
+	return resource, nil
+}
+
+// Update 修改资源，外部链接变化时重置链接检查状态；上传文件的资源不能修改类型
+func (s *KnowledgeResourceService) Update(ctx context.Context, pointID, resourceID uuid.UUID, editor KnowledgeEditor, input KnowledgeResourceInput) (*entities.KnowledgeResource, error) {
+	if _, err := s.loadEditablePoint(ctx, pointID, editor); err != nil {
+		return nil, err
+	}
+	resource, err := s.loadResource(ctx, pointID, resourceID)
+	if err != nil {
+		return nil, err
+	}
+	if resource.IsUpload() && input.Type != resource.Type {
+		return nil, apperrors.New(apperrors.ErrorTypeValidation, 400, "上传文件的资源不能修改类型")
+	}
+
+	if err := applyKnowledgeResourceInput(resource, input); err != nil {
+		return nil, err
+	}
+	if err := s.resourceRepo.Update(ctx, resource); err != nil {
+		return nil, err
+	}
+	return resource, nil
+}
+
+// Delete 删除资源及其上传的文件
+func (s *KnowledgeResourceService) Delete(ctx context.Context, pointID, resourceID uuid.UUID, editor KnowledgeEditor) error {
+	if _, err := s.loadEditablePoint(ctx, pointID, editor); err != nil {
+		return err
+	}
+	resource, err := s.loadResource(ctx, pointID, resourceID)
+	if err != nil {
+		return err
+	}
+	if err := s.resourceRepo.Delete(ctx, resource.ID); err != nil {
+		return err
+	}
+	if resource.IsUpload() {
+		s.deleteBlob(ctx, resource.StorageKey)
+	}
+	return nil
+}
+
+// Reorder 调整资源顺序，ids须恰好包含知识点的全部资源
+func (s *KnowledgeResourceService) Reorder(ctx context.Context, pointID uuid.UUID, editor KnowledgeEditor, ids []uuid.UUID) ([]*entities.KnowledgeResource, error) {
+	if _, err := s.loadEditablePoint(ctx, pointID, editor); err != nil {
+		return nil, err
+	}
+	resources, err := s.resourceRepo.ListByPoint(ctx, pointID)
+	if err != nil {
+		return nil, err
+	}
+
+	remaining := make(map[uuid.UUID]bool, len(resources))
+	for _, resource := range resources {
+		remaining[resource.ID] = true
+	}
+	for _, id := range ids {
+		if !remaining[id] {
+			return nil, apperrors.New(apperrors.ErrorTypeValidation, 400, "资源列表包含未知或重复的资源").WithDetail("id", id.String())
+		}
+		delete(remaining, id)
+	}
+	if len(remaining) > 0 {
+		return nil, apperrors.New(apperrors.ErrorTypeValidation, 400, "资源列表须包含知识点的全部资源")
+	}
+
+	if err := s.resourceRepo.Reorder(ctx, pointID, ids); err != nil {
+		return nil, err
+	}
+	return s.resourceRepo.ListByPoint(ctx, pointID)
+}
+
+// ListBroken 分页获取失效的外部链接，只有作者和审核员可以查看
+func (s *KnowledgeResourceService) ListBroken(ctx context.Context, editor KnowledgeEditor, offset, limit int) ([]*entities.KnowledgeResource, int64, error) {
+	if !editor.IsEditorial() {
+		return nil, 0, apperrors.New(apperrors.ErrorTypeForbidden, 403, "只有作者或审核员可以查看失效链接")
+	}
+	return s.resourceRepo.ListBroken(ctx, offset, limit)
+}
+
+// DownloadURL 生成上传文件的签名下载链接和过期时间
+func (s *KnowledgeResourceService) DownloadURL(resource *entities.KnowledgeResource) (string, time.Time) {
+	expiresAt := time.Now().Add(s.config.URLExpiration).Truncate(time.Second)
+	expires := strconv.FormatInt(expiresAt.Unix(), 10)
+	query := url.Values{}
+	query.Set("expires", expires)
+	query.Set("signature", s.sign(resource.ID, expires))
+	return fmt.Sprintf("%s/%s?%s", s.config.DownloadPath, resource.ID, query.Encode()), expiresAt
+}
+
+// OpenDownload 校验下载链接的签名和有效期，返回资源和文件内容，调用方负责关闭
+func (s *KnowledgeResourceService) OpenDownload(ctx context.Context, resourceID uuid.UUID, expires, signature string) (*entities.KnowledgeResource, io.ReadCloser, error) {
+	if !hmac.Equal([]byte(s.sign(resourceID, expires)), []byte(signature)) {
+		return nil, nil, apperrors.New(apperrors.ErrorTypeForbidden, 403, "下载链接无效")
+	}
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > expiresAt {
+		return nil, nil, apperrors.New(apperrors.ErrorTypeForbidden, 410, "下载链接已过期")
+	}
+
+	resource, err := s.resourceRepo.GetByID(ctx, resourceID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if resource == nil || !resource.IsUpload() || s.storage == nil {
+		return nil, nil, apperrors.New(apperrors.ErrorTypeNotFound, 404, "文件不存在")
+	}
+	file, err := s.storage.Open(ctx, resource.StorageKey)
+	if err != nil {
+		return nil, nil, apperrors.New(apperrors.ErrorTypeNotFound, 404, "文件不存在").WithCause(err)
+	}
+	return resource, file, nil
+}
+
+// sign 计算资源ID和过期时间的签名
+func (s *KnowledgeResourceService) sign(resourceID uuid.UUID, expires string) string {
+	mac := hmac.New(sha256.New, s.config.SigningKey)
+	mac.Write([]byte(resourceID.String() + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// loadEditablePoint 获取可修改资源的知识点，需要作者权限且知识点为草稿
+func (s *KnowledgeResourceService) loadEditablePoint(ctx context.Context, pointID uuid.UUID, editor KnowledgeEditor) (*entities.KnowledgePoint, error) {
+	if !editor.Author {
+		return nil, apperrors.New(apperrors.ErrorTypeForbidden, 403, "只有作者可以修改知识点资源")
+	}
+	point, err := s.knowledgeRepo.GetByID(ctx, pointID)
+	if err != nil {
+		return nil, apperrors.New(apperrors.ErrorTypeNotFound, 404, "知识点不存在").WithCause(err)
+	}
+	if !point.IsDraft() {
+		return nil, apperrors.New(apperrors.ErrorTypeConflict, 409, "只有草稿可以修改资源，请先撤回为草稿").
+			WithDetail("status", point.Status)
+	}
+	return point, nil
+}
+
+// loadResource 获取知识点下的资源，不存在时返回NotFound错误
+func (s *KnowledgeResourceService) loadResource(ctx context.Context, pointID, resourceID uuid.UUID) (*entities.KnowledgeResource, error) {
+	resource, err := s.resourceRepo.GetByID(ctx, resourceID)
+	if err != nil {
+		return nil, err
+	}
+	if resource == nil || resource.KnowledgePointID != pointID {
+		return nil, apperrors.New(apperrors.ErrorTypeNotFound, 404, "资源不存在")
+	}
+	return resource, nil
+}
+
+// deleteBlob 删除上传的文件，失败时只记录日志
+func (s *KnowledgeResourceService) deleteBlob(ctx context.Context, key string) {
+	if err := s.storage.Delete(ctx, key); err != nil {
+		logger.Warn("删除上传文件失败", logger.String("key", key), logger.String("error", err.Error()))
+	}
+}
+
+// applyKnowledgeResourceInput 校验输入并写入资源
+// 参考文献只需标题；图表和3D模型需要链接或内嵌数据；图片和文档需要链接或上传的文件；其余类型需要链接
+func applyKnowledgeResourceInput(resource *entities.KnowledgeResource, input KnowledgeResourceInput) error {
+	resourceType := entities.KnowledgeResourceType(input.Type)
+	if !resourceType.IsValid() {
+		return apperrors.New(apperrors.ErrorTypeValidation, 400, "无效的资源类型").WithDetail("type", input.Type)
+	}
+	title := strings.TrimSpace(input.Title)
+	if title == "" || utf8.RuneCountInString(title) > 255 {
+		return apperrors.New(apperrors.ErrorTypeValidation, 400, "资源标题不能为空且不超过255个字符")
+	}
+
+	link := strings.TrimSpace(input.URL)
+	if link != "" {
+		parsed, err := url.Parse(link)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" || len(link) > 2048 {
+			return apperrors.New(apperrors.ErrorTypeValidation, 400, "资源链接须为http或https地址").WithDetail("url", link)
+		}
+		if resource.IsUpload() {
+			return apperrors.New(apperrors.ErrorTypeValidation, 400, "上传文件的资源不能同时设置外部链接")
+		}
+	}
+
+	data := "{}"
+	if trimmed := bytes.TrimSpace(input.Data); len(trimmed) > 0 && string(trimmed) != "null" {
+		if !json.Valid(trimmed) || (trimmed[0] != '{' && trimmed[0] != '[') {
+			return apperrors.New(apperrors.ErrorTypeValidation, 400, "资源数据须为JSON对象或数组")
+		}
+		data = string(trimmed)
+	}
+	if input.Year != nil && (*input.Year < 1000 || *input.Year > time.Now().Year()+1) {
+		return apperrors.New(apperrors.ErrorTypeValidation, 400, "无效的发表年份")
+	}
+
+	switch resourceType {
+	case entities.KnowledgeResourceReference:
+	case entities.KnowledgeResourceChart, entities.KnowledgeResourceModel3D:
+		if link == "" && data == "{}" {
+			return apperrors.New(apperrors.ErrorTypeValidation, 400, "图表和3D模型须提供链接或数据")
+		}
+	case entities.KnowledgeResourceImage, entities.KnowledgeResourceDocument:
+		if link == "" && !resource.IsUpload() {
+			return apperrors.New(apperrors.ErrorTypeValidation, 400, "图片和文档须提供链接或上传文件")
+		}
+	default:
+		if link == "" {
+			return apperrors.New(apperrors.ErrorTypeValidation, 400, "该类型的资源须提供链接")
+		}
+	}
+
+	if link != resource.URL {
+		resource.LinkStatus = string(entities.KnowledgeLinkUnchecked)
+		resource.LinkFailures = 0
+		resource.LinkError = ""
+		resource.LinkCheckedAt = nil
+	}
+	resource.Type = string(resourceType)
+	resource.Title = title
+	resource.Description = strings.TrimSpace(input.Description)
+	resource.URL = link
+	resource.Data = data
+	resource.Author = truncateRunes(strings.TrimSpace(input.Author), 255)
+	resource.Source = truncateRunes(strings.TrimSpace(input.Source), 255)
+	resource.Year = input.Year
+	return nil
+}
+
+// countingReader 统计已读取的字节数
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+// Read 实现io.Reader接口
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// truncateRunes 按字符截断字符串
+func truncateRunes(value string, limit int) string {
+	if utf8.RuneCountInString(value) <= limit {
+		return value
+	}
+	return string([]rune(value)[:limit])
+}
@@ -0,0 +1,264 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+	apperrors "sical-go-backend/pkg/errors"
+)
+
+// knowledgeFieldLabels 自动生成修改说明时使用的字段名称
+var knowledgeFieldLabels = map[string]string{
+	"title":         "标题",
+	"description":   "描述",
+	"content":       "正文",
+	"category":      "类别",
+	"difficulty":    "难度",
+	"resources":     "学习资源",
+	"prerequisites": "前置知识点",
+	"tags":          "标签",
+}
+
+// KnowledgeEditor 知识点的操作者，UserID为空表示由系统执行
+// Author为具有作者权限的用户，可创建和修改草稿；Reviewer为具有审核员权限的用户，可审核、发布和归档
+type KnowledgeEditor struct {
+	UserID   *uint
+	Author   bool
+	Reviewer bool
+}
+
+// IsEditorial 是否为作者或审核员，可查看未发布的知识点
+func (e KnowledgeEditor) IsEditorial() bool {
+	return e.Author || e.Reviewer
+}
+
+// Is 是否为指定用户
+func (e KnowledgeEditor) Is(userID *uint) bool {
+	return e.UserID != nil && userID != nil && *e.UserID == *userID
+}
+
+// KnowledgeFieldChange 两个版本之间单个字段的变化
+type KnowledgeFieldChange struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+// KnowledgeRevisionDiff 两个版本之间的字段级差异，只包含有变化的字段
+type KnowledgeRevisionDiff struct {
+	From    *entities.KnowledgePointRevision
+	To      *entities.KnowledgePointRevision
+	Changes []KnowledgeFieldChange
+}
+
+// KnowledgeRevisionService 知识点修订服务
+// 知识点的创建、修改和回滚都会生成不可修改的修订，修改时按版本号做乐观锁校验
+// 只有作者可以创建知识点，只有草稿状态的知识点可以修改或回滚
+type KnowledgeRevisionService struct {
+	knowledgeRepo repositories.KnowledgePointRepository
+	revisionRepo  repositories.KnowledgeRevisionRepository
+}
+
+// NewKnowledgeRevisionService 创建知识点修订服务
+func NewKnowledgeRevisionService(knowledgeRepo repositories.KnowledgePointRepository, revisionRepo repositories.KnowledgeRevisionRepository) *KnowledgeRevisionService {
+	return &KnowledgeRevisionService{
+		knowledgeRepo: knowledgeRepo,
+		revisionRepo:  revisionRepo,
+	}
+}
+
+// Create 以草稿状态创建知识点并记录版本1的修订
+func (s *KnowledgeRevisionService) Create(ctx context.Context, point *entities.KnowledgePoint, editor KnowledgeEditor) error {
+	if !editor.Author {
+		return apperrors.New(apperrors.ErrorTypeForbidden, 403, "只有作者可以创建知识点")
+	}
+	point.Version = 1
+	point.Status = string(entities.KnowledgeStatusDraft)
+	point.AuthorID = editor.UserID
+	revision := entities.NewKnowledgePointRevision(point, entities.KnowledgeRevisionCreate)
+	revision.AuthorID = editor.UserID
+	revision.ChangeSummary = "创建知识点"
+	return s.revisionRepo.CreatePoint(ctx, point, revision)
+}
+
+// Update 在预期版本上修改知识点，apply修改传入的知识点，返回错误时放弃修改
+// 内容没有变化时不生成新版本；summary为空时根据变化的字段生成修改说明
+func (s *KnowledgeRevisionService) Update(ctx context.Context, id uuid.UUID, expectedVersion int, editor KnowledgeEditor, summary string, apply func(point *entities.KnowledgePoint) error) (*entities.KnowledgePoint, error) {
+	point, err := s.loadEditablePoint(ctx, id, expectedVersion, editor)
+	if err != nil {
+		return nil, err
+	}
+
+	before := entities.NewKnowledgePointRevision(point, entities.KnowledgeRevisionUpdate)
+	if err := apply(point); err != nil {
+		return nil, err
+	}
+	return s.save(ctx, point, before, expectedVersion, editor, summary, nil)
+}
+
+// Restore 将知识点恢复为指定版本的内容，恢复结果作为新版本保存
+func (s *KnowledgeRevisionService) Restore(ctx context.Context, id uuid.UUID, version, expectedVersion int, editor KnowledgeEditor, summary string) (*entities.KnowledgePoint, error) {
+	point, err := s.loadEditablePoint(ctx, id, expectedVersion, editor)
+	if err != nil {
+		return nil, err
+	}
+	target, err := s.loadRevision(ctx, id, version)
+	if err != nil {
+		return nil, err
+	}
+
+	before := entities.NewKnowledgePointRevision(point, entities.KnowledgeRevisionUpdate)
+	target.ApplyTo(point)
+	if summary == "" {
+		summary = fmt.Sprintf("恢复到版本%d", version)
+	}
+	return s.save(ctx, point, before, expectedVersion, editor, summary, &version)
+}
+
+// ListRevisions 分页获取知识点的修订列表，列表中的修订不包含正文
+func (s *KnowledgeRevisionService) ListRevisions(ctx context.Context, id uuid.UUID, offset, limit int) ([]*entities.KnowledgePointRevision, int64, error) {
+	if _, err := s.loadPoint(ctx, id); err != nil {
+		return nil, 0, err
+	}
+	return s.revisionRepo.List(ctx, id, offset, limit)
+}
+
+// GetRevision 获取知识点指定版本的修订
+func (s *KnowledgeRevisionService) GetRevision(ctx context.Context, id uuid.UUID, version int) (*entities.KnowledgePointRevision, error) {
+	return s.loadRevision(ctx, id, version)
+}
+
+// Diff 比较知识点任意两个版本，返回字段级差异
+func (s *KnowledgeRevisionService) Diff(ctx context.Context, id uuid.UUID, fromVersion, toVersion int) (*KnowledgeRevisionDiff, error) {
+	from, err := s.loadRevision(ctx, id, fromVersion)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.loadRevision(ctx, id, toVersion)
+	if err != nil {
+		return nil, err
+	}
+	return &KnowledgeRevisionDiff{
+		From:    from,
+		To:      to,
+		Changes: diffKnowledgeRevisions(from, to),
+	}, nil
+}
+
+// save 比较修改前后的内容，有变化时校验版本号并保存为新版本
+func (s *KnowledgeRevisionService) save(ctx context.Context, point *entities.KnowledgePoint, before *entities.KnowledgePointRevision, expectedVersion int, editor KnowledgeEditor, summary string, restoredFrom *int) (*entities.KnowledgePoint, error) {
+	action := entities.KnowledgeRevisionUpdate
+	if restoredFrom != nil {
+		action = entities.KnowledgeRevisionRestore
+	}
+	revision := entities.NewKnowledgePointRevision(point, action)
+	changes := diffKnowledgeRevisions(before, revision)
+	if len(changes) == 0 {
+		return point, nil
+	}
+
+	summary, err := normalizeChangeSummary(summary, changes)
+	if err != nil {
+		return nil, err
+	}
+	revision.AuthorID = editor.UserID
+	revision.ChangeSummary = summary
+	revision.RestoredFrom = restoredFrom
+
+	updated, err := s.revisionRepo.UpdatePoint(ctx, point, expectedVersion, revision)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, knowledgeVersionConflict(expectedVersion)
+	}
+	return point, nil
+}
+
+// loadPoint 获取知识点，不存在时返回NotFound错误
+func (s *KnowledgeRevisionService) loadPoint(ctx context.Context, id uuid.UUID) (*entities.KnowledgePoint, error) {
+	point, err := s.knowledgeRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, apperrors.New(apperrors.ErrorTypeNotFound, 404, "知识点不存在").WithCause(err)
+	}
+	return point, nil
+}
+
+// loadEditablePoint 获取待修改的知识点，校验作者权限、草稿状态和版本号
+func (s *KnowledgeRevisionService) loadEditablePoint(ctx context.Context, id uuid.UUID, expectedVersion int, editor KnowledgeEditor) (*entities.KnowledgePoint, error) {
+	if !editor.Author {
+		return nil, apperrors.New(apperrors.ErrorTypeForbidden, 403, "只有作者可以修改知识点")
+	}
+	point, err := s.loadPoint(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !point.IsDraft() {
+		return nil, apperrors.New(apperrors.ErrorTypeConflict, 409, "只有草稿可以修改，请先撤回为草稿").
+			WithDetail("status", point.Status)
+	}
+	if err := checkKnowledgeVersion(point, expectedVersion); err != nil {
+		return nil, err
+	}
+	return point, nil
+}
+
+// loadRevision 获取知识点指定版本的修订，不存在时返回NotFound错误
+func (s *KnowledgeRevisionService) loadRevision(ctx context.Context, id uuid.UUID, version int) (*entities.KnowledgePointRevision, error) {
+	revision, err := s.revisionRepo.GetByVersion(ctx, id, version)
+	if err != nil {
+		return nil, err
+	}
+	if revision == nil {
+		return nil, apperrors.New(apperrors.ErrorTypeNotFound, 404, "修订版本不存在").WithDetail("version", strconv.Itoa(version))
+	}
+	return revision, nil
+}
+
+// checkKnowledgeVersion 校验客户端持有的版本号是否为知识点的当前版本
+func checkKnowledgeVersion(point *entities.KnowledgePoint, expectedVersion int) error {
+	if point.Version != expectedVersion {
+		return knowledgeVersionConflict(expectedVersion)
+	}
+	return nil
+}
+
+// knowledgeVersionConflict 知识点已被他人修改
+func knowledgeVersionConflict(expectedVersion int) error {
+	return apperrors.New(apperrors.ErrorTypeConflict, 412, "知识点已被修改，请获取最新版本后重试").
+		WithDetail("expected_version", strconv.Itoa(expectedVersion))
+}
+
+// diffKnowledgeRevisions 按字段比较两个修订的内容
+func diffKnowledgeRevisions(from, to *entities.KnowledgePointRevision) []KnowledgeFieldChange {
+	changes := []KnowledgeFieldChange{}
+	toFields := to.Fields()
+	for i, field := range from.Fields() {
+		if field[1] != toFields[i][1] {
+			changes = append(changes, KnowledgeFieldChange{Field: field[0], From: field[1], To: toFields[i][1]})
+		}
+	}
+	return changes
+}
+
+// normalizeChangeSummary 去除首尾空白并校验修改说明长度，为空时列出变化的字段
+func normalizeChangeSummary(summary string, changes []KnowledgeFieldChange) (string, error) {
+	summary = strings.TrimSpace(summary)
+	if summary == "" {
+		labels := make([]string, 0, len(changes))
+		for _, change := range changes {
+			labels = append(labels, knowledgeFieldLabels[change.Field])
+		}
+		return "修改" + strings.Join(labels, "、"), nil
+	}
+	if utf8.RuneCountInString(summary) > entities.MaxChangeSummaryLength {
+		return "", apperrors.New(apperrors.ErrorTypeValidation, 400, fmt.Sprintf("修改说明不能超过%d个字符", entities.MaxChangeSummaryLength))
+	}
+	return summary, nil
+}
@@ -0,0 +1,412 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+	apperrors "sical-go-backend/pkg/errors"
+	"sical-go-backend/pkg/logger"
+	"sical-go-backend/pkg/pinyin"
+)
+
+const (
+	// maxSearchKeywordLength 检索词的最大字符数
+	maxSearchKeywordLength = 100
+	// snippetLength 摘要的最大字符数
+	snippetLength = 160
+	// snippetLeading 摘要中首个命中位置之前保留的字符数
+	snippetLeading = 30
+)
+
+const (
+	highlightStart = "<mark>"
+	highlightEnd   = "</mark>"
+)
+
+const (
+	// maxSuggestKeywordLength 检索建议输入的最大字符数
+	maxSuggestKeywordLength = 50
+	// defaultSuggestLimit 默认返回的建议数
+	defaultSuggestLimit = 8
+	// maxSuggestLimit 最多返回的建议数
+	maxSuggestLimit = 20
+	// suggestCacheTTL 检索建议缓存时间，热门检索词的变化在过期后生效
+	suggestCacheTTL = 10 * time.Minute
+	// suggestCacheVersionKey 检索建议缓存版本号，知识点变更时递增使旧缓存失效
+	suggestCacheVersionKey = "knowledge:suggest:version"
+	// popularQueryWindow 统计热门检索词的时间范围
+	popularQueryWindow = 30 * 24 * time.Hour
+	// popularQueryLimit 参与加权的热门检索词数量
+	popularQueryLimit = 20
+	// prefixMatchBoost 前缀或拼音前缀命中的加分
+	prefixMatchBoost = 0.5
+	// popularityBoost 热门检索词命中的最高加分
+	popularityBoost = 0.3
+)
+
+// KnowledgeSearchHit 带高亮的知识点检索结果，高亮片段已做HTML转义，命中部分以<mark>标记
+type KnowledgeSearchHit struct {
+	Point          *entities.KnowledgePoint
+	Rank           float64
+	TitleHighlight string
+	Snippet        string
+}
+
+// KnowledgeSuggestItem 知识点检索建议
+type KnowledgeSuggestItem struct {
+	ID         uuid.UUID `json:"id"`
+	Title      string    `json:"title"`
+	Category   string    `json:"category"`
+	Difficulty string    `json:"difficulty"`
+	Score      float64   `json:"score"`
+}
+
+// KnowledgeSuggestResult 检索建议结果，包含以输入开头的热门检索词和匹配的知识点
+type KnowledgeSuggestResult struct {
+	Queries         []repositories.QueryPopularity `json:"queries"`
+	KnowledgePoints []*KnowledgeSuggestItem        `json:"knowledge_points"`
+}
+
+// KnowledgeSearchPage 知识点检索结果页
+type KnowledgeSearchPage struct {
+	Hits         []*KnowledgeSearchHit
+	Total        int64
+	Categories   []repositories.KnowledgeSearchFacet
+	Difficulties []repositories.KnowledgeSearchFacet
+}
+
+// SuggestionCache 检索建议缓存，cache.Redis实现了该接口
+type SuggestionCache interface {
+	GetJSON(ctx context.Context, key string, dest interface{}) error
+	SetJSON(ctx context.Context, key string, value interface{}, expiration time.Duration) error
+	Increment(ctx context.Context, key string) (int64, error)
+	IncrementBy(ctx context.Context, key string, value int64) (int64, error)
+}
+
+// KnowledgeSearchService 知识点全文检索服务，在数据库相关度排序的基础上生成高亮标题和摘要，并提供输入联想
+type KnowledgeSearchService struct {
+	knowledgeRepo repositories.KnowledgePointRepository
+	searchLogRepo repositories.SearchLogRepository
+	cache         SuggestionCache
+}
+
+// NewKnowledgeSearchService 创建知识点全文检索服务，cache为空时检索建议不缓存
+func NewKnowledgeSearchService(
+	knowledgeRepo repositories.KnowledgePointRepository,
+	searchLogRepo repositories.SearchLogRepository,
+	cache SuggestionCache,
+) *KnowledgeSearchService {
+	return &KnowledgeSearchService{
+		knowledgeRepo: knowledgeRepo,
+		searchLogRepo: searchLogRepo,
+		cache:         cache,
+	}
+}
+
+// Search 检索知识点，返回当前页结果、总数以及类别和难度分面
+func (s *KnowledgeSearchService) Search(ctx context.Context, query repositories.KnowledgeSearchQuery) (*KnowledgeSearchPage, error) {
+	query.Keyword = strings.TrimSpace(query.Keyword)
+	if query.Keyword == "" {
+		return nil, apperrors.New(apperrors.ErrorTypeValidation, 400, "缺少搜索关键词")
+	}
+	if utf8.RuneCountInString(query.Keyword) > maxSearchKeywordLength {
+		return nil, apperrors.New(apperrors.ErrorTypeValidation, 400, fmt.Sprintf("搜索关键词不能超过%d个字符", maxSearchKeywordLength))
+	}
+	if query.Difficulty != "" && !isValidDifficulty(query.Difficulty) {
+		return nil, apperrors.New(apperrors.ErrorTypeValidation, 400, "无效的难度值").
+			WithDetail("difficulty", query.Difficulty)
+	}
+
+	result, err := s.knowledgeRepo.Search(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if query.Offset == 0 {
+		s.logSearch(ctx, query.Keyword, result.Total)
+	}
+
+	terms := searchTerms(query.Keyword)
+	page := &KnowledgeSearchPage{
+		Hits:         make([]*KnowledgeSearchHit, 0, len(result.Hits)),
+		Total:        result.Total,
+		Categories:   result.Categories,
+		Difficulties: result.Difficulties,
+	}
+	for _, hit := range result.Hits {
+		body := strings.TrimSpace(hit.Point.Description + " " + hit.Point.Content)
+		page.Hits = append(page.Hits, &KnowledgeSearchHit{
+			Point:          hit.Point,
+			Rank:           hit.Rank,
+			TitleHighlight: highlight(hit.Point.Title, terms),
+			Snippet:        snippet(body, terms),
+		})
+	}
+	return page, nil
+}
+
+// Suggest 获取输入联想：标题前缀、拼音全拼或首字母、三元组模糊匹配的知识点，以及热门检索词
+// 结果按匹配度排序，前缀命中和包含热门检索词的知识点优先
+func (s *KnowledgeSearchService) Suggest(ctx context.Context, keyword string, limit int) (*KnowledgeSuggestResult, error) {
+	text := strings.ToLower(strings.TrimSpace(keyword))
+	if text == "" {
+		return nil, apperrors.New(apperrors.ErrorTypeValidation, 400, "缺少输入内容")
+	}
+	if utf8.RuneCountInString(text) > maxSuggestKeywordLength {
+		return nil, apperrors.New(apperrors.ErrorTypeValidation, 400, fmt.Sprintf("输入内容不能超过%d个字符", maxSuggestKeywordLength))
+	}
+	if limit <= 0 {
+		limit = defaultSuggestLimit
+	}
+	if limit > maxSuggestLimit {
+		limit = maxSuggestLimit
+	}
+
+	key := s.suggestCacheKey(ctx, text, limit)
+	if key != "" {
+		var cached KnowledgeSuggestResult
+		if err := s.cache.GetJSON(ctx, key, &cached); err == nil {
+			return &cached, nil
+		}
+	}
+
+	result, err := s.suggest(ctx, text, limit)
+	if err != nil {
+		return nil, err
+	}
+	if key != "" {
+		if err := s.cache.SetJSON(ctx, key, result, suggestCacheTTL); err != nil {
+			logger.Error("缓存检索建议失败", logger.String("error", err.Error()))
+		}
+	}
+	return result, nil
+}
+
+// InvalidateSuggestions 使全部检索建议缓存失效，知识点创建、更新或删除后调用
+func (s *KnowledgeSearchService) InvalidateSuggestions(ctx context.Context) {
+	if s.cache == nil {
+		return
+	}
+	if _, err := s.cache.Increment(ctx, suggestCacheVersionKey); err != nil {
+		logger.Error("清除检索建议缓存失败", logger.String("error", err.Error()))
+	}
+}
+
+// suggest 查询热门检索词和候选知识点，并按匹配度和热度重新排序
+func (s *KnowledgeSearchService) suggest(ctx context.Context, text string, limit int) (*KnowledgeSuggestResult, error) {
+	popular, err := s.searchLogRepo.PopularQueries(ctx, text, time.Now().Add(-popularQueryWindow), popularQueryLimit)
+	if err != nil {
+		return nil, err
+	}
+	result := &KnowledgeSuggestResult{
+		Queries:         popular,
+		KnowledgePoints: []*KnowledgeSuggestItem{},
+	}
+	if len(result.Queries) > limit {
+		result.Queries = result.Queries[:limit]
+	}
+
+	textPinyin, _ := pinyin.Convert(text)
+	if textPinyin == "" {
+		// 输入不含汉字、字母或数字，无法匹配标题
+		return result, nil
+	}
+	candidates, err := s.knowledgeRepo.Suggest(ctx, repositories.KnowledgeSuggestQuery{
+		Text:   text,
+		Pinyin: textPinyin,
+		Limit:  limit * 3,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var popularTotal int64
+	for _, query := range popular {
+		popularTotal += query.Count
+	}
+	for _, candidate := range candidates {
+		score := candidate.Similarity
+		if candidate.PrefixMatch {
+			score += prefixMatchBoost
+		}
+		if popularTotal > 0 {
+			score += popularityBoost * float64(popularHits(candidate.Title, popular)) / float64(popularTotal)
+		}
+		result.KnowledgePoints = append(result.KnowledgePoints, &KnowledgeSuggestItem{
+			ID:         candidate.ID,
+			Title:      candidate.Title,
+			Category:   candidate.Category,
+			Difficulty: candidate.Difficulty,
+			Score:      score,
+		})
+	}
+	sort.SliceStable(result.KnowledgePoints, func(i, j int) bool {
+		return result.KnowledgePoints[i].Score > result.KnowledgePoints[j].Score
+	})
+	if len(result.KnowledgePoints) > limit {
+		result.KnowledgePoints = result.KnowledgePoints[:limit]
+	}
+	return result, nil
+}
+
+// suggestCacheKey 生成检索建议缓存键，未启用缓存或读取版本号失败时返回空字符串
+func (s *KnowledgeSearchService) suggestCacheKey(ctx context.Context, text string, limit int) string {
+	if s.cache == nil {
+		return ""
+	}
+	// 递增0读取当前版本号，版本号不存在时初始化为0
+	version, err := s.cache.IncrementBy(ctx, suggestCacheVersionKey, 0)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("knowledge:suggest:%d:%d:%s", version, limit, text)
+}
+
+// logSearch 记录检索日志用于统计热门检索词，失败时不影响检索
+func (s *KnowledgeSearchService) logSearch(ctx context.Context, keyword string, total int64) {
+	log := &entities.SearchLog{
+		Query:       keyword,
+		Normalized:  strings.ToLower(keyword),
+		ResultCount: total,
+	}
+	if err := s.searchLogRepo.Create(ctx, log); err != nil {
+		logger.Error("记录检索日志失败", logger.String("error", err.Error()))
+	}
+}
+
+// popularHits 统计标题或其拼音中包含的热门检索词的检索次数之和
+func popularHits(title string, popular []repositories.QueryPopularity) int64 {
+	lowerTitle := strings.ToLower(title)
+	titlePinyin, _ := pinyin.Convert(title)
+	var hits int64
+	for _, query := range popular {
+		queryPinyin, _ := pinyin.Convert(query.Query)
+		if strings.Contains(lowerTitle, query.Query) || (queryPinyin != "" && strings.Contains(titlePinyin, queryPinyin)) {
+			hits += query.Count
+		}
+	}
+	return hits
+}
+
+// searchTerms 将检索词按空白拆分为小写的高亮词
+func searchTerms(keyword string) [][]rune {
+	var terms [][]rune
+	for _, field := range strings.Fields(strings.ToLower(keyword)) {
+		terms = append(terms, []rune(field))
+	}
+	return terms
+}
+
+// markMatches 标记文本中命中检索词的字符，整词未命中的中文检索词按相邻双字标记
+func markMatches(text []rune, terms [][]rune) []bool {
+	lower := []rune(strings.ToLower(string(text)))
+	marks := make([]bool, len(text))
+	for _, term := range terms {
+		if markTerm(lower, term, marks) || len(term) < 2 || !containsHan(term) {
+			continue
+		}
+		for i := 0; i+2 <= len(term); i++ {
+			markTerm(lower, term[i:i+2], marks)
+		}
+	}
+	return marks
+}
+
+// markTerm 标记词在文本中的全部出现位置，返回是否命中
+func markTerm(text, term []rune, marks []bool) bool {
+	found := false
+	for i := 0; i+len(term) <= len(text); i++ {
+		if runesEqual(text[i:i+len(term)], term) {
+			for j := i; j < i+len(term); j++ {
+				marks[j] = true
+			}
+			found = true
+		}
+	}
+	return found
+}
+
+// snippet 截取首个命中位置附近的正文作为摘要，未命中时取正文开头
+func snippet(body string, terms [][]rune) string {
+	text := []rune(body)
+	marks := markMatches(text, terms)
+	start := 0
+	for i, marked := range marks {
+		if marked {
+			start = i - snippetLeading
+			break
+		}
+	}
+	if start < 0 {
+		start = 0
+	}
+	end := start + snippetLength
+	if end > len(text) {
+		end = len(text)
+	}
+
+	result := renderHighlight(text[start:end], marks[start:end])
+	if start > 0 {
+		result = "…" + result
+	}
+	if end < len(text) {
+		result += "…"
+	}
+	return result
+}
+
+// highlight 标记文本中命中的检索词
+func highlight(value string, terms [][]rune) string {
+	text := []rune(value)
+	return renderHighlight(text, markMatches(text, terms))
+}
+
+// renderHighlight 转义文本并用<mark>包裹连续的命中字符
+func renderHighlight(text []rune, marks []bool) string {
+	var builder strings.Builder
+	for i := 0; i < len(text); {
+		j := i
+		for j < len(text) && marks[j] == marks[i] {
+			j++
+		}
+		segment := html.EscapeString(string(text[i:j]))
+		if marks[i] {
+			builder.WriteString(highlightStart + segment + highlightEnd)
+		} else {
+			builder.WriteString(segment)
+		}
+		i = j
+	}
+	return builder.String()
+}
+
+// containsHan 是否包含汉字
+func containsHan(term []rune) bool {
+	for _, r := range term {
+		if unicode.Is(unicode.Han, r) {
+			return true
+		}
+	}
+	return false
+}
+
+// runesEqual 比较两个字符序列是否相同
+func runesEqual(a, b []rune) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
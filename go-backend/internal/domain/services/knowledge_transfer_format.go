@@ -0,0 +1,434 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	apperrors "sical-go-backend/pkg/errors"
+)
+
+// KnowledgeTransferFormat 知识点批量导入导出的文件格式
+type KnowledgeTransferFormat string
+
+const (
+	KnowledgeFormatCSV      KnowledgeTransferFormat = "csv"
+	KnowledgeFormatJSONL    KnowledgeTransferFormat = "jsonl"
+	KnowledgeFormatMarkdown KnowledgeTransferFormat = "markdown" // 每个知识点一个带YAML front matter的Markdown文件，正文为知识点内容
+)
+
+// IsValid 是否为支持的格式
+func (f KnowledgeTransferFormat) IsValid() bool {
+	switch f {
+	case KnowledgeFormatCSV, KnowledgeFormatJSONL, KnowledgeFormatMarkdown:
+		return true
+	}
+	return false
+}
+
+// knowledgeCSVColumns CSV的列，导入时必须包含key和title列，其余列可省略
+// tags和prerequisites以|分隔，元素本身包含|时写为JSON数组；resources为JSON文本
+var knowledgeCSVColumns = []string{
+	"key", "title", "description", "category", "difficulty", "status",
+	"tags", "prerequisites", "resources", "content",
+}
+
+// knowledgeFrontMatterDelimiter Markdown front matter的分隔行
+const knowledgeFrontMatterDelimiter = "---"
+
+// KnowledgeRecord 批量导入导出的知识点记录
+// Key为稳定的外部标识，导入时按Key新建或更新知识点；Prerequisites为前置知识点的外部标识或标题
+// Status只在导出时填写，导入时忽略，编辑状态由审核流程维护
+type KnowledgeRecord struct {
+	Key           string          `json:"key"`
+	Title         string          `json:"title"`
+	Description   string          `json:"description"`
+	Category      string          `json:"category"`
+	Difficulty    string          `json:"difficulty"`
+	Status        string          `json:"status,omitempty"`
+	Tags          []string        `json:"tags"`
+	Prerequisites []string        `json:"prerequisites"`
+	Resources     json.RawMessage `json:"resources,omitempty"`
+	Content       string          `json:"content"`
+
+	line int    // 记录在CSV或JSON Lines中的起始行号
+	file string // 记录所在的Markdown文件
+}
+
+// issue 生成指向该记录的导入问题
+func (r *KnowledgeRecord) issue(field, message string) KnowledgeImportIssue {
+	return KnowledgeImportIssue{Line: r.line, File: r.file, Key: r.Key, Field: field, Message: message}
+}
+
+// KnowledgeImportIssue 导入时发现的问题，Line为CSV或JSON Lines中的行号，File为Markdown文件路径
+type KnowledgeImportIssue struct {
+	Line    int    `json:"line,omitempty"`
+	File    string `json:"file,omitempty"`
+	Key     string `json:"key,omitempty"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// KnowledgeImportBatch 解析后的导入记录和解析阶段发现的问题
+type KnowledgeImportBatch struct {
+	Records []*KnowledgeRecord
+	Issues  []KnowledgeImportIssue
+}
+
+// KnowledgeFile Markdown格式中的单个文件，Name为相对路径
+type KnowledgeFile struct {
+	Name string
+	Data []byte
+}
+
+// knowledgeFrontMatter Markdown文件的front matter
+type knowledgeFrontMatter struct {
+	Key           string      `yaml:"key"`
+	Title         string      `yaml:"title"`
+	Description   string      `yaml:"description,omitempty"`
+	Category      string      `yaml:"category"`
+	Difficulty    string      `yaml:"difficulty"`
+	Status        string      `yaml:"status,omitempty"`
+	Tags          []string    `yaml:"tags,omitempty"`
+	Prerequisites []string    `yaml:"prerequisites,omitempty"`
+	Resources     interface{} `yaml:"resources,omitempty"`
+}
+
+// DecodeKnowledgeCSV 解析CSV格式的知识点，首行为列名
+// 单行数据有误时记录问题并继续，列名或CSV结构无效时返回错误
+func DecodeKnowledgeCSV(r io.Reader) (*KnowledgeImportBatch, error) {
+	reader := csv.NewReader(r)
+	header, err := reader.Read()
+	if err != nil {
+		return nil, invalidKnowledgeFile("无法读取CSV列名", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if !isKnowledgeCSVColumn(name) {
+			return nil, apperrors.New(apperrors.ErrorTypeValidation, 400, fmt.Sprintf("未知的CSV列: %s", name)).
+				WithDetail("allowed", strings.Join(knowledgeCSVColumns, ","))
+		}
+		if _, ok := columns[name]; ok {
+			return nil, apperrors.New(apperrors.ErrorTypeValidation, 400, fmt.Sprintf("CSV列重复: %s", name))
+		}
+		columns[name] = i
+	}
+	for _, required := range []string{"key", "title"} {
+		if _, ok := columns[required]; !ok {
+			return nil, apperrors.New(apperrors.ErrorTypeValidation, 400, fmt.Sprintf("CSV缺少必需列: %s", required))
+		}
+	}
+
+	batch := &KnowledgeImportBatch{}
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line, _ := reader.FieldPos(0)
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) && errors.Is(parseErr.Err, csv.ErrFieldCount) {
+				batch.Issues = append(batch.Issues, KnowledgeImportIssue{Line: line, Message: "列数与列名不一致"})
+				continue
+			}
+			return nil, invalidKnowledgeFile("CSV格式无效", err)
+		}
+
+		cell := func(name string) string {
+			if i, ok := columns[name]; ok {
+				return row[i]
+			}
+			return ""
+		}
+		record := &KnowledgeRecord{
+			Key:         strings.TrimSpace(cell("key")),
+			Title:       cell("title"),
+			Description: cell("description"),
+			Category:    cell("category"),
+			Difficulty:  cell("difficulty"),
+			Status:      cell("status"),
+			Content:     cell("content"),
+			line:        line,
+		}
+		if record.Tags, err = parseKnowledgeListCell(cell("tags")); err != nil {
+			batch.Issues = append(batch.Issues, record.issue("tags", "标签格式无效"))
+			continue
+		}
+		if record.Prerequisites, err = parseKnowledgeListCell(cell("prerequisites")); err != nil {
+			batch.Issues = append(batch.Issues, record.issue("prerequisites", "前置知识点格式无效"))
+			continue
+		}
+		if resources := strings.TrimSpace(cell("resources")); resources != "" {
+			record.Resources = json.RawMessage(resources)
+		}
+		batch.Records = append(batch.Records, record)
+	}
+	return batch, nil
+}
+
+// DecodeKnowledgeJSONL 解析JSON Lines格式的知识点，每行一个JSON对象，忽略空行
+func DecodeKnowledgeJSONL(r io.Reader) (*KnowledgeImportBatch, error) {
+	reader := bufio.NewReader(r)
+	batch := &KnowledgeImportBatch{}
+	for line := 1; ; line++ {
+		data, err := reader.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return nil, invalidKnowledgeFile("读取JSON Lines失败", err)
+		}
+		if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 {
+			record := &KnowledgeRecord{}
+			decoder := json.NewDecoder(bytes.NewReader(trimmed))
+			decoder.DisallowUnknownFields()
+			if decodeErr := decoder.Decode(record); decodeErr != nil {
+				batch.Issues = append(batch.Issues, KnowledgeImportIssue{Line: line, Message: fmt.Sprintf("JSON格式无效: %v", decodeErr)})
+			} else {
+				record.Key = strings.TrimSpace(record.Key)
+				record.line = line
+				batch.Records = append(batch.Records, record)
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+	}
+	return batch, nil
+}
+
+// DecodeKnowledgeMarkdown 解析目录或压缩包中的全部.md文件，忽略以.或_开头的文件和目录
+// front matter省略key时以文件名(不含扩展名)作为外部标识
+func DecodeKnowledgeMarkdown(fsys fs.FS) (*KnowledgeImportBatch, error) {
+	batch := &KnowledgeImportBatch{}
+	err := fs.WalkDir(fsys, ".", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		base := entry.Name()
+		if name != "." && (strings.HasPrefix(base, ".") || strings.HasPrefix(base, "_")) {
+			if entry.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if entry.IsDir() || !strings.EqualFold(path.Ext(base), ".md") {
+			return nil
+		}
+
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		record, err := parseKnowledgeMarkdown(data)
+		if err != nil {
+			batch.Issues = append(batch.Issues, KnowledgeImportIssue{File: name, Message: err.Error()})
+			return nil
+		}
+		if record.Key == "" {
+			record.Key = strings.TrimSuffix(base, path.Ext(base))
+		}
+		record.file = name
+		batch.Records = append(batch.Records, record)
+		return nil
+	})
+	if err != nil {
+		return nil, invalidKnowledgeFile("读取Markdown文件失败", err)
+	}
+	return batch, nil
+}
+
+// EncodeKnowledgeCSV 以CSV格式写出知识点，首行为列名
+func EncodeKnowledgeCSV(w io.Writer, records []*KnowledgeRecord) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(knowledgeCSVColumns); err != nil {
+		return err
+	}
+	for _, record := range records {
+		row := []string{
+			record.Key,
+			record.Title,
+			record.Description,
+			record.Category,
+			record.Difficulty,
+			record.Status,
+			formatKnowledgeListCell(record.Tags),
+			formatKnowledgeListCell(record.Prerequisites),
+			string(record.Resources),
+			record.Content,
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// EncodeKnowledgeJSONL 以JSON Lines格式写出知识点
+func EncodeKnowledgeJSONL(w io.Writer, records []*KnowledgeRecord) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EncodeKnowledgeMarkdown 将每个知识点生成一个以外部标识命名的Markdown文件
+func EncodeKnowledgeMarkdown(records []*KnowledgeRecord) ([]KnowledgeFile, error) {
+	files := make([]KnowledgeFile, 0, len(records))
+	for _, record := range records {
+		front := knowledgeFrontMatter{
+			Key:           record.Key,
+			Title:         record.Title,
+			Description:   record.Description,
+			Category:      record.Category,
+			Difficulty:    record.Difficulty,
+			Status:        record.Status,
+			Tags:          record.Tags,
+			Prerequisites: record.Prerequisites,
+		}
+		if len(record.Resources) > 0 {
+			if err := json.Unmarshal(record.Resources, &front.Resources); err != nil {
+				return nil, fmt.Errorf("知识点%s的学习资源不是有效的JSON: %w", record.Key, err)
+			}
+		}
+		header, err := yaml.Marshal(&front)
+		if err != nil {
+			return nil, fmt.Errorf("生成知识点%s的front matter失败: %w", record.Key, err)
+		}
+
+		var buf bytes.Buffer
+		buf.WriteString(knowledgeFrontMatterDelimiter + "\n")
+		buf.Write(header)
+		buf.WriteString(knowledgeFrontMatterDelimiter + "\n")
+		buf.WriteString(record.Content)
+		files = append(files, KnowledgeFile{Name: record.Key + ".md", Data: buf.Bytes()})
+	}
+	return files, nil
+}
+
+// parseKnowledgeMarkdown 解析单个Markdown文件，分隔行之后的内容原样作为正文
+func parseKnowledgeMarkdown(data []byte) (*KnowledgeRecord, error) {
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+	header, rest, ok := cutKnowledgeFrontMatterLine(data)
+	if !ok || header != knowledgeFrontMatterDelimiter {
+		return nil, errors.New("文件须以---开始的front matter开头")
+	}
+
+	var front []byte
+	for {
+		line, next, found := cutKnowledgeFrontMatterLine(rest)
+		if line == knowledgeFrontMatterDelimiter {
+			rest = next
+			break
+		}
+		if !found {
+			return nil, errors.New("front matter缺少结束的---")
+		}
+		front = append(front, rest[:len(rest)-len(next)]...)
+		rest = next
+	}
+
+	var matter knowledgeFrontMatter
+	decoder := yaml.NewDecoder(bytes.NewReader(front))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&matter); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("front matter格式无效: %v", err)
+	}
+
+	record := &KnowledgeRecord{
+		Key:           strings.TrimSpace(matter.Key),
+		Title:         matter.Title,
+		Description:   matter.Description,
+		Category:      matter.Category,
+		Difficulty:    matter.Difficulty,
+		Status:        matter.Status,
+		Tags:          matter.Tags,
+		Prerequisites: matter.Prerequisites,
+		Content:       string(rest),
+	}
+	if record.Tags == nil {
+		record.Tags = []string{}
+	}
+	if record.Prerequisites == nil {
+		record.Prerequisites = []string{}
+	}
+	if matter.Resources != nil {
+		resources, err := json.Marshal(matter.Resources)
+		if err != nil {
+			return nil, fmt.Errorf("学习资源无法转换为JSON: %v", err)
+		}
+		record.Resources = resources
+	}
+	return record, nil
+}
+
+// cutKnowledgeFrontMatterLine 取出第一行(不含换行符)和剩余内容，found表示该行以换行符结束
+func cutKnowledgeFrontMatterLine(data []byte) (line string, rest []byte, found bool) {
+	before, after, found := bytes.Cut(data, []byte("\n"))
+	return strings.TrimSuffix(string(before), "\r"), after, found
+}
+
+// parseKnowledgeListCell 解析CSV中的列表单元格，以[开头时按JSON数组解析，否则以|分隔
+func parseKnowledgeListCell(value string) ([]string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return []string{}, nil
+	}
+	if strings.HasPrefix(value, "[") {
+		items := []string{}
+		if err := json.Unmarshal([]byte(value), &items); err != nil {
+			return nil, err
+		}
+		return items, nil
+	}
+	items := []string{}
+	for _, item := range strings.Split(value, "|") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+// formatKnowledgeListCell 生成CSV中的列表单元格，元素无法用|分隔无损表示时写为JSON数组
+func formatKnowledgeListCell(items []string) string {
+	for _, item := range items {
+		if item == "" || strings.Contains(item, "|") || strings.TrimSpace(item) != item {
+			data, _ := json.Marshal(items)
+			return string(data)
+		}
+	}
+	if len(items) > 0 && strings.HasPrefix(items[0], "[") {
+		data, _ := json.Marshal(items)
+		return string(data)
+	}
+	return strings.Join(items, "|")
+}
+
+// isKnowledgeCSVColumn 是否为支持的CSV列
+func isKnowledgeCSVColumn(name string) bool {
+	for _, column := range knowledgeCSVColumns {
+		if column == name {
+			return true
+		}
+	}
+	return false
+}
+
+// invalidKnowledgeFile 导入文件无法解析
+func invalidKnowledgeFile(message string, err error) error {
+	return apperrors.New(apperrors.ErrorTypeValidation, 400, message).WithDetail("reason", err.Error()).WithCause(err)
+}
@@ -0,0 +1,516 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+	apperrors "sical-go-backend/pkg/errors"
+)
+
+const (
+	// MaxKnowledgeImportRecords 单次导入的最大知识点数
+	MaxKnowledgeImportRecords = 2000
+	// maxKnowledgeKeyLength 外部标识的最大长度
+	maxKnowledgeKeyLength = 100
+	// maxKnowledgeTags 单个知识点的最大标签数
+	maxKnowledgeTags = 20
+	// maxKnowledgeTagLength 单个标签的最大字符数
+	maxKnowledgeTagLength = 50
+)
+
+// knowledgeKeyPattern 外部标识只允许字母、数字和._-，可直接用作导出的文件名
+var knowledgeKeyPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// KnowledgeImportAction 导入记录的处理结果
+type KnowledgeImportAction string
+
+const (
+	KnowledgeImportCreate    KnowledgeImportAction = "create"
+	KnowledgeImportUpdate    KnowledgeImportAction = "update"
+	KnowledgeImportUnchanged KnowledgeImportAction = "unchanged"
+)
+
+// KnowledgeImportResult 单条导入记录的处理结果
+type KnowledgeImportResult struct {
+	Line   int                   `json:"line,omitempty"`
+	File   string                `json:"file,omitempty"`
+	Key    string                `json:"key"`
+	ID     uuid.UUID             `json:"id"`
+	Action KnowledgeImportAction `json:"action"`
+}
+
+// KnowledgeImportReport 导入报告，存在任何问题时不保存任何记录
+type KnowledgeImportReport struct {
+	DryRun    bool                    `json:"dry_run"`
+	Applied   bool                    `json:"applied"`
+	Total     int                     `json:"total"`
+	Created   int                     `json:"created"`
+	Updated   int                     `json:"updated"`
+	Unchanged int                     `json:"unchanged"`
+	Results   []KnowledgeImportResult `json:"results"`
+	Errors    []KnowledgeImportIssue  `json:"errors"`
+}
+
+// KnowledgeTransferService 知识点批量导入导出服务
+// 导入先校验全部记录，按外部标识新建或更新知识点，前置知识点按外部标识或标题解析为ID；
+// 新建的知识点为草稿，已有知识点只有草稿可以通过导入修改，全部记录在一个事务中保存
+type KnowledgeTransferService struct {
+	transferRepo    repositories.KnowledgeTransferRepository
+	taxonomyService *TaxonomyService
+}
+
+// NewKnowledgeTransferService 创建知识点批量导入导出服务
+func NewKnowledgeTransferService(transferRepo repositories.KnowledgeTransferRepository, taxonomyService *TaxonomyService) *KnowledgeTransferService {
+	return &KnowledgeTransferService{
+		transferRepo:    transferRepo,
+		taxonomyService: taxonomyService,
+	}
+}
+
+// knowledgeImportTarget 导入记录对应的知识点，existing为空表示新建
+type knowledgeImportTarget struct {
+	record   *KnowledgeRecord
+	id       uuid.UUID
+	existing *entities.KnowledgePoint
+}
+
+// Import 校验并导入知识点，dryRun为true或存在问题时只返回报告不保存
+func (s *KnowledgeTransferService) Import(ctx context.Context, batch *KnowledgeImportBatch, editor KnowledgeEditor, dryRun bool) (*KnowledgeImportReport, error) {
+	if !editor.Author {
+		return nil, apperrors.New(apperrors.ErrorTypeForbidden, 403, "只有作者可以导入知识点")
+	}
+	if len(batch.Records) > MaxKnowledgeImportRecords {
+		return nil, apperrors.New(apperrors.ErrorTypeValidation, 400, fmt.Sprintf("单次最多导入%d个知识点", MaxKnowledgeImportRecords))
+	}
+
+	report := &KnowledgeImportReport{
+		DryRun:  dryRun,
+		Total:   len(batch.Records) + len(batch.Issues),
+		Results: []KnowledgeImportResult{},
+		Errors:  append([]KnowledgeImportIssue{}, batch.Issues...),
+	}
+
+	// 逐条校验字段，外部标识重复的记录只保留第一条
+	categories := make(map[string]bool)
+	byKey := make(map[string]*knowledgeImportTarget, len(batch.Records))
+	targets := make([]*knowledgeImportTarget, 0, len(batch.Records))
+	for _, record := range batch.Records {
+		issues := s.validateRecord(ctx, record, categories)
+		if _, ok := byKey[record.Key]; ok && record.Key != "" {
+			issues = append(issues, record.issue("key", "外部标识重复"))
+		}
+		if len(issues) > 0 {
+			report.Errors = append(report.Errors, issues...)
+			continue
+		}
+		target := &knowledgeImportTarget{record: record, id: uuid.New()}
+		byKey[record.Key] = target
+		targets = append(targets, target)
+	}
+
+	if err := s.matchExisting(ctx, byKey); err != nil {
+		return nil, err
+	}
+	resolver, err := s.newPrerequisiteResolver(ctx, targets, byKey)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]*repositories.KnowledgeImportChange, 0, len(targets))
+	for _, target := range targets {
+		change, issues := s.buildChange(target, resolver, editor)
+		if len(issues) > 0 {
+			report.Errors = append(report.Errors, issues...)
+			continue
+		}
+
+		result := KnowledgeImportResult{Line: target.record.line, File: target.record.file, Key: target.record.Key, ID: target.id}
+		switch {
+		case change == nil:
+			result.Action = KnowledgeImportUnchanged
+			report.Unchanged++
+		case change.ExpectedVersion == 0:
+			result.Action = KnowledgeImportCreate
+			report.Created++
+		default:
+			result.Action = KnowledgeImportUpdate
+			report.Updated++
+		}
+		report.Results = append(report.Results, result)
+		if change != nil {
+			changes = append(changes, change)
+		}
+	}
+
+	if dryRun || len(report.Errors) > 0 || len(changes) == 0 {
+		return report, nil
+	}
+
+	applied, err := s.transferRepo.ApplyImport(ctx, changes)
+	if err != nil {
+		return nil, err
+	}
+	if !applied {
+		return nil, apperrors.New(apperrors.ErrorTypeConflict, 409, "导入期间有知识点被修改，请重新导入")
+	}
+	report.Applied = true
+	return report, nil
+}
+
+// Export 导出满足筛选条件的知识点，前置知识点以外部标识表示，没有外部标识的知识点以ID作为外部标识
+func (s *KnowledgeTransferService) Export(ctx context.Context, filter repositories.KnowledgeExportFilter, editor KnowledgeEditor) ([]*KnowledgeRecord, error) {
+	if !editor.IsEditorial() {
+		return nil, apperrors.New(apperrors.ErrorTypeForbidden, 403, "只有作者或审核员可以导出知识点")
+	}
+	if filter.Status != "" && !entities.KnowledgeStatus(filter.Status).IsValid() {
+		return nil, apperrors.New(apperrors.ErrorTypeValidation, 400, "无效的编辑状态").WithDetail("status", filter.Status)
+	}
+
+	points, err := s.transferRepo.ListForExport(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// 前置知识点可能不在导出范围内，补充查询其外部标识
+	keys := make(map[string]string, len(points))
+	for _, point := range points {
+		keys[point.ID.String()] = knowledgeRecordKey(point)
+	}
+	var missing []uuid.UUID
+	for _, point := range points {
+		for _, ref := range knowledgePrerequisiteRefs(point) {
+			if id, err := uuid.Parse(ref); err == nil {
+				if _, ok := keys[id.String()]; !ok {
+					keys[id.String()] = ""
+					missing = append(missing, id)
+				}
+			}
+		}
+	}
+	referenced, err := s.transferRepo.FindByIDs(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+	for _, point := range referenced {
+		keys[point.ID.String()] = knowledgeRecordKey(point)
+	}
+
+	records := make([]*KnowledgeRecord, 0, len(points))
+	for _, point := range points {
+		prerequisites := []string{}
+		for _, ref := range knowledgePrerequisiteRefs(point) {
+			if id, err := uuid.Parse(ref); err == nil && keys[id.String()] != "" {
+				ref = keys[id.String()]
+			}
+			prerequisites = append(prerequisites, ref)
+		}
+		record := &KnowledgeRecord{
+			Key:           knowledgeRecordKey(point),
+			Title:         point.Title,
+			Description:   point.Description,
+			Category:      point.Category,
+			Difficulty:    point.Difficulty,
+			Status:        point.Status,
+			Tags:          point.TagList(),
+			Prerequisites: prerequisites,
+			Content:       point.Content,
+		}
+		if resources := strings.TrimSpace(point.Resources); resources != "" {
+			record.Resources = json.RawMessage(resources)
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// validateRecord 校验单条记录的字段，categories缓存类别的校验结果
+func (s *KnowledgeTransferService) validateRecord(ctx context.Context, record *KnowledgeRecord, categories map[string]bool) []KnowledgeImportIssue {
+	var issues []KnowledgeImportIssue
+	switch {
+	case record.Key == "":
+		issues = append(issues, record.issue("key", "外部标识不能为空"))
+	case len(record.Key) > maxKnowledgeKeyLength || !knowledgeKeyPattern.MatchString(record.Key):
+		issues = append(issues, record.issue("key", fmt.Sprintf("外部标识只能包含字母、数字和._-，且不超过%d个字符", maxKnowledgeKeyLength)))
+	}
+
+	record.Title = strings.TrimSpace(record.Title)
+	if record.Title == "" || utf8.RuneCountInString(record.Title) > 255 {
+		issues = append(issues, record.issue("title", "标题不能为空且不超过255个字符"))
+	}
+	if strings.TrimSpace(record.Content) == "" {
+		issues = append(issues, record.issue("content", "正文不能为空"))
+	}
+	if _, ok := knowledgeDifficultyRanks[record.Difficulty]; !ok {
+		issues = append(issues, record.issue("difficulty", "无效的难度值，可选beginner、intermediate、advanced"))
+	}
+
+	record.Category = strings.TrimSpace(record.Category)
+	valid, checked := categories[record.Category]
+	if !checked {
+		valid = record.Category != "" && s.taxonomyService.ValidateCategory(ctx, record.Category) == nil
+		categories[record.Category] = valid
+	}
+	if !valid {
+		issues = append(issues, record.issue("category", fmt.Sprintf("无效的类别: %s", record.Category)))
+	}
+
+	if len(record.Tags) > maxKnowledgeTags {
+		issues = append(issues, record.issue("tags", fmt.Sprintf("标签不能超过%d个", maxKnowledgeTags)))
+	}
+	for _, tag := range record.Tags {
+		if utf8.RuneCountInString(tag) > maxKnowledgeTagLength {
+			issues = append(issues, record.issue("tags", fmt.Sprintf("标签不能超过%d个字符", maxKnowledgeTagLength)))
+			break
+		}
+	}
+	if len(record.Resources) > 0 && !json.Valid(record.Resources) {
+		issues = append(issues, record.issue("resources", "学习资源不是有效的JSON"))
+	}
+	return issues
+}
+
+// matchExisting 按外部标识查找已有知识点；外部标识为ID且该知识点尚未设置外部标识时，视为同一知识点
+func (s *KnowledgeTransferService) matchExisting(ctx context.Context, byKey map[string]*knowledgeImportTarget) error {
+	keys := make([]string, 0, len(byKey))
+	for key := range byKey {
+		keys = append(keys, key)
+	}
+	points, err := s.transferRepo.FindByExternalKeys(ctx, keys)
+	if err != nil {
+		return err
+	}
+	for _, point := range points {
+		target := byKey[*point.ExternalKey]
+		target.id, target.existing = point.ID, point
+	}
+
+	var ids []uuid.UUID
+	for key, target := range byKey {
+		if target.existing == nil {
+			if id, err := uuid.Parse(key); err == nil {
+				ids = append(ids, id)
+			}
+		}
+	}
+	points, err = s.transferRepo.FindByIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for _, point := range points {
+		if point.ExternalKey == nil {
+			target := byKey[point.ID.String()]
+			target.id, target.existing = point.ID, point
+		}
+	}
+	return nil
+}
+
+// knowledgePrerequisiteResolver 将前置知识点引用解析为知识点ID
+// 依次按导入记录的外部标识、已有知识点的外部标识或ID、导入记录的标题、已有知识点的标题匹配
+type knowledgePrerequisiteResolver struct {
+	batchKeys    map[string]*knowledgeImportTarget
+	batchTitles  map[string][]uuid.UUID
+	storedKeys   map[string]uuid.UUID
+	storedTitles map[string][]uuid.UUID
+}
+
+// newPrerequisiteResolver 预先查询全部引用可能对应的已有知识点
+func (s *KnowledgeTransferService) newPrerequisiteResolver(ctx context.Context, targets []*knowledgeImportTarget, byKey map[string]*knowledgeImportTarget) (*knowledgePrerequisiteResolver, error) {
+	resolver := &knowledgePrerequisiteResolver{
+		batchKeys:    byKey,
+		batchTitles:  make(map[string][]uuid.UUID),
+		storedKeys:   make(map[string]uuid.UUID),
+		storedTitles: make(map[string][]uuid.UUID),
+	}
+	refs := make(map[string]bool)
+	var ids []uuid.UUID
+	for _, target := range targets {
+		resolver.batchTitles[target.record.Title] = append(resolver.batchTitles[target.record.Title], target.id)
+		for _, ref := range target.record.Prerequisites {
+			ref = strings.TrimSpace(ref)
+			if _, ok := byKey[ref]; ok || ref == "" || refs[ref] {
+				continue
+			}
+			refs[ref] = true
+			if id, err := uuid.Parse(ref); err == nil {
+				ids = append(ids, id)
+			}
+		}
+	}
+	if len(refs) == 0 {
+		return resolver, nil
+	}
+
+	names := make([]string, 0, len(refs))
+	for ref := range refs {
+		names = append(names, ref)
+	}
+	byExternalKey, err := s.transferRepo.FindByExternalKeys(ctx, names)
+	if err != nil {
+		return nil, err
+	}
+	for _, point := range byExternalKey {
+		resolver.storedKeys[*point.ExternalKey] = point.ID
+	}
+	byID, err := s.transferRepo.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, point := range byID {
+		resolver.storedKeys[point.ID.String()] = point.ID
+	}
+	byTitle, err := s.transferRepo.FindByTitles(ctx, names)
+	if err != nil {
+		return nil, err
+	}
+	for _, point := range byTitle {
+		resolver.storedTitles[point.Title] = append(resolver.storedTitles[point.Title], point.ID)
+	}
+	return resolver, nil
+}
+
+// resolve 解析单个引用，无法唯一确定时返回错误说明
+func (r *knowledgePrerequisiteResolver) resolve(ref string) (uuid.UUID, string) {
+	if target, ok := r.batchKeys[ref]; ok {
+		return target.id, ""
+	}
+	if id, ok := r.storedKeys[ref]; ok {
+		return id, ""
+	}
+	for _, matches := range [][]uuid.UUID{r.batchTitles[ref], r.storedTitles[ref]} {
+		switch len(matches) {
+		case 0:
+			continue
+		case 1:
+			return matches[0], ""
+		default:
+			return uuid.Nil, fmt.Sprintf("前置知识点\"%s\"对应多个同名知识点，请改用外部标识", ref)
+		}
+	}
+	return uuid.Nil, fmt.Sprintf("前置知识点\"%s\"不存在", ref)
+}
+
+// buildChange 将记录应用到知识点并生成变更，内容和外部标识都没有变化时返回nil
+func (s *KnowledgeTransferService) buildChange(target *knowledgeImportTarget, resolver *knowledgePrerequisiteResolver, editor KnowledgeEditor) (*repositories.KnowledgeImportChange, []KnowledgeImportIssue) {
+	record := target.record
+	var issues []KnowledgeImportIssue
+	prerequisites := make([]string, 0, len(record.Prerequisites))
+	seen := make(map[uuid.UUID]bool, len(record.Prerequisites))
+	for _, ref := range record.Prerequisites {
+		if ref = strings.TrimSpace(ref); ref == "" {
+			continue
+		}
+		id, problem := resolver.resolve(ref)
+		switch {
+		case problem != "":
+			issues = append(issues, record.issue("prerequisites", problem))
+		case id == target.id:
+			issues = append(issues, record.issue("prerequisites", "知识点不能以自身为前置知识点"))
+		case !seen[id]:
+			seen[id] = true
+			prerequisites = append(prerequisites, id.String())
+		}
+	}
+	if len(issues) > 0 {
+		return nil, issues
+	}
+
+	point := &entities.KnowledgePoint{ID: target.id}
+	if target.existing != nil {
+		copied := *target.existing
+		point = &copied
+	}
+	before := entities.NewKnowledgePointRevision(point, entities.KnowledgeRevisionUpdate)
+
+	point.Title = record.Title
+	point.Description = record.Description
+	point.Category = record.Category
+	point.Difficulty = record.Difficulty
+	point.Content = record.Content
+	point.SetTags(record.Tags)
+	prerequisiteJSON, _ := json.Marshal(prerequisites)
+	point.Prerequisites = string(prerequisiteJSON)
+	point.Resources = "[]"
+	if len(record.Resources) > 0 {
+		point.Resources = string(record.Resources)
+	}
+	// 数据库返回的jsonb格式与导入内容的空白可能不同，语义相同时保留原值
+	for _, field := range []struct{ current, previous *string }{
+		{&point.Tags, &before.Tags},
+		{&point.Prerequisites, &before.Prerequisites},
+		{&point.Resources, &before.Resources},
+	} {
+		if sameKnowledgeJSON(*field.current, *field.previous) {
+			*field.current = *field.previous
+		}
+	}
+
+	key := record.Key
+	point.ExternalKey = &key
+
+	if target.existing == nil {
+		point.Version = 1
+		point.Status = string(entities.KnowledgeStatusDraft)
+		point.AuthorID = editor.UserID
+		revision := entities.NewKnowledgePointRevision(point, entities.KnowledgeRevisionCreate)
+		revision.AuthorID = editor.UserID
+		revision.ChangeSummary = "导入知识点"
+		return &repositories.KnowledgeImportChange{Point: point, Revision: revision}, nil
+	}
+
+	change := &repositories.KnowledgeImportChange{Point: point, ExpectedVersion: target.existing.Version}
+	after := entities.NewKnowledgePointRevision(point, entities.KnowledgeRevisionUpdate)
+	if changes := diffKnowledgeRevisions(before, after); len(changes) > 0 {
+		if !target.existing.IsDraft() {
+			return nil, []KnowledgeImportIssue{record.issue("", fmt.Sprintf("知识点当前为%s状态，只有草稿可以通过导入修改", target.existing.Status))}
+		}
+		summary, _ := normalizeChangeSummary("", changes)
+		after.AuthorID = editor.UserID
+		after.ChangeSummary = "导入" + summary
+		change.Revision = after
+		return change, nil
+	}
+	if target.existing.ExternalKey == nil || *target.existing.ExternalKey != key {
+		return change, nil
+	}
+	return nil, nil
+}
+
+// knowledgeRecordKey 知识点导出时的外部标识，未设置时使用ID
+func knowledgeRecordKey(point *entities.KnowledgePoint) string {
+	if point.ExternalKey != nil && *point.ExternalKey != "" {
+		return *point.ExternalKey
+	}
+	return point.ID.String()
+}
+
+// knowledgePrerequisiteRefs 解析知识点保存的前置知识点列表，格式无效时返回空列表
+func knowledgePrerequisiteRefs(point *entities.KnowledgePoint) []string {
+	refs := []string{}
+	if strings.TrimSpace(point.Prerequisites) != "" {
+		if err := json.Unmarshal([]byte(point.Prerequisites), &refs); err != nil {
+			return []string{}
+		}
+	}
+	return refs
+}
+
+// sameKnowledgeJSON 两段JSON文本是否表示相同的值
+func sameKnowledgeJSON(a, b string) bool {
+	if a == b {
+		return true
+	}
+	var left, right interface{}
+	if json.Unmarshal([]byte(a), &left) != nil || json.Unmarshal([]byte(b), &right) != nil {
+		return false
+	}
+	return reflect.DeepEqual(left, right)
+}
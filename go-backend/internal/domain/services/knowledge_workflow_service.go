@@ -0,0 +1,250 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+	apperrors "sical-go-backend/pkg/errors"
+	"sical-go-backend/pkg/logger"
+)
+
+// knowledgePublishBatchSize 每轮定时发布处理的知识点数
+const knowledgePublishBatchSize = 100
+
+// KnowledgeTransitionInput 编辑流程操作的附加信息
+type KnowledgeTransitionInput struct {
+	Comment   string     // 审核意见，退回修改时必填
+	PublishAt *time.Time // 仅用于发布操作，晚于当前时间时定时发布
+}
+
+// KnowledgeWorkflowService 知识点编辑流程服务
+// 知识点按草稿、审核中、已审核、已发布、已归档流转，作者提交审核，审核员审核、发布和归档，
+// 审核员不能审核自己创建或提交的知识点。只有已发布的知识点对学习者可见
+type KnowledgeWorkflowService struct {
+	knowledgeRepo repositories.KnowledgePointRepository
+	workflowRepo  repositories.KnowledgeWorkflowRepository
+	searchService *KnowledgeSearchService
+
+	startOnce sync.Once
+}
+
+// NewKnowledgeWorkflowService 创建知识点编辑流程服务
+// searchService 不为空时在发布状态变化后刷新检索建议
+func NewKnowledgeWorkflowService(
+	knowledgeRepo repositories.KnowledgePointRepository,
+	workflowRepo repositories.KnowledgeWorkflowRepository,
+	searchService *KnowledgeSearchService,
+) *KnowledgeWorkflowService {
+	return &KnowledgeWorkflowService{
+		knowledgeRepo: knowledgeRepo,
+		workflowRepo:  workflowRepo,
+		searchService: searchService,
+	}
+}
+
+// Start 启动后台定期发布到期的知识点，重复调用只启动一次
+func (s *KnowledgeWorkflowService) Start(ctx context.Context, interval time.Duration) {
+	s.startOnce.Do(func() {
+		go func() {
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					if _, err := s.PublishDue(ctx); err != nil {
+						logger.Error("定时发布知识点失败", logger.String("error", err.Error()))
+					}
+				}
+			}
+		}()
+	})
+}
+
+// Transition 执行编辑流程操作，返回更新后的知识点
+func (s *KnowledgeWorkflowService) Transition(ctx context.Context, id uuid.UUID, action string, editor KnowledgeEditor, input KnowledgeTransitionInput) (*entities.KnowledgePoint, error) {
+	point, err := s.knowledgeRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, apperrors.New(apperrors.ErrorTypeNotFound, 404, "知识点不存在").WithCause(err)
+	}
+	comment, err := normalizeReviewComment(input.Comment)
+	if err != nil {
+		return nil, err
+	}
+
+	from := entities.KnowledgeStatus(point.Status)
+	eventAction := entities.KnowledgeWorkflowAction(action)
+	var target entities.KnowledgeStatus
+	switch eventAction {
+	case entities.KnowledgeActionSubmit:
+		if !editor.Author {
+			return nil, knowledgeWorkflowForbidden("只有作者可以提交审核")
+		}
+		target = entities.KnowledgeStatusInReview
+		point.SubmittedBy = editor.UserID
+	case entities.KnowledgeActionApprove, entities.KnowledgeActionReject:
+		if !editor.Reviewer {
+			return nil, knowledgeWorkflowForbidden("只有审核员可以审核知识点")
+		}
+		if editor.Is(point.AuthorID) || editor.Is(point.SubmittedBy) {
+			return nil, knowledgeWorkflowForbidden("不能审核自己创建或提交的知识点")
+		}
+		target = entities.KnowledgeStatusApproved
+		if eventAction == entities.KnowledgeActionReject {
+			if from != entities.KnowledgeStatusInReview {
+				return nil, invalidKnowledgeTransition(from, eventAction)
+			}
+			if comment == "" {
+				return nil, apperrors.New(apperrors.ErrorTypeValidation, 400, "退回修改时请填写审核意见")
+			}
+			target = entities.KnowledgeStatusDraft
+		}
+	case entities.KnowledgeActionPublish:
+		if !editor.Reviewer {
+			return nil, knowledgeWorkflowForbidden("只有审核员可以发布知识点")
+		}
+		target = entities.KnowledgeStatusPublished
+	case entities.KnowledgeActionArchive:
+		if !editor.Reviewer {
+			return nil, knowledgeWorkflowForbidden("只有审核员可以归档知识点")
+		}
+		target = entities.KnowledgeStatusArchived
+	case entities.KnowledgeActionReopen:
+		if !editor.IsEditorial() {
+			return nil, knowledgeWorkflowForbidden("只有作者或审核员可以撤回知识点")
+		}
+		target = entities.KnowledgeStatusDraft
+	default:
+		return nil, apperrors.New(apperrors.ErrorTypeValidation, 400, "不支持的操作").WithDetail("action", action)
+	}
+	if !from.CanTransitionTo(target) {
+		return nil, invalidKnowledgeTransition(from, eventAction)
+	}
+
+	now := time.Now()
+	point.Status = string(target)
+	point.PublishAt = nil
+	switch target {
+	case entities.KnowledgeStatusPublished:
+		if input.PublishAt != nil && input.PublishAt.After(now) {
+			// 定时发布：保持已审核状态，到期后由后台任务发布
+			eventAction = entities.KnowledgeActionSchedule
+			point.Status = string(entities.KnowledgeStatusApproved)
+			point.PublishAt = input.PublishAt
+		} else {
+			point.PublishedAt = &now
+		}
+	case entities.KnowledgeStatusDraft:
+		point.SubmittedBy = nil
+	}
+
+	event := &entities.KnowledgeReviewEvent{
+		ID:               uuid.New(),
+		KnowledgePointID: point.ID,
+		Action:           string(eventAction),
+		FromStatus:       string(from),
+		ToStatus:         point.Status,
+		ActorID:          editor.UserID,
+		Version:          point.Version,
+		Comment:          comment,
+		PublishAt:        point.PublishAt,
+	}
+	if err := s.apply(ctx, point, from, event); err != nil {
+		return nil, err
+	}
+	return point, nil
+}
+
+// History 获取知识点的编辑流程记录和审核意见，按时间正序
+func (s *KnowledgeWorkflowService) History(ctx context.Context, id uuid.UUID) ([]*entities.KnowledgeReviewEvent, error) {
+	if _, err := s.knowledgeRepo.GetByID(ctx, id); err != nil {
+		return nil, apperrors.New(apperrors.ErrorTypeNotFound, 404, "知识点不存在").WithCause(err)
+	}
+	return s.workflowRepo.ListEvents(ctx, id)
+}
+
+// PublishDue 发布定时发布时间已到的知识点，返回发布的数量
+func (s *KnowledgeWorkflowService) PublishDue(ctx context.Context) (int, error) {
+	now := time.Now()
+	points, err := s.workflowRepo.ListDue(ctx, now, knowledgePublishBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	published := 0
+	for _, point := range points {
+		scheduledAt := point.PublishAt
+		point.Status = string(entities.KnowledgeStatusPublished)
+		point.PublishAt = nil
+		point.PublishedAt = &now
+		event := &entities.KnowledgeReviewEvent{
+			ID:               uuid.New(),
+			KnowledgePointID: point.ID,
+			Action:           string(entities.KnowledgeActionPublish),
+			FromStatus:       string(entities.KnowledgeStatusApproved),
+			ToStatus:         point.Status,
+			Version:          point.Version,
+			Comment:          "定时发布",
+			PublishAt:        scheduledAt,
+		}
+		if err := s.apply(ctx, point, entities.KnowledgeStatusApproved, event); err != nil {
+			logger.Warn("定时发布知识点失败",
+				logger.String("knowledge_point_id", point.ID.String()),
+				logger.String("error", err.Error()))
+			continue
+		}
+		published++
+	}
+	return published, nil
+}
+
+// apply 以原状态为条件保存状态变化，发布状态变化时刷新检索建议
+func (s *KnowledgeWorkflowService) apply(ctx context.Context, point *entities.KnowledgePoint, from entities.KnowledgeStatus, event *entities.KnowledgeReviewEvent) error {
+	updated, err := s.workflowRepo.Transition(ctx, point, string(from), event)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return apperrors.New(apperrors.ErrorTypeConflict, 409, "知识点状态已变化，请刷新后重试")
+	}
+
+	if s.searchService != nil && (from == entities.KnowledgeStatusPublished || point.IsPublished()) {
+		s.searchService.InvalidateSuggestions(ctx)
+	}
+
+	logger.Info("知识点状态变更",
+		logger.String("knowledge_point_id", point.ID.String()),
+		logger.String("action", event.Action),
+		logger.String("from", event.FromStatus),
+		logger.String("to", event.ToStatus))
+	return nil
+}
+
+// knowledgeWorkflowForbidden 无权执行编辑流程操作
+func knowledgeWorkflowForbidden(message string) error {
+	return apperrors.New(apperrors.ErrorTypeForbidden, 403, message)
+}
+
+// invalidKnowledgeTransition 当前状态不允许执行该操作
+func invalidKnowledgeTransition(from entities.KnowledgeStatus, action entities.KnowledgeWorkflowAction) error {
+	return apperrors.New(apperrors.ErrorTypeConflict, 409, "当前状态不允许该操作").
+		WithDetail("status", string(from)).
+		WithDetail("action", string(action))
+}
+
+// normalizeReviewComment 去除首尾空白并校验审核意见长度
+func normalizeReviewComment(comment string) (string, error) {
+	comment = strings.TrimSpace(comment)
+	if utf8.RuneCountInString(comment) > entities.MaxReviewCommentLength {
+		return "", apperrors.New(apperrors.ErrorTypeValidation, 400, fmt.Sprintf("审核意见不能超过%d个字符", entities.MaxReviewCommentLength))
+	}
+	return comment, nil
+}
@@ -4,32 +4,57 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"sort"
 	"strings"
 
 	"github.com/google/uuid"
 	"sical-go-backend/internal/domain/entities"
 	"sical-go-backend/internal/domain/repositories"
+	apperrors "sical-go-backend/pkg/errors"
 	"sical-go-backend/pkg/logger"
 )
 
+// studyTimeMultipliers 学习时间与正文阅读时间的倍数，包含练习和复习，难度越高倍数越大
+var studyTimeMultipliers = map[string]float64{
+	"beginner":     4,
+	"intermediate": 6,
+	"advanced":     8,
+}
+
+// defaultStudyTimeMultiplier 未知难度的学习时间倍数
+const defaultStudyTimeMultiplier = 6
+
 // LearningPathService 学习路径服务
 type LearningPathService struct {
-	pathRepo      repositories.LearningPathRepository
-	goalRepo      repositories.LearningGoalRepository
-	knowledgeRepo repositories.KnowledgePointRepository
+	pathRepo        repositories.LearningPathRepository
+	goalRepo        repositories.LearningGoalRepository
+	knowledgeRepo   repositories.KnowledgePointRepository
+	statusService   *StatusService
+	taxonomy        *TaxonomyService
+	ratings         *RatingService
+	renderer        *KnowledgeRenderService
 }
 
-// NewLearningPathService 创建学习路径服务
+// NewLearningPathService 创建学习路径服务，ratings不为空时以知识点评分作为选择等价知识点的质量信号，
+// renderer不为空时按正文的预计阅读时间估算步骤学习时间
 func NewLearningPathService(
 	pathRepo repositories.LearningPathRepository,
 	goalRepo repositories.LearningGoalRepository,
 	knowledgeRepo repositories.KnowledgePointRepository,
+	statusService *StatusService,
+	taxonomy *TaxonomyService,
+	ratings *RatingService,
+	renderer *KnowledgeRenderService,
 ) *LearningPathService {
 	return &LearningPathService{
-		pathRepo:      pathRepo,
-		goalRepo:      goalRepo,
-		knowledgeRepo: knowledgeRepo,
+		pathRepo:        pathRepo,
+		goalRepo:        goalRepo,
+		knowledgeRepo:   knowledgeRepo,
+		statusService:   statusService,
+		taxonomy:        taxonomy,
+		ratings:         ratings,
+		renderer:        renderer,
 	}
 }
 
@@ -75,10 +100,10 @@ func (s *LearningPathService) GenerateLearningPath(ctx context.Context, req *Pat
 	}
 
 	// 3. 分析知识点依赖关系
-	orderedPoints := s.analyzeKnowledgeDependencies(knowledgePoints)
+	orderedPoints := s.analyzeKnowledgeDependencies(knowledgePoints, s.knowledgeQuality(ctx, knowledgePoints))
 
 	// 4. 生成学习路径步骤
-	steps := s.generatePathSteps(orderedPoints, req.TimeLimit)
+	steps := s.generatePathSteps(ctx, orderedPoints, req.TimeLimit)
 
 	// 5. 计算总时间
 	totalTime := s.calculateTotalTime(steps)
@@ -110,7 +135,7 @@ func (s *LearningPathService) CreateLearningPath(ctx context.Context, goalID uui
 			Description:       step.Description,
 			Order:             step.Order,
 			EstimatedDuration: step.EstimatedDuration,
-			Status:            "pending",
+			Status:            string(entities.PathStatusPending),
 		}
 
 		// 创建路径
@@ -131,9 +156,38 @@ func (s *LearningPathService) CreateLearningPath(ctx context.Context, goalID uui
 		logger.String("goal_id", goalID.String()),
 		logger.Int("paths_count", len(paths)))
 
+	// 新增步骤会改变进度分母，需要重新计算
+	if _, err := s.statusService.SyncGoalProgress(ctx, goalID); err != nil {
+		logger.Error("更新目标进度失败", logger.String("error", err.Error()))
+	}
+
 	return paths, nil
 }
 
+// LoadOwnedGoal 加载学习目标并校验归属
+func (s *LearningPathService) LoadOwnedGoal(ctx context.Context, userID uint, goalID uuid.UUID) (*entities.LearningGoal, error) {
+	goal, err := s.goalRepo.GetByID(ctx, goalID)
+	if err != nil {
+		return nil, apperrors.New(apperrors.ErrorTypeNotFound, 404, "学习目标不存在").WithCause(err)
+	}
+	if goal.UserID != userID {
+		return nil, apperrors.New(apperrors.ErrorTypeForbidden, 403, "无权访问该学习目标")
+	}
+	return goal, nil
+}
+
+// LoadOwnedPath 加载学习路径并校验其所属学习目标的归属
+func (s *LearningPathService) LoadOwnedPath(ctx context.Context, userID uint, pathID uuid.UUID) (*entities.LearningPath, error) {
+	path, err := s.pathRepo.GetByID(ctx, pathID)
+	if err != nil {
+		return nil, apperrors.New(apperrors.ErrorTypeNotFound, 404, "学习路径不存在").WithCause(err)
+	}
+	if path.LearningGoal.UserID != userID {
+		return nil, apperrors.New(apperrors.ErrorTypeForbidden, 403, "无权访问该学习路径")
+	}
+	return path, nil
+}
+
 // GetLearningPaths 获取学习路径列表
 func (s *LearningPathService) GetLearningPaths(ctx context.Context, goalID uuid.UUID) ([]*entities.LearningPath, error) {
 	return s.pathRepo.GetByGoalID(ctx, goalID)
@@ -145,19 +199,31 @@ func (s *LearningPathService) GetLearningPath(ctx context.Context, id uuid.UUID)
 }
 
 // UpdateLearningPathStatus 更新学习路径状态
-func (s *LearningPathService) UpdateLearningPathStatus(ctx context.Context, id uuid.UUID, status string) error {
-	// 验证状态值
-	validStatuses := []string{"pending", "in_progress", "completed"}
-	if !s.isValidStatus(status, validStatuses) {
-		return fmt.Errorf("无效的状态值: %s", status)
-	}
-
-	return s.pathRepo.UpdateStatus(ctx, id, status)
+// 状态转换受状态机约束，步骤开始前需完成前置步骤（override为true时跳过检查）
+func (s *LearningPathService) UpdateLearningPathStatus(ctx context.Context, id uuid.UUID, status string, override bool, reason string) (*entities.LearningPath, error) {
+	return s.statusService.TransitionPath(ctx, id, entities.PathStatus(status), TransitionOptions{
+		Trigger:  entities.TransitionTriggerUser,
+		Override: override,
+		Reason:   reason,
+	})
 }
 
 // DeleteLearningPath 删除学习路径
 func (s *LearningPathService) DeleteLearningPath(ctx context.Context, id uuid.UUID) error {
-	return s.pathRepo.Delete(ctx, id)
+	path, err := s.pathRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.pathRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	if _, err := s.statusService.SyncGoalProgress(ctx, path.GoalID); err != nil {
+		logger.Error("更新目标进度失败", logger.String("error", err.Error()))
+	}
+
+	return nil
 }
 
 // getRelevantKnowledgePoints 获取相关知识点
@@ -202,22 +268,27 @@ func (s *LearningPathService) getRelevantKnowledgePoints(ctx context.Context, ca
 }
 
 // analyzeKnowledgeDependencies 分析知识点依赖关系
-func (s *LearningPathService) analyzeKnowledgeDependencies(points []*entities.KnowledgePoint) []*entities.KnowledgePoint {
+// quality为知识点的评分质量信号，依赖分数相同的知识点中评分高的优先，在时间限制内更容易被选入路径
+func (s *LearningPathService) analyzeKnowledgeDependencies(points []*entities.KnowledgePoint, quality map[uuid.UUID]float64) []*entities.KnowledgePoint {
 	// 简化的依赖分析：根据难度和前置条件排序
 	type pointWithScore struct {
-		point *entities.KnowledgePoint
-		score int
+		point   *entities.KnowledgePoint
+		score   int
+		quality float64
 	}
 
 	var scoredPoints []pointWithScore
 	for _, point := range points {
 		score := s.calculateDependencyScore(point)
-		scoredPoints = append(scoredPoints, pointWithScore{point: point, score: score})
+		scoredPoints = append(scoredPoints, pointWithScore{point: point, score: score, quality: quality[point.ID]})
 	}
 
-	// 按分数排序（分数低的先学）
-	sort.Slice(scoredPoints, func(i, j int) bool {
-		return scoredPoints[i].score < scoredPoints[j].score
+	// 按分数排序（分数低的先学），分数相同时评分高的优先
+	sort.SliceStable(scoredPoints, func(i, j int) bool {
+		if scoredPoints[i].score != scoredPoints[j].score {
+			return scoredPoints[i].score < scoredPoints[j].score
+		}
+		return scoredPoints[i].quality > scoredPoints[j].quality
 	})
 
 	var orderedPoints []*entities.KnowledgePoint
@@ -228,14 +299,32 @@ func (s *LearningPathService) analyzeKnowledgeDependencies(points []*entities.Kn
 	return orderedPoints
 }
 
+// knowledgeQuality 获取知识点的评分质量信号，未启用评分或获取失败时返回nil，不影响路径生成
+func (s *LearningPathService) knowledgeQuality(ctx context.Context, points []*entities.KnowledgePoint) map[uuid.UUID]float64 {
+	if s.ratings == nil || len(points) == 0 {
+		return nil
+	}
+	ids := make([]uuid.UUID, 0, len(points))
+	for _, point := range points {
+		ids = append(ids, point.ID)
+	}
+	quality, err := s.ratings.KnowledgeQuality(ctx, ids)
+	if err != nil {
+		logger.Error("获取知识点评分失败", logger.String("error", err.Error()))
+		return nil
+	}
+	return quality
+}
+
 // generatePathSteps 生成路径步骤
-func (s *LearningPathService) generatePathSteps(points []*entities.KnowledgePoint, timeLimit int) []PathStep {
+func (s *LearningPathService) generatePathSteps(ctx context.Context, points []*entities.KnowledgePoint, timeLimit int) []PathStep {
 	var steps []PathStep
 	totalTime := 0
+	readingMinutes := s.readingMinutes(ctx, points)
 
 	for i, point := range points {
-		// 估算学习时间（基于难度）
-		estimatedTime := s.estimateStudyTime(point)
+		// 估算学习时间（基于正文阅读时间和难度）
+		estimatedTime := s.estimateStudyTime(ctx, point, readingMinutes[point.ID])
 		
 		// 检查时间限制
 		if timeLimit > 0 && totalTime+estimatedTime > timeLimit {
@@ -316,19 +405,30 @@ func (s *LearningPathService) calculateDependencyScore(point *entities.Knowledge
 	return score
 }
 
-// estimateStudyTime 估算学习时间
-func (s *LearningPathService) estimateStudyTime(point *entities.KnowledgePoint) int {
-	// 基于难度的基础时间估算
-	baseTime := map[string]int{
-		"beginner":     2, // 2小时
-		"intermediate": 4, // 4小时
-		"advanced":     6, // 6小时
+// readingMinutes 获取知识点正文的预计阅读分钟数，未启用渲染或获取失败时返回nil，不影响路径生成
+func (s *LearningPathService) readingMinutes(ctx context.Context, points []*entities.KnowledgePoint) map[uuid.UUID]int {
+	if s.renderer == nil || len(points) == 0 {
+		return nil
+	}
+	minutes, err := s.renderer.ReadingMinutes(ctx, points)
+	if err != nil {
+		logger.Error("估算知识点阅读时间失败", logger.String("error", err.Error()))
+		return nil
 	}
+	return minutes
+}
 
-	if time, exists := baseTime[point.Difficulty]; exists {
-		return time
+// estimateStudyTime 估算学习时间(小时)，按正文阅读时间乘以难度倍数，至少1小时；
+// 正文为空或无法估算阅读时间时使用类别体系中配置的难度基准时间
+func (s *LearningPathService) estimateStudyTime(ctx context.Context, point *entities.KnowledgePoint, readingMinutes int) int {
+	if readingMinutes <= 0 {
+		return s.taxonomy.StepHours(ctx, point.Category, point.Difficulty)
+	}
+	multiplier, exists := studyTimeMultipliers[point.Difficulty]
+	if !exists {
+		multiplier = defaultStudyTimeMultiplier
 	}
-	return 3 // 默认3小时
+	return max(1, int(math.Ceil(float64(readingMinutes)*multiplier/60)))
 }
 
 // parsePrerequisites 解析前置条件
@@ -344,13 +444,3 @@ func (s *LearningPathService) parsePrerequisites(prerequisitesJSON string) []str
 
 	return prerequisites
 }
-
-// isValidStatus 验证状态值
-func (s *LearningPathService) isValidStatus(status string, validStatuses []string) bool {
-	for _, validStatus := range validStatuses {
-		if status == validStatus {
-			return true
-		}
-	}
-	return false
-}
\ No newline at end of file
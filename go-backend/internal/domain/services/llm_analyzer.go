@@ -0,0 +1,492 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+	"sical-go-backend/pkg/llm"
+	"sical-go-backend/pkg/logger"
+)
+
+// maxPromptKnowledgePoints 提示词中最多携带的知识点数量
+const maxPromptKnowledgePoints = 50
+
+// llmAnalysisFields 各分析类型要求模型输出的结果字段
+var llmAnalysisFields = map[string][]string{
+	entities.AnalysisTypeSkillGap:     {"skill_gaps", "strengths"},
+	entities.AnalysisTypePrerequisite: {"prerequisites", "missing_prerequisites"},
+	entities.AnalysisTypeDifficulty:   {"difficulty_level", "difficulty_factors"},
+	entities.AnalysisTypeTimeEstimate: {"estimated_time"},
+}
+
+// llmAnalysisTasks 各分析类型的任务说明，附加在系统提示词之后
+var llmAnalysisTasks = map[string]string{
+	entities.AnalysisTypeSkillGap:     "技能差距。skill_gaps 为尚未掌握的必备技能，strengths 为已具备的必备技能，只能使用知识点列表中的标题",
+	entities.AnalysisTypePrerequisite: "前置条件。prerequisites 为达成目标需要先具备的前置知识，missing_prerequisites 为其中学习者尚未掌握的部分",
+	entities.AnalysisTypeDifficulty:   "难度评估。difficulty_level 为目标对该学习者的实际难度，difficulty_factors 列出影响难度的因素",
+	entities.AnalysisTypeTimeEstimate: "时间预估。estimated_time 为该学习者达成目标所需的学习小时数",
+}
+
+// LLMAnalyzerConfig LLM分析器的计费配置
+type LLMAnalyzerConfig struct {
+	PromptCostPer1K     float64 // 每千输入token费用(美元)
+	CompletionCostPer1K float64 // 每千输出token费用(美元)
+	MonthlyBudget       float64 // 每个用户每月费用上限(美元)，0表示不限
+}
+
+// LLMAnalyzer 调用OpenAI兼容接口的单项分析器，每种分析类型使用各自的提示词和JSON Schema
+// 调用失败、响应不合法或用户超出预算时降级到同类型的规则分析器
+type LLMAnalyzer struct {
+	analysisType string
+	client       *llm.Client
+	fallback     Analyzer
+	usageRepo    repositories.AnalysisUsageRepository
+	taxonomy     *TaxonomyService
+	config       LLMAnalyzerConfig
+}
+
+// NewLLMAnalyzers 为每种分析类型创建LLM分析器，fallbacks为各类型降级使用的分析器
+func NewLLMAnalyzers(
+	client *llm.Client,
+	fallbacks Analyzers,
+	usageRepo repositories.AnalysisUsageRepository,
+	taxonomy *TaxonomyService,
+	config LLMAnalyzerConfig,
+) Analyzers {
+	analyzers := make([]Analyzer, 0, len(entities.IndividualAnalysisTypes))
+	for _, analysisType := range entities.IndividualAnalysisTypes {
+		analyzers = append(analyzers, NewLLMAnalyzer(analysisType, client, fallbacks[analysisType], usageRepo, taxonomy, config))
+	}
+	return NewAnalyzers(analyzers...)
+}
+
+// NewLLMAnalyzer 创建指定分析类型的LLM分析器
+func NewLLMAnalyzer(
+	analysisType string,
+	client *llm.Client,
+	fallback Analyzer,
+	usageRepo repositories.AnalysisUsageRepository,
+	taxonomy *TaxonomyService,
+	config LLMAnalyzerConfig,
+) *LLMAnalyzer {
+	return &LLMAnalyzer{
+		analysisType: analysisType,
+		client:       client,
+		fallback:     fallback,
+		usageRepo:    usageRepo,
+		taxonomy:     taxonomy,
+		config:       config,
+	}
+}
+
+// Name 分析器名称
+func (a *LLMAnalyzer) Name() string {
+	return AnalyzerLLM
+}
+
+// Type 负责的分析类型
+func (a *LLMAnalyzer) Type() string {
+	return a.analysisType
+}
+
+// Analyze 调用模型执行单项分析
+func (a *LLMAnalyzer) Analyze(ctx context.Context, input *AnalysisInput) (*AnalysisOutput, error) {
+	userID := input.Goal.UserID
+
+	exceeded, err := a.budgetExceeded(ctx, input)
+	if err != nil {
+		logger.Warn("统计用户分析费用失败，使用规则分析器",
+			logger.String("type", a.analysisType),
+			logger.String("error", err.Error()))
+		return a.fallback.Analyze(ctx, input)
+	}
+	if exceeded {
+		logger.Info("用户本月分析费用已达上限，使用规则分析器", logger.Uint("user_id", userID))
+		return a.fallback.Analyze(ctx, input)
+	}
+
+	start := time.Now()
+	output, usage, err := a.analyzeWithModel(ctx, input)
+
+	record := &entities.AnalysisUsage{
+		UserID:       userID,
+		GoalID:       input.Goal.ID,
+		Analyzer:     a.Name(),
+		AnalysisType: a.analysisType,
+		Model:        a.client.Model(),
+		DurationMs:   time.Since(start).Milliseconds(),
+	}
+	if usage != nil {
+		record.PromptTokens = usage.PromptTokens
+		record.CompletionTokens = usage.CompletionTokens
+		record.Cost = a.calculateCost(usage)
+	}
+
+	if err != nil {
+		record.Fallback = true
+		record.Error = err.Error()
+		a.recordUsage(ctx, record)
+
+		logger.Warn("LLM分析失败，降级到规则分析器",
+			logger.String("goal_id", input.Goal.ID.String()),
+			logger.String("type", a.analysisType),
+			logger.String("error", err.Error()))
+		return a.fallback.Analyze(ctx, input)
+	}
+
+	a.recordUsage(ctx, record)
+	return output, nil
+}
+
+// llmAnalysisPayload 模型返回的结构化分析结果，只包含本次分析类型要求的字段
+type llmAnalysisPayload struct {
+	SkillGaps            []string            `json:"skill_gaps"`
+	Strengths            []string            `json:"strengths"`
+	Prerequisites        []string            `json:"prerequisites"`
+	MissingPrerequisites []string            `json:"missing_prerequisites"`
+	DifficultyLevel      string              `json:"difficulty_level"`
+	EstimatedTime        int                 `json:"estimated_time"`
+	DifficultyFactors    []string            `json:"difficulty_factors"`
+	Recommendations      []llmRecommendation `json:"recommendations"`
+	ConfidenceScore      float64             `json:"confidence_score"`
+}
+
+// llmRecommendation 模型返回的推荐，知识点以标题引用
+type llmRecommendation struct {
+	Type            string   `json:"type"`
+	Title           string   `json:"title"`
+	Description     string   `json:"description"`
+	Priority        string   `json:"priority"`
+	EstimatedTime   int      `json:"estimated_time"`
+	Reason          string   `json:"reason"`
+	KnowledgePoints []string `json:"knowledge_points"`
+}
+
+// analyzeWithModel 调用模型并校验结果；返回的usage在请求成功但结果不合法时同样有效
+func (a *LLMAnalyzer) analyzeWithModel(ctx context.Context, input *AnalysisInput) (*AnalysisOutput, *llm.Usage, error) {
+	prompt, err := a.buildPrompt(input)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp, err := a.client.ChatCompletion(ctx, &llm.ChatRequest{
+		Messages: []llm.Message{
+			{Role: "system", Content: analysisSystemPrompt + llmAnalysisTasks[a.analysisType]},
+			{Role: "user", Content: prompt},
+		},
+		ResponseFormat: &llm.ResponseFormat{
+			Type: "json_schema",
+			JSONSchema: &llm.JSONSchema{
+				Name:   a.analysisType + "_analysis",
+				Schema: llmAnalysisSchema(a.analysisType),
+				Strict: true,
+			},
+		},
+		Temperature: 0.2,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	usage := &resp.Usage
+
+	var payload llmAnalysisPayload
+	if err := json.Unmarshal([]byte(resp.Content()), &payload); err != nil {
+		return nil, usage, fmt.Errorf("解析模型输出失败: %w", err)
+	}
+	if err := validateLLMPayload(a.analysisType, &payload); err != nil {
+		return nil, usage, err
+	}
+
+	evidence := input.Evidence()
+	switch a.analysisType {
+	case entities.AnalysisTypeSkillGap:
+		evidence.RequiredSkills = len(payload.SkillGaps) + len(payload.Strengths)
+	case entities.AnalysisTypePrerequisite:
+		evidence.RequiredSkills = len(payload.Prerequisites)
+	}
+
+	recommendations := a.linkRecommendations(ctx, input, payload.Recommendations)
+	summaries := make([]string, 0, len(recommendations))
+	for _, rec := range recommendations {
+		summaries = append(summaries, rec.Title)
+	}
+
+	result := entities.AnalysisResult{
+		SchemaVersion:        entities.AnalysisSchemaVersion,
+		SkillGaps:            payload.SkillGaps,
+		Strengths:            payload.Strengths,
+		Prerequisites:        payload.Prerequisites,
+		MissingPrerequisites: payload.MissingPrerequisites,
+		DifficultyLevel:      payload.DifficultyLevel,
+		EstimatedTime:        payload.EstimatedTime,
+		DifficultyFactors:    payload.DifficultyFactors,
+		Evidence:             evidence,
+	}.Extract(a.analysisType)
+	result.Recommendations = summaries
+
+	return &AnalysisOutput{
+		Result:          &result,
+		Recommendations: recommendations,
+		ConfidenceScore: math.Round(payload.ConfidenceScore*100) / 100,
+		Analyzer:        a.Name(),
+	}, usage, nil
+}
+
+// linkRecommendations 将模型按标题引用的知识点解析为知识点ID，并生成路径模板和学习资源
+// 不在类别知识点中的标题视为模型臆造，直接忽略
+func (a *LLMAnalyzer) linkRecommendations(ctx context.Context, input *AnalysisInput, recs []llmRecommendation) entities.Recommendations {
+	byTitle := make(map[string]*entities.KnowledgePoint, len(input.KnowledgePoints))
+	for _, point := range input.KnowledgePoints {
+		byTitle[normalizeSkill(point.Title)] = point
+	}
+	stepHours := func(point *entities.KnowledgePoint) int {
+		return a.taxonomy.StepHours(ctx, input.Goal.Category, point.Difficulty)
+	}
+
+	recommendations := make(entities.Recommendations, 0, len(recs))
+	for _, rec := range recs {
+		var points []*entities.KnowledgePoint
+		seen := map[uuid.UUID]bool{}
+		for _, title := range rec.KnowledgePoints {
+			if point, ok := byTitle[normalizeSkill(title)]; ok && !seen[point.ID] {
+				seen[point.ID] = true
+				points = append(points, point)
+			}
+		}
+
+		linked := entities.Recommendation{
+			Type:          rec.Type,
+			Title:         rec.Title,
+			Description:   rec.Description,
+			Priority:      rec.Priority,
+			EstimatedTime: rec.EstimatedTime,
+			Reason:        rec.Reason,
+			AnalysisType:  a.analysisType,
+		}
+		if len(points) > 0 {
+			linked.KnowledgePointIDs = knowledgePointIDs(points)
+			switch rec.Type {
+			case "learning_path", "skill_building":
+				linked.PathTemplate = newPathTemplate(rec.Title, points, stepHours)
+			case "resource":
+				linked.Resources = knowledgePointResources(points)
+			}
+		}
+		recommendations = append(recommendations, linked)
+	}
+	return recommendations
+}
+
+// validateLLMPayload 校验模型输出中本次分析类型要求的字段及其取值范围
+func validateLLMPayload(analysisType string, p *llmAnalysisPayload) error {
+	switch analysisType {
+	case entities.AnalysisTypeSkillGap:
+		if p.SkillGaps == nil || p.Strengths == nil {
+			return fmt.Errorf("模型输出缺少必要字段")
+		}
+	case entities.AnalysisTypePrerequisite:
+		if p.Prerequisites == nil || p.MissingPrerequisites == nil {
+			return fmt.Errorf("模型输出缺少必要字段")
+		}
+	case entities.AnalysisTypeDifficulty:
+		if !isValidDifficulty(p.DifficultyLevel) {
+			return fmt.Errorf("模型输出的难度无效: %q", p.DifficultyLevel)
+		}
+		if p.DifficultyFactors == nil {
+			return fmt.Errorf("模型输出缺少必要字段")
+		}
+	case entities.AnalysisTypeTimeEstimate:
+		if p.EstimatedTime <= 0 || p.EstimatedTime > 5000 {
+			return fmt.Errorf("模型输出的预估时间超出范围: %d", p.EstimatedTime)
+		}
+	}
+	if p.ConfidenceScore < 0 || p.ConfidenceScore > 1 {
+		return fmt.Errorf("模型输出的置信度超出范围: %v", p.ConfidenceScore)
+	}
+	if p.Recommendations == nil {
+		return fmt.Errorf("模型输出缺少必要字段")
+	}
+	for i, rec := range p.Recommendations {
+		if strings.TrimSpace(rec.Title) == "" {
+			return fmt.Errorf("第%d条推荐缺少标题", i+1)
+		}
+		switch rec.Type {
+		case "learning_path", "resource", "skill_building":
+		default:
+			return fmt.Errorf("第%d条推荐类型无效: %q", i+1, rec.Type)
+		}
+		switch rec.Priority {
+		case "high", "medium", "low":
+		default:
+			return fmt.Errorf("第%d条推荐优先级无效: %q", i+1, rec.Priority)
+		}
+		if rec.EstimatedTime < 0 {
+			return fmt.Errorf("第%d条推荐预估时间无效: %d", i+1, rec.EstimatedTime)
+		}
+	}
+	return nil
+}
+
+// buildPrompt 构建用户提示词，包含目标、学习画像和类别知识点
+func (a *LLMAnalyzer) buildPrompt(input *AnalysisInput) (string, error) {
+	goal, profile := input.Goal, input.Profile
+
+	type pointInfo struct {
+		Title         string `json:"title"`
+		Difficulty    string `json:"difficulty"`
+		Mastered      bool   `json:"mastered"`
+		Prerequisites string `json:"prerequisites,omitempty"`
+	}
+	points := make([]pointInfo, 0, len(input.KnowledgePoints))
+	for i, point := range input.KnowledgePoints {
+		if i >= maxPromptKnowledgePoints {
+			break
+		}
+		points = append(points, pointInfo{
+			Title:         point.Title,
+			Difficulty:    point.Difficulty,
+			Mastered:      profile.HasMastered(point),
+			Prerequisites: point.Prerequisites,
+		})
+	}
+
+	completedPaths := make([]string, 0, len(profile.CompletedPaths))
+	for _, path := range profile.CompletedPaths {
+		completedPaths = append(completedPaths, path.Title)
+	}
+	completedGoals := make([]string, 0, len(profile.CompletedGoals))
+	for _, g := range profile.CompletedGoals {
+		completedGoals = append(completedGoals, fmt.Sprintf("%s(%s)", g.Title, g.Category))
+	}
+
+	data, err := json.Marshal(map[string]interface{}{
+		"goal": map[string]interface{}{
+			"title":       goal.Title,
+			"description": goal.Description,
+			"category":    goal.Category,
+			"difficulty":  goal.Difficulty,
+		},
+		"learner": map[string]interface{}{
+			"completed_steps": completedPaths,
+			"completed_goals": completedGoals,
+			"goal_count":      len(profile.Goals),
+		},
+		"knowledge_points": points,
+	})
+	if err != nil {
+		return "", fmt.Errorf("构建分析提示词失败: %w", err)
+	}
+	return string(data), nil
+}
+
+// budgetExceeded 检查用户本月分析费用是否已达上限
+func (a *LLMAnalyzer) budgetExceeded(ctx context.Context, input *AnalysisInput) (bool, error) {
+	if a.config.MonthlyBudget <= 0 || a.usageRepo == nil {
+		return false, nil
+	}
+
+	now := time.Now()
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	spent, err := a.usageRepo.SumCostByUserID(ctx, input.Goal.UserID, monthStart)
+	if err != nil {
+		return false, err
+	}
+	return spent >= a.config.MonthlyBudget, nil
+}
+
+// calculateCost 根据token用量计算费用
+func (a *LLMAnalyzer) calculateCost(usage *llm.Usage) float64 {
+	return float64(usage.PromptTokens)/1000*a.config.PromptCostPer1K +
+		float64(usage.CompletionTokens)/1000*a.config.CompletionCostPer1K
+}
+
+// recordUsage 记录调用用量，记录失败不影响分析结果
+func (a *LLMAnalyzer) recordUsage(ctx context.Context, usage *entities.AnalysisUsage) {
+	if a.usageRepo == nil {
+		return
+	}
+	if err := a.usageRepo.Create(ctx, usage); err != nil {
+		logger.Error("记录分析调用用量失败", logger.String("error", err.Error()))
+	}
+}
+
+// analysisSystemPrompt 分析系统提示词，其后接本次分析类型的任务说明
+const analysisSystemPrompt = `你是医学与药学教育领域的学习规划顾问。根据用户提供的学习目标、学习者已完成的内容和该类别的知识点，
+完成指定的单项分析，并给出可执行的学习建议。
+要求：
+- mastered 为 true 的知识点视为已掌握
+- 每条推荐都要在 reason 中说明依据的学习者数据
+- 每条推荐在 knowledge_points 中列出建议学习的知识点标题（只能使用知识点列表中的标题），没有对应知识点时为空数组
+- confidence_score 反映本项分析可用数据的充分程度(0-1)
+- 只输出符合 JSON Schema 的内容
+本次分析：`
+
+// analysisResultProperties 分析结果各字段的JSON Schema
+var analysisResultProperties = map[string]interface{}{
+	"skill_gaps":            stringArraySchema,
+	"strengths":             stringArraySchema,
+	"prerequisites":         stringArraySchema,
+	"missing_prerequisites": stringArraySchema,
+	"difficulty_level": map[string]interface{}{
+		"type": "string",
+		"enum": []string{"beginner", "intermediate", "advanced"},
+	},
+	"estimated_time":     map[string]interface{}{"type": "integer"},
+	"difficulty_factors": stringArraySchema,
+}
+
+// analysisRecommendationsSchema 推荐列表的JSON Schema
+var analysisRecommendationsSchema = map[string]interface{}{
+	"type": "array",
+	"items": map[string]interface{}{
+		"type":                 "object",
+		"additionalProperties": false,
+		"required":             []string{"type", "title", "description", "priority", "estimated_time", "reason", "knowledge_points"},
+		"properties": map[string]interface{}{
+			"type": map[string]interface{}{
+				"type": "string",
+				"enum": []string{"learning_path", "resource", "skill_building"},
+			},
+			"title":       map[string]interface{}{"type": "string"},
+			"description": map[string]interface{}{"type": "string"},
+			"priority": map[string]interface{}{
+				"type": "string",
+				"enum": []string{"high", "medium", "low"},
+			},
+			"estimated_time":   map[string]interface{}{"type": "integer"},
+			"reason":           map[string]interface{}{"type": "string"},
+			"knowledge_points": stringArraySchema,
+		},
+	},
+}
+
+var stringArraySchema = map[string]interface{}{
+	"type":  "array",
+	"items": map[string]interface{}{"type": "string"},
+}
+
+// llmAnalysisSchema 构建指定分析类型的JSON Schema，只包含该类型的结果字段、推荐和置信度
+func llmAnalysisSchema(analysisType string) map[string]interface{} {
+	fields := llmAnalysisFields[analysisType]
+	required := append(append([]string{}, fields...), "recommendations", "confidence_score")
+	properties := make(map[string]interface{}, len(required))
+	for _, field := range fields {
+		properties[field] = analysisResultProperties[field]
+	}
+	properties["recommendations"] = analysisRecommendationsSchema
+	properties["confidence_score"] = map[string]interface{}{"type": "number"}
+
+	return map[string]interface{}{
+		"type":                 "object",
+		"additionalProperties": false,
+		"required":             required,
+		"properties":           properties,
+	}
+}
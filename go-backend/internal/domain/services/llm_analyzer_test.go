@@ -0,0 +1,256 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/pkg/llm"
+	"sical-go-backend/pkg/llm/llmstub"
+)
+
+// fakeUsageRepository 内存中的调用记录仓储
+type fakeUsageRepository struct {
+	mu     sync.Mutex
+	spent  float64
+	usages []*entities.AnalysisUsage
+}
+
+func (r *fakeUsageRepository) Create(ctx context.Context, usage *entities.AnalysisUsage) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.usages = append(r.usages, usage)
+	return nil
+}
+
+func (r *fakeUsageRepository) GetByUserID(ctx context.Context, userID uint, from *time.Time) ([]*entities.AnalysisUsage, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*entities.AnalysisUsage{}, r.usages...), nil
+}
+
+func (r *fakeUsageRepository) SumCostByUserID(ctx context.Context, userID uint, from time.Time) (float64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	total := r.spent
+	for _, usage := range r.usages {
+		total += usage.Cost
+	}
+	return total, nil
+}
+
+// fakeFallbackAnalyzer 记录调用次数的降级分析器
+type fakeFallbackAnalyzer struct {
+	analysisType string
+	calls        int
+}
+
+func (a *fakeFallbackAnalyzer) Name() string { return AnalyzerRules }
+
+func (a *fakeFallbackAnalyzer) Type() string { return a.analysisType }
+
+func (a *fakeFallbackAnalyzer) Analyze(ctx context.Context, input *AnalysisInput) (*AnalysisOutput, error) {
+	a.calls++
+	return &AnalysisOutput{
+		Result:          &entities.AnalysisResult{SchemaVersion: entities.AnalysisSchemaVersion},
+		Recommendations: entities.Recommendations{},
+		ConfidenceScore: 0.3,
+		Analyzer:        a.Name(),
+	}, nil
+}
+
+// newTestAnalysisInput 两个知识点中第一个已掌握
+func newTestAnalysisInput() *AnalysisInput {
+	mastered := &entities.KnowledgePoint{ID: uuid.New(), Title: "药物代谢", Difficulty: "beginner"}
+	missing := &entities.KnowledgePoint{ID: uuid.New(), Title: "药物相互作用", Difficulty: "intermediate"}
+	return &AnalysisInput{
+		Goal: &entities.LearningGoal{
+			ID:         uuid.New(),
+			UserID:     1,
+			Title:      "掌握临床药理学",
+			Category:   "pharmacology",
+			Difficulty: "intermediate",
+		},
+		Profile:         &UserLearningProfile{Mastery: map[uuid.UUID]float64{mastered.ID: 1}},
+		KnowledgePoints: []*entities.KnowledgePoint{mastered, missing},
+	}
+}
+
+func newTestLLMAnalyzer(baseURL string, fallback Analyzer, usageRepo *fakeUsageRepository, budget float64) *LLMAnalyzer {
+	client := llm.NewClient(llm.Config{BaseURL: baseURL, Model: "stub-model", Timeout: time.Second})
+	return NewLLMAnalyzer(fallback.Type(), client, fallback, usageRepo, nil, LLMAnalyzerConfig{
+		PromptCostPer1K:     0.001,
+		CompletionCostPer1K: 0.002,
+		MonthlyBudget:       budget,
+	})
+}
+
+func TestLLMAnalyzerRecordsUsage(t *testing.T) {
+	stub := llmstub.NewHandler()
+	server := httptest.NewServer(stub)
+	defer server.Close()
+
+	fallback := &fakeFallbackAnalyzer{analysisType: entities.AnalysisTypeSkillGap}
+	usageRepo := &fakeUsageRepository{}
+	analyzer := newTestLLMAnalyzer(server.URL, fallback, usageRepo, 1)
+
+	input := newTestAnalysisInput()
+	output, err := analyzer.Analyze(context.Background(), input)
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+	if output.Analyzer != AnalyzerLLM || fallback.calls != 0 {
+		t.Fatalf("analyzer = %s, fallback calls = %d, want llm without fallback", output.Analyzer, fallback.calls)
+	}
+	if len(output.Result.SkillGaps) != 1 || output.Result.SkillGaps[0] != "药物相互作用" {
+		t.Fatalf("skill gaps = %v", output.Result.SkillGaps)
+	}
+	if len(output.Recommendations) != 1 || len(output.Recommendations[0].KnowledgePointIDs) != 1 {
+		t.Fatalf("recommendations = %+v, want one linked to the missing point", output.Recommendations)
+	}
+
+	if len(usageRepo.usages) != 1 {
+		t.Fatalf("usage records = %d, want 1", len(usageRepo.usages))
+	}
+	usage := usageRepo.usages[0]
+	if usage.Fallback || usage.Error != "" {
+		t.Fatalf("usage = %+v, want successful call", usage)
+	}
+	if usage.UserID != input.Goal.UserID || usage.GoalID != input.Goal.ID ||
+		usage.AnalysisType != entities.AnalysisTypeSkillGap || usage.Model != "stub-model" {
+		t.Fatalf("usage = %+v, unexpected identifiers", usage)
+	}
+	if usage.PromptTokens <= 0 || usage.CompletionTokens <= 0 {
+		t.Fatalf("usage tokens = %d/%d, want both > 0", usage.PromptTokens, usage.CompletionTokens)
+	}
+	wantCost := float64(usage.PromptTokens)/1000*0.001 + float64(usage.CompletionTokens)/1000*0.002
+	if usage.Cost != wantCost {
+		t.Fatalf("usage cost = %v, want %v", usage.Cost, wantCost)
+	}
+}
+
+func TestLLMAnalyzerFallsBackOnInvalidOutput(t *testing.T) {
+	outputs := map[string]string{
+		"not json":        `analysis done`,
+		"missing fields":  `{"skill_gaps":["药物相互作用"],"recommendations":[],"confidence_score":0.8}`,
+		"bad confidence":  `{"skill_gaps":[],"strengths":[],"recommendations":[],"confidence_score":3}`,
+		"bad rec type":    `{"skill_gaps":[],"strengths":[],"recommendations":[{"type":"video","title":"t","description":"","priority":"high","estimated_time":1,"reason":"","knowledge_points":[]}],"confidence_score":0.5}`,
+		"missing recs":    `{"skill_gaps":[],"strengths":[],"confidence_score":0.5}`,
+		"rec no priority": `{"skill_gaps":[],"strengths":[],"recommendations":[{"type":"resource","title":"t","description":"","priority":"","estimated_time":1,"reason":"","knowledge_points":[]}],"confidence_score":0.5}`,
+	}
+	for name, content := range outputs {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":` + quoteJSON(content) + `}}],"usage":{"prompt_tokens":100,"completion_tokens":20,"total_tokens":120}}`))
+		}))
+
+		fallback := &fakeFallbackAnalyzer{analysisType: entities.AnalysisTypeSkillGap}
+		usageRepo := &fakeUsageRepository{}
+		output, err := newTestLLMAnalyzer(server.URL, fallback, usageRepo, 0).Analyze(context.Background(), newTestAnalysisInput())
+		server.Close()
+
+		if err != nil {
+			t.Fatalf("%s: Analyze() error = %v", name, err)
+		}
+		if output.Analyzer != AnalyzerRules || fallback.calls != 1 {
+			t.Fatalf("%s: analyzer = %s, fallback calls = %d, want rules fallback", name, output.Analyzer, fallback.calls)
+		}
+		if len(usageRepo.usages) != 1 {
+			t.Fatalf("%s: usage records = %d, want 1", name, len(usageRepo.usages))
+		}
+		usage := usageRepo.usages[0]
+		if !usage.Fallback || usage.Error == "" {
+			t.Fatalf("%s: usage = %+v, want fallback with error", name, usage)
+		}
+		// 模型已响应，token仍计入费用
+		if usage.PromptTokens != 100 || usage.CompletionTokens != 20 || usage.Cost <= 0 {
+			t.Fatalf("%s: usage = %+v, want tokens and cost recorded", name, usage)
+		}
+	}
+}
+
+func TestLLMAnalyzerFallsBackOnAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	fallback := &fakeFallbackAnalyzer{analysisType: entities.AnalysisTypeDifficulty}
+	usageRepo := &fakeUsageRepository{}
+	output, err := newTestLLMAnalyzer(server.URL, fallback, usageRepo, 0).Analyze(context.Background(), newTestAnalysisInput())
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+	if output.Analyzer != AnalyzerRules || fallback.calls != 1 {
+		t.Fatalf("analyzer = %s, fallback calls = %d, want rules fallback", output.Analyzer, fallback.calls)
+	}
+	if len(usageRepo.usages) != 1 || !usageRepo.usages[0].Fallback || usageRepo.usages[0].Cost != 0 {
+		t.Fatalf("usages = %+v, want one fallback record without cost", usageRepo.usages)
+	}
+}
+
+func TestLLMAnalyzerFallsBackWhenBudgetExceeded(t *testing.T) {
+	stub := llmstub.NewHandler()
+	server := httptest.NewServer(stub)
+	defer server.Close()
+
+	fallback := &fakeFallbackAnalyzer{analysisType: entities.AnalysisTypeTimeEstimate}
+	usageRepo := &fakeUsageRepository{spent: 1}
+	output, err := newTestLLMAnalyzer(server.URL, fallback, usageRepo, 1).Analyze(context.Background(), newTestAnalysisInput())
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+	if output.Analyzer != AnalyzerRules || fallback.calls != 1 {
+		t.Fatalf("analyzer = %s, fallback calls = %d, want rules fallback", output.Analyzer, fallback.calls)
+	}
+	if stub.Requests() != 0 {
+		t.Fatalf("model requests = %d, want 0", stub.Requests())
+	}
+	if len(usageRepo.usages) != 0 {
+		t.Fatalf("usage records = %d, want 0", len(usageRepo.usages))
+	}
+}
+
+func TestLLMAnalyzersCoverAllTypesWithStub(t *testing.T) {
+	stub := llmstub.NewHandler()
+	server := httptest.NewServer(stub)
+	defer server.Close()
+
+	fallbacks := Analyzers{}
+	for _, analysisType := range entities.IndividualAnalysisTypes {
+		fallbacks[analysisType] = &fakeFallbackAnalyzer{analysisType: analysisType}
+	}
+	usageRepo := &fakeUsageRepository{}
+	client := llm.NewClient(llm.Config{BaseURL: server.URL, Model: "stub-model", Timeout: time.Second})
+	analyzers := NewLLMAnalyzers(client, fallbacks, usageRepo, nil, LLMAnalyzerConfig{})
+
+	for _, analysisType := range entities.IndividualAnalysisTypes {
+		output, err := analyzers[analysisType].Analyze(context.Background(), newTestAnalysisInput())
+		if err != nil {
+			t.Fatalf("%s: Analyze() error = %v", analysisType, err)
+		}
+		if output.Analyzer != AnalyzerLLM {
+			t.Fatalf("%s: analyzer = %s, want llm", analysisType, output.Analyzer)
+		}
+	}
+	if got := int(stub.Requests()); got != len(entities.IndividualAnalysisTypes) {
+		t.Fatalf("model requests = %d, want %d", got, len(entities.IndividualAnalysisTypes))
+	}
+	for _, usage := range usageRepo.usages {
+		if usage.Fallback {
+			t.Fatalf("usage = %+v, want no fallback", usage)
+		}
+	}
+}
+
+// quoteJSON 将字符串编码为JSON字符串字面量
+func quoteJSON(s string) string {
+	data, _ := json.Marshal(s)
+	return string(data)
+}
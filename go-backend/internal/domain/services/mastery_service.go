@@ -0,0 +1,180 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+	apperrors "sical-go-backend/pkg/errors"
+	"sical-go-backend/pkg/logger"
+)
+
+// 各来源观测在掌握度中的权重；诊断为专门测量，权重最高
+const (
+	diagnosticMasteryWeight = 0.6
+	assessmentMasteryWeight = 0.4
+	reviewMasteryWeight     = 0.3
+	pathMasteryWeight       = 0.2
+)
+
+// pathCompletionLevel 完成学习步骤视为的掌握度及回忆质量
+const (
+	pathCompletionLevel   = 0.8
+	pathCompletionQuality = 4
+)
+
+// MasteryService 用户知识点掌握度服务，汇总测评、诊断、学习步骤和复习结果，并按SM-2安排复习
+type MasteryService struct {
+	masteryRepo   repositories.UserKnowledgeMasteryRepository
+	knowledgeRepo repositories.KnowledgePointRepository
+}
+
+// NewMasteryService 创建用户知识点掌握度服务
+func NewMasteryService(
+	masteryRepo repositories.UserKnowledgeMasteryRepository,
+	knowledgeRepo repositories.KnowledgePointRepository,
+) *MasteryService {
+	return &MasteryService{
+		masteryRepo:   masteryRepo,
+		knowledgeRepo: knowledgeRepo,
+	}
+}
+
+// GetMastery 获取用户各知识点的掌握度(0-1)，实现KnowledgeMasteryProvider
+func (s *MasteryService) GetMastery(ctx context.Context, userID uint) (map[uuid.UUID]float64, error) {
+	records, err := s.masteryRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	mastery := make(map[uuid.UUID]float64, len(records))
+	for _, record := range records {
+		mastery[record.KnowledgePointID] = record.Mastery
+	}
+	return mastery, nil
+}
+
+// List 获取用户全部知识点掌握度
+func (s *MasteryService) List(ctx context.Context, userID uint) ([]*entities.UserKnowledgeMastery, error) {
+	return s.masteryRepo.ListByUser(ctx, userID)
+}
+
+// Get 获取用户对单个知识点的掌握度
+func (s *MasteryService) Get(ctx context.Context, userID uint, pointID uuid.UUID) (*entities.UserKnowledgeMastery, error) {
+	record, err := s.masteryRepo.Get(ctx, userID, pointID)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return nil, apperrors.New(apperrors.ErrorTypeNotFound, 404, "暂无该知识点的学习记录")
+	}
+	return record, nil
+}
+
+// ListDue 分页获取截至今天结束时到期需要复习的知识点
+func (s *MasteryService) ListDue(ctx context.Context, userID uint, now time.Time, offset, limit int) ([]*entities.UserKnowledgeMastery, int64, error) {
+	endOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).AddDate(0, 0, 1)
+	return s.masteryRepo.ListDue(ctx, userID, endOfDay, offset, limit)
+}
+
+// Review 提交复习结果，quality为回忆质量(0-5)
+func (s *MasteryService) Review(ctx context.Context, userID uint, pointID uuid.UUID, quality int) (*entities.UserKnowledgeMastery, error) {
+	if quality < 0 || quality > entities.MaxReviewQuality {
+		return nil, apperrors.New(apperrors.ErrorTypeValidation, 400, "回忆质量必须在0到5之间")
+	}
+	if _, err := s.knowledgeRepo.GetByID(ctx, pointID); err != nil {
+		return nil, apperrors.New(apperrors.ErrorTypeNotFound, 404, "知识点不存在").WithCause(err)
+	}
+
+	level := float64(quality) / entities.MaxReviewQuality
+	return s.record(ctx, userID, pointID, level, reviewMasteryWeight, entities.MasterySourceReview, quality)
+}
+
+// RecordAttempt 根据已评分作答中各知识点的得分率更新掌握度
+func (s *MasteryService) RecordAttempt(ctx context.Context, attempt *entities.AssessmentAttempt, assessment *entities.Assessment) {
+	type score struct{ earned, total float64 }
+	scores := make(map[uuid.UUID]*score)
+	questions := questionIndex(assessment)
+	for _, answer := range attempt.Answers {
+		question, ok := questions[answer.QuestionID]
+		if !ok || question.KnowledgePointID == nil || answer.EarnedPoints == nil {
+			continue
+		}
+		sc, ok := scores[*question.KnowledgePointID]
+		if !ok {
+			sc = &score{}
+			scores[*question.KnowledgePointID] = sc
+		}
+		sc.earned += *answer.EarnedPoints
+		sc.total += question.Points
+	}
+
+	for pointID, sc := range scores {
+		if sc.total <= 0 {
+			continue
+		}
+		ratio := sc.earned / sc.total
+		if _, err := s.record(ctx, attempt.UserID, pointID, ratio, assessmentMasteryWeight, entities.MasterySourceAssessment, entities.QualityFromScore(ratio)); err != nil {
+			logger.Error("根据测评更新掌握度失败",
+				logger.String("attempt_id", attempt.ID.String()),
+				logger.String("knowledge_point_id", pointID.String()),
+				logger.String("error", err.Error()))
+		}
+	}
+}
+
+// RecordDiagnostic 根据完成的诊断测评更新掌握度，未作答的知识点不计入
+func (s *MasteryService) RecordDiagnostic(ctx context.Context, session *entities.DiagnosticSession) {
+	for _, estimate := range session.Estimates {
+		if estimate.Answered == 0 {
+			continue
+		}
+		if _, err := s.record(ctx, session.UserID, estimate.KnowledgePointID, estimate.Mastery, diagnosticMasteryWeight, entities.MasterySourceDiagnostic, entities.QualityFromScore(estimate.Mastery)); err != nil {
+			logger.Error("根据诊断更新掌握度失败",
+				logger.String("session_id", session.ID.String()),
+				logger.String("knowledge_point_id", estimate.KnowledgePointID.String()),
+				logger.String("error", err.Error()))
+		}
+	}
+}
+
+// OnPathTransition 学习步骤完成后更新步骤关联知识点的掌握度，作为状态机的after钩子注册
+func (s *MasteryService) OnPathTransition(ctx context.Context, event *TransitionEvent) error {
+	if event.Path == nil || event.To != string(entities.PathStatusCompleted) {
+		return nil
+	}
+	userID := event.Path.LearningGoal.UserID
+	if userID == 0 {
+		return nil
+	}
+	for _, point := range event.Path.KnowledgePoints {
+		if _, err := s.record(ctx, userID, point.ID, pathCompletionLevel, pathMasteryWeight, entities.MasterySourcePath, pathCompletionQuality); err != nil {
+			// 掌握度更新失败不影响步骤状态转换
+			logger.Error("根据学习步骤更新掌握度失败",
+				logger.String("path_id", event.Path.ID.String()),
+				logger.String("knowledge_point_id", point.ID.String()),
+				logger.String("error", err.Error()))
+		}
+	}
+	return nil
+}
+
+// record 合并一次掌握度观测并重新安排复习
+func (s *MasteryService) record(ctx context.Context, userID uint, pointID uuid.UUID, level, weight float64, source entities.MasterySource, quality int) (*entities.UserKnowledgeMastery, error) {
+	now := time.Now()
+	record, err := s.masteryRepo.Get(ctx, userID, pointID)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		record = entities.NewUserKnowledgeMastery(userID, pointID, now)
+	}
+
+	record.Observe(level, weight, source)
+	record.Schedule(quality, now)
+	if err := s.masteryRepo.Save(ctx, record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
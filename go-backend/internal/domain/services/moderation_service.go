@@ -0,0 +1,501 @@
+package services
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+	apperrors "sical-go-backend/pkg/errors"
+	"sical-go-backend/pkg/logger"
+)
+
+// reportHideThreshold 评论被举报达到该次数后自动隐藏，等待人工审核
+const reportHideThreshold = 5
+
+// 审核处理动作
+const (
+	ModerationResolveApprove = "approve" // 内容无问题
+	ModerationResolveReject  = "reject"  // 内容违规，隐藏评论或清空简介
+	ModerationResolveSuspend = "suspend" // 内容违规并暂停作者账号
+	ModerationResolveBan     = "ban"     // 内容违规并封禁作者账号
+)
+
+// 审核隐藏评论时记录的原因
+const (
+	pendingReviewReason = "内容待审核"
+	rejectedReason      = "内容违规"
+)
+
+// ModerationRuleInput 过滤规则内容
+type ModerationRuleInput struct {
+	Name      string `json:"name"`
+	Pattern   string `json:"pattern"`
+	MatchType string `json:"match_type"`
+	Action    string `json:"action"`
+	Category  string `json:"category"`
+	IsActive  *bool  `json:"is_active"`
+}
+
+// ModerationService 内容审核服务
+// 发布前由Moderator自动判定，命中拦截规则的内容拒绝发布，命中审核规则或被举报的内容进入人工审核队列
+type ModerationService struct {
+	moderator   Moderator
+	ruleRepo    repositories.ModerationRuleRepository
+	caseRepo    repositories.ModerationCaseRepository
+	userRepo    repositories.UserRepository
+	profileRepo repositories.UserProfileRepository
+	sessionRepo repositories.UserSessionRepository
+	commentRepo repositories.CommentRepository
+}
+
+// NewModerationService 创建内容审核服务
+func NewModerationService(
+	moderator Moderator,
+	ruleRepo repositories.ModerationRuleRepository,
+	caseRepo repositories.ModerationCaseRepository,
+	userRepo repositories.UserRepository,
+	profileRepo repositories.UserProfileRepository,
+	sessionRepo repositories.UserSessionRepository,
+	commentRepo repositories.CommentRepository,
+) *ModerationService {
+	return &ModerationService{
+		moderator:   moderator,
+		ruleRepo:    ruleRepo,
+		caseRepo:    caseRepo,
+		userRepo:    userRepo,
+		profileRepo: profileRepo,
+		sessionRepo: sessionRepo,
+		commentRepo: commentRepo,
+	}
+}
+
+// CheckAuthor 检查用户是否可以发布内容，暂停或封禁的账号不能发布
+func (s *ModerationService) CheckAuthor(ctx context.Context, userID uint) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return apperrors.New(apperrors.ErrorTypeNotFound, 404, "用户不存在").WithCause(err)
+	}
+	if user.IsSuspended() || user.IsBanned() {
+		return apperrors.New(apperrors.ErrorTypeForbidden, 403, "账号已被限制发布内容").
+			WithDetail("status", user.Status)
+	}
+	return nil
+}
+
+// Screen 发布前自动审核内容，命中拦截规则时记录案件并返回错误
+// 审核器出错时放行内容，避免审核服务故障影响正常发布
+func (s *ModerationService) Screen(ctx context.Context, contentType entities.ModerationContentType, contentID string, authorID uint, text string) (*ModerationVerdict, error) {
+	verdict, err := s.moderator.Check(ctx, text)
+	if err != nil {
+		logger.Error("内容自动审核失败",
+			logger.String("content_type", string(contentType)),
+			logger.String("error", err.Error()))
+		return &ModerationVerdict{Action: entities.ModerationAllow}, nil
+	}
+	if verdict.Action != entities.ModerationBlock {
+		return verdict, nil
+	}
+
+	now := time.Now()
+	blocked := s.newAutoCase(contentType, contentID, authorID, text, verdict)
+	blocked.Status = string(entities.ModerationRejected)
+	blocked.Resolution = ModerationResolveReject
+	blocked.ResolvedAt = &now
+	if err := s.caseRepo.Create(ctx, blocked); err != nil {
+		logger.Error("记录拦截内容失败", logger.String("error", err.Error()))
+	}
+
+	logger.Info("内容被自动拦截",
+		logger.String("content_type", string(contentType)),
+		logger.Int("author_id", int(authorID)),
+		logger.String("rule", verdict.RuleName))
+	return verdict, apperrors.New(apperrors.ErrorTypeValidation, 400, "内容包含违规信息，无法发布").
+		WithDetail("category", verdict.Category)
+}
+
+// FlagForReview 将自动判定需要人工审核的内容加入审核队列，内容已有待处理案件时不重复创建
+func (s *ModerationService) FlagForReview(ctx context.Context, contentType entities.ModerationContentType, contentID string, authorID uint, text string, verdict *ModerationVerdict) {
+	if verdict == nil || verdict.Action != entities.ModerationReview {
+		return
+	}
+	existing, err := s.caseRepo.GetPending(ctx, string(contentType), contentID)
+	if err == nil && existing != nil {
+		return
+	}
+	if err := s.caseRepo.Create(ctx, s.newAutoCase(contentType, contentID, authorID, text, verdict)); err != nil {
+		logger.Error("创建审核案件失败",
+			logger.String("content_type", string(contentType)),
+			logger.String("content_id", contentID),
+			logger.String("error", err.Error()))
+	}
+}
+
+// Report 举报评论或用户简介，同一内容的举报累计到同一个待处理案件
+func (s *ModerationService) Report(ctx context.Context, reporterID uint, contentType, contentID, reason, detail string) (*entities.ModerationCase, error) {
+	authorID, content, err := s.loadContent(ctx, entities.ModerationContentType(contentType), contentID)
+	if err != nil {
+		return nil, err
+	}
+	if authorID == reporterID {
+		return nil, apperrors.New(apperrors.ErrorTypeValidation, 400, "不能举报自己的内容")
+	}
+
+	moderationCase, err := s.caseRepo.GetPending(ctx, contentType, contentID)
+	if err != nil {
+		return nil, err
+	}
+	if moderationCase == nil {
+		moderationCase = &entities.ModerationCase{
+			ContentType: contentType,
+			ContentID:   contentID,
+			AuthorID:    authorID,
+			Content:     content,
+			Source:      string(entities.ModerationSourceReport),
+			Status:      string(entities.ModerationPending),
+		}
+		if err := s.caseRepo.Create(ctx, moderationCase); err != nil {
+			// 并发举报时案件可能已由其他请求创建
+			existing, getErr := s.caseRepo.GetPending(ctx, contentType, contentID)
+			if getErr != nil || existing == nil {
+				return nil, err
+			}
+			moderationCase = existing
+		}
+	}
+
+	added, err := s.caseRepo.AddReport(ctx, &entities.ContentReport{
+		CaseID:      moderationCase.ID,
+		ContentType: contentType,
+		ContentID:   contentID,
+		ReporterID:  reporterID,
+		Reason:      reason,
+		Detail:      strings.TrimSpace(detail),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !added {
+		return nil, apperrors.New(apperrors.ErrorTypeConflict, 409, "您已举报过该内容")
+	}
+	moderationCase.ReportCount++
+
+	if contentType == string(entities.ModerationContentComment) && moderationCase.ReportCount >= reportHideThreshold {
+		s.hideComment(ctx, contentID, nil, pendingReviewReason)
+	}
+	return moderationCase, nil
+}
+
+// ListCases 按条件分页获取审核案件
+func (s *ModerationService) ListCases(ctx context.Context, filter repositories.ModerationCaseFilter, offset, limit int) ([]*entities.ModerationCase, int64, error) {
+	return s.caseRepo.List(ctx, filter, offset, limit)
+}
+
+// GetCase 获取审核案件及举报记录
+func (s *ModerationService) GetCase(ctx context.Context, id uuid.UUID) (*entities.ModerationCase, error) {
+	moderationCase, err := s.caseRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, apperrors.New(apperrors.ErrorTypeNotFound, 404, "审核案件不存在").WithCause(err)
+	}
+	return moderationCase, nil
+}
+
+// ResolveCase 处理审核案件，action为approve、reject、suspend或ban
+func (s *ModerationService) ResolveCase(ctx context.Context, moderatorID uint, id uuid.UUID, action, note string) (*entities.ModerationCase, error) {
+	moderationCase, err := s.GetCase(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if moderationCase.Status != string(entities.ModerationPending) {
+		return nil, apperrors.New(apperrors.ErrorTypeConflict, 409, "审核案件已处理")
+	}
+
+	var authorStatus entities.UserStatus
+	switch action {
+	case ModerationResolveApprove, ModerationResolveReject:
+	case ModerationResolveSuspend:
+		authorStatus = entities.StatusSuspended
+	case ModerationResolveBan:
+		authorStatus = entities.StatusBanned
+	default:
+		return nil, apperrors.New(apperrors.ErrorTypeValidation, 400, "审核动作无效").WithDetail("action", action)
+	}
+	if authorStatus != "" {
+		author, err := s.userRepo.GetByID(ctx, moderationCase.AuthorID)
+		if err != nil {
+			return nil, apperrors.New(apperrors.ErrorTypeNotFound, 404, "内容作者不存在").WithCause(err)
+		}
+		if author.IsAdmin() || author.IsModerator() || author.Role == "super_admin" {
+			return nil, apperrors.New(apperrors.ErrorTypeForbidden, 403, "不能限制管理员或审核员账号")
+		}
+	}
+
+	now := time.Now()
+	moderationCase.Status = string(entities.ModerationRejected)
+	if action == ModerationResolveApprove {
+		moderationCase.Status = string(entities.ModerationApproved)
+	}
+	moderationCase.Resolution = action
+	moderationCase.ResolutionNote = strings.TrimSpace(note)
+	moderationCase.ResolvedBy = &moderatorID
+	moderationCase.ResolvedAt = &now
+
+	resolved, err := s.caseRepo.Resolve(ctx, moderationCase)
+	if err != nil {
+		return nil, err
+	}
+	if !resolved {
+		return nil, apperrors.New(apperrors.ErrorTypeConflict, 409, "审核案件已处理")
+	}
+
+	if err := s.applyResolution(ctx, moderatorID, moderationCase); err != nil {
+		return nil, err
+	}
+	if authorStatus != "" {
+		if err := s.restrictAuthor(ctx, moderationCase.AuthorID, authorStatus); err != nil {
+			return nil, err
+		}
+	}
+
+	logger.Info("审核案件已处理",
+		logger.String("case_id", moderationCase.ID.String()),
+		logger.String("resolution", action),
+		logger.Int("moderator_id", int(moderatorID)))
+	return moderationCase, nil
+}
+
+// ListRules 获取全部过滤规则
+func (s *ModerationService) ListRules(ctx context.Context) ([]*entities.ModerationRule, error) {
+	return s.ruleRepo.List(ctx, false)
+}
+
+// CreateRule 创建过滤规则并重新加载过滤器
+func (s *ModerationService) CreateRule(ctx context.Context, createdBy uint, input ModerationRuleInput) (*entities.ModerationRule, error) {
+	rule := &entities.ModerationRule{
+		MatchType: string(entities.ModerationMatchKeyword),
+		Action:    string(entities.ModerationReview),
+		IsActive:  true,
+		CreatedBy: createdBy,
+	}
+	if err := applyRuleInput(rule, input); err != nil {
+		return nil, err
+	}
+	if err := s.ruleRepo.Create(ctx, rule); err != nil {
+		return nil, err
+	}
+	s.reloadRules(ctx)
+	return rule, nil
+}
+
+// UpdateRule 更新过滤规则并重新加载过滤器
+func (s *ModerationService) UpdateRule(ctx context.Context, id uuid.UUID, input ModerationRuleInput) (*entities.ModerationRule, error) {
+	rule, err := s.ruleRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, apperrors.New(apperrors.ErrorTypeNotFound, 404, "过滤规则不存在").WithCause(err)
+	}
+	if err := applyRuleInput(rule, input); err != nil {
+		return nil, err
+	}
+	if err := s.ruleRepo.Update(ctx, rule); err != nil {
+		return nil, err
+	}
+	s.reloadRules(ctx)
+	return rule, nil
+}
+
+// DeleteRule 删除过滤规则并重新加载过滤器
+func (s *ModerationService) DeleteRule(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.ruleRepo.GetByID(ctx, id); err != nil {
+		return apperrors.New(apperrors.ErrorTypeNotFound, 404, "过滤规则不存在").WithCause(err)
+	}
+	if err := s.ruleRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.reloadRules(ctx)
+	return nil
+}
+
+// ReloadRules 立即重新加载过滤规则
+func (s *ModerationService) ReloadRules(ctx context.Context) error {
+	reloader, ok := s.moderator.(ruleReloader)
+	if !ok {
+		return nil
+	}
+	return reloader.Reload(ctx)
+}
+
+// reloadRules 规则修改后重新加载过滤器，失败时等待定期刷新
+func (s *ModerationService) reloadRules(ctx context.Context) {
+	if err := s.ReloadRules(ctx); err != nil {
+		logger.Error("重新加载内容过滤规则失败", logger.String("error", err.Error()))
+	}
+}
+
+// newAutoCase 根据自动判定结果构建审核案件
+func (s *ModerationService) newAutoCase(contentType entities.ModerationContentType, contentID string, authorID uint, text string, verdict *ModerationVerdict) *entities.ModerationCase {
+	return &entities.ModerationCase{
+		ContentType:     string(contentType),
+		ContentID:       contentID,
+		AuthorID:        authorID,
+		Content:         text,
+		Source:          string(entities.ModerationSourceAuto),
+		Status:          string(entities.ModerationPending),
+		AutoAction:      string(verdict.Action),
+		MatchedRuleID:   verdict.RuleID,
+		MatchedRuleName: verdict.RuleName,
+		MatchedText:     verdict.Matched,
+	}
+}
+
+// loadContent 加载被举报内容的作者和当前文本
+func (s *ModerationService) loadContent(ctx context.Context, contentType entities.ModerationContentType, contentID string) (uint, string, error) {
+	switch contentType {
+	case entities.ModerationContentComment:
+		id, err := uuid.Parse(contentID)
+		if err != nil {
+			return 0, "", apperrors.New(apperrors.ErrorTypeValidation, 400, "评论ID格式无效")
+		}
+		comment, err := s.commentRepo.GetByID(ctx, id)
+		if err != nil || comment.IsDeleted() {
+			return 0, "", apperrors.New(apperrors.ErrorTypeNotFound, 404, "评论不存在")
+		}
+		return comment.UserID, comment.Content, nil
+	case entities.ModerationContentUserBio:
+		userID, err := strconv.ParseUint(contentID, 10, 64)
+		if err != nil {
+			return 0, "", apperrors.New(apperrors.ErrorTypeValidation, 400, "用户ID格式无效")
+		}
+		profile, err := s.profileRepo.GetByUserID(ctx, uint(userID))
+		if err != nil || strings.TrimSpace(profile.Bio) == "" {
+			return 0, "", apperrors.New(apperrors.ErrorTypeNotFound, 404, "用户简介不存在")
+		}
+		return profile.UserID, profile.Bio, nil
+	default:
+		return 0, "", apperrors.New(apperrors.ErrorTypeValidation, 400, "举报内容类型无效")
+	}
+}
+
+// applyResolution 按处理结果更新内容：通过时恢复被隐藏的评论，违规时隐藏评论或清空简介
+func (s *ModerationService) applyResolution(ctx context.Context, moderatorID uint, moderationCase *entities.ModerationCase) error {
+	approved := moderationCase.Status == string(entities.ModerationApproved)
+	switch entities.ModerationContentType(moderationCase.ContentType) {
+	case entities.ModerationContentComment:
+		if approved {
+			s.unhideComment(ctx, moderationCase.ContentID)
+		} else {
+			s.hideComment(ctx, moderationCase.ContentID, &moderatorID, rejectedReason)
+		}
+	case entities.ModerationContentUserBio:
+		if approved {
+			return nil
+		}
+		userID, err := strconv.ParseUint(moderationCase.ContentID, 10, 64)
+		if err != nil {
+			return nil
+		}
+		if err := s.profileRepo.UpdateBio(ctx, uint(userID), ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// restrictAuthor 暂停或封禁作者账号并使其会话失效
+func (s *ModerationService) restrictAuthor(ctx context.Context, authorID uint, status entities.UserStatus) error {
+	if err := s.userRepo.UpdateStatus(ctx, authorID, string(status)); err != nil {
+		return err
+	}
+	if err := s.sessionRepo.DeactivateByUserID(ctx, authorID); err != nil {
+		logger.Error("注销受限用户会话失败",
+			logger.Int("user_id", int(authorID)),
+			logger.String("error", err.Error()))
+	}
+	return nil
+}
+
+// hideComment 隐藏评论，评论已隐藏时保持原状态
+func (s *ModerationService) hideComment(ctx context.Context, commentID string, moderatorID *uint, reason string) {
+	comment := s.findComment(ctx, commentID)
+	if comment == nil || (comment.IsHidden && moderatorID == nil) {
+		return
+	}
+	comment.IsHidden = true
+	comment.HiddenBy = moderatorID
+	comment.HiddenReason = reason
+	if err := s.commentRepo.UpdateModeration(ctx, comment); err != nil {
+		logger.Error("隐藏评论失败", logger.String("comment_id", commentID), logger.String("error", err.Error()))
+	}
+}
+
+// unhideComment 恢复因审核被隐藏的评论
+func (s *ModerationService) unhideComment(ctx context.Context, commentID string) {
+	comment := s.findComment(ctx, commentID)
+	if comment == nil || !comment.IsHidden || comment.HiddenReason != pendingReviewReason {
+		return
+	}
+	comment.IsHidden = false
+	comment.HiddenBy = nil
+	comment.HiddenReason = ""
+	if err := s.commentRepo.UpdateModeration(ctx, comment); err != nil {
+		logger.Error("恢复评论失败", logger.String("comment_id", commentID), logger.String("error", err.Error()))
+	}
+}
+
+// findComment 根据案件中的内容ID加载评论，不存在时返回nil
+func (s *ModerationService) findComment(ctx context.Context, commentID string) *entities.Comment {
+	id, err := uuid.Parse(commentID)
+	if err != nil {
+		return nil
+	}
+	comment, err := s.commentRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil
+	}
+	return comment
+}
+
+// applyRuleInput 校验并应用过滤规则内容，正则规则需能成功编译
+func applyRuleInput(rule *entities.ModerationRule, input ModerationRuleInput) error {
+	if input.Name != "" {
+		rule.Name = strings.TrimSpace(input.Name)
+	}
+	if input.Pattern != "" {
+		rule.Pattern = input.Pattern
+	}
+	if input.MatchType != "" {
+		rule.MatchType = input.MatchType
+	}
+	if input.Action != "" {
+		rule.Action = input.Action
+	}
+	if input.Category != "" {
+		rule.Category = strings.TrimSpace(input.Category)
+	}
+	if input.IsActive != nil {
+		rule.IsActive = *input.IsActive
+	}
+
+	if rule.Name == "" || strings.TrimSpace(rule.Pattern) == "" {
+		return apperrors.New(apperrors.ErrorTypeValidation, 400, "请填写规则名称和匹配内容")
+	}
+	if rule.Action != string(entities.ModerationReview) && rule.Action != string(entities.ModerationBlock) {
+		return apperrors.New(apperrors.ErrorTypeValidation, 400, "规则动作必须为review或block").WithDetail("action", rule.Action)
+	}
+	switch entities.ModerationMatchType(rule.MatchType) {
+	case entities.ModerationMatchKeyword:
+		if compactModerationText(foldModerationText(rule.Pattern)) == "" {
+			return apperrors.New(apperrors.ErrorTypeValidation, 400, "关键词不能只包含空白或标点")
+		}
+	case entities.ModerationMatchRegex:
+		if _, err := regexp.Compile(rule.Pattern); err != nil {
+			return apperrors.New(apperrors.ErrorTypeValidation, 400, "正则表达式无效").WithDetail("pattern", err.Error())
+		}
+	default:
+		return apperrors.New(apperrors.ErrorTypeValidation, 400, "匹配方式必须为keyword或regex").WithDetail("match_type", rule.MatchType)
+	}
+	return nil
+}
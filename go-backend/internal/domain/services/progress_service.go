@@ -0,0 +1,188 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+	"sical-go-backend/pkg/logger"
+)
+
+// ProgressService 学习进度跟踪服务
+type ProgressService struct {
+	goalRepo    repositories.LearningGoalRepository
+	pathRepo    repositories.LearningPathRepository
+	timeLogRepo repositories.StepTimeLogRepository
+	historyRepo repositories.GoalProgressHistoryRepository
+}
+
+// NewProgressService 创建学习进度跟踪服务
+func NewProgressService(
+	goalRepo repositories.LearningGoalRepository,
+	pathRepo repositories.LearningPathRepository,
+	timeLogRepo repositories.StepTimeLogRepository,
+	historyRepo repositories.GoalProgressHistoryRepository,
+) *ProgressService {
+	return &ProgressService{
+		goalRepo:    goalRepo,
+		pathRepo:    pathRepo,
+		timeLogRepo: timeLogRepo,
+		historyRepo: historyRepo,
+	}
+}
+
+// GoalProgress 目标进度汇总
+type GoalProgress struct {
+	GoalID         uuid.UUID `json:"goal_id"`
+	Progress       float64   `json:"progress"` // 0-100
+	Status         string    `json:"status"`
+	CompletedSteps int       `json:"completed_steps"`
+	TotalSteps     int       `json:"total_steps"`
+	CompletedHours int       `json:"completed_hours"`
+	TotalHours     int       `json:"total_hours"`
+	TimeSpent      int       `json:"time_spent"` // 分钟
+}
+
+// AllStepsCompleted 检查目标的全部步骤是否已完成
+func (p *GoalProgress) AllStepsCompleted() bool {
+	return p.TotalSteps > 0 && p.CompletedSteps == p.TotalSteps
+}
+
+// RecalculateGoalProgress 根据已完成步骤重新计算目标进度
+// 进度按步骤的预估学习时间加权；目标状态的自动变更由StatusService负责
+func (s *ProgressService) RecalculateGoalProgress(ctx context.Context, goalID uuid.UUID) (*GoalProgress, error) {
+	goal, err := s.goalRepo.GetByID(ctx, goalID)
+	if err != nil {
+		return nil, fmt.Errorf("获取学习目标失败: %w", err)
+	}
+
+	paths, err := s.pathRepo.GetByGoalID(ctx, goalID)
+	if err != nil {
+		return nil, fmt.Errorf("获取学习路径失败: %w", err)
+	}
+
+	progress := s.calculateProgress(goalID, paths)
+	progress.Status = goal.Status
+
+	if progress.Progress == goal.Progress {
+		return progress, nil
+	}
+
+	if err := s.goalRepo.UpdateProgress(ctx, goalID, progress.Progress); err != nil {
+		return nil, err
+	}
+
+	history := &entities.GoalProgressHistory{
+		GoalID:         goalID,
+		Progress:       progress.Progress,
+		CompletedSteps: progress.CompletedSteps,
+		TotalSteps:     progress.TotalSteps,
+		CompletedHours: progress.CompletedHours,
+		TotalHours:     progress.TotalHours,
+		RecordedAt:     time.Now(),
+	}
+	if err := s.historyRepo.Create(ctx, history); err != nil {
+		// 历史记录失败不影响进度更新
+		logger.Error("记录进度历史失败", logger.String("error", err.Error()))
+	}
+
+	logger.Info("学习目标进度已更新",
+		logger.String("goal_id", goalID.String()),
+		logger.Float64("progress", progress.Progress))
+
+	return progress, nil
+}
+
+// GetGoalProgress 获取目标当前进度汇总
+func (s *ProgressService) GetGoalProgress(ctx context.Context, goalID uuid.UUID) (*GoalProgress, error) {
+	goal, err := s.goalRepo.GetByID(ctx, goalID)
+	if err != nil {
+		return nil, fmt.Errorf("获取学习目标失败: %w", err)
+	}
+
+	paths, err := s.pathRepo.GetByGoalID(ctx, goalID)
+	if err != nil {
+		return nil, fmt.Errorf("获取学习路径失败: %w", err)
+	}
+
+	progress := s.calculateProgress(goalID, paths)
+	progress.Status = goal.Status
+	return progress, nil
+}
+
+// LogStepTime 记录步骤学习时间
+func (s *ProgressService) LogStepTime(ctx context.Context, pathID uuid.UUID, minutes int, note string, loggedAt *time.Time) (*entities.StepTimeLog, error) {
+	if minutes <= 0 {
+		return nil, fmt.Errorf("学习时间必须大于0")
+	}
+
+	path, err := s.pathRepo.GetByID(ctx, pathID)
+	if err != nil {
+		return nil, fmt.Errorf("获取学习路径失败: %w", err)
+	}
+
+	timeLog := &entities.StepTimeLog{
+		PathID:   pathID,
+		GoalID:   path.GoalID,
+		Minutes:  minutes,
+		Note:     note,
+		LoggedAt: time.Now(),
+	}
+	if loggedAt != nil {
+		timeLog.LoggedAt = *loggedAt
+	}
+
+	if err := s.timeLogRepo.Create(ctx, timeLog); err != nil {
+		return nil, err
+	}
+
+	if err := s.pathRepo.AddTimeSpent(ctx, pathID, minutes); err != nil {
+		return nil, err
+	}
+
+	return timeLog, nil
+}
+
+// GetStepTimeLogs 获取步骤学习时间记录
+func (s *ProgressService) GetStepTimeLogs(ctx context.Context, pathID uuid.UUID) ([]*entities.StepTimeLog, error) {
+	return s.timeLogRepo.GetByPathID(ctx, pathID)
+}
+
+// GetProgressHistory 获取目标进度历史
+func (s *ProgressService) GetProgressHistory(ctx context.Context, goalID uuid.UUID, from, to *time.Time) ([]*entities.GoalProgressHistory, error) {
+	return s.historyRepo.GetByGoalID(ctx, goalID, from, to)
+}
+
+// calculateProgress 计算按预估时间加权的完成进度
+func (s *ProgressService) calculateProgress(goalID uuid.UUID, paths []*entities.LearningPath) *GoalProgress {
+	progress := &GoalProgress{
+		GoalID:     goalID,
+		TotalSteps: len(paths),
+	}
+
+	for _, path := range paths {
+		progress.TotalHours += path.EstimatedDuration
+		progress.TimeSpent += path.TimeSpent
+		if path.IsCompleted() {
+			progress.CompletedSteps++
+			progress.CompletedHours += path.EstimatedDuration
+		}
+	}
+
+	switch {
+	case progress.TotalHours > 0:
+		progress.Progress = float64(progress.CompletedHours) / float64(progress.TotalHours) * 100
+	case progress.TotalSteps > 0:
+		// 未设置预估时间时退化为按步骤数计算
+		progress.Progress = float64(progress.CompletedSteps) / float64(progress.TotalSteps) * 100
+	}
+
+	// 与数据库decimal(5,2)精度保持一致
+	progress.Progress = math.Round(progress.Progress*100) / 100
+
+	return progress
+}
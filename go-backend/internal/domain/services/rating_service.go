@@ -0,0 +1,319 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+	apperrors "sical-go-backend/pkg/errors"
+	"sical-go-backend/pkg/logger"
+)
+
+const (
+	// ratingPriorWeight 贝叶斯加权评分的先验权重，相当于预先计入的评分数
+	ratingPriorWeight = 5.0
+	// defaultRatingPriorMean 尚无任何评分时使用的先验均值
+	defaultRatingPriorMean = 3.0
+)
+
+// RateInput 评分内容
+type RateInput struct {
+	Score  int
+	Review string
+}
+
+// RatingStats 对象的评分汇总及贝叶斯加权评分
+type RatingStats struct {
+	Summary       *entities.RatingSummary
+	WeightedScore float64
+}
+
+// ReviewPage 分页的评价列表
+type ReviewPage struct {
+	Reviews []*entities.Rating
+	Voted   map[uuid.UUID]bool // 当前用户已标记为有帮助的评价
+	Total   int64
+}
+
+// RatingService 评分服务，负责知识点和学习路径的星级评分、文字评价和有帮助投票
+// 评分汇总增量维护，排序使用向全局均值收缩的贝叶斯加权评分
+type RatingService struct {
+	ratingRepo    repositories.RatingRepository
+	knowledgeRepo repositories.KnowledgePointRepository
+	pathRepo      repositories.LearningPathRepository
+}
+
+// NewRatingService 创建评分服务
+func NewRatingService(
+	ratingRepo repositories.RatingRepository,
+	knowledgeRepo repositories.KnowledgePointRepository,
+	pathRepo repositories.LearningPathRepository,
+) *RatingService {
+	return &RatingService{
+		ratingRepo:    ratingRepo,
+		knowledgeRepo: knowledgeRepo,
+		pathRepo:      pathRepo,
+	}
+}
+
+// Rate 创建或修改用户对对象的评分，返回的布尔值表示是否为新建
+func (s *RatingService) Rate(ctx context.Context, userID uint, targetType string, targetID uuid.UUID, input RateInput) (*entities.Rating, bool, error) {
+	if input.Score < entities.MinRatingScore || input.Score > entities.MaxRatingScore {
+		return nil, false, apperrors.New(apperrors.ErrorTypeValidation, 400,
+			fmt.Sprintf("评分须在%d到%d星之间", entities.MinRatingScore, entities.MaxRatingScore))
+	}
+	review, err := normalizeReview(input.Review)
+	if err != nil {
+		return nil, false, err
+	}
+	if err := s.validateTarget(ctx, targetType, targetID); err != nil {
+		return nil, false, err
+	}
+
+	rating, err := s.ratingRepo.GetByUser(ctx, targetType, targetID, userID)
+	if err != nil {
+		return nil, false, err
+	}
+	if rating == nil {
+		rating = &entities.Rating{
+			TargetType: targetType,
+			TargetID:   targetID,
+			UserID:     userID,
+			Score:      input.Score,
+			Review:     review,
+		}
+		if err := s.ratingRepo.Create(ctx, rating); err != nil {
+			return nil, false, err
+		}
+		logger.Info("用户提交评分",
+			logger.String("target_type", targetType),
+			logger.String("target_id", targetID.String()),
+			logger.Int("score", input.Score))
+		return rating, true, nil
+	}
+
+	if rating.Score == input.Score && rating.Review == review {
+		return rating, false, nil
+	}
+	rating.Score = input.Score
+	rating.Review = review
+	if err := s.ratingRepo.Update(ctx, rating); err != nil {
+		return nil, false, err
+	}
+	rating.IsEdited = true
+	return rating, false, nil
+}
+
+// GetMine 获取用户对对象的评分
+func (s *RatingService) GetMine(ctx context.Context, userID uint, targetType string, targetID uuid.UUID) (*entities.Rating, error) {
+	if !entities.RatingTargetType(targetType).IsValid() {
+		return nil, invalidRatingTarget(targetType)
+	}
+	rating, err := s.ratingRepo.GetByUser(ctx, targetType, targetID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if rating == nil {
+		return nil, apperrors.New(apperrors.ErrorTypeNotFound, 404, "尚未评分")
+	}
+	return rating, nil
+}
+
+// DeleteMine 删除用户对对象的评分
+func (s *RatingService) DeleteMine(ctx context.Context, userID uint, targetType string, targetID uuid.UUID) error {
+	rating, err := s.GetMine(ctx, userID, targetType, targetID)
+	if err != nil {
+		return err
+	}
+	return s.ratingRepo.Delete(ctx, rating)
+}
+
+// ListReviews 分页获取对象下的文字评价，默认最有帮助的优先
+func (s *RatingService) ListReviews(ctx context.Context, userID uint, targetType string, targetID uuid.UUID, order string, offset, limit int) (*ReviewPage, error) {
+	if !entities.RatingTargetType(targetType).IsValid() {
+		return nil, invalidRatingTarget(targetType)
+	}
+	reviewOrder := repositories.ReviewOrder(order)
+	switch reviewOrder {
+	case "":
+		reviewOrder = repositories.ReviewOrderHelpful
+	case repositories.ReviewOrderHelpful, repositories.ReviewOrderRecent:
+	default:
+		return nil, apperrors.New(apperrors.ErrorTypeValidation, 400, "评价排序方式无效").
+			WithDetail("sort", order)
+	}
+
+	reviews, total, err := s.ratingRepo.ListReviews(ctx, targetType, targetID, reviewOrder, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uuid.UUID, 0, len(reviews))
+	for _, review := range reviews {
+		ids = append(ids, review.ID)
+	}
+	voted, err := s.ratingRepo.VotedBy(ctx, userID, ids)
+	if err != nil {
+		return nil, err
+	}
+	return &ReviewPage{Reviews: reviews, Voted: voted, Total: total}, nil
+}
+
+// MarkHelpful 将评价标记为有帮助，不能为自己的评价投票，重复投票不报错
+func (s *RatingService) MarkHelpful(ctx context.Context, userID uint, ratingID uuid.UUID) (*entities.Rating, error) {
+	rating, err := s.loadReview(ctx, ratingID)
+	if err != nil {
+		return nil, err
+	}
+	if rating.UserID == userID {
+		return nil, apperrors.New(apperrors.ErrorTypeValidation, 400, "不能为自己的评价投票")
+	}
+	added, err := s.ratingRepo.AddHelpfulVote(ctx, ratingID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if added {
+		rating.HelpfulCount++
+	}
+	return rating, nil
+}
+
+// UnmarkHelpful 取消有帮助投票，未投票时不报错
+func (s *RatingService) UnmarkHelpful(ctx context.Context, userID uint, ratingID uuid.UUID) (*entities.Rating, error) {
+	rating, err := s.loadReview(ctx, ratingID)
+	if err != nil {
+		return nil, err
+	}
+	removed, err := s.ratingRepo.RemoveHelpfulVote(ctx, ratingID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if removed && rating.HelpfulCount > 0 {
+		rating.HelpfulCount--
+	}
+	return rating, nil
+}
+
+// GetStats 获取对象的评分汇总及加权评分
+func (s *RatingService) GetStats(ctx context.Context, targetType string, targetID uuid.UUID) (*RatingStats, error) {
+	if !entities.RatingTargetType(targetType).IsValid() {
+		return nil, invalidRatingTarget(targetType)
+	}
+	summary, err := s.ratingRepo.GetSummary(ctx, targetType, targetID)
+	if err != nil {
+		return nil, err
+	}
+	priorMean, err := s.priorMean(ctx, targetType)
+	if err != nil {
+		return nil, err
+	}
+	return &RatingStats{
+		Summary:       summary,
+		WeightedScore: summary.BayesianScore(priorMean, ratingPriorWeight),
+	}, nil
+}
+
+// ListTopRated 按贝叶斯加权评分倒序分页获取评分最高的对象
+func (s *RatingService) ListTopRated(ctx context.Context, targetType string, offset, limit int) ([]*RatingStats, int64, error) {
+	if !entities.RatingTargetType(targetType).IsValid() {
+		return nil, 0, invalidRatingTarget(targetType)
+	}
+	priorMean, err := s.priorMean(ctx, targetType)
+	if err != nil {
+		return nil, 0, err
+	}
+	summaries, total, err := s.ratingRepo.ListTopRated(ctx, targetType, priorMean, ratingPriorWeight, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	stats := make([]*RatingStats, 0, len(summaries))
+	for _, summary := range summaries {
+		stats = append(stats, &RatingStats{
+			Summary:       summary,
+			WeightedScore: summary.BayesianScore(priorMean, ratingPriorWeight),
+		})
+	}
+	return stats, total, nil
+}
+
+// KnowledgeQuality 获取知识点的加权评分作为质量信号，未评分的知识点取先验均值
+func (s *RatingService) KnowledgeQuality(ctx context.Context, pointIDs []uuid.UUID) (map[uuid.UUID]float64, error) {
+	targetType := string(entities.RatingTargetKnowledgePoint)
+	summaries, err := s.ratingRepo.ListSummaries(ctx, targetType, pointIDs)
+	if err != nil {
+		return nil, err
+	}
+	priorMean, err := s.priorMean(ctx, targetType)
+	if err != nil {
+		return nil, err
+	}
+
+	quality := make(map[uuid.UUID]float64, len(pointIDs))
+	for _, id := range pointIDs {
+		if summary, ok := summaries[id]; ok {
+			quality[id] = summary.BayesianScore(priorMean, ratingPriorWeight)
+		} else {
+			quality[id] = priorMean
+		}
+	}
+	return quality, nil
+}
+
+// priorMean 贝叶斯加权的先验均值，取同类对象全部评分的平均星级
+func (s *RatingService) priorMean(ctx context.Context, targetType string) (float64, error) {
+	mean, count, err := s.ratingRepo.GlobalMean(ctx, targetType)
+	if err != nil {
+		return 0, err
+	}
+	if count == 0 {
+		return defaultRatingPriorMean, nil
+	}
+	return mean, nil
+}
+
+// loadReview 加载附带文字的评价，纯星级评分不能投票
+func (s *RatingService) loadReview(ctx context.Context, ratingID uuid.UUID) (*entities.Rating, error) {
+	rating, err := s.ratingRepo.GetByID(ctx, ratingID)
+	if err != nil {
+		return nil, apperrors.New(apperrors.ErrorTypeNotFound, 404, "评价不存在").WithCause(err)
+	}
+	if !rating.HasReview() {
+		return nil, apperrors.New(apperrors.ErrorTypeNotFound, 404, "评价不存在")
+	}
+	return rating, nil
+}
+
+// validateTarget 校验评分对象存在
+func (s *RatingService) validateTarget(ctx context.Context, targetType string, targetID uuid.UUID) error {
+	switch entities.RatingTargetType(targetType) {
+	case entities.RatingTargetKnowledgePoint:
+		if _, err := s.knowledgeRepo.GetByID(ctx, targetID); err != nil {
+			return apperrors.New(apperrors.ErrorTypeNotFound, 404, "知识点不存在").WithCause(err)
+		}
+	case entities.RatingTargetLearningPath:
+		if _, err := s.pathRepo.GetByID(ctx, targetID); err != nil {
+			return apperrors.New(apperrors.ErrorTypeNotFound, 404, "学习路径不存在").WithCause(err)
+		}
+	default:
+		return invalidRatingTarget(targetType)
+	}
+	return nil
+}
+
+// invalidRatingTarget 评分对象类型无效的错误
+func invalidRatingTarget(targetType string) error {
+	return apperrors.New(apperrors.ErrorTypeValidation, 400, "评分对象类型无效").
+		WithDetail("target_type", targetType)
+}
+
+// normalizeReview 去除首尾空白并校验评价长度，评价可为空
+func normalizeReview(review string) (string, error) {
+	review = strings.TrimSpace(review)
+	if utf8.RuneCountInString(review) > entities.MaxReviewLength {
+		return "", apperrors.New(apperrors.ErrorTypeValidation, 400, fmt.Sprintf("评价内容不能超过%d个字符", entities.MaxReviewLength))
+	}
+	return review, nil
+}
@@ -0,0 +1,224 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+)
+
+// fakeKnowledgeRepository 只支持按ID查询的知识点仓储
+type fakeKnowledgeRepository struct {
+	repositories.KnowledgePointRepository
+	points map[uuid.UUID]*entities.KnowledgePoint
+}
+
+func (r *fakeKnowledgeRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.KnowledgePoint, error) {
+	point, ok := r.points[id]
+	if !ok {
+		return nil, errors.New("知识点不存在")
+	}
+	return point, nil
+}
+
+// fakeRatingRepository 内存中的评分仓储，与数据库实现一样随评分增删改增量维护汇总
+type fakeRatingRepository struct {
+	repositories.RatingRepository
+	ratings   map[uuid.UUID]*entities.Rating
+	summaries map[uuid.UUID]*entities.RatingSummary
+}
+
+func newFakeRatingRepository() *fakeRatingRepository {
+	return &fakeRatingRepository{
+		ratings:   map[uuid.UUID]*entities.Rating{},
+		summaries: map[uuid.UUID]*entities.RatingSummary{},
+	}
+}
+
+func (r *fakeRatingRepository) Create(ctx context.Context, rating *entities.Rating) error {
+	rating.ID = uuid.New()
+	stored := *rating
+	r.ratings[rating.ID] = &stored
+	r.adjustSummary(rating, 1, 0, rating.Score)
+	return nil
+}
+
+func (r *fakeRatingRepository) Update(ctx context.Context, rating *entities.Rating) error {
+	current, ok := r.ratings[rating.ID]
+	if !ok {
+		return errors.New("评分不存在")
+	}
+	r.adjustSummary(current, 0, current.Score, rating.Score)
+	current.Score = rating.Score
+	current.Review = rating.Review
+	return nil
+}
+
+func (r *fakeRatingRepository) Delete(ctx context.Context, rating *entities.Rating) error {
+	current, ok := r.ratings[rating.ID]
+	if !ok {
+		return errors.New("评分不存在")
+	}
+	r.adjustSummary(current, -1, current.Score, 0)
+	delete(r.ratings, rating.ID)
+	return nil
+}
+
+func (r *fakeRatingRepository) GetByUser(ctx context.Context, targetType string, targetID uuid.UUID, userID uint) (*entities.Rating, error) {
+	for _, rating := range r.ratings {
+		if rating.TargetType == targetType && rating.TargetID == targetID && rating.UserID == userID {
+			found := *rating
+			return &found, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeRatingRepository) ListSummaries(ctx context.Context, targetType string, targetIDs []uuid.UUID) (map[uuid.UUID]*entities.RatingSummary, error) {
+	summaries := make(map[uuid.UUID]*entities.RatingSummary)
+	for _, id := range targetIDs {
+		if summary, ok := r.summaries[id]; ok && summary.TargetType == targetType {
+			summaries[id] = summary
+		}
+	}
+	return summaries, nil
+}
+
+func (r *fakeRatingRepository) GlobalMean(ctx context.Context, targetType string) (float64, int64, error) {
+	var sum, count int
+	for _, summary := range r.summaries {
+		if summary.TargetType == targetType {
+			sum += summary.ScoreSum
+			count += summary.RatingCount
+		}
+	}
+	if count == 0 {
+		return 0, 0, nil
+	}
+	return float64(sum) / float64(count), int64(count), nil
+}
+
+// adjustSummary 与数据库实现相同的增量调整，删除最后一条评分后汇总行保留
+func (r *fakeRatingRepository) adjustSummary(rating *entities.Rating, countDelta, removed, added int) {
+	summary, ok := r.summaries[rating.TargetID]
+	if !ok {
+		summary = &entities.RatingSummary{TargetType: rating.TargetType, TargetID: rating.TargetID}
+		r.summaries[rating.TargetID] = summary
+	}
+	summary.RatingCount += countDelta
+	summary.ScoreSum += added - removed
+	summary.Average = 0
+	if summary.RatingCount > 0 {
+		summary.Average = float64(summary.ScoreSum) / float64(summary.RatingCount)
+	}
+}
+
+func TestRatingServiceKnowledgeQuality(t *testing.T) {
+	ctx := context.Background()
+	a, b, c := uuid.New(), uuid.New(), uuid.New()
+	knowledgeRepo := &fakeKnowledgeRepository{points: map[uuid.UUID]*entities.KnowledgePoint{
+		a: {ID: a}, b: {ID: b}, c: {ID: c},
+	}}
+	ratingRepo := newFakeRatingRepository()
+	service := NewRatingService(ratingRepo, knowledgeRepo, nil)
+	targetType := string(entities.RatingTargetKnowledgePoint)
+
+	rate := func(userID uint, pointID uuid.UUID, score int, wantCreated bool) {
+		t.Helper()
+		_, created, err := service.Rate(ctx, userID, targetType, pointID, RateInput{Score: score})
+		if err != nil {
+			t.Fatalf("Rate() error = %v", err)
+		}
+		if created != wantCreated {
+			t.Fatalf("Rate() created = %v, want %v", created, wantCreated)
+		}
+	}
+	remove := func(userID uint, pointID uuid.UUID) {
+		t.Helper()
+		if err := service.DeleteMine(ctx, userID, targetType, pointID); err != nil {
+			t.Fatalf("DeleteMine() error = %v", err)
+		}
+	}
+
+	// 先验权重为5，先验均值取全部知识点评分的平均星级，c始终未评分
+	steps := []struct {
+		name    string
+		act     func()
+		want    [3]float64
+		summary [2][2]int // a、b的评分数和星级总和
+	}{
+		{
+			name: "no ratings use the default prior",
+			act:  func() {},
+			want: [3]float64{defaultRatingPriorMean, defaultRatingPriorMean, defaultRatingPriorMean},
+		},
+		{
+			name: "create",
+			act: func() {
+				rate(1, a, 5, true)
+				rate(2, a, 4, true)
+				rate(3, b, 1, true)
+			},
+			// 先验均值10/3
+			want:    [3]float64{(50.0/3 + 9) / 7, (50.0/3 + 1) / 6, 10.0 / 3},
+			summary: [2][2]int{{2, 9}, {1, 1}},
+		},
+		{
+			name: "update",
+			act: func() {
+				rate(3, b, 3, false)
+				rate(3, b, 3, false) // 未变化
+			},
+			// 先验均值4
+			want:    [3]float64{(20.0 + 9) / 7, (20.0 + 3) / 6, 4},
+			summary: [2][2]int{{2, 9}, {1, 3}},
+		},
+		{
+			name: "delete",
+			act:  func() { remove(1, a) },
+			// 先验均值3.5
+			want:    [3]float64{(17.5 + 4) / 6, (17.5 + 3) / 6, 3.5},
+			summary: [2][2]int{{1, 4}, {1, 3}},
+		},
+		{
+			name: "delete the last rating of a point",
+			act:  func() { remove(2, a) },
+			// 先验均值3，汇总行保留但评分数为0时取先验均值
+			want:    [3]float64{3, 3, 3},
+			summary: [2][2]int{{0, 0}, {1, 3}},
+		},
+	}
+	for _, step := range steps {
+		step.act()
+		quality, err := service.KnowledgeQuality(ctx, []uuid.UUID{a, b, c})
+		if err != nil {
+			t.Fatalf("%s: KnowledgeQuality() error = %v", step.name, err)
+		}
+		for i, id := range []uuid.UUID{a, b, c} {
+			if math.Abs(quality[id]-step.want[i]) > 1e-9 {
+				t.Errorf("%s: quality[%d] = %v, want %v", step.name, i, quality[id], step.want[i])
+			}
+		}
+		for i, id := range []uuid.UUID{a, b} {
+			summary, ok := ratingRepo.summaries[id]
+			count, sum := 0, 0
+			if ok {
+				count, sum = summary.RatingCount, summary.ScoreSum
+			}
+			if count != step.summary[i][0] || sum != step.summary[i][1] {
+				t.Errorf("%s: summary[%d] = %d/%d, want %d/%d", step.name, i, count, sum, step.summary[i][0], step.summary[i][1])
+			}
+		}
+	}
+
+	if _, _, err := service.Rate(ctx, 4, targetType, uuid.New(), RateInput{Score: 5}); err == nil {
+		t.Error("Rate() on a missing point error = nil")
+	}
+	if _, _, err := service.Rate(ctx, 4, targetType, a, RateInput{Score: 6}); err == nil {
+		t.Error("Rate() with 6 stars error = nil")
+	}
+}
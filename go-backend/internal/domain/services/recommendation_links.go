@@ -0,0 +1,136 @@
+package services
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+)
+
+// maxRecommendationResources 单条推荐最多附带的学习资源数
+const maxRecommendationResources = 5
+
+// newPathTemplate 按难度由浅入深为知识点生成学习路径模板，每个知识点一个步骤
+func newPathTemplate(title string, points []*entities.KnowledgePoint, stepHours func(point *entities.KnowledgePoint) int) *entities.PathTemplate {
+	if len(points) == 0 {
+		return nil
+	}
+
+	ordered := append([]*entities.KnowledgePoint{}, points...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return difficultyRank(ordered[i].Difficulty) < difficultyRank(ordered[j].Difficulty)
+	})
+
+	template := &entities.PathTemplate{Title: title}
+	for _, point := range ordered {
+		id := point.ID
+		hours := stepHours(point)
+		template.Steps = append(template.Steps, entities.PathTemplateStep{
+			Title:             point.Title,
+			Description:       point.Description,
+			EstimatedDuration: hours,
+			KnowledgePointID:  &id,
+		})
+		template.TotalTime += hours
+	}
+	return template
+}
+
+// newSkillPathTemplate 为类别体系中配置的技能生成学习路径模板（类别下暂无知识点时使用）
+func newSkillPathTemplate(title string, skills []string, stepHours int) *entities.PathTemplate {
+	if len(skills) == 0 {
+		return nil
+	}
+
+	template := &entities.PathTemplate{Title: title}
+	for _, skill := range skills {
+		template.Steps = append(template.Steps, entities.PathTemplateStep{
+			Title:             skill,
+			EstimatedDuration: stepHours,
+		})
+		template.TotalTime += stepHours
+	}
+	return template
+}
+
+// knowledgePointIDs 提取知识点ID
+func knowledgePointIDs(points []*entities.KnowledgePoint) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(points))
+	for _, point := range points {
+		ids = append(ids, point.ID)
+	}
+	return ids
+}
+
+// knowledgePointResources 提取知识点配置的学习资源
+// 资源字段支持链接字符串数组，或包含 title/name 与 url/link 的对象数组
+func knowledgePointResources(points []*entities.KnowledgePoint) []entities.RecommendationResource {
+	var resources []entities.RecommendationResource
+	for _, point := range points {
+		for _, res := range parseKnowledgePointResources(point) {
+			if len(resources) >= maxRecommendationResources {
+				return resources
+			}
+			resources = append(resources, res)
+		}
+	}
+	return resources
+}
+
+// parseKnowledgePointResources 解析单个知识点的资源字段，格式无效时忽略
+func parseKnowledgePointResources(point *entities.KnowledgePoint) []entities.RecommendationResource {
+	if strings.TrimSpace(point.Resources) == "" {
+		return nil
+	}
+
+	var items []json.RawMessage
+	if err := json.Unmarshal([]byte(point.Resources), &items); err != nil {
+		return nil
+	}
+
+	var resources []entities.RecommendationResource
+	for _, item := range items {
+		var link string
+		if err := json.Unmarshal(item, &link); err == nil {
+			if link = strings.TrimSpace(link); link != "" {
+				resources = append(resources, entities.RecommendationResource{
+					Title:            point.Title,
+					URL:              link,
+					KnowledgePointID: point.ID,
+				})
+			}
+			continue
+		}
+
+		var obj struct {
+			Title string `json:"title"`
+			Name  string `json:"name"`
+			URL   string `json:"url"`
+			Link  string `json:"link"`
+		}
+		if err := json.Unmarshal(item, &obj); err != nil {
+			continue
+		}
+		res := entities.RecommendationResource{
+			Title:            firstNonEmpty(obj.Title, obj.Name, point.Title),
+			URL:              firstNonEmpty(obj.URL, obj.Link),
+			KnowledgePointID: point.ID,
+		}
+		if res.URL != "" {
+			resources = append(resources, res)
+		}
+	}
+	return resources
+}
+
+// firstNonEmpty 返回第一个非空字符串
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,247 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+	apperrors "sical-go-backend/pkg/errors"
+	"sical-go-backend/pkg/logger"
+)
+
+// 推荐暂缓时长限制
+const (
+	defaultRecommendationSnooze = 7 * 24 * time.Hour
+	maxRecommendationSnooze     = 90 * 24 * time.Hour
+)
+
+// RecommendationService 分析推荐处理服务，负责接受、忽略和暂缓推荐并记录处理结果
+type RecommendationService struct {
+	goalRepo     repositories.LearningGoalRepository
+	analysisRepo repositories.GoalAnalysisRepository
+	outcomeRepo  repositories.RecommendationOutcomeRepository
+	pathService  *LearningPathService
+}
+
+// NewRecommendationService 创建分析推荐处理服务
+func NewRecommendationService(
+	goalRepo repositories.LearningGoalRepository,
+	analysisRepo repositories.GoalAnalysisRepository,
+	outcomeRepo repositories.RecommendationOutcomeRepository,
+	pathService *LearningPathService,
+) *RecommendationService {
+	return &RecommendationService{
+		goalRepo:     goalRepo,
+		analysisRepo: analysisRepo,
+		outcomeRepo:  outcomeRepo,
+		pathService:  pathService,
+	}
+}
+
+// RecommendationRef 推荐定位信息
+type RecommendationRef struct {
+	UserID           uint
+	GoalID           uuid.UUID
+	AnalysisID       uuid.UUID
+	RecommendationID uuid.UUID
+}
+
+// RecommendationActionResult 推荐处理结果
+type RecommendationActionResult struct {
+	Recommendation *entities.Recommendation        `json:"recommendation"`
+	Outcome        *entities.RecommendationOutcome `json:"outcome"`
+	Paths          []*entities.LearningPath        `json:"paths,omitempty"` // 接受推荐时创建的学习路径
+}
+
+// Accept 接受推荐，推荐带有路径模板时按模板为目标创建学习路径
+func (s *RecommendationService) Accept(ctx context.Context, ref RecommendationRef) (*RecommendationActionResult, error) {
+	analysis, index, err := s.loadPending(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	rec := analysis.Recommendations[index]
+
+	generated, err := s.buildGeneratedPath(ctx, ref.GoalID, &rec)
+	if err != nil {
+		return nil, err
+	}
+
+	// 先写入处理记录，唯一索引保证同一推荐只会被接受一次
+	outcome := s.newOutcome(ref, analysis, &rec, entities.RecommendationAccepted)
+	if generated != nil {
+		outcome.CreatedPaths = len(generated.Steps)
+	}
+	if err := s.outcomeRepo.Create(ctx, outcome); err != nil {
+		return nil, apperrors.New(apperrors.ErrorTypeConflict, 409, "推荐正在处理或已被接受").WithCause(err)
+	}
+
+	var paths []*entities.LearningPath
+	if generated != nil {
+		paths, err = s.pathService.CreateLearningPath(ctx, ref.GoalID, generated)
+		if err != nil {
+			if delErr := s.outcomeRepo.Delete(ctx, outcome.ID); delErr != nil {
+				logger.Error("回滚推荐处理记录失败", logger.String("error", delErr.Error()))
+			}
+			return nil, err
+		}
+	}
+
+	rec.Status = entities.RecommendationAccepted
+	rec.SnoozedUntil = nil
+	if err := s.analysisRepo.UpdateRecommendation(ctx, analysis.ID, index, &rec); err != nil {
+		return nil, err
+	}
+
+	logger.Info("推荐已接受",
+		logger.String("recommendation_id", rec.ID.String()),
+		logger.String("goal_id", ref.GoalID.String()),
+		logger.Int("created_paths", len(paths)))
+	return &RecommendationActionResult{Recommendation: &rec, Outcome: outcome, Paths: paths}, nil
+}
+
+// Dismiss 忽略推荐，需要说明原因
+func (s *RecommendationService) Dismiss(ctx context.Context, ref RecommendationRef, reason string) (*RecommendationActionResult, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, apperrors.New(apperrors.ErrorTypeValidation, 400, "请填写忽略原因")
+	}
+
+	analysis, index, err := s.loadPending(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	rec := analysis.Recommendations[index]
+
+	rec.Status = entities.RecommendationDismissed
+	rec.SnoozedUntil = nil
+	if err := s.analysisRepo.UpdateRecommendation(ctx, analysis.ID, index, &rec); err != nil {
+		return nil, err
+	}
+
+	outcome := s.newOutcome(ref, analysis, &rec, entities.RecommendationDismissed)
+	outcome.Reason = reason
+	if err := s.outcomeRepo.Create(ctx, outcome); err != nil {
+		return nil, err
+	}
+
+	return &RecommendationActionResult{Recommendation: &rec, Outcome: outcome}, nil
+}
+
+// Snooze 暂缓推荐，until为空时暂缓7天；暂缓期间推荐不出现在综合分析中
+func (s *RecommendationService) Snooze(ctx context.Context, ref RecommendationRef, until *time.Time) (*RecommendationActionResult, error) {
+	now := time.Now()
+	snoozedUntil := now.Add(defaultRecommendationSnooze)
+	if until != nil {
+		snoozedUntil = *until
+	}
+	if !snoozedUntil.After(now) || snoozedUntil.Sub(now) > maxRecommendationSnooze {
+		return nil, apperrors.New(apperrors.ErrorTypeValidation, 400, "暂缓时间必须在未来90天内")
+	}
+
+	analysis, index, err := s.loadPending(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	rec := analysis.Recommendations[index]
+
+	rec.Status = entities.RecommendationSnoozed
+	rec.SnoozedUntil = &snoozedUntil
+	if err := s.analysisRepo.UpdateRecommendation(ctx, analysis.ID, index, &rec); err != nil {
+		return nil, err
+	}
+
+	outcome := s.newOutcome(ref, analysis, &rec, entities.RecommendationSnoozed)
+	outcome.SnoozedUntil = &snoozedUntil
+	if err := s.outcomeRepo.Create(ctx, outcome); err != nil {
+		return nil, err
+	}
+
+	return &RecommendationActionResult{Recommendation: &rec, Outcome: outcome}, nil
+}
+
+// loadPending 加载推荐所在的分析记录，校验目标归属和推荐状态
+// 已接受或已忽略的推荐不能再次处理，暂缓中的推荐可以提前处理
+func (s *RecommendationService) loadPending(ctx context.Context, ref RecommendationRef) (*entities.GoalAnalysis, int, error) {
+	goal, err := s.goalRepo.GetByID(ctx, ref.GoalID)
+	if err != nil {
+		return nil, 0, apperrors.New(apperrors.ErrorTypeNotFound, 404, "学习目标不存在").WithCause(err)
+	}
+	if goal.UserID != ref.UserID {
+		return nil, 0, apperrors.New(apperrors.ErrorTypeForbidden, 403, "无权处理该目标的推荐")
+	}
+
+	analysis, err := s.analysisRepo.GetByID(ctx, ref.AnalysisID)
+	if err != nil || analysis.GoalID != ref.GoalID {
+		return nil, 0, apperrors.New(apperrors.ErrorTypeNotFound, 404, "分析记录不存在")
+	}
+
+	for i := range analysis.Recommendations {
+		if analysis.Recommendations[i].ID != ref.RecommendationID {
+			continue
+		}
+		switch status := analysis.Recommendations[i].CurrentStatus(time.Now()); status {
+		case entities.RecommendationAccepted, entities.RecommendationDismissed:
+			return nil, 0, apperrors.New(apperrors.ErrorTypeConflict, 409, fmt.Sprintf("推荐已处理: %s", status))
+		}
+		return analysis, i, nil
+	}
+	return nil, 0, apperrors.New(apperrors.ErrorTypeNotFound, 404, "推荐不存在")
+}
+
+// buildGeneratedPath 将推荐的路径模板转换为学习路径，步骤排在目标现有路径之后；没有模板时返回nil
+func (s *RecommendationService) buildGeneratedPath(ctx context.Context, goalID uuid.UUID, rec *entities.Recommendation) (*GeneratedPath, error) {
+	if rec.PathTemplate == nil || len(rec.PathTemplate.Steps) == 0 {
+		return nil, nil
+	}
+
+	existing, err := s.pathService.GetLearningPaths(ctx, goalID)
+	if err != nil {
+		return nil, err
+	}
+	nextOrder := 1
+	for _, path := range existing {
+		if path.Order >= nextOrder {
+			nextOrder = path.Order + 1
+		}
+	}
+
+	generated := &GeneratedPath{
+		Title:       rec.PathTemplate.Title,
+		Description: rec.Description,
+		TotalTime:   rec.PathTemplate.TotalTime,
+	}
+	for i, step := range rec.PathTemplate.Steps {
+		pathStep := PathStep{
+			Title:             step.Title,
+			Description:       step.Description,
+			Order:             nextOrder + i,
+			EstimatedDuration: step.EstimatedDuration,
+			KnowledgePointIDs: []string{},
+			Prerequisites:     []string{},
+		}
+		if step.KnowledgePointID != nil {
+			pathStep.KnowledgePointIDs = append(pathStep.KnowledgePointIDs, step.KnowledgePointID.String())
+		}
+		generated.Steps = append(generated.Steps, pathStep)
+	}
+	return generated, nil
+}
+
+// newOutcome 构建推荐处理记录
+func (s *RecommendationService) newOutcome(ref RecommendationRef, analysis *entities.GoalAnalysis, rec *entities.Recommendation, action entities.RecommendationStatus) *entities.RecommendationOutcome {
+	return &entities.RecommendationOutcome{
+		RecommendationID:   rec.ID,
+		AnalysisID:         analysis.ID,
+		GoalID:             ref.GoalID,
+		UserID:             ref.UserID,
+		Action:             string(action),
+		RecommendationType: rec.Type,
+		AnalysisType:       analysis.AnalysisType,
+		Analyzer:           analysis.Analyzer,
+		Priority:           rec.Priority,
+	}
+}
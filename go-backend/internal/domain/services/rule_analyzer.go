@@ -0,0 +1,595 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+	"sical-go-backend/pkg/logger"
+)
+
+// ruleConfidenceWeights 各分析类型置信度中各项证据的权重，另有0.1基础分
+// graph为类别下有知识点可供比对，paths为已完成的学习步骤，goals为目标历史，assessed为相关技能中有测评数据的比例
+var ruleConfidenceWeights = map[string]struct{ graph, paths, goals, assessed float64 }{
+	entities.AnalysisTypeSkillGap:     {0.3, 0.2, 0.1, 0.3},
+	entities.AnalysisTypePrerequisite: {0.3, 0.3, 0, 0.3},   // 前置知识依据已完成路径和测评判断，与目标历史无关
+	entities.AnalysisTypeDifficulty:   {0.2, 0.2, 0.3, 0.2}, // 难度调整主要依据学习经验
+	entities.AnalysisTypeTimeEstimate: {0.3, 0.2, 0.2, 0.2},
+}
+
+// RuleAnalyzer 基于规则的分析，依据知识图谱、类别体系和用户学习画像计算各项分析，由各单项规则分析器共用
+type RuleAnalyzer struct {
+	knowledgeRepo repositories.KnowledgePointRepository
+	taxonomy      *TaxonomyService
+}
+
+// NewRuleAnalyzer 创建基于规则的分析
+func NewRuleAnalyzer(knowledgeRepo repositories.KnowledgePointRepository, taxonomy *TaxonomyService) *RuleAnalyzer {
+	return &RuleAnalyzer{
+		knowledgeRepo: knowledgeRepo,
+		taxonomy:      taxonomy,
+	}
+}
+
+// Analyzers 返回全部单项规则分析器
+func (s *RuleAnalyzer) Analyzers() Analyzers {
+	return NewAnalyzers(
+		&SkillGapRuleAnalyzer{rules: s},
+		&PrerequisiteRuleAnalyzer{rules: s},
+		&DifficultyRuleAnalyzer{rules: s},
+		&TimeEstimateRuleAnalyzer{rules: s},
+	)
+}
+
+// SkillGapRuleAnalyzer 技能差距规则分析器
+type SkillGapRuleAnalyzer struct {
+	rules *RuleAnalyzer
+}
+
+// Name 分析器名称
+func (a *SkillGapRuleAnalyzer) Name() string {
+	return AnalyzerRules
+}
+
+// Type 负责的分析类型
+func (a *SkillGapRuleAnalyzer) Type() string {
+	return entities.AnalysisTypeSkillGap
+}
+
+// Analyze 以不高于目标难度的类别知识点为必备技能，按掌握情况区分技能差距和已具备技能
+func (a *SkillGapRuleAnalyzer) Analyze(ctx context.Context, input *AnalysisInput) (*AnalysisOutput, error) {
+	skillGap, err := a.rules.analyzeSkillGap(ctx, input.Goal, input.Profile, input.KnowledgePoints)
+	if err != nil {
+		logger.Error("技能差距分析失败", logger.String("error", err.Error()))
+		return nil, err
+	}
+
+	evidence := input.Evidence()
+	evidence.RequiredSkills = len(skillGap.SkillGaps) + len(skillGap.Strengths)
+	evidence.AssessedSkills = skillGap.AssessedCount
+
+	return &AnalysisOutput{
+		Result: &entities.AnalysisResult{
+			SchemaVersion: entities.AnalysisSchemaVersion,
+			SkillGaps:     skillGap.SkillGaps,
+			Strengths:     skillGap.Strengths,
+			Evidence:      evidence,
+		},
+		Recommendations: a.rules.skillGapRecommendations(ctx, input, skillGap),
+		ConfidenceScore: a.rules.calculateConfidenceScore(a.Type(), evidence),
+		Analyzer:        a.Name(),
+	}, nil
+}
+
+// PrerequisiteRuleAnalyzer 前置条件规则分析器
+type PrerequisiteRuleAnalyzer struct {
+	rules *RuleAnalyzer
+}
+
+// Name 分析器名称
+func (a *PrerequisiteRuleAnalyzer) Name() string {
+	return AnalyzerRules
+}
+
+// Type 负责的分析类型
+func (a *PrerequisiteRuleAnalyzer) Type() string {
+	return entities.AnalysisTypePrerequisite
+}
+
+// Analyze 依据必备知识点在知识图谱中的前置引用，找出尚未掌握的前置知识
+func (a *PrerequisiteRuleAnalyzer) Analyze(ctx context.Context, input *AnalysisInput) (*AnalysisOutput, error) {
+	prereq, err := a.rules.analyzePrerequisites(ctx, input.Goal, input.Profile, input.KnowledgePoints)
+	if err != nil {
+		logger.Error("前置条件分析失败", logger.String("error", err.Error()))
+		return nil, err
+	}
+
+	evidence := input.Evidence()
+	evidence.RequiredSkills = len(prereq.Prerequisites)
+	evidence.AssessedSkills = prereq.AssessedCount
+
+	return &AnalysisOutput{
+		Result: &entities.AnalysisResult{
+			SchemaVersion:        entities.AnalysisSchemaVersion,
+			Prerequisites:        prereq.Prerequisites,
+			MissingPrerequisites: prereq.Missing,
+			Evidence:             evidence,
+		},
+		Recommendations: a.rules.prerequisiteRecommendations(ctx, input, prereq),
+		ConfidenceScore: a.rules.calculateConfidenceScore(a.Type(), evidence),
+		Analyzer:        a.Name(),
+	}, nil
+}
+
+// DifficultyRuleAnalyzer 难度评估规则分析器
+type DifficultyRuleAnalyzer struct {
+	rules *RuleAnalyzer
+}
+
+// Name 分析器名称
+func (a *DifficultyRuleAnalyzer) Name() string {
+	return AnalyzerRules
+}
+
+// Type 负责的分析类型
+func (a *DifficultyRuleAnalyzer) Type() string {
+	return entities.AnalysisTypeDifficulty
+}
+
+// Analyze 依据待学知识点数量和学习经验评估目标难度
+func (a *DifficultyRuleAnalyzer) Analyze(ctx context.Context, input *AnalysisInput) (*AnalysisOutput, error) {
+	difficulty, evidence, err := a.rules.assess(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AnalysisOutput{
+		Result: &entities.AnalysisResult{
+			SchemaVersion:     entities.AnalysisSchemaVersion,
+			DifficultyLevel:   difficulty.Level,
+			DifficultyFactors: difficulty.Factors,
+			Evidence:          evidence,
+		},
+		Recommendations: entities.Recommendations{},
+		ConfidenceScore: a.rules.calculateConfidenceScore(a.Type(), evidence),
+		Analyzer:        a.Name(),
+	}, nil
+}
+
+// TimeEstimateRuleAnalyzer 时间预估规则分析器
+type TimeEstimateRuleAnalyzer struct {
+	rules *RuleAnalyzer
+}
+
+// Name 分析器名称
+func (a *TimeEstimateRuleAnalyzer) Name() string {
+	return AnalyzerRules
+}
+
+// Type 负责的分析类型
+func (a *TimeEstimateRuleAnalyzer) Type() string {
+	return entities.AnalysisTypeTimeEstimate
+}
+
+// Analyze 按待学知识点逐项估算学习时间，并依据学习经验和目标复杂度调整
+func (a *TimeEstimateRuleAnalyzer) Analyze(ctx context.Context, input *AnalysisInput) (*AnalysisOutput, error) {
+	difficulty, evidence, err := a.rules.assess(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	recommendations := entities.Recommendations{}
+	if difficulty.EstimatedTime > 100 {
+		recommendations = append(recommendations, entities.Recommendation{
+			Type:          "learning_path",
+			Title:         "拆分学习目标",
+			Description:   "将目标分解为多个可在数周内完成的小目标",
+			Priority:      "low",
+			EstimatedTime: 0,
+			Reason:        fmt.Sprintf("预估学习时间%d小时，超过100小时", difficulty.EstimatedTime),
+			AnalysisType:  entities.AnalysisTypeTimeEstimate,
+		})
+	}
+
+	return &AnalysisOutput{
+		Result: &entities.AnalysisResult{
+			SchemaVersion: entities.AnalysisSchemaVersion,
+			EstimatedTime: difficulty.EstimatedTime,
+			Evidence:      evidence,
+		},
+		Recommendations: recommendations,
+		ConfidenceScore: a.rules.calculateConfidenceScore(a.Type(), evidence),
+		Analyzer:        a.Name(),
+	}, nil
+}
+
+// assess 依次执行技能差距、前置条件分析和难度评估，供难度评估和时间预估使用
+func (s *RuleAnalyzer) assess(ctx context.Context, input *AnalysisInput) (*DifficultyAnalysis, *entities.AnalysisEvidence, error) {
+	goal, profile, points := input.Goal, input.Profile, input.KnowledgePoints
+
+	skillGap, err := s.analyzeSkillGap(ctx, goal, profile, points)
+	if err != nil {
+		logger.Error("技能差距分析失败", logger.String("error", err.Error()))
+		return nil, nil, err
+	}
+	prereq, err := s.analyzePrerequisites(ctx, goal, profile, points)
+	if err != nil {
+		logger.Error("前置条件分析失败", logger.String("error", err.Error()))
+		return nil, nil, err
+	}
+	difficulty, err := s.assessDifficulty(ctx, goal, profile, skillGap, prereq)
+	if err != nil {
+		logger.Error("难度评估失败", logger.String("error", err.Error()))
+		return nil, nil, err
+	}
+
+	evidence := input.Evidence()
+	evidence.RequiredSkills = len(skillGap.SkillGaps) + len(skillGap.Strengths) + len(prereq.Prerequisites)
+	evidence.AssessedSkills = skillGap.AssessedCount + prereq.AssessedCount
+	return difficulty, evidence, nil
+}
+
+// SkillGapAnalysis 技能差距分析结果
+type SkillGapAnalysis struct {
+	SkillGaps     []string                   `json:"skill_gaps"`
+	Strengths     []string                   `json:"strengths"`
+	GapPointIDs   []uuid.UUID                `json:"gap_point_ids,omitempty"`
+	GapPoints     []*entities.KnowledgePoint `json:"-"`
+	AssessedCount int                        `json:"assessed_count"`
+	FromGraph     bool                       `json:"from_graph"` // 是否基于知识图谱（否则使用类别体系中配置的技能）
+}
+
+// analyzeSkillGap 分析技能差距
+func (s *RuleAnalyzer) analyzeSkillGap(ctx context.Context, goal *entities.LearningGoal, profile *UserLearningProfile, points []*entities.KnowledgePoint) (*SkillGapAnalysis, error) {
+	analysis := &SkillGapAnalysis{
+		SkillGaps: []string{},
+		Strengths: []string{},
+	}
+
+	// 优先以知识图谱中不高于目标难度的知识点作为必备技能
+	required := s.requiredKnowledgePoints(goal, points)
+	if len(required) > 0 {
+		analysis.FromGraph = true
+		for _, point := range required {
+			if profile.HasAssessment(point.ID) {
+				analysis.AssessedCount++
+			}
+			if profile.HasMastered(point) {
+				analysis.Strengths = append(analysis.Strengths, point.Title)
+			} else {
+				analysis.SkillGaps = append(analysis.SkillGaps, point.Title)
+				analysis.GapPointIDs = append(analysis.GapPointIDs, point.ID)
+				analysis.GapPoints = append(analysis.GapPoints, point)
+			}
+		}
+		return analysis, nil
+	}
+
+	// 类别下暂无知识点时退回类别体系中配置的必备技能，依据已完成的路径和目标判断
+	for _, skill := range s.taxonomy.RequiredSkills(ctx, goal.Category) {
+		if profile.HasCoveredSkill(skill) {
+			analysis.Strengths = append(analysis.Strengths, skill)
+		} else {
+			analysis.SkillGaps = append(analysis.SkillGaps, skill)
+		}
+	}
+
+	return analysis, nil
+}
+
+// PrerequisiteAnalysis 前置条件分析结果
+type PrerequisiteAnalysis struct {
+	Prerequisites   []string                   `json:"prerequisites"`
+	Missing         []string                   `json:"missing"`
+	MissingPointIDs []uuid.UUID                `json:"missing_point_ids,omitempty"`
+	MissingPoints   []*entities.KnowledgePoint `json:"-"`
+	AssessedCount   int                        `json:"assessed_count"`
+	FromGraph       bool                       `json:"from_graph"`
+}
+
+// analyzePrerequisites 分析前置条件
+// 前置条件取自必备知识点在知识图谱中引用的、不属于必备集合本身的知识点
+func (s *RuleAnalyzer) analyzePrerequisites(ctx context.Context, goal *entities.LearningGoal, profile *UserLearningProfile, points []*entities.KnowledgePoint) (*PrerequisiteAnalysis, error) {
+	analysis := &PrerequisiteAnalysis{
+		Prerequisites: []string{},
+		Missing:       []string{},
+	}
+
+	required := s.requiredKnowledgePoints(goal, points)
+	if len(required) > 0 {
+		analysis.FromGraph = true
+
+		requiredIDs := make(map[uuid.UUID]bool, len(required))
+		requiredTitles := make(map[string]bool, len(required))
+		for _, point := range required {
+			requiredIDs[point.ID] = true
+			requiredTitles[normalizeSkill(point.Title)] = true
+		}
+		known := make(map[uuid.UUID]*entities.KnowledgePoint, len(points))
+		for _, point := range points {
+			known[point.ID] = point
+		}
+
+		seen := map[string]bool{}
+		for _, point := range required {
+			ids, titles := parsePrerequisiteRefs(point.Prerequisites)
+
+			for _, id := range ids {
+				if requiredIDs[id] || seen[id.String()] {
+					continue
+				}
+				seen[id.String()] = true
+
+				prereq, ok := known[id]
+				if !ok {
+					// 前置知识点可能属于其他类别
+					found, err := s.knowledgeRepo.GetByID(ctx, id)
+					if err != nil {
+						logger.Warn("前置知识点不存在", logger.String("knowledge_point_id", id.String()))
+						continue
+					}
+					prereq = found
+					known[id] = found
+				}
+
+				analysis.Prerequisites = append(analysis.Prerequisites, prereq.Title)
+				if profile.HasAssessment(prereq.ID) {
+					analysis.AssessedCount++
+				}
+				if !profile.HasMastered(prereq) {
+					analysis.Missing = append(analysis.Missing, prereq.Title)
+					analysis.MissingPointIDs = append(analysis.MissingPointIDs, prereq.ID)
+					analysis.MissingPoints = append(analysis.MissingPoints, prereq)
+				}
+			}
+
+			for _, title := range titles {
+				key := normalizeSkill(title)
+				if requiredTitles[key] || seen[key] {
+					continue
+				}
+				seen[key] = true
+
+				analysis.Prerequisites = append(analysis.Prerequisites, title)
+				if !profile.HasCoveredSkill(title) {
+					analysis.Missing = append(analysis.Missing, title)
+				}
+			}
+		}
+		return analysis, nil
+	}
+
+	// 根据类别体系确定该难度下的前置条件
+	for _, prereq := range s.taxonomy.Prerequisites(ctx, goal.Category, goal.Difficulty) {
+		analysis.Prerequisites = append(analysis.Prerequisites, prereq)
+		if !profile.HasCoveredSkill(prereq) {
+			analysis.Missing = append(analysis.Missing, prereq)
+		}
+	}
+
+	return analysis, nil
+}
+
+// DifficultyAnalysis 难度分析结果
+type DifficultyAnalysis struct {
+	Level         string   `json:"level"`
+	EstimatedTime int      `json:"estimated_time"`
+	Factors       []string `json:"factors"`
+}
+
+// assessDifficulty 评估难度
+func (s *RuleAnalyzer) assessDifficulty(ctx context.Context, goal *entities.LearningGoal, profile *UserLearningProfile, skillGap *SkillGapAnalysis, prereq *PrerequisiteAnalysis) (*DifficultyAnalysis, error) {
+	// 基于多个因素评估难度
+	factors := []string{}
+	estimatedTime := 0
+
+	if skillGap.FromGraph {
+		// 按尚未掌握的知识点逐项估算学习时间
+		for range skillGap.GapPointIDs {
+			estimatedTime += s.taxonomy.StepHours(ctx, goal.Category, goal.Difficulty)
+		}
+		for range prereq.MissingPointIDs {
+			estimatedTime += s.taxonomy.StepHours(ctx, goal.Category, "beginner")
+		}
+		factors = append(factors, fmt.Sprintf("%d个待学知识点、%d个待补前置知识点", len(skillGap.GapPointIDs), len(prereq.MissingPointIDs)))
+	} else {
+		// 根据类别体系确定基准时间
+		estimatedTime = s.taxonomy.BaseHours(ctx, goal.Category)
+	}
+
+	// 根据用户经验调整
+	if profile.IsBeginner() {
+		estimatedTime = int(float64(estimatedTime) * 1.5)
+		factors = append(factors, "初学者需要更多时间")
+	} else if completed := profile.CompletedGoalsInCategory(goal.Category); completed > 0 {
+		estimatedTime = int(float64(estimatedTime) * 0.8)
+		factors = append(factors, fmt.Sprintf("已完成%d个同类别目标", completed))
+	}
+
+	// 根据目标复杂度调整
+	if strings.Contains(strings.ToLower(goal.Description), "advanced") {
+		estimatedTime = int(float64(estimatedTime) * 1.3)
+		factors = append(factors, "高级目标增加复杂度")
+	}
+
+	return &DifficultyAnalysis{
+		Level:         goal.Difficulty,
+		EstimatedTime: estimatedTime,
+		Factors:       factors,
+	}, nil
+}
+
+// skillGapRecommendations 生成技能差距相关的推荐，每条推荐附带推荐依据，并尽量关联知识点、路径模板或学习资源
+func (s *RuleAnalyzer) skillGapRecommendations(ctx context.Context, input *AnalysisInput, skillGap *SkillGapAnalysis) entities.Recommendations {
+	goal, profile, points := input.Goal, input.Profile, input.KnowledgePoints
+	recommendations := entities.Recommendations{}
+	required := len(skillGap.SkillGaps) + len(skillGap.Strengths)
+	stepHours := func(point *entities.KnowledgePoint) int {
+		return s.taxonomy.StepHours(ctx, goal.Category, point.Difficulty)
+	}
+
+	// 学习路径推荐
+	if len(skillGap.SkillGaps) > 0 {
+		rec := entities.Recommendation{
+			Type:         "learning_path",
+			Title:        "技能提升路径",
+			Description:  fmt.Sprintf("针对 %s 等技能的系统性学习路径", strings.Join(skillGap.SkillGaps, ", ")),
+			Priority:     "high",
+			Reason:       fmt.Sprintf("%s类别的%d项必备技能中有%d项尚未掌握", goal.Category, required, len(skillGap.SkillGaps)),
+			AnalysisType: entities.AnalysisTypeSkillGap,
+		}
+		if len(skillGap.GapPoints) > 0 {
+			rec.KnowledgePointIDs = knowledgePointIDs(skillGap.GapPoints)
+			rec.PathTemplate = newPathTemplate(fmt.Sprintf("%s - 技能提升", goal.Title), skillGap.GapPoints, stepHours)
+		} else {
+			rec.PathTemplate = newSkillPathTemplate(fmt.Sprintf("%s - 技能提升", goal.Title), skillGap.SkillGaps, s.taxonomy.StepHours(ctx, goal.Category, goal.Difficulty))
+		}
+		rec.EstimatedTime = rec.PathTemplate.TotalTime
+		recommendations = append(recommendations, rec)
+	}
+
+	// 资源推荐：入门级知识点配置的学习资源
+	if profile.IsBeginner() {
+		beginnerPoints := filterByDifficulty(points, "beginner")
+		recommendations = append(recommendations, entities.Recommendation{
+			Type:              "resource",
+			Title:             "入门学习资源",
+			Description:       "从基础资料开始建立知识框架",
+			Priority:          "medium",
+			EstimatedTime:     10,
+			Reason:            fmt.Sprintf("尚未完成任何学习目标，仅完成%d个学习步骤", len(profile.CompletedPaths)),
+			AnalysisType:      entities.AnalysisTypeSkillGap,
+			KnowledgePointIDs: knowledgePointIDs(beginnerPoints),
+			Resources:         knowledgePointResources(beginnerPoints),
+		})
+	}
+
+	// 已具备全部技能时给出巩固建议，附带更高难度知识点的资源
+	if len(skillGap.SkillGaps) == 0 {
+		advancedPoints := filterAboveDifficulty(points, goal.Difficulty)
+		recommendations = append(recommendations, entities.Recommendation{
+			Type:              "resource",
+			Title:             "巩固与拓展",
+			Description:       "通过练习和进阶资料巩固已掌握的内容",
+			Priority:          "low",
+			EstimatedTime:     5,
+			Reason:            fmt.Sprintf("%d项必备技能均已在已完成路径或测评中体现", required),
+			AnalysisType:      entities.AnalysisTypeSkillGap,
+			KnowledgePointIDs: knowledgePointIDs(advancedPoints),
+			Resources:         knowledgePointResources(advancedPoints),
+		})
+	}
+
+	return recommendations
+}
+
+// prerequisiteRecommendations 生成前置条件相关的推荐
+func (s *RuleAnalyzer) prerequisiteRecommendations(ctx context.Context, input *AnalysisInput, prereq *PrerequisiteAnalysis) entities.Recommendations {
+	goal := input.Goal
+	recommendations := entities.Recommendations{}
+	if len(prereq.Missing) == 0 {
+		return recommendations
+	}
+	stepHours := func(point *entities.KnowledgePoint) int {
+		return s.taxonomy.StepHours(ctx, goal.Category, point.Difficulty)
+	}
+
+	rec := entities.Recommendation{
+		Type:          "skill_building",
+		Title:         "补齐前置知识",
+		Description:   fmt.Sprintf("先学习 %s", strings.Join(prereq.Missing, ", ")),
+		Priority:      "high",
+		EstimatedTime: len(prereq.Missing) * s.taxonomy.StepHours(ctx, goal.Category, "beginner"),
+		Reason:        fmt.Sprintf("必备知识点依赖的%d项前置知识尚未在已完成路径或测评中体现", len(prereq.Missing)),
+		AnalysisType:  entities.AnalysisTypePrerequisite,
+	}
+	if len(prereq.MissingPoints) > 0 {
+		rec.KnowledgePointIDs = knowledgePointIDs(prereq.MissingPoints)
+		rec.PathTemplate = newPathTemplate("前置知识", prereq.MissingPoints, stepHours)
+	} else {
+		rec.PathTemplate = newSkillPathTemplate("前置知识", prereq.Missing, s.taxonomy.StepHours(ctx, goal.Category, "beginner"))
+	}
+	return append(recommendations, rec)
+}
+
+// calculateConfidenceScore 计算单项分析的置信度分数
+// 置信度取决于该分析所依据证据的多少：知识图谱覆盖、学习历史和测评数据，各分析类型的权重不同
+func (s *RuleAnalyzer) calculateConfidenceScore(analysisType string, evidence *entities.AnalysisEvidence) float64 {
+	weights := ruleConfidenceWeights[analysisType]
+	score := 0.1 // 基础分数
+
+	// 类别下有知识点可供比对
+	if evidence.KnowledgePoints > 0 {
+		score += weights.graph
+	}
+
+	// 已完成的学习步骤越多，对掌握情况的判断越可靠
+	score += weights.paths * math.Min(1, float64(evidence.CompletedPaths)/5)
+
+	// 目标历史
+	score += weights.goals * math.Min(1, float64(evidence.GoalHistory)/3)
+
+	// 相关技能中有测评数据的比例
+	if evidence.RequiredSkills > 0 {
+		score += weights.assessed * float64(evidence.AssessedSkills) / float64(evidence.RequiredSkills)
+	}
+
+	// 确保分数在0-1范围内
+	if score > 1.0 {
+		score = 1.0
+	}
+
+	return math.Round(score*100) / 100
+}
+
+// requiredKnowledgePoints 获取不高于目标难度的类别知识点
+func (s *RuleAnalyzer) requiredKnowledgePoints(goal *entities.LearningGoal, points []*entities.KnowledgePoint) []*entities.KnowledgePoint {
+	goalRank := difficultyRank(goal.Difficulty)
+	var required []*entities.KnowledgePoint
+	for _, point := range points {
+		if difficultyRank(point.Difficulty) <= goalRank {
+			required = append(required, point)
+		}
+	}
+	return required
+}
+
+// difficultyRank 难度等级排序值
+func difficultyRank(difficulty string) int {
+	switch difficulty {
+	case "beginner":
+		return 1
+	case "intermediate":
+		return 2
+	case "advanced":
+		return 3
+	}
+	return 2
+}
+
+// filterByDifficulty 筛选指定难度的知识点
+func filterByDifficulty(points []*entities.KnowledgePoint, difficulty string) []*entities.KnowledgePoint {
+	var filtered []*entities.KnowledgePoint
+	for _, point := range points {
+		if point.Difficulty == difficulty {
+			filtered = append(filtered, point)
+		}
+	}
+	return filtered
+}
+
+// filterAboveDifficulty 筛选高于指定难度的知识点
+func filterAboveDifficulty(points []*entities.KnowledgePoint, difficulty string) []*entities.KnowledgePoint {
+	rank := difficultyRank(difficulty)
+	var filtered []*entities.KnowledgePoint
+	for _, point := range points {
+		if difficultyRank(point.Difficulty) > rank {
+			filtered = append(filtered, point)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,354 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+	apperrors "sical-go-backend/pkg/errors"
+	"sical-go-backend/pkg/logger"
+)
+
+// TransitionEvent 状态转换事件
+type TransitionEvent struct {
+	EntityType entities.TransitionEntityType
+	EntityID   uuid.UUID
+	GoalID     uuid.UUID
+	From       string
+	To         string
+	Trigger    entities.TransitionTrigger
+	Override   bool
+	Reason     string
+	At         time.Time
+
+	// 转换的实体（二者仅有一个非空）
+	Goal *entities.LearningGoal
+	Path *entities.LearningPath
+}
+
+// TransitionHook 状态转换钩子
+// before钩子在持久化前执行，可修改实体字段；after钩子在持久化后执行
+type TransitionHook func(ctx context.Context, event *TransitionEvent) error
+
+// TransitionOptions 状态转换选项
+type TransitionOptions struct {
+	Trigger  entities.TransitionTrigger
+	Override bool   // 跳过前置步骤完成检查
+	Reason   string // 转换原因（覆盖前置检查时建议填写）
+}
+
+// StatusService 学习目标与路径步骤的状态机服务
+type StatusService struct {
+	goalRepo        repositories.LearningGoalRepository
+	pathRepo        repositories.LearningPathRepository
+	transitionRepo  repositories.StatusTransitionRepository
+	progressService *ProgressService
+	beforeHooks     []TransitionHook
+	afterHooks      []TransitionHook
+}
+
+// NewStatusService 创建状态机服务
+func NewStatusService(
+	goalRepo repositories.LearningGoalRepository,
+	pathRepo repositories.LearningPathRepository,
+	transitionRepo repositories.StatusTransitionRepository,
+	progressService *ProgressService,
+) *StatusService {
+	s := &StatusService{
+		goalRepo:        goalRepo,
+		pathRepo:        pathRepo,
+		transitionRepo:  transitionRepo,
+		progressService: progressService,
+	}
+
+	// 默认钩子：时间戳、进度重算、事件日志
+	s.OnBeforeTransition(s.stampTimestamps)
+	s.OnAfterTransition(s.recalculateProgress)
+	s.OnAfterTransition(s.publishEvent)
+
+	return s
+}
+
+// OnBeforeTransition 注册持久化前执行的钩子
+func (s *StatusService) OnBeforeTransition(hook TransitionHook) {
+	s.beforeHooks = append(s.beforeHooks, hook)
+}
+
+// OnAfterTransition 注册持久化后执行的钩子
+func (s *StatusService) OnAfterTransition(hook TransitionHook) {
+	s.afterHooks = append(s.afterHooks, hook)
+}
+
+// TransitionPath 转换学习路径步骤状态
+func (s *StatusService) TransitionPath(ctx context.Context, pathID uuid.UUID, target entities.PathStatus, opts TransitionOptions) (*entities.LearningPath, error) {
+	if !target.IsValid() {
+		return nil, apperrors.New(apperrors.ErrorTypeValidation, 400, fmt.Sprintf("无效的状态值: %s", target))
+	}
+
+	path, err := s.pathRepo.GetByID(ctx, pathID)
+	if err != nil {
+		return nil, apperrors.New(apperrors.ErrorTypeNotFound, 404, "学习路径不存在").WithCause(err)
+	}
+
+	current := entities.PathStatus(path.Status)
+	if current == target {
+		return path, nil
+	}
+	if !current.CanTransitionTo(target) {
+		return nil, newInvalidTransitionError(string(current), string(target))
+	}
+
+	// 开始步骤前检查前置步骤是否完成
+	if target == entities.PathStatusInProgress && !opts.Override {
+		predecessor, err := s.findPredecessor(ctx, path)
+		if err != nil {
+			return nil, err
+		}
+		if predecessor != nil && !predecessor.IsCompleted() {
+			return nil, apperrors.New(apperrors.ErrorTypeConflict, 409, "前置步骤尚未完成").
+				WithDetail("predecessor_id", predecessor.ID.String()).
+				WithDetail("predecessor_title", predecessor.Title)
+		}
+	}
+
+	event := s.newEvent(entities.TransitionEntityPath, path.ID, path.GoalID, path.Status, string(target), opts)
+	event.Path = path
+
+	if err := s.runHooks(ctx, s.beforeHooks, event); err != nil {
+		return nil, err
+	}
+
+	path.Status = string(target)
+	if err := s.pathRepo.UpdateProgressFields(ctx, path); err != nil {
+		return nil, err
+	}
+
+	if err := s.recordTransition(ctx, event); err != nil {
+		return nil, err
+	}
+
+	if err := s.runHooks(ctx, s.afterHooks, event); err != nil {
+		return nil, err
+	}
+
+	return path, nil
+}
+
+// TransitionGoal 转换学习目标状态
+func (s *StatusService) TransitionGoal(ctx context.Context, goalID uuid.UUID, target entities.GoalStatus, opts TransitionOptions) (*entities.LearningGoal, error) {
+	if !target.IsValid() {
+		return nil, apperrors.New(apperrors.ErrorTypeValidation, 400, fmt.Sprintf("无效的状态值: %s", target))
+	}
+
+	goal, err := s.goalRepo.GetByID(ctx, goalID)
+	if err != nil {
+		return nil, apperrors.New(apperrors.ErrorTypeNotFound, 404, "学习目标不存在").WithCause(err)
+	}
+
+	current := entities.GoalStatus(goal.Status)
+	if current == target {
+		return goal, nil
+	}
+	if !current.CanTransitionTo(target) {
+		return nil, newInvalidTransitionError(string(current), string(target))
+	}
+
+	// 用户手动完成目标时要求全部步骤已完成
+	if target == entities.GoalStatusCompleted && opts.Trigger != entities.TransitionTriggerSystem && !opts.Override {
+		progress, err := s.progressService.GetGoalProgress(ctx, goalID)
+		if err != nil {
+			return nil, err
+		}
+		if !progress.AllStepsCompleted() {
+			return nil, apperrors.New(apperrors.ErrorTypeConflict, 409, "学习目标仍有未完成的步骤")
+		}
+	}
+
+	event := s.newEvent(entities.TransitionEntityGoal, goal.ID, goal.ID, goal.Status, string(target), opts)
+	event.Goal = goal
+
+	if err := s.runHooks(ctx, s.beforeHooks, event); err != nil {
+		return nil, err
+	}
+
+	goal.Status = string(target)
+	if err := s.goalRepo.Update(ctx, goal); err != nil {
+		return nil, err
+	}
+
+	if err := s.recordTransition(ctx, event); err != nil {
+		return nil, err
+	}
+
+	if err := s.runHooks(ctx, s.afterHooks, event); err != nil {
+		return nil, err
+	}
+
+	return goal, nil
+}
+
+// SyncGoalProgress 重新计算目标进度并按步骤完成情况自动完成或重新激活目标
+func (s *StatusService) SyncGoalProgress(ctx context.Context, goalID uuid.UUID) (*GoalProgress, error) {
+	progress, err := s.progressService.RecalculateGoalProgress(ctx, goalID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := entities.GoalStatus(progress.Status)
+	opts := TransitionOptions{Trigger: entities.TransitionTriggerSystem}
+
+	switch {
+	case progress.AllStepsCompleted() && status.CanTransitionTo(entities.GoalStatusCompleted):
+		opts.Reason = "全部步骤已完成"
+		if _, err := s.TransitionGoal(ctx, goalID, entities.GoalStatusCompleted, opts); err != nil {
+			return nil, err
+		}
+		progress.Status = string(entities.GoalStatusCompleted)
+	case !progress.AllStepsCompleted() && status == entities.GoalStatusCompleted:
+		opts.Reason = "存在未完成的步骤"
+		if _, err := s.TransitionGoal(ctx, goalID, entities.GoalStatusActive, opts); err != nil {
+			return nil, err
+		}
+		progress.Status = string(entities.GoalStatusActive)
+	}
+
+	return progress, nil
+}
+
+// GetPathTransitions 获取步骤的状态转换历史
+func (s *StatusService) GetPathTransitions(ctx context.Context, pathID uuid.UUID) ([]*entities.StatusTransition, error) {
+	return s.transitionRepo.GetByEntity(ctx, string(entities.TransitionEntityPath), pathID)
+}
+
+// GetGoalTransitions 获取目标及其步骤的状态转换历史
+func (s *StatusService) GetGoalTransitions(ctx context.Context, goalID uuid.UUID) ([]*entities.StatusTransition, error) {
+	return s.transitionRepo.GetByGoalID(ctx, goalID)
+}
+
+// findPredecessor 查找步骤的直接前置步骤（同一目标下顺序最接近且更靠前的步骤）
+func (s *StatusService) findPredecessor(ctx context.Context, path *entities.LearningPath) (*entities.LearningPath, error) {
+	siblings, err := s.pathRepo.GetByGoalID(ctx, path.GoalID)
+	if err != nil {
+		return nil, err
+	}
+
+	var predecessor *entities.LearningPath
+	for _, sibling := range siblings {
+		if sibling.ID == path.ID || sibling.Order >= path.Order {
+			continue
+		}
+		if predecessor == nil || sibling.Order > predecessor.Order {
+			predecessor = sibling
+		}
+	}
+	return predecessor, nil
+}
+
+// newEvent 构建状态转换事件
+func (s *StatusService) newEvent(entityType entities.TransitionEntityType, entityID, goalID uuid.UUID, from, to string, opts TransitionOptions) *TransitionEvent {
+	trigger := opts.Trigger
+	if trigger == "" {
+		trigger = entities.TransitionTriggerUser
+	}
+	return &TransitionEvent{
+		EntityType: entityType,
+		EntityID:   entityID,
+		GoalID:     goalID,
+		From:       from,
+		To:         to,
+		Trigger:    trigger,
+		Override:   opts.Override,
+		Reason:     opts.Reason,
+		At:         time.Now(),
+	}
+}
+
+// runHooks 依次执行钩子，任一钩子出错即中止
+func (s *StatusService) runHooks(ctx context.Context, hooks []TransitionHook, event *TransitionEvent) error {
+	for _, hook := range hooks {
+		if err := hook(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordTransition 记录状态转换历史
+func (s *StatusService) recordTransition(ctx context.Context, event *TransitionEvent) error {
+	return s.transitionRepo.Create(ctx, &entities.StatusTransition{
+		EntityType: string(event.EntityType),
+		EntityID:   event.EntityID,
+		GoalID:     event.GoalID,
+		FromStatus: event.From,
+		ToStatus:   event.To,
+		Trigger:    string(event.Trigger),
+		Override:   event.Override,
+		Reason:     event.Reason,
+	})
+}
+
+// stampTimestamps 根据目标状态维护开始/完成时间
+func (s *StatusService) stampTimestamps(ctx context.Context, event *TransitionEvent) error {
+	now := event.At
+
+	if path := event.Path; path != nil {
+		switch entities.PathStatus(event.To) {
+		case entities.PathStatusPending:
+			path.StartedAt = nil
+			path.CompletedAt = nil
+		case entities.PathStatusInProgress:
+			if path.StartedAt == nil {
+				path.StartedAt = &now
+			}
+			path.CompletedAt = nil
+		case entities.PathStatusCompleted:
+			if path.StartedAt == nil {
+				path.StartedAt = &now
+			}
+			path.CompletedAt = &now
+		}
+	}
+
+	if goal := event.Goal; goal != nil {
+		if entities.GoalStatus(event.To) == entities.GoalStatusCompleted {
+			goal.CompletedAt = &now
+		} else {
+			goal.CompletedAt = nil
+		}
+	}
+
+	return nil
+}
+
+// recalculateProgress 步骤状态变化后重新计算目标进度
+func (s *StatusService) recalculateProgress(ctx context.Context, event *TransitionEvent) error {
+	if event.EntityType != entities.TransitionEntityPath {
+		return nil
+	}
+	if _, err := s.SyncGoalProgress(ctx, event.GoalID); err != nil {
+		return fmt.Errorf("更新目标进度失败: %w", err)
+	}
+	return nil
+}
+
+// publishEvent 输出状态转换事件日志
+func (s *StatusService) publishEvent(ctx context.Context, event *TransitionEvent) error {
+	logger.Info("状态转换",
+		logger.String("entity_type", string(event.EntityType)),
+		logger.String("entity_id", event.EntityID.String()),
+		logger.String("from", event.From),
+		logger.String("to", event.To),
+		logger.String("trigger", string(event.Trigger)),
+		logger.Bool("override", event.Override))
+	return nil
+}
+
+// newInvalidTransitionError 创建非法状态转换错误
+func newInvalidTransitionError(from, to string) *apperrors.AppError {
+	return apperrors.New(apperrors.ErrorTypeConflict, 409, fmt.Sprintf("不允许从 %s 转换到 %s", from, to)).
+		WithDetail("from", from).
+		WithDetail("to", to)
+}
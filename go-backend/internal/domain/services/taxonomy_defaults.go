@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/pkg/logger"
+)
+
+// defaultCategory 默认类别配置
+type defaultCategory struct {
+	Name          string
+	Description   string
+	BaseHours     int
+	StepHours     map[string]int
+	Required      []string
+	Prerequisites map[string][]string // 难度 -> 前置条件
+}
+
+// defaultTaxonomy 默认类别体系，与知识库的类别保持一致
+var defaultTaxonomy = []defaultCategory{
+	{
+		Name:        "医学基础",
+		Description: "人体结构、功能与疾病发生的基础知识",
+		BaseHours:   120,
+		StepHours:   map[string]int{"beginner": 3, "intermediate": 5, "advanced": 8},
+		Required:    []string{"人体解剖学", "生理学", "生物化学", "病理学"},
+		Prerequisites: map[string][]string{
+			"beginner":     {"高中生物", "高中化学"},
+			"intermediate": {"人体解剖学", "生理学"},
+			"advanced":     {"病理生理学", "分子生物学"},
+		},
+	},
+	{
+		Name:        "临床医学",
+		Description: "疾病的诊断、治疗与临床决策",
+		BaseHours:   160,
+		StepHours:   map[string]int{"beginner": 4, "intermediate": 6, "advanced": 10},
+		Required:    []string{"诊断学", "内科学", "外科学", "临床思维"},
+		Prerequisites: map[string][]string{
+			"beginner":     {"人体解剖学", "生理学"},
+			"intermediate": {"病理学", "药理学基础"},
+			"advanced":     {"诊断学", "循证医学"},
+		},
+	},
+	{
+		Name:        "药理学",
+		Description: "药物与机体的相互作用及其规律",
+		BaseHours:   100,
+		StepHours:   map[string]int{"beginner": 3, "intermediate": 5, "advanced": 8},
+		Required:    []string{"药效学", "药动学", "药物不良反应", "合理用药"},
+		Prerequisites: map[string][]string{
+			"beginner":     {"生理学", "生物化学"},
+			"intermediate": {"病理学", "药理学基础"},
+			"advanced":     {"临床药理学", "分子药理学"},
+		},
+	},
+	{
+		Name:        "药物化学",
+		Description: "药物的化学结构、合成与构效关系",
+		BaseHours:   100,
+		StepHours:   map[string]int{"beginner": 3, "intermediate": 5, "advanced": 8},
+		Required:    []string{"有机化学", "构效关系", "药物合成", "药物设计"},
+		Prerequisites: map[string][]string{
+			"beginner":     {"无机化学", "有机化学基础"},
+			"intermediate": {"有机化学", "生物化学"},
+			"advanced":     {"药物合成", "计算化学"},
+		},
+	},
+	{
+		Name:        "药剂学",
+		Description: "药物制剂的设计、生产与质量控制",
+		BaseHours:   90,
+		StepHours:   map[string]int{"beginner": 3, "intermediate": 5, "advanced": 7},
+		Required:    []string{"药物制剂", "生物药剂学", "药物稳定性", "制剂工艺"},
+		Prerequisites: map[string][]string{
+			"beginner":     {"物理化学基础", "药物化学基础"},
+			"intermediate": {"物理药剂学", "药物分析"},
+			"advanced":     {"生物药剂学", "新型给药系统"},
+		},
+	},
+	{
+		Name:        "其他",
+		Description: "未归入以上类别的知识",
+		BaseHours:   defaultBaseHours,
+	},
+}
+
+// SeedDefaults 写入默认类别体系，已存在的类别保持不变
+func (s *TaxonomyService) SeedDefaults(ctx context.Context) error {
+	for i, def := range defaultTaxonomy {
+		if _, err := s.taxonomyRepo.GetCategoryByName(ctx, def.Name); err == nil {
+			continue
+		}
+
+		category := &entities.Category{
+			Name:        def.Name,
+			Description: def.Description,
+			BaseHours:   def.BaseHours,
+			SortOrder:   i + 1,
+			IsActive:    true,
+		}
+		if err := s.taxonomyRepo.CreateCategory(ctx, category); err != nil {
+			return err
+		}
+
+		for difficulty, hours := range def.StepHours {
+			if _, err := s.SetCategoryDuration(ctx, category.ID, difficulty, hours); err != nil {
+				return err
+			}
+		}
+
+		for order, name := range def.Required {
+			if err := s.seedMapping(ctx, category, name, entities.CategorySkillRequired, "", order); err != nil {
+				return err
+			}
+		}
+		for difficulty, names := range def.Prerequisites {
+			for order, name := range names {
+				if err := s.seedMapping(ctx, category, name, entities.CategorySkillPrerequisite, difficulty, order); err != nil {
+					return err
+				}
+			}
+		}
+
+		logger.Info("默认类别已创建", logger.String("category", category.Name))
+	}
+	return nil
+}
+
+// seedMapping 创建（或复用）技能并映射到类别
+func (s *TaxonomyService) seedMapping(ctx context.Context, category *entities.Category, skillName string, kind entities.CategorySkillKind, difficulty string, order int) error {
+	skill, err := s.taxonomyRepo.GetSkillByName(ctx, skillName)
+	if err != nil {
+		skill = &entities.Skill{Name: skillName}
+		if err := s.taxonomyRepo.CreateSkill(ctx, skill); err != nil {
+			return err
+		}
+	}
+
+	return s.taxonomyRepo.CreateCategorySkill(ctx, &entities.CategorySkill{
+		CategoryID: category.ID,
+		SkillID:    skill.ID,
+		Kind:       string(kind),
+		Difficulty: difficulty,
+		SortOrder:  order,
+	})
+}
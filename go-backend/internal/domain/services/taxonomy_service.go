@@ -0,0 +1,311 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+	apperrors "sical-go-backend/pkg/errors"
+	"sical-go-backend/pkg/logger"
+)
+
+// 类别未配置技能或时间时使用的默认值
+var (
+	defaultRequiredSkills = []string{"基础知识", "实践技能"}
+	defaultPrerequisites  = []string{"基础知识"}
+	defaultStepHours      = map[string]int{
+		"beginner":     2,
+		"intermediate": 4,
+		"advanced":     6,
+	}
+)
+
+const (
+	defaultBaseHours         = 50
+	defaultStepHoursFallback = 3
+)
+
+// TaxonomyService 类别与技能体系服务
+type TaxonomyService struct {
+	taxonomyRepo repositories.TaxonomyRepository
+}
+
+// NewTaxonomyService 创建类别与技能体系服务
+func NewTaxonomyService(taxonomyRepo repositories.TaxonomyRepository) *TaxonomyService {
+	return &TaxonomyService{
+		taxonomyRepo: taxonomyRepo,
+	}
+}
+
+// ValidateCategory 校验类别名称是否为已启用的类别或子类别
+func (s *TaxonomyService) ValidateCategory(ctx context.Context, name string) error {
+	category, err := s.taxonomyRepo.GetCategoryByName(ctx, strings.TrimSpace(name))
+	if err != nil || !category.IsActive {
+		return apperrors.New(apperrors.ErrorTypeValidation, 400, fmt.Sprintf("无效的类别: %s", name)).
+			WithDetail("category", name)
+	}
+	return nil
+}
+
+// ListCategories 获取类别列表
+func (s *TaxonomyService) ListCategories(ctx context.Context, includeInactive bool) ([]*entities.Category, error) {
+	return s.taxonomyRepo.ListCategories(ctx, includeInactive)
+}
+
+// GetCategory 获取类别
+func (s *TaxonomyService) GetCategory(ctx context.Context, id uuid.UUID) (*entities.Category, error) {
+	category, err := s.taxonomyRepo.GetCategoryByID(ctx, id)
+	if err != nil {
+		return nil, apperrors.New(apperrors.ErrorTypeNotFound, 404, "类别不存在").WithCause(err)
+	}
+	return category, nil
+}
+
+// CreateCategory 创建类别或子类别（仅支持两级）
+func (s *TaxonomyService) CreateCategory(ctx context.Context, category *entities.Category) error {
+	category.Name = strings.TrimSpace(category.Name)
+	if category.Name == "" {
+		return apperrors.New(apperrors.ErrorTypeValidation, 400, "类别名称不能为空")
+	}
+	if _, err := s.taxonomyRepo.GetCategoryByName(ctx, category.Name); err == nil {
+		return apperrors.New(apperrors.ErrorTypeConflict, 409, fmt.Sprintf("类别已存在: %s", category.Name))
+	}
+
+	if category.ParentID != nil {
+		parent, err := s.taxonomyRepo.GetCategoryByID(ctx, *category.ParentID)
+		if err != nil {
+			return apperrors.New(apperrors.ErrorTypeValidation, 400, "父类别不存在").WithCause(err)
+		}
+		if parent.IsSubcategory() {
+			return apperrors.New(apperrors.ErrorTypeValidation, 400, "子类别下不能再创建子类别")
+		}
+	}
+
+	return s.taxonomyRepo.CreateCategory(ctx, category)
+}
+
+// UpdateCategory 更新类别
+// 类别名称被学习目标和知识点引用，不允许修改；如需更名请停用后新建
+func (s *TaxonomyService) UpdateCategory(ctx context.Context, category *entities.Category) error {
+	return s.taxonomyRepo.UpdateCategory(ctx, category)
+}
+
+// DeleteCategory 删除类别（存在子类别时不允许删除）
+func (s *TaxonomyService) DeleteCategory(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.GetCategory(ctx, id); err != nil {
+		return err
+	}
+
+	categories, err := s.taxonomyRepo.ListCategories(ctx, true)
+	if err != nil {
+		return err
+	}
+	for _, c := range categories {
+		if c.ParentID != nil && *c.ParentID == id {
+			return apperrors.New(apperrors.ErrorTypeConflict, 409, "类别下仍有子类别").
+				WithDetail("subcategory", c.Name)
+		}
+	}
+
+	return s.taxonomyRepo.DeleteCategory(ctx, id)
+}
+
+// ListSkills 获取技能列表
+func (s *TaxonomyService) ListSkills(ctx context.Context) ([]*entities.Skill, error) {
+	return s.taxonomyRepo.ListSkills(ctx)
+}
+
+// GetSkill 获取技能
+func (s *TaxonomyService) GetSkill(ctx context.Context, id uuid.UUID) (*entities.Skill, error) {
+	skill, err := s.taxonomyRepo.GetSkillByID(ctx, id)
+	if err != nil {
+		return nil, apperrors.New(apperrors.ErrorTypeNotFound, 404, "技能不存在").WithCause(err)
+	}
+	return skill, nil
+}
+
+// CreateSkill 创建技能
+func (s *TaxonomyService) CreateSkill(ctx context.Context, skill *entities.Skill) error {
+	skill.Name = strings.TrimSpace(skill.Name)
+	if skill.Name == "" {
+		return apperrors.New(apperrors.ErrorTypeValidation, 400, "技能名称不能为空")
+	}
+	if _, err := s.taxonomyRepo.GetSkillByName(ctx, skill.Name); err == nil {
+		return apperrors.New(apperrors.ErrorTypeConflict, 409, fmt.Sprintf("技能已存在: %s", skill.Name))
+	}
+	return s.taxonomyRepo.CreateSkill(ctx, skill)
+}
+
+// UpdateSkill 更新技能
+func (s *TaxonomyService) UpdateSkill(ctx context.Context, skill *entities.Skill) error {
+	skill.Name = strings.TrimSpace(skill.Name)
+	if skill.Name == "" {
+		return apperrors.New(apperrors.ErrorTypeValidation, 400, "技能名称不能为空")
+	}
+	if existing, err := s.taxonomyRepo.GetSkillByName(ctx, skill.Name); err == nil && existing.ID != skill.ID {
+		return apperrors.New(apperrors.ErrorTypeConflict, 409, fmt.Sprintf("技能已存在: %s", skill.Name))
+	}
+	return s.taxonomyRepo.UpdateSkill(ctx, skill)
+}
+
+// DeleteSkill 删除技能
+func (s *TaxonomyService) DeleteSkill(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.GetSkill(ctx, id); err != nil {
+		return err
+	}
+	return s.taxonomyRepo.DeleteSkill(ctx, id)
+}
+
+// GetCategorySkills 获取类别的技能映射
+func (s *TaxonomyService) GetCategorySkills(ctx context.Context, categoryID uuid.UUID) ([]*entities.CategorySkill, error) {
+	if _, err := s.GetCategory(ctx, categoryID); err != nil {
+		return nil, err
+	}
+	return s.taxonomyRepo.GetCategorySkills(ctx, categoryID, "")
+}
+
+// AddCategorySkill 为类别添加必备技能或前置条件
+func (s *TaxonomyService) AddCategorySkill(ctx context.Context, mapping *entities.CategorySkill) error {
+	if !entities.CategorySkillKind(mapping.Kind).IsValid() {
+		return apperrors.New(apperrors.ErrorTypeValidation, 400, fmt.Sprintf("无效的映射类型: %s", mapping.Kind))
+	}
+	if mapping.Difficulty != "" && !isValidDifficulty(mapping.Difficulty) {
+		return apperrors.New(apperrors.ErrorTypeValidation, 400, fmt.Sprintf("无效的难度值: %s", mapping.Difficulty))
+	}
+	if _, err := s.GetCategory(ctx, mapping.CategoryID); err != nil {
+		return err
+	}
+	if _, err := s.GetSkill(ctx, mapping.SkillID); err != nil {
+		return err
+	}
+	return s.taxonomyRepo.CreateCategorySkill(ctx, mapping)
+}
+
+// RemoveCategorySkill 删除类别技能映射
+func (s *TaxonomyService) RemoveCategorySkill(ctx context.Context, categoryID, mappingID uuid.UUID) error {
+	mappings, err := s.GetCategorySkills(ctx, categoryID)
+	if err != nil {
+		return err
+	}
+	for _, mapping := range mappings {
+		if mapping.ID == mappingID {
+			return s.taxonomyRepo.DeleteCategorySkill(ctx, mappingID)
+		}
+	}
+	return apperrors.New(apperrors.ErrorTypeNotFound, 404, "类别技能映射不存在")
+}
+
+// SetCategoryDuration 设置类别在指定难度下单个知识点的基准学习时间
+func (s *TaxonomyService) SetCategoryDuration(ctx context.Context, categoryID uuid.UUID, difficulty string, stepHours int) (*entities.CategoryDuration, error) {
+	if !isValidDifficulty(difficulty) {
+		return nil, apperrors.New(apperrors.ErrorTypeValidation, 400, fmt.Sprintf("无效的难度值: %s", difficulty))
+	}
+	if stepHours <= 0 {
+		return nil, apperrors.New(apperrors.ErrorTypeValidation, 400, "基准学习时间必须大于0")
+	}
+	if _, err := s.GetCategory(ctx, categoryID); err != nil {
+		return nil, err
+	}
+
+	duration := &entities.CategoryDuration{
+		CategoryID: categoryID,
+		Difficulty: difficulty,
+		StepHours:  stepHours,
+	}
+	if err := s.taxonomyRepo.UpsertCategoryDuration(ctx, duration); err != nil {
+		return nil, err
+	}
+	return duration, nil
+}
+
+// RequiredSkills 获取类别的必备技能名称
+// 子类别未配置时沿用父类别，均未配置时返回默认技能
+func (s *TaxonomyService) RequiredSkills(ctx context.Context, categoryName string) []string {
+	skills := s.resolveSkills(ctx, categoryName, entities.CategorySkillRequired, "")
+	if len(skills) == 0 {
+		return defaultRequiredSkills
+	}
+	return skills
+}
+
+// Prerequisites 获取类别在指定难度下的前置条件名称
+func (s *TaxonomyService) Prerequisites(ctx context.Context, categoryName, difficulty string) []string {
+	skills := s.resolveSkills(ctx, categoryName, entities.CategorySkillPrerequisite, difficulty)
+	if len(skills) == 0 {
+		return defaultPrerequisites
+	}
+	return skills
+}
+
+// BaseHours 获取类别目标的基准学习时间(小时)
+func (s *TaxonomyService) BaseHours(ctx context.Context, categoryName string) int {
+	for _, category := range s.categoryChain(ctx, categoryName) {
+		if category.BaseHours > 0 {
+			return category.BaseHours
+		}
+	}
+	return defaultBaseHours
+}
+
+// StepHours 获取类别在指定难度下单个知识点的基准学习时间(小时)
+func (s *TaxonomyService) StepHours(ctx context.Context, categoryName, difficulty string) int {
+	for _, category := range s.categoryChain(ctx, categoryName) {
+		for _, duration := range category.Durations {
+			if duration.Difficulty == difficulty {
+				return duration.StepHours
+			}
+		}
+	}
+	if hours, exists := defaultStepHours[difficulty]; exists {
+		return hours
+	}
+	return defaultStepHoursFallback
+}
+
+// resolveSkills 按类别链查找技能映射，取第一个有配置的类别
+func (s *TaxonomyService) resolveSkills(ctx context.Context, categoryName string, kind entities.CategorySkillKind, difficulty string) []string {
+	for _, category := range s.categoryChain(ctx, categoryName) {
+		mappings, err := s.taxonomyRepo.GetCategorySkills(ctx, category.ID, string(kind))
+		if err != nil {
+			logger.Warn("获取类别技能映射失败",
+				logger.String("category", category.Name),
+				logger.String("error", err.Error()))
+			return nil
+		}
+
+		var skills []string
+		for _, mapping := range mappings {
+			if mapping.Difficulty == "" || mapping.Difficulty == difficulty {
+				skills = append(skills, mapping.Skill.Name)
+			}
+		}
+		if len(skills) > 0 {
+			return skills
+		}
+	}
+	return nil
+}
+
+// categoryChain 获取类别及其父类别（子类别在前），类别不存在时返回空
+func (s *TaxonomyService) categoryChain(ctx context.Context, categoryName string) []*entities.Category {
+	category, err := s.taxonomyRepo.GetCategoryByName(ctx, categoryName)
+	if err != nil {
+		return nil
+	}
+
+	chain := []*entities.Category{category}
+	if category.ParentID != nil {
+		if parent, err := s.taxonomyRepo.GetCategoryByID(ctx, *category.ParentID); err == nil {
+			chain = append(chain, parent)
+		}
+	}
+	return chain
+}
+
+// isValidDifficulty 检查难度值是否合法
+func isValidDifficulty(difficulty string) bool {
+	return difficulty == "beginner" || difficulty == "intermediate" || difficulty == "advanced"
+}
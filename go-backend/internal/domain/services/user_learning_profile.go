@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+)
+
+// masteryThreshold 掌握度达到该值视为已掌握知识点
+const masteryThreshold = 0.7
+
+// KnowledgeMasteryProvider 用户知识点掌握度来源（如测评结果）
+type KnowledgeMasteryProvider interface {
+	// GetMastery 获取用户各知识点的掌握度(0-1)
+	GetMastery(ctx context.Context, userID uint) (map[uuid.UUID]float64, error)
+}
+
+// UserLearningProfile 用户学习画像，汇总目标历史、已完成路径和测评结果
+type UserLearningProfile struct {
+	UserID         uint
+	Goals          []*entities.LearningGoal // 不含当前分析的目标
+	CompletedGoals []*entities.LearningGoal
+	CompletedPaths []*entities.LearningPath
+	Mastery        map[uuid.UUID]float64
+
+	coveredPoints map[uuid.UUID]bool
+	coveredTitles map[string]bool
+}
+
+// loadUserLearningProfile 加载用户学习画像
+func loadUserLearningProfile(
+	ctx context.Context,
+	goal *entities.LearningGoal,
+	goalRepo repositories.LearningGoalRepository,
+	pathRepo repositories.LearningPathRepository,
+	masteryProvider KnowledgeMasteryProvider,
+) (*UserLearningProfile, error) {
+	profile := &UserLearningProfile{
+		UserID:        goal.UserID,
+		Mastery:       map[uuid.UUID]float64{},
+		coveredPoints: map[uuid.UUID]bool{},
+		coveredTitles: map[string]bool{},
+	}
+
+	goals, err := goalRepo.GetByUserID(ctx, goal.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("获取用户目标历史失败: %w", err)
+	}
+
+	// 当前目标自身已完成的步骤同样算作用户已掌握的内容
+	goals = append(goals, goal)
+	seen := map[uuid.UUID]bool{}
+	for _, g := range goals {
+		if seen[g.ID] {
+			continue
+		}
+		seen[g.ID] = true
+
+		if g.ID != goal.ID {
+			profile.Goals = append(profile.Goals, g)
+			if g.IsCompleted() {
+				profile.CompletedGoals = append(profile.CompletedGoals, g)
+			}
+		}
+
+		paths, err := pathRepo.GetByGoalID(ctx, g.ID)
+		if err != nil {
+			return nil, fmt.Errorf("获取用户学习路径失败: %w", err)
+		}
+		for _, path := range paths {
+			if !path.IsCompleted() {
+				continue
+			}
+			profile.CompletedPaths = append(profile.CompletedPaths, path)
+			profile.coveredTitles[normalizeSkill(path.Title)] = true
+			for _, kp := range path.KnowledgePoints {
+				profile.coveredPoints[kp.ID] = true
+				profile.coveredTitles[normalizeSkill(kp.Title)] = true
+			}
+		}
+	}
+
+	if masteryProvider != nil {
+		mastery, err := masteryProvider.GetMastery(ctx, goal.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("获取用户测评结果失败: %w", err)
+		}
+		if mastery != nil {
+			profile.Mastery = mastery
+		}
+	}
+
+	return profile, nil
+}
+
+// HasMastered 检查用户是否已掌握知识点
+// 测评掌握度优先；无测评数据时以已完成路径中是否覆盖该知识点为准
+func (p *UserLearningProfile) HasMastered(point *entities.KnowledgePoint) bool {
+	if level, ok := p.Mastery[point.ID]; ok {
+		return level >= masteryThreshold
+	}
+	return p.coveredPoints[point.ID] || p.coveredTitles[normalizeSkill(point.Title)]
+}
+
+// HasAssessment 检查知识点是否有测评数据
+func (p *UserLearningProfile) HasAssessment(pointID uuid.UUID) bool {
+	_, ok := p.Mastery[pointID]
+	return ok
+}
+
+// HasCoveredSkill 检查用户已完成的路径或目标是否覆盖指定技能名称
+func (p *UserLearningProfile) HasCoveredSkill(skill string) bool {
+	key := normalizeSkill(skill)
+	if p.coveredTitles[key] {
+		return true
+	}
+	for _, g := range p.CompletedGoals {
+		if normalizeSkill(g.Title) == key {
+			return true
+		}
+	}
+	return false
+}
+
+// CompletedGoalsInCategory 统计用户在指定类别下已完成的目标数
+func (p *UserLearningProfile) CompletedGoalsInCategory(category string) int {
+	count := 0
+	for _, g := range p.CompletedGoals {
+		if g.Category == category {
+			count++
+		}
+	}
+	return count
+}
+
+// IsBeginner 判断用户是否为初学者：没有完成过目标且完成的步骤很少
+func (p *UserLearningProfile) IsBeginner() bool {
+	return len(p.CompletedGoals) == 0 && len(p.CompletedPaths) < 3
+}
+
+// parsePrerequisiteRefs 解析知识点前置条件，返回ID引用和标题引用
+func parsePrerequisiteRefs(prerequisitesJSON string) ([]uuid.UUID, []string) {
+	if prerequisitesJSON == "" {
+		return nil, nil
+	}
+
+	var refs []string
+	if err := json.Unmarshal([]byte(prerequisitesJSON), &refs); err != nil {
+		return nil, nil
+	}
+
+	var ids []uuid.UUID
+	var titles []string
+	for _, ref := range refs {
+		if id, err := uuid.Parse(ref); err == nil {
+			ids = append(ids, id)
+		} else if strings.TrimSpace(ref) != "" {
+			titles = append(titles, ref)
+		}
+	}
+	return ids, titles
+}
+
+// normalizeSkill 规范化技能名称用于比较
+func normalizeSkill(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"strconv"
 	"time"
 
 	"sical-go-backend/internal/domain/entities"
@@ -122,9 +123,10 @@ type userService struct {
 	jwtManager     *jwt.JWTManager
 	validator      validator.Validator
 	passwordHasher PasswordHasher
+	moderation     *ModerationService
 }
 
-// NewUserService 创建用户服务
+// NewUserService 创建用户服务，修改后的个人简介需经过moderation内容审核
 func NewUserService(
 	userRepo repositories.UserRepository,
 	profileRepo repositories.UserProfileRepository,
@@ -132,6 +134,7 @@ func NewUserService(
 	jwtManager *jwt.JWTManager,
 	validator validator.Validator,
 	passwordHasher PasswordHasher,
+	moderation *ModerationService,
 ) UserService {
 	return &userService{
 		userRepo:       userRepo,
@@ -140,6 +143,7 @@ func NewUserService(
 		jwtManager:     jwtManager,
 		validator:      validator,
 		passwordHasher: passwordHasher,
+		moderation:     moderation,
 	}
 }
 
@@ -327,11 +331,25 @@ func (s *userService) UpdateProfile(ctx context.Context, userID uint, req *Updat
 	if req.Avatar != nil {
 		profile.Avatar = *req.Avatar
 	}
+	// 修改后的简介需经过内容审核
+	var verdict *ModerationVerdict
+	bioContentID := strconv.FormatUint(uint64(userID), 10)
 	if req.Bio != nil {
+		if *req.Bio != "" && *req.Bio != profile.Bio {
+			if verdict, err = s.moderation.Screen(ctx, entities.ModerationContentUserBio, bioContentID, userID, *req.Bio); err != nil {
+				return err
+			}
+		}
 		profile.Bio = *req.Bio
 	}
 
-	return s.profileRepo.Update(ctx, profile)
+	if err := s.profileRepo.Update(ctx, profile); err != nil {
+		return err
+	}
+	if verdict != nil {
+		s.moderation.FlagForReview(ctx, entities.ModerationContentUserBio, bioContentID, userID, profile.Bio, verdict)
+	}
+	return nil
 }
 
 // ChangePassword 修改密码
@@ -0,0 +1,72 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+	"sical-go-backend/pkg/logger"
+)
+
+// accountIDColumns 早期以UUID保存的用户ID列，账号ID为自增整数，需转换为bigint
+var accountIDColumns = []struct {
+	table    string
+	column   string
+	required bool // 非空的归属列
+}{
+	{"learning_goals", "user_id", true},
+	{"analysis_usages", "user_id", true},
+	{"recommendation_outcomes", "user_id", true},
+	{"assessments", "created_by", true},
+	{"assessment_attempts", "user_id", true},
+	{"attempt_answers", "graded_by", false},
+	{"regrade_requests", "user_id", true},
+	{"regrade_requests", "resolved_by", false},
+	{"diagnostic_sessions", "user_id", true},
+	{"user_knowledge_mastery", "user_id", true},
+}
+
+// MigrateAccountIDColumns 将UUID类型的用户ID列转换为账号ID，须在自动迁移之前执行
+// UUID无法对应任何账号，转换时置空，必填列上无归属的记录随之删除
+func MigrateAccountIDColumns(ctx context.Context, db *gorm.DB) error {
+	db = db.WithContext(ctx)
+
+	for _, target := range accountIDColumns {
+		var dataType string
+		if err := db.Raw("SELECT data_type FROM information_schema.columns WHERE table_schema = current_schema() AND table_name = ? AND column_name = ?",
+			target.table, target.column).Scan(&dataType).Error; err != nil {
+			return fmt.Errorf("检查%s.%s列类型失败: %w", target.table, target.column, err)
+		}
+		if dataType != "uuid" {
+			continue
+		}
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec(fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s DROP NOT NULL", target.table, target.column)).Error; err != nil {
+				return err
+			}
+			var orphaned int64
+			if err := tx.Table(target.table).Where(target.column + " IS NOT NULL").Count(&orphaned).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec(fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s TYPE bigint USING NULL", target.table, target.column)).Error; err != nil {
+				return err
+			}
+			if orphaned > 0 {
+				logger.Warn("用户ID列中的UUID无法对应账号，已置空",
+					logger.String("table", target.table),
+					logger.String("column", target.column),
+					logger.Int64("rows", orphaned))
+			}
+			if !target.required {
+				return nil
+			}
+			// 无归属的记录无法访问，删除后自动迁移才能恢复非空约束
+			return tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE %s IS NULL", target.table, target.column)).Error
+		})
+		if err != nil {
+			return fmt.Errorf("转换%s.%s列失败: %w", target.table, target.column, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+)
+
+// analysisJobRepositoryImpl 分析任务仓储实现（基于PostgreSQL的任务队列）
+type analysisJobRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewAnalysisJobRepository 创建分析任务仓储实例
+func NewAnalysisJobRepository(db *gorm.DB) repositories.AnalysisJobRepository {
+	return &analysisJobRepositoryImpl{
+		db: db,
+	}
+}
+
+// Create 创建任务
+func (r *analysisJobRepositoryImpl) Create(ctx context.Context, job *entities.AnalysisJob) error {
+	if err := r.db.WithContext(ctx).Create(job).Error; err != nil {
+		return fmt.Errorf("创建分析任务失败: %w", err)
+	}
+	return nil
+}
+
+// GetByID 根据ID获取任务
+func (r *analysisJobRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*entities.AnalysisJob, error) {
+	var job entities.AnalysisJob
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&job).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("分析任务不存在")
+		}
+		return nil, fmt.Errorf("获取分析任务失败: %w", err)
+	}
+	return &job, nil
+}
+
+// FindRecentByDedupKey 查找同键的排队中任务、staleBefore之后开始执行的任务或since之后创建的成功任务
+// 开始执行早于staleBefore的任务视为工作协程已中断，不再用于去重
+func (r *analysisJobRepositoryImpl) FindRecentByDedupKey(ctx context.Context, dedupKey string, since, staleBefore time.Time) (*entities.AnalysisJob, error) {
+	var jobs []*entities.AnalysisJob
+	err := r.db.WithContext(ctx).
+		Where("dedup_key = ?", dedupKey).
+		Where("status = ? OR (status = ? AND started_at >= ?) OR (status = ? AND created_at >= ?)",
+			string(entities.AnalysisJobQueued),
+			string(entities.AnalysisJobRunning), staleBefore,
+			string(entities.AnalysisJobSucceeded), since).
+		Order("created_at DESC").
+		Limit(1).
+		Find(&jobs).Error
+	if err != nil {
+		return nil, fmt.Errorf("查询分析任务失败: %w", err)
+	}
+	if len(jobs) == 0 {
+		return nil, nil
+	}
+	return jobs[0], nil
+}
+
+// ClaimNext 领取下一个待执行任务
+// 使用 FOR UPDATE SKIP LOCKED 保证多个工作协程（或多个实例）不会领取同一任务
+func (r *analysisJobRepositoryImpl) ClaimNext(ctx context.Context, staleBefore time.Time, maxAttempts int) (*entities.AnalysisJob, error) {
+	var claimed *entities.AnalysisJob
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 执行超时且重试次数已用尽的任务不会再被领取，直接标记为失败
+		err := tx.Model(&entities.AnalysisJob{}).
+			Where("status = ? AND started_at < ? AND attempts >= ?",
+				string(entities.AnalysisJobRunning), staleBefore, maxAttempts).
+			Updates(map[string]interface{}{
+				"status":      string(entities.AnalysisJobFailed),
+				"error":       "任务执行超时且已达最大尝试次数",
+				"finished_at": time.Now(),
+			}).Error
+		if err != nil {
+			return err
+		}
+
+		var jobs []*entities.AnalysisJob
+		err = tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? OR (status = ? AND started_at < ?)",
+				string(entities.AnalysisJobQueued), string(entities.AnalysisJobRunning), staleBefore).
+			Where("attempts < ?", maxAttempts).
+			Order("created_at ASC").
+			Limit(1).
+			Find(&jobs).Error
+		if err != nil {
+			return err
+		}
+		if len(jobs) == 0 {
+			return nil
+		}
+
+		job := jobs[0]
+		now := time.Now()
+		job.Status = string(entities.AnalysisJobRunning)
+		job.Attempts++
+		job.StartedAt = &now
+		updates := map[string]interface{}{
+			"status":     job.Status,
+			"attempts":   job.Attempts,
+			"started_at": job.StartedAt,
+		}
+		if err := tx.Model(&entities.AnalysisJob{}).Where("id = ?", job.ID).Updates(updates).Error; err != nil {
+			return err
+		}
+		claimed = job
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("领取分析任务失败: %w", err)
+	}
+	return claimed, nil
+}
+
+// MarkSucceeded 标记任务成功
+func (r *analysisJobRepositoryImpl) MarkSucceeded(ctx context.Context, id uuid.UUID) error {
+	updates := map[string]interface{}{
+		"status":      string(entities.AnalysisJobSucceeded),
+		"error":       "",
+		"finished_at": time.Now(),
+	}
+	if err := r.db.WithContext(ctx).Model(&entities.AnalysisJob{}).Where("id = ?", id).Updates(updates).Error; err != nil {
+		return fmt.Errorf("更新分析任务状态失败: %w", err)
+	}
+	return nil
+}
+
+// MarkFailed 标记任务失败
+func (r *analysisJobRepositoryImpl) MarkFailed(ctx context.Context, id uuid.UUID, errMsg string) error {
+	updates := map[string]interface{}{
+		"status":      string(entities.AnalysisJobFailed),
+		"error":       errMsg,
+		"finished_at": time.Now(),
+	}
+	if err := r.db.WithContext(ctx).Model(&entities.AnalysisJob{}).Where("id = ?", id).Updates(updates).Error; err != nil {
+		return fmt.Errorf("更新分析任务状态失败: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+)
+
+// analysisUsageRepositoryImpl 分析调用记录仓储实现
+type analysisUsageRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewAnalysisUsageRepository 创建分析调用记录仓储实例
+func NewAnalysisUsageRepository(db *gorm.DB) repositories.AnalysisUsageRepository {
+	return &analysisUsageRepositoryImpl{
+		db: db,
+	}
+}
+
+// Create 创建调用记录
+func (r *analysisUsageRepositoryImpl) Create(ctx context.Context, usage *entities.AnalysisUsage) error {
+	if err := r.db.WithContext(ctx).Create(usage).Error; err != nil {
+		return fmt.Errorf("创建分析调用记录失败: %w", err)
+	}
+	return nil
+}
+
+// GetByUserID 获取用户在时间范围内的调用记录
+func (r *analysisUsageRepositoryImpl) GetByUserID(ctx context.Context, userID uint, from *time.Time) ([]*entities.AnalysisUsage, error) {
+	var usages []*entities.AnalysisUsage
+	query := r.db.WithContext(ctx).Where("user_id = ?", userID)
+	if from != nil {
+		query = query.Where("created_at >= ?", *from)
+	}
+	if err := query.Order("created_at DESC").Find(&usages).Error; err != nil {
+		return nil, fmt.Errorf("获取分析调用记录失败: %w", err)
+	}
+	return usages, nil
+}
+
+// SumCostByUserID 统计用户自from起的调用费用
+func (r *analysisUsageRepositoryImpl) SumCostByUserID(ctx context.Context, userID uint, from time.Time) (float64, error) {
+	var total float64
+	err := r.db.WithContext(ctx).Model(&entities.AnalysisUsage{}).
+		Where("user_id = ? AND created_at >= ?", userID, from).
+		Select("COALESCE(SUM(cost), 0)").
+		Scan(&total).Error
+	if err != nil {
+		return 0, fmt.Errorf("统计分析调用费用失败: %w", err)
+	}
+	return total, nil
+}
@@ -0,0 +1,317 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+)
+
+// assessmentRepositoryImpl 测评仓储实现
+type assessmentRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewAssessmentRepository 创建测评仓储实例
+func NewAssessmentRepository(db *gorm.DB) repositories.AssessmentRepository {
+	return &assessmentRepositoryImpl{
+		db: db,
+	}
+}
+
+// Create 创建测评，题目和知识点关联随测评一并写入
+func (r *assessmentRepositoryImpl) Create(ctx context.Context, assessment *entities.Assessment) error {
+	if err := r.db.WithContext(ctx).Omit("KnowledgePoints.*").Create(assessment).Error; err != nil {
+		return fmt.Errorf("创建测评失败: %w", err)
+	}
+	return nil
+}
+
+// GetByID 根据ID获取测评
+func (r *assessmentRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*entities.Assessment, error) {
+	var assessment entities.Assessment
+	err := r.db.WithContext(ctx).
+		Preload("Questions", func(db *gorm.DB) *gorm.DB {
+			return db.Order(`"order" ASC, created_at ASC`)
+		}).
+		Preload("KnowledgePoints").
+		Where("id = ?", id).
+		First(&assessment).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("测评不存在")
+		}
+		return nil, fmt.Errorf("获取测评失败: %w", err)
+	}
+	return &assessment, nil
+}
+
+// Update 更新测评基本信息，不级联更新题目
+func (r *assessmentRepositoryImpl) Update(ctx context.Context, assessment *entities.Assessment) error {
+	if err := r.db.WithContext(ctx).Omit(clause.Associations).Save(assessment).Error; err != nil {
+		return fmt.Errorf("更新测评失败: %w", err)
+	}
+	return nil
+}
+
+// List 按条件分页获取测评列表
+func (r *assessmentRepositoryImpl) List(ctx context.Context, filter repositories.AssessmentFilter, offset, limit int) ([]*entities.Assessment, int64, error) {
+	query := r.db.WithContext(ctx).Model(&entities.Assessment{})
+	if filter.Category != "" {
+		query = query.Where("category = ?", filter.Category)
+	}
+	if filter.Difficulty != "" {
+		query = query.Where("difficulty = ?", filter.Difficulty)
+	}
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+	if filter.PublishedOnly {
+		query = query.Where("is_published = ?", true)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("统计测评数量失败: %w", err)
+	}
+
+	var assessments []*entities.Assessment
+	if err := query.Order("created_at DESC").Offset(offset).Limit(limit).Find(&assessments).Error; err != nil {
+		return nil, 0, fmt.Errorf("获取测评列表失败: %w", err)
+	}
+	return assessments, total, nil
+}
+
+// ListQuestionsByKnowledgePoints 获取已发布测评中关联指定知识点的题目
+func (r *assessmentRepositoryImpl) ListQuestionsByKnowledgePoints(ctx context.Context, knowledgePointIDs []uuid.UUID, types []string) ([]*entities.AssessmentQuestion, error) {
+	var questions []*entities.AssessmentQuestion
+	if len(knowledgePointIDs) == 0 {
+		return questions, nil
+	}
+	query := r.db.WithContext(ctx).
+		Joins("JOIN assessments ON assessments.id = assessment_questions.assessment_id").
+		Where("assessments.is_published = ? AND assessments.deleted_at IS NULL", true).
+		Where("assessment_questions.knowledge_point_id IN ?", knowledgePointIDs)
+	if len(types) > 0 {
+		query = query.Where("assessment_questions.type IN ?", types)
+	}
+	if err := query.Find(&questions).Error; err != nil {
+		return nil, fmt.Errorf("获取知识点题目失败: %w", err)
+	}
+	return questions, nil
+}
+
+// GetQuestionByID 根据ID获取题目
+func (r *assessmentRepositoryImpl) GetQuestionByID(ctx context.Context, id uuid.UUID) (*entities.AssessmentQuestion, error) {
+	var question entities.AssessmentQuestion
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&question).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("题目不存在")
+		}
+		return nil, fmt.Errorf("获取题目失败: %w", err)
+	}
+	return &question, nil
+}
+
+// RecordCompletion 在数据库中原子地累计完成人次和平均分
+func (r *assessmentRepositoryImpl) RecordCompletion(ctx context.Context, id uuid.UUID, score float64) error {
+	err := r.db.WithContext(ctx).
+		Model(&entities.Assessment{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"average_score":   gorm.Expr("(average_score * completed_count + ?) / (completed_count + 1)", score),
+			"completed_count": gorm.Expr("completed_count + 1"),
+		}).Error
+	if err != nil {
+		return fmt.Errorf("更新测评统计失败: %w", err)
+	}
+	return nil
+}
+
+// AdjustAverageScore 按成绩变化修正平均分
+func (r *assessmentRepositoryImpl) AdjustAverageScore(ctx context.Context, id uuid.UUID, delta float64) error {
+	err := r.db.WithContext(ctx).
+		Model(&entities.Assessment{}).
+		Where("id = ? AND completed_count > 0", id).
+		Update("average_score", gorm.Expr("average_score + ? / completed_count", delta)).Error
+	if err != nil {
+		return fmt.Errorf("更新测评统计失败: %w", err)
+	}
+	return nil
+}
+
+// assessmentAttemptRepositoryImpl 测评作答仓储实现
+type assessmentAttemptRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewAssessmentAttemptRepository 创建测评作答仓储实例
+func NewAssessmentAttemptRepository(db *gorm.DB) repositories.AssessmentAttemptRepository {
+	return &assessmentAttemptRepositoryImpl{
+		db: db,
+	}
+}
+
+// Create 创建作答记录
+func (r *assessmentAttemptRepositoryImpl) Create(ctx context.Context, attempt *entities.AssessmentAttempt) error {
+	if err := r.db.WithContext(ctx).Create(attempt).Error; err != nil {
+		return fmt.Errorf("创建作答记录失败: %w", err)
+	}
+	return nil
+}
+
+// GetByID 根据ID获取作答记录及答案
+func (r *assessmentAttemptRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*entities.AssessmentAttempt, error) {
+	var attempt entities.AssessmentAttempt
+	if err := r.db.WithContext(ctx).Preload("Answers").Where("id = ?", id).First(&attempt).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("作答记录不存在")
+		}
+		return nil, fmt.Errorf("获取作答记录失败: %w", err)
+	}
+	return &attempt, nil
+}
+
+// GetInProgress 获取用户在测评上进行中的作答
+func (r *assessmentAttemptRepositoryImpl) GetInProgress(ctx context.Context, assessmentID uuid.UUID, userID uint) (*entities.AssessmentAttempt, error) {
+	var attempts []*entities.AssessmentAttempt
+	err := r.db.WithContext(ctx).
+		Preload("Answers").
+		Where("assessment_id = ? AND user_id = ? AND status = ?", assessmentID, userID, string(entities.AttemptInProgress)).
+		Order("started_at DESC").
+		Limit(1).
+		Find(&attempts).Error
+	if err != nil {
+		return nil, fmt.Errorf("查询进行中的作答失败: %w", err)
+	}
+	if len(attempts) == 0 {
+		return nil, nil
+	}
+	return attempts[0], nil
+}
+
+// CountByUser 统计用户在测评上的作答次数
+func (r *assessmentAttemptRepositoryImpl) CountByUser(ctx context.Context, assessmentID uuid.UUID, userID uint) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).
+		Model(&entities.AssessmentAttempt{}).
+		Where("assessment_id = ? AND user_id = ?", assessmentID, userID).
+		Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("统计作答次数失败: %w", err)
+	}
+	return count, nil
+}
+
+// ListByUser 获取用户在测评上的作答记录
+func (r *assessmentAttemptRepositoryImpl) ListByUser(ctx context.Context, assessmentID uuid.UUID, userID uint) ([]*entities.AssessmentAttempt, error) {
+	var attempts []*entities.AssessmentAttempt
+	if err := r.db.WithContext(ctx).
+		Where("assessment_id = ? AND user_id = ?", assessmentID, userID).
+		Order("attempt_number DESC").
+		Find(&attempts).Error; err != nil {
+		return nil, fmt.Errorf("获取作答记录失败: %w", err)
+	}
+	return attempts, nil
+}
+
+// SaveAnswers 保存答案，按(作答, 题目)覆盖已有答案
+func (r *assessmentAttemptRepositoryImpl) SaveAnswers(ctx context.Context, answers []*entities.AttemptAnswer) error {
+	if len(answers) == 0 {
+		return nil
+	}
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "attempt_id"}, {Name: "question_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"answer", "updated_at"}),
+	}).Create(&answers).Error
+	if err != nil {
+		return fmt.Errorf("保存答案失败: %w", err)
+	}
+	return nil
+}
+
+// MarkSubmitted 以条件更新标记作答已提交，避免并发提交重复评分
+func (r *assessmentAttemptRepositoryImpl) MarkSubmitted(ctx context.Context, id uuid.UUID, submittedAt time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&entities.AssessmentAttempt{}).
+		Where("id = ? AND status = ?", id, string(entities.AttemptInProgress)).
+		Updates(map[string]interface{}{
+			"status":       string(entities.AttemptSubmitted),
+			"submitted_at": submittedAt,
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("提交作答失败: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Update 更新作答记录，答案的评分结果一并保存
+func (r *assessmentAttemptRepositoryImpl) Update(ctx context.Context, attempt *entities.AssessmentAttempt) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Save(attempt).Error; err != nil {
+			return fmt.Errorf("更新作答记录失败: %w", err)
+		}
+		for i := range attempt.Answers {
+			if err := tx.Save(&attempt.Answers[i]).Error; err != nil {
+				return fmt.Errorf("更新答案评分失败: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// ListPendingReview 分页获取待人工评分的作答
+func (r *assessmentAttemptRepositoryImpl) ListPendingReview(ctx context.Context, assessmentID *uuid.UUID, offset, limit int) ([]*entities.AssessmentAttempt, int64, error) {
+	query := r.db.WithContext(ctx).
+		Model(&entities.AssessmentAttempt{}).
+		Where("status = ?", string(entities.AttemptSubmitted))
+	if assessmentID != nil {
+		query = query.Where("assessment_id = ?", *assessmentID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("统计待评分作答失败: %w", err)
+	}
+
+	var attempts []*entities.AssessmentAttempt
+	if err := query.
+		Preload("Answers", "grading_status = ?", string(entities.AnswerNeedsReview)).
+		Order("submitted_at ASC").
+		Offset(offset).
+		Limit(limit).
+		Find(&attempts).Error; err != nil {
+		return nil, 0, fmt.Errorf("获取待评分作答失败: %w", err)
+	}
+	return attempts, total, nil
+}
+
+// UpdateAnswer 更新单题答案
+func (r *assessmentAttemptRepositoryImpl) UpdateAnswer(ctx context.Context, answer *entities.AttemptAnswer) error {
+	if err := r.db.WithContext(ctx).Save(answer).Error; err != nil {
+		return fmt.Errorf("更新答案评分失败: %w", err)
+	}
+	return nil
+}
+
+// UpdateScore 以条件更新保存作答成绩，避免并发评分时重复计入统计
+func (r *assessmentAttemptRepositoryImpl) UpdateScore(ctx context.Context, attempt *entities.AssessmentAttempt, fromStatus string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&entities.AssessmentAttempt{}).
+		Where("id = ? AND status = ?", attempt.ID, fromStatus).
+		Updates(map[string]interface{}{
+			"status":        attempt.Status,
+			"earned_points": attempt.EarnedPoints,
+			"score":         attempt.Score,
+			"passed":        attempt.Passed,
+			"graded_at":     attempt.GradedAt,
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("更新作答成绩失败: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
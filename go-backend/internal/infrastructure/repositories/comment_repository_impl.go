@@ -0,0 +1,285 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+)
+
+// commentRepositoryImpl 评论仓储实现
+type commentRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewCommentRepository 创建评论仓储实例
+func NewCommentRepository(db *gorm.DB) repositories.CommentRepository {
+	return &commentRepositoryImpl{
+		db: db,
+	}
+}
+
+// Create 在事务中创建评论，回复同步增加父评论的回复数
+func (r *commentRepositoryImpl) Create(ctx context.Context, comment *entities.Comment) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(comment).Error; err != nil {
+			return err
+		}
+		if comment.ParentID == nil {
+			return nil
+		}
+		return tx.Model(&entities.Comment{}).
+			Where("id = ?", *comment.ParentID).
+			UpdateColumn("reply_count", gorm.Expr("reply_count + 1")).Error
+	})
+	if err != nil {
+		return fmt.Errorf("创建评论失败: %w", err)
+	}
+	return nil
+}
+
+// GetByID 根据ID获取评论，包含已删除的评论
+func (r *commentRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*entities.Comment, error) {
+	var comment entities.Comment
+	if err := r.preload(r.db.WithContext(ctx).Unscoped()).
+		Where("id = ?", id).
+		First(&comment).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("评论不存在")
+		}
+		return nil, fmt.Errorf("获取评论失败: %w", err)
+	}
+	return &comment, nil
+}
+
+// ListThreads 获取对象下的讨论串，置顶优先，其余按创建时间倒序
+func (r *commentRepositoryImpl) ListThreads(ctx context.Context, targetType string, targetID uuid.UUID, opts repositories.CommentListOptions) ([]*entities.Comment, error) {
+	query := r.visible(r.db.WithContext(ctx), opts).
+		Where("target_type = ? AND target_id = ? AND parent_id IS NULL", targetType, targetID)
+	if cursor := opts.Cursor; cursor != nil {
+		query = query.Where(
+			"is_pinned < ? OR (is_pinned = ? AND (created_at < ? OR (created_at = ? AND id < ?)))",
+			cursor.Pinned, cursor.Pinned, cursor.CreatedAt, cursor.CreatedAt, cursor.ID)
+	}
+
+	var comments []*entities.Comment
+	if err := query.
+		Order("is_pinned DESC").
+		Order("created_at DESC").
+		Order("id DESC").
+		Limit(opts.Limit).
+		Find(&comments).Error; err != nil {
+		return nil, fmt.Errorf("获取讨论列表失败: %w", err)
+	}
+	return comments, nil
+}
+
+// ListReplies 获取讨论串内的全部回复，按创建时间正序
+func (r *commentRepositoryImpl) ListReplies(ctx context.Context, rootID uuid.UUID, opts repositories.CommentListOptions) ([]*entities.Comment, error) {
+	query := r.visible(r.db.WithContext(ctx), opts).Where("root_id = ?", rootID)
+	if cursor := opts.Cursor; cursor != nil {
+		query = query.Where("created_at > ? OR (created_at = ? AND id > ?)", cursor.CreatedAt, cursor.CreatedAt, cursor.ID)
+	}
+
+	var comments []*entities.Comment
+	if err := query.
+		Order("created_at ASC").
+		Order("id ASC").
+		Limit(opts.Limit).
+		Find(&comments).Error; err != nil {
+		return nil, fmt.Errorf("获取回复列表失败: %w", err)
+	}
+	return comments, nil
+}
+
+// UpdateContent 在事务中记录编辑历史并更新评论内容，重新写入提及
+func (r *commentRepositoryImpl) UpdateContent(ctx context.Context, comment *entities.Comment, edit *entities.CommentEdit) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(edit).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&entities.Comment{}).
+			Where("id = ?", comment.ID).
+			Updates(map[string]interface{}{
+				"content":    comment.Content,
+				"is_edited":  true,
+				"edited_at":  comment.EditedAt,
+				"updated_at": time.Now(),
+			}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("comment_id = ?", comment.ID).Delete(&entities.CommentMention{}).Error; err != nil {
+			return err
+		}
+		if len(comment.Mentions) == 0 {
+			return nil
+		}
+		return tx.Create(&comment.Mentions).Error
+	})
+	if err != nil {
+		return fmt.Errorf("更新评论失败: %w", err)
+	}
+	return nil
+}
+
+// ListEdits 获取评论的编辑历史，按编辑时间倒序
+func (r *commentRepositoryImpl) ListEdits(ctx context.Context, commentID uuid.UUID) ([]*entities.CommentEdit, error) {
+	var edits []*entities.CommentEdit
+	if err := r.db.WithContext(ctx).
+		Where("comment_id = ?", commentID).
+		Order("created_at DESC").
+		Find(&edits).Error; err != nil {
+		return nil, fmt.Errorf("获取编辑历史失败: %w", err)
+	}
+	return edits, nil
+}
+
+// Delete 在事务中软删除评论，回复同步减少父评论的回复数
+func (r *commentRepositoryImpl) Delete(ctx context.Context, comment *entities.Comment) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&entities.Comment{}, "id = ?", comment.ID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 || comment.ParentID == nil {
+			return nil
+		}
+		return tx.Unscoped().Model(&entities.Comment{}).
+			Where("id = ? AND reply_count > 0", *comment.ParentID).
+			UpdateColumn("reply_count", gorm.Expr("reply_count - 1")).Error
+	})
+	if err != nil {
+		return fmt.Errorf("删除评论失败: %w", err)
+	}
+	return nil
+}
+
+// UpdateModeration 更新评论的隐藏、置顶和锁定状态
+func (r *commentRepositoryImpl) UpdateModeration(ctx context.Context, comment *entities.Comment) error {
+	if err := r.db.WithContext(ctx).
+		Model(&entities.Comment{}).
+		Where("id = ?", comment.ID).
+		Updates(map[string]interface{}{
+			"is_hidden":     comment.IsHidden,
+			"hidden_by":     comment.HiddenBy,
+			"hidden_reason": comment.HiddenReason,
+			"is_pinned":     comment.IsPinned,
+			"is_locked":     comment.IsLocked,
+			"updated_at":    time.Now(),
+		}).Error; err != nil {
+		return fmt.Errorf("更新评论管理状态失败: %w", err)
+	}
+	return nil
+}
+
+// AddLike 在事务中点赞评论并增加点赞数，已点赞时返回false
+func (r *commentRepositoryImpl) AddLike(ctx context.Context, commentID uuid.UUID, userID uint) (bool, error) {
+	added := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&entities.CommentLike{CommentID: commentID, UserID: userID})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		added = true
+		return tx.Model(&entities.Comment{}).
+			Where("id = ?", commentID).
+			UpdateColumn("like_count", gorm.Expr("like_count + 1")).Error
+	})
+	if err != nil {
+		return false, fmt.Errorf("点赞评论失败: %w", err)
+	}
+	return added, nil
+}
+
+// RemoveLike 在事务中取消点赞并减少点赞数，未点赞时返回false
+func (r *commentRepositoryImpl) RemoveLike(ctx context.Context, commentID uuid.UUID, userID uint) (bool, error) {
+	removed := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("comment_id = ? AND user_id = ?", commentID, userID).Delete(&entities.CommentLike{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		removed = true
+		return tx.Unscoped().Model(&entities.Comment{}).
+			Where("id = ? AND like_count > 0", commentID).
+			UpdateColumn("like_count", gorm.Expr("like_count - 1")).Error
+	})
+	if err != nil {
+		return false, fmt.Errorf("取消点赞失败: %w", err)
+	}
+	return removed, nil
+}
+
+// LikedBy 返回指定评论中用户已点赞的评论ID
+func (r *commentRepositoryImpl) LikedBy(ctx context.Context, userID uint, commentIDs []uuid.UUID) (map[uuid.UUID]bool, error) {
+	liked := make(map[uuid.UUID]bool)
+	if len(commentIDs) == 0 {
+		return liked, nil
+	}
+
+	var ids []uuid.UUID
+	if err := r.db.WithContext(ctx).
+		Model(&entities.CommentLike{}).
+		Where("user_id = ? AND comment_id IN ?", userID, commentIDs).
+		Pluck("comment_id", &ids).Error; err != nil {
+		return nil, fmt.Errorf("获取点赞状态失败: %w", err)
+	}
+	for _, id := range ids {
+		liked[id] = true
+	}
+	return liked, nil
+}
+
+// ListMentioning 分页获取提及用户的未删除且未隐藏的评论，按创建时间倒序
+func (r *commentRepositoryImpl) ListMentioning(ctx context.Context, userID uint, offset, limit int) ([]*entities.Comment, int64, error) {
+	query := r.db.WithContext(ctx).
+		Model(&entities.Comment{}).
+		Joins("JOIN comment_mentions ON comment_mentions.comment_id = comments.id").
+		Where("comment_mentions.user_id = ? AND comments.is_hidden = ?", userID, false)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("统计提及评论失败: %w", err)
+	}
+
+	var comments []*entities.Comment
+	if err := r.preload(query).
+		Select("comments.*").
+		Order("comments.created_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&comments).Error; err != nil {
+		return nil, 0, fmt.Errorf("获取提及评论失败: %w", err)
+	}
+	return comments, total, nil
+}
+
+// visible 评论列表的可见范围：已删除的评论仅在仍有回复时保留，隐藏评论按选项过滤
+func (r *commentRepositoryImpl) visible(query *gorm.DB, opts repositories.CommentListOptions) *gorm.DB {
+	query = r.preload(query.Unscoped()).Where("deleted_at IS NULL OR reply_count > 0")
+	if !opts.IncludeHidden {
+		query = query.Where("is_hidden = ?", false)
+	}
+	return query
+}
+
+// preload 加载评论作者和提及用户
+func (r *commentRepositoryImpl) preload(query *gorm.DB) *gorm.DB {
+	return query.
+		Preload("User", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id", "username", "role")
+		}).
+		Preload("Mentions")
+}
@@ -0,0 +1,100 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+)
+
+// diagnosticSessionRepositoryImpl 自适应诊断测评仓储实现
+type diagnosticSessionRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewDiagnosticSessionRepository 创建自适应诊断测评仓储实例
+func NewDiagnosticSessionRepository(db *gorm.DB) repositories.DiagnosticSessionRepository {
+	return &diagnosticSessionRepositoryImpl{
+		db: db,
+	}
+}
+
+// Create 创建诊断测评
+func (r *diagnosticSessionRepositoryImpl) Create(ctx context.Context, session *entities.DiagnosticSession) error {
+	if err := r.db.WithContext(ctx).Create(session).Error; err != nil {
+		return fmt.Errorf("创建诊断测评失败: %w", err)
+	}
+	return nil
+}
+
+// GetByID 根据ID获取诊断测评及作答记录
+func (r *diagnosticSessionRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*entities.DiagnosticSession, error) {
+	var session entities.DiagnosticSession
+	err := r.db.WithContext(ctx).
+		Preload("Responses", func(db *gorm.DB) *gorm.DB {
+			return db.Order("sequence ASC")
+		}).
+		Where("id = ?", id).
+		First(&session).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("诊断测评不存在")
+		}
+		return nil, fmt.Errorf("获取诊断测评失败: %w", err)
+	}
+	return &session, nil
+}
+
+// GetInProgress 获取目标进行中的诊断测评
+func (r *diagnosticSessionRepositoryImpl) GetInProgress(ctx context.Context, goalID uuid.UUID) (*entities.DiagnosticSession, error) {
+	var sessions []*entities.DiagnosticSession
+	err := r.db.WithContext(ctx).
+		Preload("Responses", func(db *gorm.DB) *gorm.DB {
+			return db.Order("sequence ASC")
+		}).
+		Where("goal_id = ? AND status = ?", goalID, string(entities.DiagnosticInProgress)).
+		Order("created_at DESC").
+		Limit(1).
+		Find(&sessions).Error
+	if err != nil {
+		return nil, fmt.Errorf("查询进行中的诊断测评失败: %w", err)
+	}
+	if len(sessions) == 0 {
+		return nil, nil
+	}
+	return sessions[0], nil
+}
+
+// AddResponse 在事务中记录作答并更新诊断测评，以当前待答题目作为条件避免重复提交
+func (r *diagnosticSessionRepositoryImpl) AddResponse(ctx context.Context, session *entities.DiagnosticSession, response *entities.DiagnosticResponse, answeredQuestionID uuid.UUID) (bool, error) {
+	applied := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entities.DiagnosticSession{}).
+			Where("id = ? AND status = ? AND current_question_id = ?", session.ID, string(entities.DiagnosticInProgress), answeredQuestionID).
+			Updates(map[string]interface{}{
+				"status":              session.Status,
+				"current_question_id": session.CurrentQuestionID,
+				"estimates":           session.Estimates,
+				"completed_at":        session.CompletedAt,
+			})
+		if result.Error != nil {
+			return fmt.Errorf("更新诊断测评失败: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		if err := tx.Omit(clause.Associations).Create(response).Error; err != nil {
+			return fmt.Errorf("记录诊断作答失败: %w", err)
+		}
+		applied = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return applied, nil
+}
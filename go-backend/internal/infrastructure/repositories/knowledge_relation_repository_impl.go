@@ -0,0 +1,162 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+)
+
+// knowledgeCooccurrenceBaskets 各共现来源的"购物篮"查询，每行为一个篮子(学习路径或学习者)中的一个已发布知识点
+var knowledgeCooccurrenceBaskets = map[repositories.KnowledgeCooccurrenceSource]string{
+	repositories.KnowledgeCooccurrenceFromPaths: `
+		SELECT pkp.learning_path_id AS basket, pkp.knowledge_point_id AS point
+		FROM path_knowledge_points pkp
+		JOIN learning_paths lp ON lp.id = pkp.learning_path_id AND lp.deleted_at IS NULL
+		JOIN knowledge_points kp ON kp.id = pkp.knowledge_point_id AND kp.deleted_at IS NULL AND kp.status = @status`,
+	repositories.KnowledgeCooccurrenceFromLearners: `
+		SELECT m.user_id AS basket, m.knowledge_point_id AS point
+		FROM user_knowledge_mastery m
+		JOIN knowledge_points kp ON kp.id = m.knowledge_point_id AND kp.deleted_at IS NULL AND kp.status = @status
+		WHERE m.evidence_count > 0`,
+}
+
+// knowledgeRelationRepositoryImpl 相关知识点仓储实现
+type knowledgeRelationRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewKnowledgeRelationRepository 创建相关知识点仓储实例
+func NewKnowledgeRelationRepository(db *gorm.DB) repositories.KnowledgeRelationRepository {
+	return &knowledgeRelationRepositoryImpl{
+		db: db,
+	}
+}
+
+// CreateRelation 在事务中检查两个方向是否已存在，不存在时同时写入两个方向
+func (r *knowledgeRelationRepositoryImpl) CreateRelation(ctx context.Context, relation *entities.KnowledgeRelation) (bool, error) {
+	created := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&entities.KnowledgeRelation{}).
+			Where("(knowledge_point_id = ? AND related_id = ?) OR (knowledge_point_id = ? AND related_id = ?)",
+				relation.KnowledgePointID, relation.RelatedID, relation.RelatedID, relation.KnowledgePointID).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return nil
+		}
+
+		reverse := *relation
+		reverse.ID = uuid.New()
+		reverse.KnowledgePointID, reverse.RelatedID = relation.RelatedID, relation.KnowledgePointID
+		if err := tx.Omit("Related").Create([]*entities.KnowledgeRelation{relation, &reverse}).Error; err != nil {
+			return err
+		}
+		created = true
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("创建相关知识点链接失败: %w", err)
+	}
+	return created, nil
+}
+
+// DeleteRelation 删除两个方向的相关链接
+func (r *knowledgeRelationRepositoryImpl) DeleteRelation(ctx context.Context, pointID, relatedID uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Where("(knowledge_point_id = ? AND related_id = ?) OR (knowledge_point_id = ? AND related_id = ?)",
+			pointID, relatedID, relatedID, pointID).
+		Delete(&entities.KnowledgeRelation{})
+	if result.Error != nil {
+		return false, fmt.Errorf("删除相关知识点链接失败: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ListRelations 获取知识点的相关链接，按创建时间正序
+func (r *knowledgeRelationRepositoryImpl) ListRelations(ctx context.Context, pointID uuid.UUID) ([]*entities.KnowledgeRelation, error) {
+	var relations []*entities.KnowledgeRelation
+	if err := r.db.WithContext(ctx).
+		Preload("Related").
+		Where("knowledge_point_id = ?", pointID).
+		Order("created_at ASC").
+		Find(&relations).Error; err != nil {
+		return nil, fmt.Errorf("获取相关知识点链接失败: %w", err)
+	}
+	return relations, nil
+}
+
+// CountCooccurrences 在数据库中按篮子自连接统计共现次数
+func (r *knowledgeRelationRepositoryImpl) CountCooccurrences(ctx context.Context, source repositories.KnowledgeCooccurrenceSource, minSupport int) (*repositories.KnowledgeCooccurrenceCounts, error) {
+	baskets, ok := knowledgeCooccurrenceBaskets[source]
+	if !ok {
+		return nil, fmt.Errorf("未知的共现来源: %s", source)
+	}
+	params := map[string]interface{}{
+		"status":      string(entities.KnowledgeStatusPublished),
+		"min_support": minSupport,
+	}
+
+	var items []struct {
+		Point uuid.UUID
+		Count int
+	}
+	if err := r.db.WithContext(ctx).Raw(`
+		WITH baskets AS (`+baskets+`)
+		SELECT point, COUNT(DISTINCT basket) AS count FROM baskets GROUP BY point`, params).
+		Scan(&items).Error; err != nil {
+		return nil, fmt.Errorf("统计知识点出现次数失败: %w", err)
+	}
+
+	counts := &repositories.KnowledgeCooccurrenceCounts{Items: make(map[uuid.UUID]int, len(items))}
+	for _, item := range items {
+		counts.Items[item.Point] = item.Count
+	}
+	if err := r.db.WithContext(ctx).Raw(`
+		WITH baskets AS (`+baskets+`)
+		SELECT a.point AS knowledge_point_id, b.point AS related_id, COUNT(DISTINCT a.basket) AS count
+		FROM baskets a
+		JOIN baskets b ON b.basket = a.basket AND b.point <> a.point
+		GROUP BY a.point, b.point
+		HAVING COUNT(DISTINCT a.basket) >= @min_support`, params).
+		Scan(&counts.Pairs).Error; err != nil {
+		return nil, fmt.Errorf("统计知识点共现次数失败: %w", err)
+	}
+	return counts, nil
+}
+
+// ReplaceCooccurrences 在事务中清空并批量写入共现记录，读取方不会看到计算到一半的结果
+func (r *knowledgeRelationRepositoryImpl) ReplaceCooccurrences(ctx context.Context, cooccurrences []*entities.KnowledgeCooccurrence) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("1 = 1").Delete(&entities.KnowledgeCooccurrence{}).Error; err != nil {
+			return err
+		}
+		if len(cooccurrences) == 0 {
+			return nil
+		}
+		return tx.Omit("Related").CreateInBatches(cooccurrences, 500).Error
+	})
+	if err != nil {
+		return fmt.Errorf("保存知识点共现记录失败: %w", err)
+	}
+	return nil
+}
+
+// ListCooccurrences 获取知识点相似度最高的共现记录
+func (r *knowledgeRelationRepositoryImpl) ListCooccurrences(ctx context.Context, pointID uuid.UUID, limit int) ([]*entities.KnowledgeCooccurrence, error) {
+	var cooccurrences []*entities.KnowledgeCooccurrence
+	if err := r.db.WithContext(ctx).
+		Preload("Related").
+		Where("knowledge_point_id = ?", pointID).
+		Order("score DESC").
+		Limit(limit).
+		Find(&cooccurrences).Error; err != nil {
+		return nil, fmt.Errorf("获取知识点共现记录失败: %w", err)
+	}
+	return cooccurrences, nil
+}
@@ -0,0 +1,49 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+)
+
+// knowledgeRenderingRepositoryImpl 知识点正文渲染缓存仓储实现
+type knowledgeRenderingRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewKnowledgeRenderingRepository 创建知识点正文渲染缓存仓储实例
+func NewKnowledgeRenderingRepository(db *gorm.DB) repositories.KnowledgeRenderingRepository {
+	return &knowledgeRenderingRepositoryImpl{
+		db: db,
+	}
+}
+
+// ListByPoints 获取知识点的渲染缓存
+func (r *knowledgeRenderingRepositoryImpl) ListByPoints(ctx context.Context, pointIDs []uuid.UUID) ([]*entities.KnowledgePointRendering, error) {
+	var renderings []*entities.KnowledgePointRendering
+	if len(pointIDs) == 0 {
+		return renderings, nil
+	}
+	if err := r.db.WithContext(ctx).Where("knowledge_point_id IN ?", pointIDs).Find(&renderings).Error; err != nil {
+		return nil, fmt.Errorf("获取知识点渲染缓存失败: %w", err)
+	}
+	return renderings, nil
+}
+
+// Save 以知识点ID为键写入渲染缓存，并发渲染同一知识点时后写入的覆盖先写入的
+func (r *knowledgeRenderingRepositoryImpl) Save(ctx context.Context, rendering *entities.KnowledgePointRendering) error {
+	if err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "knowledge_point_id"}},
+			UpdateAll: true,
+		}).
+		Create(rendering).Error; err != nil {
+		return fmt.Errorf("保存知识点渲染缓存失败: %w", err)
+	}
+	return nil
+}
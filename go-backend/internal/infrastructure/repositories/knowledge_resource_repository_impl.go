@@ -0,0 +1,161 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+)
+
+// knowledgeResourceRepositoryImpl 知识点资源仓储实现
+type knowledgeResourceRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewKnowledgeResourceRepository 创建知识点资源仓储实例
+func NewKnowledgeResourceRepository(db *gorm.DB) repositories.KnowledgeResourceRepository {
+	return &knowledgeResourceRepositoryImpl{
+		db: db,
+	}
+}
+
+// Create 锁定知识点行后取当前最大排序位置，新资源排在最后
+func (r *knowledgeResourceRepositoryImpl) Create(ctx context.Context, resource *entities.KnowledgeResource) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var point entities.KnowledgePoint
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").
+			Where("id = ?", resource.KnowledgePointID).
+			First(&point).Error; err != nil {
+			return err
+		}
+
+		var position int
+		if err := tx.Model(&entities.KnowledgeResource{}).
+			Where("knowledge_point_id = ?", resource.KnowledgePointID).
+			Select("COALESCE(MAX(position), 0)").
+			Scan(&position).Error; err != nil {
+			return err
+		}
+		resource.Position = position + 1
+		return tx.Create(resource).Error
+	})
+	if err != nil {
+		return fmt.Errorf("创建知识点资源失败: %w", err)
+	}
+	return nil
+}
+
+// Update 保存资源内容，不修改排序
+func (r *knowledgeResourceRepositoryImpl) Update(ctx context.Context, resource *entities.KnowledgeResource) error {
+	if err := r.db.WithContext(ctx).
+		Model(resource).
+		Select("*").
+		Omit("id", "knowledge_point_id", "position", "created_at").
+		Updates(resource).Error; err != nil {
+		return fmt.Errorf("更新知识点资源失败: %w", err)
+	}
+	return nil
+}
+
+// Delete 删除资源
+func (r *knowledgeResourceRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := r.db.WithContext(ctx).Delete(&entities.KnowledgeResource{}, "id = ?", id).Error; err != nil {
+		return fmt.Errorf("删除知识点资源失败: %w", err)
+	}
+	return nil
+}
+
+// GetByID 获取资源，不存在时返回nil
+func (r *knowledgeResourceRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*entities.KnowledgeResource, error) {
+	var resources []*entities.KnowledgeResource
+	if err := r.db.WithContext(ctx).Where("id = ?", id).Limit(1).Find(&resources).Error; err != nil {
+		return nil, fmt.Errorf("获取知识点资源失败: %w", err)
+	}
+	if len(resources) == 0 {
+		return nil, nil
+	}
+	return resources[0], nil
+}
+
+// ListByPoint 获取知识点的全部资源，按排序位置正序
+func (r *knowledgeResourceRepositoryImpl) ListByPoint(ctx context.Context, pointID uuid.UUID) ([]*entities.KnowledgeResource, error) {
+	var resources []*entities.KnowledgeResource
+	if err := r.db.WithContext(ctx).
+		Where("knowledge_point_id = ?", pointID).
+		Order("position ASC, created_at ASC").
+		Find(&resources).Error; err != nil {
+		return nil, fmt.Errorf("获取知识点资源列表失败: %w", err)
+	}
+	return resources, nil
+}
+
+// Reorder 在事务中按ids的顺序重写排序位置
+func (r *knowledgeResourceRepositoryImpl) Reorder(ctx context.Context, pointID uuid.UUID, ids []uuid.UUID) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i, id := range ids {
+			if err := tx.Model(&entities.KnowledgeResource{}).
+				Where("id = ? AND knowledge_point_id = ?", id, pointID).
+				Update("position", i+1).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("调整知识点资源顺序失败: %w", err)
+	}
+	return nil
+}
+
+// ListLinksDue 获取从未检查或上次检查早于before的外部链接，从未检查的优先
+func (r *knowledgeResourceRepositoryImpl) ListLinksDue(ctx context.Context, before time.Time, limit int) ([]*entities.KnowledgeResource, error) {
+	var resources []*entities.KnowledgeResource
+	if err := r.db.WithContext(ctx).
+		Where("url <> '' AND (link_checked_at IS NULL OR link_checked_at < ?)", before).
+		Order("link_checked_at ASC NULLS FIRST").
+		Limit(limit).
+		Find(&resources).Error; err != nil {
+		return nil, fmt.Errorf("获取待检查链接失败: %w", err)
+	}
+	return resources, nil
+}
+
+// UpdateLinkStatus 以链接未变化为条件保存检查结果
+func (r *knowledgeResourceRepositoryImpl) UpdateLinkStatus(ctx context.Context, resource *entities.KnowledgeResource) error {
+	if err := r.db.WithContext(ctx).
+		Model(&entities.KnowledgeResource{}).
+		Where("id = ? AND url = ?", resource.ID, resource.URL).
+		UpdateColumns(map[string]interface{}{
+			"link_status":     resource.LinkStatus,
+			"link_failures":   resource.LinkFailures,
+			"link_error":      resource.LinkError,
+			"link_checked_at": resource.LinkCheckedAt,
+		}).Error; err != nil {
+		return fmt.Errorf("保存链接检查结果失败: %w", err)
+	}
+	return nil
+}
+
+// ListBroken 分页获取失效的外部链接，按检查时间倒序
+func (r *knowledgeResourceRepositoryImpl) ListBroken(ctx context.Context, offset, limit int) ([]*entities.KnowledgeResource, int64, error) {
+	query := r.db.WithContext(ctx).
+		Model(&entities.KnowledgeResource{}).
+		Where("link_status = ?", entities.KnowledgeLinkBroken)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("统计失效链接失败: %w", err)
+	}
+
+	var resources []*entities.KnowledgeResource
+	if err := query.Order("link_checked_at DESC").Offset(offset).Limit(limit).Find(&resources).Error; err != nil {
+		return nil, 0, fmt.Errorf("获取失效链接失败: %w", err)
+	}
+	return resources, total, nil
+}
@@ -0,0 +1,147 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+	"sical-go-backend/pkg/pinyin"
+)
+
+// errKnowledgeVersionConflict 知识点版本号或编辑状态与预期不一致，用于回滚事务
+var errKnowledgeVersionConflict = errors.New("知识点版本冲突")
+
+// knowledgeContentColumns 修改知识点内容时写入的列，编辑状态由审核流程单独维护
+var knowledgeContentColumns = []string{
+	"title", "title_pinyin", "title_initials", "description", "content", "category",
+	"difficulty", "resources", "prerequisites", "tags", "version", "updated_at",
+}
+
+// knowledgeRevisionRepositoryImpl 知识点修订仓储实现
+type knowledgeRevisionRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewKnowledgeRevisionRepository 创建知识点修订仓储实例
+func NewKnowledgeRevisionRepository(db *gorm.DB) repositories.KnowledgeRevisionRepository {
+	return &knowledgeRevisionRepositoryImpl{
+		db: db,
+	}
+}
+
+// CreatePoint 在事务中创建知识点并记录首个修订
+func (r *knowledgeRevisionRepositoryImpl) CreatePoint(ctx context.Context, point *entities.KnowledgePoint, revision *entities.KnowledgePointRevision) error {
+	point.TitlePinyin, point.TitleInitials = pinyin.Convert(point.Title)
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(point).Error; err != nil {
+			return err
+		}
+		return tx.Create(revision).Error
+	})
+	if err != nil {
+		return fmt.Errorf("创建知识点失败: %w", err)
+	}
+	return nil
+}
+
+// UpdatePoint 在事务中锁定知识点行，校验版本号和编辑状态后保存内容并记录修订
+func (r *knowledgeRevisionRepositoryImpl) UpdatePoint(ctx context.Context, point *entities.KnowledgePoint, expectedVersion int, revision *entities.KnowledgePointRevision) (bool, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return updateKnowledgeContent(tx, point, expectedVersion, revision)
+	})
+	if err != nil {
+		if errors.Is(err, errKnowledgeVersionConflict) {
+			return false, nil
+		}
+		if err == gorm.ErrRecordNotFound {
+			return false, fmt.Errorf("知识点不存在")
+		}
+		return false, fmt.Errorf("更新知识点失败: %w", err)
+	}
+	return true, nil
+}
+
+// List 获取知识点的修订列表，按版本号倒序，列表不读取正文
+func (r *knowledgeRevisionRepositoryImpl) List(ctx context.Context, pointID uuid.UUID, offset, limit int) ([]*entities.KnowledgePointRevision, int64, error) {
+	query := r.db.WithContext(ctx).
+		Model(&entities.KnowledgePointRevision{}).
+		Where("knowledge_point_id = ?", pointID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("统计知识点修订失败: %w", err)
+	}
+
+	var revisions []*entities.KnowledgePointRevision
+	if err := r.preload(query).
+		Omit("content").
+		Order("version DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&revisions).Error; err != nil {
+		return nil, 0, fmt.Errorf("获取知识点修订列表失败: %w", err)
+	}
+	return revisions, total, nil
+}
+
+// GetByVersion 获取知识点指定版本的修订，不存在时返回nil
+func (r *knowledgeRevisionRepositoryImpl) GetByVersion(ctx context.Context, pointID uuid.UUID, version int) (*entities.KnowledgePointRevision, error) {
+	var revisions []*entities.KnowledgePointRevision
+	if err := r.preload(r.db.WithContext(ctx)).
+		Where("knowledge_point_id = ? AND version = ?", pointID, version).
+		Limit(1).
+		Find(&revisions).Error; err != nil {
+		return nil, fmt.Errorf("获取知识点修订失败: %w", err)
+	}
+	if len(revisions) == 0 {
+		return nil, nil
+	}
+	return revisions[0], nil
+}
+
+// updateKnowledgeContent 锁定知识点行，校验版本号和编辑状态后保存内容并记录修订
+// 版本号或编辑状态与预期不一致时返回errKnowledgeVersionConflict
+func updateKnowledgeContent(tx *gorm.DB, point *entities.KnowledgePoint, expectedVersion int, revision *entities.KnowledgePointRevision) error {
+	point.TitlePinyin, point.TitleInitials = pinyin.Convert(point.Title)
+
+	var current entities.KnowledgePoint
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", point.ID).
+		First(&current).Error; err != nil {
+		return err
+	}
+	if current.Version != expectedVersion || current.Status != point.Status {
+		return errKnowledgeVersionConflict
+	}
+
+	var recorded int64
+	if err := tx.Model(&entities.KnowledgePointRevision{}).
+		Where("knowledge_point_id = ? AND version = ?", current.ID, current.Version).
+		Count(&recorded).Error; err != nil {
+		return err
+	}
+	if recorded == 0 {
+		if err := tx.Create(entities.NewKnowledgePointRevision(&current, entities.KnowledgeRevisionBaseline)).Error; err != nil {
+			return err
+		}
+	}
+
+	point.Version = current.Version + 1
+	revision.Version = point.Version
+	if err := tx.Model(point).Select(knowledgeContentColumns).Updates(point).Error; err != nil {
+		return err
+	}
+	return tx.Create(revision).Error
+}
+
+// preload 加载修订作者
+func (r *knowledgeRevisionRepositoryImpl) preload(query *gorm.DB) *gorm.DB {
+	return query.Preload("Author", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "username")
+	})
+}
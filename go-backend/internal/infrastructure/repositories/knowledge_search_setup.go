@@ -0,0 +1,179 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/pkg/logger"
+	"sical-go-backend/pkg/pinyin"
+)
+
+// knowledgeSearchConfig 知识点全文检索使用的文本检索配置
+// 优先基于zhparser或pg_jieba进行中文分词，两者都不可用时复制simple配置并配合n-gram切分
+const knowledgeSearchConfig = "sical_search"
+
+// knowledgeSearchNgramFunction n-gram模式下的预处理函数，将连续的中日韩字符切分为单字和相邻双字
+const knowledgeSearchNgramFunction = `
+CREATE OR REPLACE FUNCTION sical_search_text(input text) RETURNS text
+LANGUAGE plpgsql IMMUTABLE PARALLEL SAFE AS $$
+DECLARE
+	result text;
+	run text;
+	i int;
+BEGIN
+	IF input IS NULL THEN
+		RETURN '';
+	END IF;
+	result := regexp_replace(input, '[㐀-䶿一-鿿豈-﫿]+', ' ', 'g');
+	FOR run IN SELECT m[1] FROM regexp_matches(input, '([㐀-䶿一-鿿豈-﫿]+)', 'g') AS m LOOP
+		FOR i IN 1..char_length(run) LOOP
+			result := result || ' ' || substr(run, i, 1);
+			IF i < char_length(run) THEN
+				result := result || ' ' || substr(run, i, 2);
+			END IF;
+		END LOOP;
+	END LOOP;
+	RETURN result;
+END
+$$`
+
+// knowledgeSearchPassthroughFunction 分词器模式下的预处理函数，文本原样交给分词器
+const knowledgeSearchPassthroughFunction = `
+CREATE OR REPLACE FUNCTION sical_search_text(input text) RETURNS text
+LANGUAGE sql IMMUTABLE PARALLEL SAFE AS $$ SELECT coalesce(input, '') $$`
+
+// knowledgeSearchColumn 标题、描述、正文分别以A、B、C权重写入的生成列及其GIN索引
+const knowledgeSearchColumn = `
+ALTER TABLE knowledge_points ADD COLUMN IF NOT EXISTS search_vector tsvector
+GENERATED ALWAYS AS (
+	setweight(to_tsvector('sical_search', sical_search_text(title)), 'A') ||
+	setweight(to_tsvector('sical_search', sical_search_text(description)), 'B') ||
+	setweight(to_tsvector('sical_search', sical_search_text(content)), 'C')
+) STORED`
+
+const knowledgeSearchIndex = `
+CREATE INDEX IF NOT EXISTS idx_knowledge_points_search ON knowledge_points USING GIN (search_vector)`
+
+// SetupKnowledgeSearch 创建知识点全文检索所需的文本检索配置、预处理函数、生成列和索引
+// 检索配置已存在时保持原有分词方式；切换分词方式需先删除search_vector列和检索配置后重新执行
+func SetupKnowledgeSearch(ctx context.Context, db *gorm.DB) error {
+	db = db.WithContext(ctx)
+
+	var exists int64
+	if err := db.Raw("SELECT COUNT(*) FROM pg_ts_config WHERE cfgname = ?", knowledgeSearchConfig).
+		Scan(&exists).Error; err != nil {
+		return fmt.Errorf("检查检索配置失败: %w", err)
+	}
+	if exists == 0 {
+		mode, err := createKnowledgeSearchConfig(db)
+		if err != nil {
+			return err
+		}
+		logger.Info("创建知识点检索配置", logger.String("mode", mode))
+	}
+
+	for _, statement := range []string{knowledgeSearchColumn, knowledgeSearchIndex} {
+		if err := db.Exec(statement).Error; err != nil {
+			return fmt.Errorf("创建知识点检索索引失败: %w", err)
+		}
+	}
+	return nil
+}
+
+// createKnowledgeSearchConfig 按zhparser、pg_jieba、n-gram的顺序创建检索配置，返回使用的分词方式
+func createKnowledgeSearchConfig(db *gorm.DB) (string, error) {
+	attempts := []struct {
+		mode       string
+		statements []string
+	}{
+		{
+			mode: "zhparser",
+			statements: []string{
+				"CREATE EXTENSION IF NOT EXISTS zhparser",
+				"CREATE TEXT SEARCH CONFIGURATION sical_search (PARSER = zhparser)",
+				"ALTER TEXT SEARCH CONFIGURATION sical_search ADD MAPPING FOR n,v,a,i,e,l,j,x WITH simple",
+			},
+		},
+		{
+			mode: "pg_jieba",
+			statements: []string{
+				"CREATE EXTENSION IF NOT EXISTS pg_jieba",
+				"CREATE TEXT SEARCH CONFIGURATION sical_search (COPY = jiebacfg)",
+			},
+		},
+	}
+
+	for _, attempt := range attempts {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			for _, statement := range attempt.statements {
+				if err := tx.Exec(statement).Error; err != nil {
+					return err
+				}
+			}
+			return tx.Exec(knowledgeSearchPassthroughFunction).Error
+		})
+		if err == nil {
+			return attempt.mode, nil
+		}
+		logger.Warn("中文分词扩展不可用", logger.String("mode", attempt.mode), logger.String("error", err.Error()))
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("CREATE TEXT SEARCH CONFIGURATION sical_search (COPY = simple)").Error; err != nil {
+			return err
+		}
+		return tx.Exec(knowledgeSearchNgramFunction).Error
+	})
+	if err != nil {
+		return "", fmt.Errorf("创建检索配置失败: %w", err)
+	}
+	return "ngram", nil
+}
+
+// knowledgeSuggestStatements 检索建议使用的三元组索引和检索日志前缀索引
+var knowledgeSuggestStatements = []string{
+	"CREATE EXTENSION IF NOT EXISTS pg_trgm",
+	"CREATE INDEX IF NOT EXISTS idx_knowledge_points_title_trgm ON knowledge_points USING GIN (lower(title) gin_trgm_ops)",
+	"CREATE INDEX IF NOT EXISTS idx_knowledge_points_pinyin_trgm ON knowledge_points USING GIN (title_pinyin gin_trgm_ops)",
+	"CREATE INDEX IF NOT EXISTS idx_knowledge_points_initials ON knowledge_points (title_initials varchar_pattern_ops)",
+	"CREATE INDEX IF NOT EXISTS idx_search_logs_prefix ON search_logs (normalized varchar_pattern_ops, created_at)",
+}
+
+// knowledgePinyinBatchSize 回填标题拼音的批大小
+const knowledgePinyinBatchSize = 200
+
+// SetupKnowledgeSuggest 启用pg_trgm并创建检索建议所需的索引，回填尚未生成拼音的知识点
+func SetupKnowledgeSuggest(ctx context.Context, db *gorm.DB) error {
+	db = db.WithContext(ctx)
+	for _, statement := range knowledgeSuggestStatements {
+		if err := db.Exec(statement).Error; err != nil {
+			return fmt.Errorf("创建检索建议索引失败: %w", err)
+		}
+	}
+
+	var points []*entities.KnowledgePoint
+	filled := 0
+	result := db.Select("id", "title").
+		Where("title_pinyin = ''").
+		FindInBatches(&points, knowledgePinyinBatchSize, func(tx *gorm.DB, batch int) error {
+			for _, point := range points {
+				full, initials := pinyin.Convert(point.Title)
+				if err := db.Model(&entities.KnowledgePoint{}).
+					Where("id = ?", point.ID).
+					UpdateColumns(map[string]interface{}{"title_pinyin": full, "title_initials": initials}).Error; err != nil {
+					return err
+				}
+				filled++
+			}
+			return nil
+		})
+	if result.Error != nil {
+		return fmt.Errorf("回填知识点拼音失败: %w", result.Error)
+	}
+	if filled > 0 {
+		logger.Info("回填知识点拼音完成", logger.Int("count", filled))
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+	"sical-go-backend/pkg/pinyin"
+)
+
+// knowledgeTransferRepositoryImpl 知识点批量导入导出仓储实现
+type knowledgeTransferRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewKnowledgeTransferRepository 创建知识点批量导入导出仓储实例
+func NewKnowledgeTransferRepository(db *gorm.DB) repositories.KnowledgeTransferRepository {
+	return &knowledgeTransferRepositoryImpl{
+		db: db,
+	}
+}
+
+// FindByExternalKeys 按外部标识获取知识点
+func (r *knowledgeTransferRepositoryImpl) FindByExternalKeys(ctx context.Context, keys []string) ([]*entities.KnowledgePoint, error) {
+	var points []*entities.KnowledgePoint
+	if len(keys) == 0 {
+		return points, nil
+	}
+	if err := r.db.WithContext(ctx).Where("external_key IN ?", keys).Find(&points).Error; err != nil {
+		return nil, fmt.Errorf("按外部标识获取知识点失败: %w", err)
+	}
+	return points, nil
+}
+
+// FindByIDs 按ID获取知识点
+func (r *knowledgeTransferRepositoryImpl) FindByIDs(ctx context.Context, ids []uuid.UUID) ([]*entities.KnowledgePoint, error) {
+	var points []*entities.KnowledgePoint
+	if len(ids) == 0 {
+		return points, nil
+	}
+	if err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&points).Error; err != nil {
+		return nil, fmt.Errorf("按ID获取知识点失败: %w", err)
+	}
+	return points, nil
+}
+
+// FindByTitles 按标题获取知识点，只读取解析引用需要的列
+func (r *knowledgeTransferRepositoryImpl) FindByTitles(ctx context.Context, titles []string) ([]*entities.KnowledgePoint, error) {
+	var points []*entities.KnowledgePoint
+	if len(titles) == 0 {
+		return points, nil
+	}
+	if err := r.db.WithContext(ctx).
+		Select("id", "external_key", "title").
+		Where("title IN ?", titles).
+		Find(&points).Error; err != nil {
+		return nil, fmt.Errorf("按标题获取知识点失败: %w", err)
+	}
+	return points, nil
+}
+
+// ListForExport 获取满足筛选条件的全部知识点，按创建时间正序
+func (r *knowledgeTransferRepositoryImpl) ListForExport(ctx context.Context, filter repositories.KnowledgeExportFilter) ([]*entities.KnowledgePoint, error) {
+	query := r.db.WithContext(ctx)
+	if filter.Category != "" {
+		query = query.Where("category = ?", filter.Category)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+
+	var points []*entities.KnowledgePoint
+	if err := query.Order("created_at ASC, id ASC").Find(&points).Error; err != nil {
+		return nil, fmt.Errorf("获取导出知识点失败: %w", err)
+	}
+	return points, nil
+}
+
+// ApplyImport 在一个事务中新建或修改知识点并记录修订，任一修改冲突时整体回滚
+func (r *knowledgeTransferRepositoryImpl) ApplyImport(ctx context.Context, changes []*repositories.KnowledgeImportChange) (bool, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, change := range changes {
+			point := change.Point
+			switch {
+			case change.ExpectedVersion == 0:
+				point.TitlePinyin, point.TitleInitials = pinyin.Convert(point.Title)
+				if err := tx.Create(point).Error; err != nil {
+					return err
+				}
+				if err := tx.Create(change.Revision).Error; err != nil {
+					return err
+				}
+			case change.Revision != nil:
+				if err := updateKnowledgeContent(tx, point, change.ExpectedVersion, change.Revision); err != nil {
+					return err
+				}
+				if err := tx.Model(point).Update("external_key", point.ExternalKey).Error; err != nil {
+					return err
+				}
+			default:
+				result := tx.Model(&entities.KnowledgePoint{}).
+					Where("id = ? AND version = ?", point.ID, change.ExpectedVersion).
+					Update("external_key", point.ExternalKey)
+				if result.Error != nil {
+					return result.Error
+				}
+				if result.RowsAffected == 0 {
+					return errKnowledgeVersionConflict
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, errKnowledgeVersionConflict) || errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("导入知识点失败: %w", err)
+	}
+	return true, nil
+}
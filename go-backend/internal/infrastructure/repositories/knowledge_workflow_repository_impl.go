@@ -0,0 +1,85 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+)
+
+// errKnowledgeStatusChanged 知识点状态已被其他操作修改，用于回滚事务
+var errKnowledgeStatusChanged = errors.New("知识点状态已变化")
+
+// knowledgeWorkflowRepositoryImpl 知识点编辑流程仓储实现
+type knowledgeWorkflowRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewKnowledgeWorkflowRepository 创建知识点编辑流程仓储实例
+func NewKnowledgeWorkflowRepository(db *gorm.DB) repositories.KnowledgeWorkflowRepository {
+	return &knowledgeWorkflowRepositoryImpl{
+		db: db,
+	}
+}
+
+// Transition 以当前状态为条件更新编辑状态，更新成功后记录流程事件
+func (r *knowledgeWorkflowRepositoryImpl) Transition(ctx context.Context, point *entities.KnowledgePoint, fromStatus string, event *entities.KnowledgeReviewEvent) (bool, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entities.KnowledgePoint{}).
+			Where("id = ? AND status = ?", point.ID, fromStatus).
+			Updates(map[string]interface{}{
+				"status":       point.Status,
+				"submitted_by": point.SubmittedBy,
+				"publish_at":   point.PublishAt,
+				"published_at": point.PublishedAt,
+				"updated_at":   time.Now(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errKnowledgeStatusChanged
+		}
+		return tx.Create(event).Error
+	})
+	if err != nil {
+		if errors.Is(err, errKnowledgeStatusChanged) {
+			return false, nil
+		}
+		return false, fmt.Errorf("更新知识点状态失败: %w", err)
+	}
+	return true, nil
+}
+
+// ListEvents 获取知识点的流程记录，按时间正序
+func (r *knowledgeWorkflowRepositoryImpl) ListEvents(ctx context.Context, pointID uuid.UUID) ([]*entities.KnowledgeReviewEvent, error) {
+	var events []*entities.KnowledgeReviewEvent
+	if err := r.db.WithContext(ctx).
+		Preload("Actor", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id", "username")
+		}).
+		Where("knowledge_point_id = ?", pointID).
+		Order("created_at ASC").
+		Find(&events).Error; err != nil {
+		return nil, fmt.Errorf("获取知识点流程记录失败: %w", err)
+	}
+	return events, nil
+}
+
+// ListDue 获取定时发布时间已到的已审核知识点，按发布时间正序
+func (r *knowledgeWorkflowRepositoryImpl) ListDue(ctx context.Context, now time.Time, limit int) ([]*entities.KnowledgePoint, error) {
+	var points []*entities.KnowledgePoint
+	if err := r.db.WithContext(ctx).
+		Where("status = ? AND publish_at IS NOT NULL AND publish_at <= ?", entities.KnowledgeStatusApproved, now).
+		Order("publish_at ASC").
+		Limit(limit).
+		Find(&points).Error; err != nil {
+		return nil, fmt.Errorf("获取待发布知识点失败: %w", err)
+	}
+	return points, nil
+}
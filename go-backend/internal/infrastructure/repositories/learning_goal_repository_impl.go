@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	"github.com/google/uuid"
@@ -43,9 +44,9 @@ func (r *learningGoalRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID)
 }
 
 // GetByUserID 根据用户ID获取学习目标列表
-func (r *learningGoalRepositoryImpl) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*entities.LearningGoal, error) {
+func (r *learningGoalRepositoryImpl) GetByUserID(ctx context.Context, userID uint) ([]*entities.LearningGoal, error) {
 	var goals []*entities.LearningGoal
-	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Find(&goals).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at DESC").Find(&goals).Error; err != nil {
 		return nil, fmt.Errorf("获取用户学习目标失败: %w", err)
 	}
 	return goals, nil
@@ -68,9 +69,9 @@ func (r *learningGoalRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) e
 }
 
 // GetByStatus 根据状态获取学习目标
-func (r *learningGoalRepositoryImpl) GetByStatus(ctx context.Context, userID uuid.UUID, status string) ([]*entities.LearningGoal, error) {
+func (r *learningGoalRepositoryImpl) GetByStatus(ctx context.Context, userID uint, status string) ([]*entities.LearningGoal, error) {
 	var goals []*entities.LearningGoal
-	if err := r.db.WithContext(ctx).Where("user_id = ? AND status = ?", userID, status).Find(&goals).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("user_id = ? AND status = ?", userID, status).Order("created_at DESC").Find(&goals).Error; err != nil {
 		return nil, fmt.Errorf("根据状态获取学习目标失败: %w", err)
 	}
 	return goals, nil
@@ -119,7 +120,7 @@ func (r *goalAnalysisRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID)
 // GetByGoalID 根据目标ID获取分析记录
 func (r *goalAnalysisRepositoryImpl) GetByGoalID(ctx context.Context, goalID uuid.UUID) ([]*entities.GoalAnalysis, error) {
 	var analyses []*entities.GoalAnalysis
-	if err := r.db.WithContext(ctx).Where("goal_id = ?", goalID).Find(&analyses).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("goal_id = ?", goalID).Order("created_at DESC").Find(&analyses).Error; err != nil {
 		return nil, fmt.Errorf("获取目标分析记录失败: %w", err)
 	}
 	return analyses, nil
@@ -137,6 +138,43 @@ func (r *goalAnalysisRepositoryImpl) GetLatestByGoalID(ctx context.Context, goal
 	return &analysis, nil
 }
 
+// GetLatestByGoalIDAndType 获取目标指定类型的最新分析记录
+func (r *goalAnalysisRepositoryImpl) GetLatestByGoalIDAndType(ctx context.Context, goalID uuid.UUID, analysisType string) (*entities.GoalAnalysis, error) {
+	var analysis entities.GoalAnalysis
+	if err := r.db.WithContext(ctx).
+		Where("goal_id = ? AND analysis_type = ?", goalID, analysisType).
+		Order("created_at DESC").
+		First(&analysis).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("分析记录不存在")
+		}
+		return nil, fmt.Errorf("获取最新分析记录失败: %w", err)
+	}
+	return &analysis, nil
+}
+
+// GetLatestPerType 获取目标每种分析类型的最新记录
+func (r *goalAnalysisRepositoryImpl) GetLatestPerType(ctx context.Context, goalID uuid.UUID) ([]*entities.GoalAnalysis, error) {
+	var analyses []*entities.GoalAnalysis
+	if err := r.db.WithContext(ctx).
+		Select("DISTINCT ON (analysis_type) *").
+		Where("goal_id = ?", goalID).
+		Order("analysis_type, created_at DESC").
+		Find(&analyses).Error; err != nil {
+		return nil, fmt.Errorf("获取各类型最新分析记录失败: %w", err)
+	}
+	return analyses, nil
+}
+
+// GetByJobID 获取异步任务产生的分析记录
+func (r *goalAnalysisRepositoryImpl) GetByJobID(ctx context.Context, jobID uuid.UUID) ([]*entities.GoalAnalysis, error) {
+	var analyses []*entities.GoalAnalysis
+	if err := r.db.WithContext(ctx).Where("job_id = ?", jobID).Order("created_at").Find(&analyses).Error; err != nil {
+		return nil, fmt.Errorf("获取任务分析记录失败: %w", err)
+	}
+	return analyses, nil
+}
+
 // Update 更新分析记录
 func (r *goalAnalysisRepositoryImpl) Update(ctx context.Context, analysis *entities.GoalAnalysis) error {
 	if err := r.db.WithContext(ctx).Save(analysis).Error; err != nil {
@@ -145,10 +183,88 @@ func (r *goalAnalysisRepositoryImpl) Update(ctx context.Context, analysis *entit
 	return nil
 }
 
+// UpdateRecommendation 更新分析记录中指定位置的推荐
+// 只替换数组中的单个元素，避免并发处理同一分析的不同推荐时相互覆盖
+func (r *goalAnalysisRepositoryImpl) UpdateRecommendation(ctx context.Context, analysisID uuid.UUID, index int, recommendation *entities.Recommendation) error {
+	data, err := json.Marshal(recommendation)
+	if err != nil {
+		return fmt.Errorf("序列化推荐失败: %w", err)
+	}
+
+	path := fmt.Sprintf("{%d}", index)
+	result := r.db.WithContext(ctx).
+		Model(&entities.GoalAnalysis{}).
+		Where("id = ? AND jsonb_array_length(recommendations) > ?", analysisID, index).
+		Update("recommendations", gorm.Expr("jsonb_set(recommendations, ?::text[], ?::jsonb)", path, string(data)))
+	if result.Error != nil {
+		return fmt.Errorf("更新推荐失败: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("推荐不存在")
+	}
+	return nil
+}
+
 // Delete 删除分析记录
 func (r *goalAnalysisRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
 	if err := r.db.WithContext(ctx).Delete(&entities.GoalAnalysis{}, "id = ?", id).Error; err != nil {
 		return fmt.Errorf("删除分析记录失败: %w", err)
 	}
 	return nil
-}
\ No newline at end of file
+}
+
+// latestAnalysisIDs 最新分析记录的ID子查询
+// 指定类型时每个目标取这些类型中最新的一条，否则每个目标的每种类型各取最新一条
+func (r *goalAnalysisRepositoryImpl) latestAnalysisIDs(ctx context.Context, analysisTypes ...string) *gorm.DB {
+	query := r.db.WithContext(ctx).Model(&entities.GoalAnalysis{})
+	if len(analysisTypes) > 0 {
+		return query.
+			Select("DISTINCT ON (goal_id) id").
+			Where("analysis_type IN ?", analysisTypes).
+			Order("goal_id, created_at DESC")
+	}
+	return query.
+		Select("DISTINCT ON (goal_id, analysis_type) id").
+		Order("goal_id, analysis_type, created_at DESC")
+}
+
+// FindLatestBySkillGap 查询最新分析中标记了指定技能差距的记录
+// 技能差距来自单项技能差距分析，或早期版本的综合分析
+func (r *goalAnalysisRepositoryImpl) FindLatestBySkillGap(ctx context.Context, skill string, offset, limit int) ([]*entities.GoalAnalysis, int64, error) {
+	query := r.db.WithContext(ctx).
+		Model(&entities.GoalAnalysis{}).
+		Where("id IN (?)", r.latestAnalysisIDs(ctx, entities.AnalysisTypeSkillGap, entities.AnalysisTypeComprehensive)).
+		Where("result->'skill_gaps' @> jsonb_build_array(?::text)", skill)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("统计技能差距分析记录失败: %w", err)
+	}
+
+	var analyses []*entities.GoalAnalysis
+	if err := query.Order("created_at DESC").Offset(offset).Limit(limit).Find(&analyses).Error; err != nil {
+		return nil, 0, fmt.Errorf("查询技能差距分析记录失败: %w", err)
+	}
+	return analyses, total, nil
+}
+
+// FindByConfidenceBelow 查询置信度低于阈值的分析记录
+func (r *goalAnalysisRepositoryImpl) FindByConfidenceBelow(ctx context.Context, threshold float64, latestOnly bool, offset, limit int) ([]*entities.GoalAnalysis, int64, error) {
+	query := r.db.WithContext(ctx).
+		Model(&entities.GoalAnalysis{}).
+		Where("confidence_score < ?", threshold)
+	if latestOnly {
+		query = query.Where("id IN (?)", r.latestAnalysisIDs(ctx))
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("统计低置信度分析记录失败: %w", err)
+	}
+
+	var analyses []*entities.GoalAnalysis
+	if err := query.Order("confidence_score ASC, created_at DESC").Offset(offset).Limit(limit).Find(&analyses).Error; err != nil {
+		return nil, 0, fmt.Errorf("查询低置信度分析记录失败: %w", err)
+	}
+	return analyses, total, nil
+}
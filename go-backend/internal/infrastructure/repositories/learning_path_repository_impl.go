@@ -2,12 +2,14 @@ package repositories
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 	"sical-go-backend/internal/domain/entities"
 	"sical-go-backend/internal/domain/repositories"
+	"sical-go-backend/pkg/pinyin"
 )
 
 // learningPathRepositoryImpl 学习路径仓储实现
@@ -45,7 +47,7 @@ func (r *learningPathRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID)
 // GetByGoalID 根据目标ID获取学习路径
 func (r *learningPathRepositoryImpl) GetByGoalID(ctx context.Context, goalID uuid.UUID) ([]*entities.LearningPath, error) {
 	var paths []*entities.LearningPath
-	if err := r.db.WithContext(ctx).Preload("KnowledgePoints").Where("goal_id = ?", goalID).Order("order ASC").Find(&paths).Error; err != nil {
+	if err := r.db.WithContext(ctx).Preload("KnowledgePoints").Where("goal_id = ?", goalID).Order(`"order" ASC`).Find(&paths).Error; err != nil {
 		return nil, fmt.Errorf("获取学习路径失败: %w", err)
 	}
 	return paths, nil
@@ -75,6 +77,27 @@ func (r *learningPathRepositoryImpl) UpdateStatus(ctx context.Context, id uuid.U
 	return nil
 }
 
+// UpdateProgressFields 更新学习路径的状态及开始/完成时间
+func (r *learningPathRepositoryImpl) UpdateProgressFields(ctx context.Context, path *entities.LearningPath) error {
+	updates := map[string]interface{}{
+		"status":       path.Status,
+		"started_at":   path.StartedAt,
+		"completed_at": path.CompletedAt,
+	}
+	if err := r.db.WithContext(ctx).Model(&entities.LearningPath{}).Where("id = ?", path.ID).Updates(updates).Error; err != nil {
+		return fmt.Errorf("更新学习路径进度失败: %w", err)
+	}
+	return nil
+}
+
+// AddTimeSpent 累加学习路径的学习时间(分钟)
+func (r *learningPathRepositoryImpl) AddTimeSpent(ctx context.Context, id uuid.UUID, minutes int) error {
+	if err := r.db.WithContext(ctx).Model(&entities.LearningPath{}).Where("id = ?", id).Update("time_spent", gorm.Expr("time_spent + ?", minutes)).Error; err != nil {
+		return fmt.Errorf("更新学习时间失败: %w", err)
+	}
+	return nil
+}
+
 // knowledgePointRepositoryImpl 知识点仓储实现
 type knowledgePointRepositoryImpl struct {
 	db *gorm.DB
@@ -87,8 +110,9 @@ func NewKnowledgePointRepository(db *gorm.DB) repositories.KnowledgePointReposit
 	}
 }
 
-// Create 创建知识点
+// Create 创建知识点，同时生成标题拼音
 func (r *knowledgePointRepositoryImpl) Create(ctx context.Context, point *entities.KnowledgePoint) error {
+	point.TitlePinyin, point.TitleInitials = pinyin.Convert(point.Title)
 	if err := r.db.WithContext(ctx).Create(point).Error; err != nil {
 		return fmt.Errorf("创建知识点失败: %w", err)
 	}
@@ -107,27 +131,192 @@ func (r *knowledgePointRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID
 	return &point, nil
 }
 
-// GetByCategory 根据类别获取知识点
+// GetByCategory 根据类别获取已发布的知识点
 func (r *knowledgePointRepositoryImpl) GetByCategory(ctx context.Context, category string) ([]*entities.KnowledgePoint, error) {
 	var points []*entities.KnowledgePoint
-	if err := r.db.WithContext(ctx).Where("category = ?", category).Find(&points).Error; err != nil {
+	if err := r.db.WithContext(ctx).
+		Where("category = ? AND status = ?", category, entities.KnowledgeStatusPublished).
+		Find(&points).Error; err != nil {
 		return nil, fmt.Errorf("根据类别获取知识点失败: %w", err)
 	}
 	return points, nil
 }
 
-// Search 搜索知识点
-func (r *knowledgePointRepositoryImpl) Search(ctx context.Context, keyword string) ([]*entities.KnowledgePoint, error) {
-	var points []*entities.KnowledgePoint
-	searchPattern := "%" + keyword + "%"
-	if err := r.db.WithContext(ctx).Where("title ILIKE ? OR description ILIKE ?", searchPattern, searchPattern).Find(&points).Error; err != nil {
+// knowledgeDifficultyRank 难度的排序表达式，按由浅入深排列
+const knowledgeDifficultyRank = "CASE difficulty WHEN 'beginner' THEN 1 WHEN 'intermediate' THEN 2 WHEN 'advanced' THEN 3 ELSE 4 END"
+
+// knowledgePrerequisiteCount 前置知识点数量的表达式，非数组视为没有前置知识点
+const knowledgePrerequisiteCount = "COALESCE(CASE WHEN jsonb_typeof(prerequisites) = 'array' THEN jsonb_array_length(prerequisites) END, 0)"
+
+// List 按筛选条件获取知识点列表，按排序字段和ID进行游标分页
+func (r *knowledgePointRepositoryImpl) List(ctx context.Context, query repositories.KnowledgeListQuery) (*repositories.KnowledgeListResult, error) {
+	scope := r.db.WithContext(ctx).Model(&entities.KnowledgePoint{})
+	if query.Category != "" {
+		scope = scope.Where("category = ?", query.Category)
+	}
+	if query.Difficulty != "" {
+		scope = scope.Where("difficulty = ?", query.Difficulty)
+	}
+	if query.Status != "" {
+		scope = scope.Where("status = ?", query.Status)
+	}
+	if len(query.Tags) > 0 {
+		tags, err := json.Marshal(query.Tags)
+		if err != nil {
+			return nil, fmt.Errorf("序列化标签失败: %w", err)
+		}
+		scope = scope.Where("tags @> ?::jsonb", string(tags))
+	}
+	if query.CreatedFrom != nil {
+		scope = scope.Where("created_at >= ?", *query.CreatedFrom)
+	}
+	if query.CreatedTo != nil {
+		scope = scope.Where("created_at < ?", *query.CreatedTo)
+	}
+	if query.HasPrerequisites != nil {
+		if *query.HasPrerequisites {
+			scope = scope.Where(knowledgePrerequisiteCount + " > 0")
+		} else {
+			scope = scope.Where(knowledgePrerequisiteCount + " = 0")
+		}
+	}
+
+	result := &repositories.KnowledgeListResult{}
+	if err := scope.Session(&gorm.Session{}).Count(&result.Total).Error; err != nil {
+		return nil, fmt.Errorf("统计知识点失败: %w", err)
+	}
+
+	expression := string(query.SortField)
+	var cursorValue interface{}
+	if cursor := query.Cursor; cursor != nil {
+		switch query.SortField {
+		case repositories.KnowledgeSortTitle:
+			cursorValue = cursor.Text
+		case repositories.KnowledgeSortDifficulty:
+			cursorValue = cursor.Rank
+		default:
+			cursorValue = cursor.Time
+		}
+	}
+	if query.SortField == repositories.KnowledgeSortDifficulty {
+		expression = knowledgeDifficultyRank
+	}
+	direction, compare := "ASC", ">"
+	if query.Descending {
+		direction, compare = "DESC", "<"
+	}
+	if query.Cursor != nil {
+		scope = scope.Where(fmt.Sprintf("(%[1]s %[2]s ? OR (%[1]s = ? AND id %[2]s ?))", expression, compare),
+			cursorValue, cursorValue, query.Cursor.ID)
+	}
+	if len(query.Columns) > 0 {
+		scope = scope.Select(query.Columns)
+	}
+
+	if err := scope.
+		Order(expression + " " + direction).
+		Order("id " + direction).
+		Limit(query.Limit).
+		Find(&result.Points).Error; err != nil {
+		return nil, fmt.Errorf("获取知识点列表失败: %w", err)
+	}
+	return result, nil
+}
+
+// Search 全文检索已发布的知识点，按相关度排序并返回分面统计
+func (r *knowledgePointRepositoryImpl) Search(ctx context.Context, query repositories.KnowledgeSearchQuery) (*repositories.KnowledgeSearchResult, error) {
+	result := &repositories.KnowledgeSearchResult{}
+	if err := r.searchScope(ctx, query, true, true).Count(&result.Total).Error; err != nil {
+		return nil, fmt.Errorf("统计检索结果失败: %w", err)
+	}
+
+	var rows []struct {
+		entities.KnowledgePoint
+		Rank float64
+	}
+	if err := r.searchScope(ctx, query, true, true).
+		Select("knowledge_points.*, ts_rank_cd(search_vector, plainto_tsquery('sical_search', sical_search_text(?)), 32) AS rank", query.Keyword).
+		Order("rank DESC").
+		Order("updated_at DESC").
+		Order("id ASC").
+		Offset(query.Offset).
+		Limit(query.Limit).
+		Scan(&rows).Error; err != nil {
 		return nil, fmt.Errorf("搜索知识点失败: %w", err)
 	}
-	return points, nil
+	for i := range rows {
+		result.Hits = append(result.Hits, &repositories.KnowledgeSearchHit{Point: &rows[i].KnowledgePoint, Rank: rows[i].Rank})
+	}
+
+	var err error
+	if result.Categories, err = r.searchFacet(r.searchScope(ctx, query, false, true), "category"); err != nil {
+		return nil, err
+	}
+	if result.Difficulties, err = r.searchFacet(r.searchScope(ctx, query, true, false), "difficulty"); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Suggest 按标题前缀、拼音和三元组相似度获取已发布知识点的检索建议，前缀匹配优先
+func (r *knowledgePointRepositoryImpl) Suggest(ctx context.Context, query repositories.KnowledgeSuggestQuery) ([]*repositories.KnowledgeSuggestion, error) {
+	textPrefix := likeEscaper.Replace(query.Text) + "%"
+	textContains := "%" + likeEscaper.Replace(query.Text) + "%"
+	pinyinPrefix := likeEscaper.Replace(query.Pinyin) + "%"
+
+	var suggestions []*repositories.KnowledgeSuggestion
+	if err := r.db.WithContext(ctx).
+		Model(&entities.KnowledgePoint{}).
+		Select(`id, title, category, difficulty,
+			GREATEST(similarity(lower(title), ?), word_similarity(?, lower(title)), similarity(title_pinyin, ?)) AS similarity,
+			(lower(title) LIKE ? OR title_pinyin LIKE ? OR title_initials LIKE ?) AS prefix_match`,
+			query.Text, query.Text, query.Pinyin, textPrefix, pinyinPrefix, pinyinPrefix).
+		Where(`lower(title) LIKE ? OR title_pinyin LIKE ? OR title_initials LIKE ?
+			OR lower(title) % ? OR ? <% lower(title) OR title_pinyin % ?`,
+			textContains, pinyinPrefix, pinyinPrefix, query.Text, query.Text, query.Pinyin).
+		Where("status = ?", entities.KnowledgeStatusPublished).
+		Order("prefix_match DESC").
+		Order("similarity DESC").
+		Order("title ASC").
+		Limit(query.Limit).
+		Scan(&suggestions).Error; err != nil {
+		return nil, fmt.Errorf("获取检索建议失败: %w", err)
+	}
+	return suggestions, nil
+}
+
+// searchScope 构建匹配检索词的已发布知识点查询，按需附加类别和难度条件
+func (r *knowledgePointRepositoryImpl) searchScope(ctx context.Context, query repositories.KnowledgeSearchQuery, byCategory, byDifficulty bool) *gorm.DB {
+	scope := r.db.WithContext(ctx).
+		Model(&entities.KnowledgePoint{}).
+		Where("status = ?", entities.KnowledgeStatusPublished).
+		Where("search_vector @@ plainto_tsquery('sical_search', sical_search_text(?))", query.Keyword)
+	if byCategory && query.Category != "" {
+		scope = scope.Where("category = ?", query.Category)
+	}
+	if byDifficulty && query.Difficulty != "" {
+		scope = scope.Where("difficulty = ?", query.Difficulty)
+	}
+	return scope
+}
+
+// searchFacet 按列统计检索结果的分面数量
+func (r *knowledgePointRepositoryImpl) searchFacet(scope *gorm.DB, column string) ([]repositories.KnowledgeSearchFacet, error) {
+	var facets []repositories.KnowledgeSearchFacet
+	if err := scope.
+		Select(column + " AS value, COUNT(*) AS count").
+		Group(column).
+		Order("count DESC").
+		Order(column).
+		Scan(&facets).Error; err != nil {
+		return nil, fmt.Errorf("统计检索分面失败: %w", err)
+	}
+	return facets, nil
 }
 
-// Update 更新知识点
+// Update 更新知识点，同时更新标题拼音
 func (r *knowledgePointRepositoryImpl) Update(ctx context.Context, point *entities.KnowledgePoint) error {
+	point.TitlePinyin, point.TitleInitials = pinyin.Convert(point.Title)
 	if err := r.db.WithContext(ctx).Save(point).Error; err != nil {
 		return fmt.Errorf("更新知识点失败: %w", err)
 	}
@@ -142,10 +331,12 @@ func (r *knowledgePointRepositoryImpl) Delete(ctx context.Context, id uuid.UUID)
 	return nil
 }
 
-// GetByDifficulty 根据难度获取知识点
+// GetByDifficulty 根据难度获取已发布的知识点
 func (r *knowledgePointRepositoryImpl) GetByDifficulty(ctx context.Context, difficulty string) ([]*entities.KnowledgePoint, error) {
 	var points []*entities.KnowledgePoint
-	if err := r.db.WithContext(ctx).Where("difficulty = ?", difficulty).Find(&points).Error; err != nil {
+	if err := r.db.WithContext(ctx).
+		Where("difficulty = ? AND status = ?", difficulty, entities.KnowledgeStatusPublished).
+		Find(&points).Error; err != nil {
 		return nil, fmt.Errorf("根据难度获取知识点失败: %w", err)
 	}
 	return points, nil
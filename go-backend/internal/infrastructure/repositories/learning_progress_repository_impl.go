@@ -0,0 +1,124 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+)
+
+// stepTimeLogRepositoryImpl 步骤学习时间记录仓储实现
+type stepTimeLogRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewStepTimeLogRepository 创建步骤学习时间记录仓储实例
+func NewStepTimeLogRepository(db *gorm.DB) repositories.StepTimeLogRepository {
+	return &stepTimeLogRepositoryImpl{
+		db: db,
+	}
+}
+
+// Create 创建学习时间记录
+func (r *stepTimeLogRepositoryImpl) Create(ctx context.Context, log *entities.StepTimeLog) error {
+	if err := r.db.WithContext(ctx).Create(log).Error; err != nil {
+		return fmt.Errorf("创建学习时间记录失败: %w", err)
+	}
+	return nil
+}
+
+// GetByPathID 根据路径ID获取学习时间记录
+func (r *stepTimeLogRepositoryImpl) GetByPathID(ctx context.Context, pathID uuid.UUID) ([]*entities.StepTimeLog, error) {
+	var logs []*entities.StepTimeLog
+	if err := r.db.WithContext(ctx).Where("path_id = ?", pathID).Order("logged_at DESC").Find(&logs).Error; err != nil {
+		return nil, fmt.Errorf("获取学习时间记录失败: %w", err)
+	}
+	return logs, nil
+}
+
+// GetByGoalID 根据目标ID获取学习时间记录
+func (r *stepTimeLogRepositoryImpl) GetByGoalID(ctx context.Context, goalID uuid.UUID) ([]*entities.StepTimeLog, error) {
+	var logs []*entities.StepTimeLog
+	if err := r.db.WithContext(ctx).Where("goal_id = ?", goalID).Order("logged_at DESC").Find(&logs).Error; err != nil {
+		return nil, fmt.Errorf("获取学习时间记录失败: %w", err)
+	}
+	return logs, nil
+}
+
+// goalProgressHistoryRepositoryImpl 目标进度历史仓储实现
+type goalProgressHistoryRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewGoalProgressHistoryRepository 创建目标进度历史仓储实例
+func NewGoalProgressHistoryRepository(db *gorm.DB) repositories.GoalProgressHistoryRepository {
+	return &goalProgressHistoryRepositoryImpl{
+		db: db,
+	}
+}
+
+// Create 创建进度快照
+func (r *goalProgressHistoryRepositoryImpl) Create(ctx context.Context, history *entities.GoalProgressHistory) error {
+	if err := r.db.WithContext(ctx).Create(history).Error; err != nil {
+		return fmt.Errorf("创建进度快照失败: %w", err)
+	}
+	return nil
+}
+
+// GetByGoalID 获取目标在时间范围内的进度快照（按时间升序）
+func (r *goalProgressHistoryRepositoryImpl) GetByGoalID(ctx context.Context, goalID uuid.UUID, from, to *time.Time) ([]*entities.GoalProgressHistory, error) {
+	var histories []*entities.GoalProgressHistory
+	query := r.db.WithContext(ctx).Where("goal_id = ?", goalID)
+	if from != nil {
+		query = query.Where("recorded_at >= ?", *from)
+	}
+	if to != nil {
+		query = query.Where("recorded_at <= ?", *to)
+	}
+	if err := query.Order("recorded_at ASC").Find(&histories).Error; err != nil {
+		return nil, fmt.Errorf("获取进度历史失败: %w", err)
+	}
+	return histories, nil
+}
+
+// statusTransitionRepositoryImpl 状态转换历史仓储实现
+type statusTransitionRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewStatusTransitionRepository 创建状态转换历史仓储实例
+func NewStatusTransitionRepository(db *gorm.DB) repositories.StatusTransitionRepository {
+	return &statusTransitionRepositoryImpl{
+		db: db,
+	}
+}
+
+// Create 创建状态转换记录
+func (r *statusTransitionRepositoryImpl) Create(ctx context.Context, transition *entities.StatusTransition) error {
+	if err := r.db.WithContext(ctx).Create(transition).Error; err != nil {
+		return fmt.Errorf("创建状态转换记录失败: %w", err)
+	}
+	return nil
+}
+
+// GetByEntity 获取实体的状态转换历史（按时间升序）
+func (r *statusTransitionRepositoryImpl) GetByEntity(ctx context.Context, entityType string, entityID uuid.UUID) ([]*entities.StatusTransition, error) {
+	var transitions []*entities.StatusTransition
+	if err := r.db.WithContext(ctx).Where("entity_type = ? AND entity_id = ?", entityType, entityID).Order("created_at ASC").Find(&transitions).Error; err != nil {
+		return nil, fmt.Errorf("获取状态转换历史失败: %w", err)
+	}
+	return transitions, nil
+}
+
+// GetByGoalID 获取目标及其全部步骤的状态转换历史（按时间升序）
+func (r *statusTransitionRepositoryImpl) GetByGoalID(ctx context.Context, goalID uuid.UUID) ([]*entities.StatusTransition, error) {
+	var transitions []*entities.StatusTransition
+	if err := r.db.WithContext(ctx).Where("goal_id = ?", goalID).Order("created_at ASC").Find(&transitions).Error; err != nil {
+		return nil, fmt.Errorf("获取状态转换历史失败: %w", err)
+	}
+	return transitions, nil
+}
@@ -0,0 +1,196 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+)
+
+// moderationRuleRepositoryImpl 内容过滤规则仓储实现
+type moderationRuleRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewModerationRuleRepository 创建内容过滤规则仓储实例
+func NewModerationRuleRepository(db *gorm.DB) repositories.ModerationRuleRepository {
+	return &moderationRuleRepositoryImpl{
+		db: db,
+	}
+}
+
+// Create 创建过滤规则
+func (r *moderationRuleRepositoryImpl) Create(ctx context.Context, rule *entities.ModerationRule) error {
+	if err := r.db.WithContext(ctx).Create(rule).Error; err != nil {
+		return fmt.Errorf("创建过滤规则失败: %w", err)
+	}
+	return nil
+}
+
+// GetByID 根据ID获取过滤规则
+func (r *moderationRuleRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*entities.ModerationRule, error) {
+	var rule entities.ModerationRule
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&rule).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("过滤规则不存在")
+		}
+		return nil, fmt.Errorf("获取过滤规则失败: %w", err)
+	}
+	return &rule, nil
+}
+
+// Update 更新过滤规则
+func (r *moderationRuleRepositoryImpl) Update(ctx context.Context, rule *entities.ModerationRule) error {
+	if err := r.db.WithContext(ctx).Save(rule).Error; err != nil {
+		return fmt.Errorf("更新过滤规则失败: %w", err)
+	}
+	return nil
+}
+
+// Delete 删除过滤规则
+func (r *moderationRuleRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := r.db.WithContext(ctx).Delete(&entities.ModerationRule{}, "id = ?", id).Error; err != nil {
+		return fmt.Errorf("删除过滤规则失败: %w", err)
+	}
+	return nil
+}
+
+// List 获取过滤规则，按创建时间排序
+func (r *moderationRuleRepositoryImpl) List(ctx context.Context, activeOnly bool) ([]*entities.ModerationRule, error) {
+	query := r.db.WithContext(ctx)
+	if activeOnly {
+		query = query.Where("is_active = ?", true)
+	}
+
+	var rules []*entities.ModerationRule
+	if err := query.Order("created_at ASC").Find(&rules).Error; err != nil {
+		return nil, fmt.Errorf("获取过滤规则失败: %w", err)
+	}
+	return rules, nil
+}
+
+// moderationCaseRepositoryImpl 审核案件仓储实现
+type moderationCaseRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewModerationCaseRepository 创建审核案件仓储实例
+func NewModerationCaseRepository(db *gorm.DB) repositories.ModerationCaseRepository {
+	return &moderationCaseRepositoryImpl{
+		db: db,
+	}
+}
+
+// Create 创建审核案件
+func (r *moderationCaseRepositoryImpl) Create(ctx context.Context, moderationCase *entities.ModerationCase) error {
+	if err := r.db.WithContext(ctx).Create(moderationCase).Error; err != nil {
+		return fmt.Errorf("创建审核案件失败: %w", err)
+	}
+	return nil
+}
+
+// GetByID 根据ID获取审核案件及举报记录
+func (r *moderationCaseRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*entities.ModerationCase, error) {
+	var moderationCase entities.ModerationCase
+	if err := r.db.WithContext(ctx).
+		Preload("Reports", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC")
+		}).
+		Where("id = ?", id).
+		First(&moderationCase).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("审核案件不存在")
+		}
+		return nil, fmt.Errorf("获取审核案件失败: %w", err)
+	}
+	return &moderationCase, nil
+}
+
+// GetPending 获取内容的待处理案件，不存在时返回nil
+func (r *moderationCaseRepositoryImpl) GetPending(ctx context.Context, contentType, contentID string) (*entities.ModerationCase, error) {
+	var cases []*entities.ModerationCase
+	if err := r.db.WithContext(ctx).
+		Where("content_type = ? AND content_id = ? AND status = ?", contentType, contentID, string(entities.ModerationPending)).
+		Limit(1).
+		Find(&cases).Error; err != nil {
+		return nil, fmt.Errorf("获取审核案件失败: %w", err)
+	}
+	if len(cases) == 0 {
+		return nil, nil
+	}
+	return cases[0], nil
+}
+
+// AddReport 在事务中记录举报并累计举报次数，重复举报时返回false
+func (r *moderationCaseRepositoryImpl) AddReport(ctx context.Context, report *entities.ContentReport) (bool, error) {
+	added := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(report)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		added = true
+		return tx.Model(&entities.ModerationCase{}).
+			Where("id = ?", report.CaseID).
+			UpdateColumn("report_count", gorm.Expr("report_count + 1")).Error
+	})
+	if err != nil {
+		return false, fmt.Errorf("记录举报失败: %w", err)
+	}
+	return added, nil
+}
+
+// List 按条件分页获取审核案件，举报多的优先，其次按创建时间
+func (r *moderationCaseRepositoryImpl) List(ctx context.Context, filter repositories.ModerationCaseFilter, offset, limit int) ([]*entities.ModerationCase, int64, error) {
+	query := r.db.WithContext(ctx).Model(&entities.ModerationCase{})
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.ContentType != "" {
+		query = query.Where("content_type = ?", filter.ContentType)
+	}
+	if filter.Source != "" {
+		query = query.Where("source = ?", filter.Source)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("统计审核案件失败: %w", err)
+	}
+
+	var cases []*entities.ModerationCase
+	if err := query.
+		Order("report_count DESC").
+		Order("created_at ASC").
+		Offset(offset).
+		Limit(limit).
+		Find(&cases).Error; err != nil {
+		return nil, 0, fmt.Errorf("获取审核案件失败: %w", err)
+	}
+	return cases, total, nil
+}
+
+// Resolve 以条件更新处理审核案件，避免重复处理
+func (r *moderationCaseRepositoryImpl) Resolve(ctx context.Context, moderationCase *entities.ModerationCase) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&entities.ModerationCase{}).
+		Where("id = ? AND status = ?", moderationCase.ID, string(entities.ModerationPending)).
+		Updates(map[string]interface{}{
+			"status":          moderationCase.Status,
+			"resolution":      moderationCase.Resolution,
+			"resolution_note": moderationCase.ResolutionNote,
+			"resolved_by":     moderationCase.ResolvedBy,
+			"resolved_at":     moderationCase.ResolvedAt,
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("处理审核案件失败: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
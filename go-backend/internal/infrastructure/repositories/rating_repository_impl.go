@@ -0,0 +1,338 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+)
+
+// ratingStarColumns 各星级在评分汇总中的计数列，下标为星级
+var ratingStarColumns = [...]string{"", "one_star", "two_star", "three_star", "four_star", "five_star"}
+
+// ratingRepositoryImpl 评分仓储实现
+type ratingRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewRatingRepository 创建评分仓储实例
+func NewRatingRepository(db *gorm.DB) repositories.RatingRepository {
+	return &ratingRepositoryImpl{
+		db: db,
+	}
+}
+
+// Create 在事务中创建评分并计入汇总
+func (r *ratingRepositoryImpl) Create(ctx context.Context, rating *entities.Rating) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(rating).Error; err != nil {
+			return err
+		}
+		return r.adjustSummary(tx, rating.TargetType, rating.TargetID, 1, 0, rating.Score)
+	})
+	if err != nil {
+		return fmt.Errorf("创建评分失败: %w", err)
+	}
+	return nil
+}
+
+// Update 在事务中锁定评分行，更新星级和评价并按差值调整汇总
+func (r *ratingRepositoryImpl) Update(ctx context.Context, rating *entities.Rating) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var current entities.Rating
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", rating.ID).
+			First(&current).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&entities.Rating{}).
+			Where("id = ?", rating.ID).
+			Updates(map[string]interface{}{
+				"score":      rating.Score,
+				"review":     rating.Review,
+				"is_edited":  true,
+				"updated_at": time.Now(),
+			}).Error; err != nil {
+			return err
+		}
+		if current.Score == rating.Score {
+			return nil
+		}
+		return r.adjustSummary(tx, current.TargetType, current.TargetID, 0, current.Score, rating.Score)
+	})
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return fmt.Errorf("评分不存在")
+		}
+		return fmt.Errorf("更新评分失败: %w", err)
+	}
+	return nil
+}
+
+// Delete 在事务中删除评分及其投票，并从汇总中扣除
+func (r *ratingRepositoryImpl) Delete(ctx context.Context, rating *entities.Rating) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var current entities.Rating
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", rating.ID).
+			First(&current).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("rating_id = ?", rating.ID).Delete(&entities.RatingHelpfulVote{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&entities.Rating{}, "id = ?", rating.ID).Error; err != nil {
+			return err
+		}
+		return r.adjustSummary(tx, current.TargetType, current.TargetID, -1, current.Score, 0)
+	})
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return fmt.Errorf("评分不存在")
+		}
+		return fmt.Errorf("删除评分失败: %w", err)
+	}
+	return nil
+}
+
+// GetByID 根据ID获取评分
+func (r *ratingRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*entities.Rating, error) {
+	var rating entities.Rating
+	if err := r.preload(r.db.WithContext(ctx)).
+		Where("id = ?", id).
+		First(&rating).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("评分不存在")
+		}
+		return nil, fmt.Errorf("获取评分失败: %w", err)
+	}
+	return &rating, nil
+}
+
+// GetByUser 获取用户对对象的评分，不存在时返回nil
+func (r *ratingRepositoryImpl) GetByUser(ctx context.Context, targetType string, targetID uuid.UUID, userID uint) (*entities.Rating, error) {
+	var ratings []*entities.Rating
+	if err := r.db.WithContext(ctx).
+		Where("target_type = ? AND target_id = ? AND user_id = ?", targetType, targetID, userID).
+		Limit(1).
+		Find(&ratings).Error; err != nil {
+		return nil, fmt.Errorf("获取评分失败: %w", err)
+	}
+	if len(ratings) == 0 {
+		return nil, nil
+	}
+	return ratings[0], nil
+}
+
+// ListReviews 分页获取对象下附带文字的评价
+func (r *ratingRepositoryImpl) ListReviews(ctx context.Context, targetType string, targetID uuid.UUID, order repositories.ReviewOrder, offset, limit int) ([]*entities.Rating, int64, error) {
+	query := r.db.WithContext(ctx).
+		Model(&entities.Rating{}).
+		Where("target_type = ? AND target_id = ? AND review <> ''", targetType, targetID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("统计评价失败: %w", err)
+	}
+
+	if order == repositories.ReviewOrderHelpful {
+		query = query.Order("helpful_count DESC")
+	}
+
+	var ratings []*entities.Rating
+	if err := r.preload(query).
+		Order("updated_at DESC").
+		Order("id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&ratings).Error; err != nil {
+		return nil, 0, fmt.Errorf("获取评价列表失败: %w", err)
+	}
+	return ratings, total, nil
+}
+
+// AddHelpfulVote 在事务中记录投票并增加有帮助数，已投票时返回false
+func (r *ratingRepositoryImpl) AddHelpfulVote(ctx context.Context, ratingID uuid.UUID, userID uint) (bool, error) {
+	added := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&entities.RatingHelpfulVote{RatingID: ratingID, UserID: userID})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		added = true
+		return tx.Model(&entities.Rating{}).
+			Where("id = ?", ratingID).
+			UpdateColumn("helpful_count", gorm.Expr("helpful_count + 1")).Error
+	})
+	if err != nil {
+		return false, fmt.Errorf("标记评价有帮助失败: %w", err)
+	}
+	return added, nil
+}
+
+// RemoveHelpfulVote 在事务中取消投票并减少有帮助数，未投票时返回false
+func (r *ratingRepositoryImpl) RemoveHelpfulVote(ctx context.Context, ratingID uuid.UUID, userID uint) (bool, error) {
+	removed := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("rating_id = ? AND user_id = ?", ratingID, userID).Delete(&entities.RatingHelpfulVote{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		removed = true
+		return tx.Model(&entities.Rating{}).
+			Where("id = ? AND helpful_count > 0", ratingID).
+			UpdateColumn("helpful_count", gorm.Expr("helpful_count - 1")).Error
+	})
+	if err != nil {
+		return false, fmt.Errorf("取消有帮助投票失败: %w", err)
+	}
+	return removed, nil
+}
+
+// VotedBy 返回指定评价中用户已投票的评价ID
+func (r *ratingRepositoryImpl) VotedBy(ctx context.Context, userID uint, ratingIDs []uuid.UUID) (map[uuid.UUID]bool, error) {
+	voted := make(map[uuid.UUID]bool)
+	if len(ratingIDs) == 0 {
+		return voted, nil
+	}
+
+	var ids []uuid.UUID
+	if err := r.db.WithContext(ctx).
+		Model(&entities.RatingHelpfulVote{}).
+		Where("user_id = ? AND rating_id IN ?", userID, ratingIDs).
+		Pluck("rating_id", &ids).Error; err != nil {
+		return nil, fmt.Errorf("获取投票状态失败: %w", err)
+	}
+	for _, id := range ids {
+		voted[id] = true
+	}
+	return voted, nil
+}
+
+// GetSummary 获取对象的评分汇总，尚无评分时返回空汇总
+func (r *ratingRepositoryImpl) GetSummary(ctx context.Context, targetType string, targetID uuid.UUID) (*entities.RatingSummary, error) {
+	var summaries []*entities.RatingSummary
+	if err := r.db.WithContext(ctx).
+		Where("target_type = ? AND target_id = ?", targetType, targetID).
+		Limit(1).
+		Find(&summaries).Error; err != nil {
+		return nil, fmt.Errorf("获取评分汇总失败: %w", err)
+	}
+	if len(summaries) == 0 {
+		return &entities.RatingSummary{TargetType: targetType, TargetID: targetID}, nil
+	}
+	return summaries[0], nil
+}
+
+// ListSummaries 批量获取对象的评分汇总，尚无评分的对象不在结果中
+func (r *ratingRepositoryImpl) ListSummaries(ctx context.Context, targetType string, targetIDs []uuid.UUID) (map[uuid.UUID]*entities.RatingSummary, error) {
+	result := make(map[uuid.UUID]*entities.RatingSummary)
+	if len(targetIDs) == 0 {
+		return result, nil
+	}
+
+	var summaries []*entities.RatingSummary
+	if err := r.db.WithContext(ctx).
+		Where("target_type = ? AND target_id IN ? AND rating_count > 0", targetType, targetIDs).
+		Find(&summaries).Error; err != nil {
+		return nil, fmt.Errorf("获取评分汇总失败: %w", err)
+	}
+	for _, summary := range summaries {
+		result[summary.TargetID] = summary
+	}
+	return result, nil
+}
+
+// GlobalMean 获取某类对象全部评分的平均星级及评分总数
+func (r *ratingRepositoryImpl) GlobalMean(ctx context.Context, targetType string) (float64, int64, error) {
+	var totals struct {
+		ScoreSum    int64
+		RatingCount int64
+	}
+	if err := r.db.WithContext(ctx).
+		Model(&entities.RatingSummary{}).
+		Select("COALESCE(SUM(score_sum), 0) AS score_sum, COALESCE(SUM(rating_count), 0) AS rating_count").
+		Where("target_type = ?", targetType).
+		Scan(&totals).Error; err != nil {
+		return 0, 0, fmt.Errorf("统计评分均值失败: %w", err)
+	}
+	if totals.RatingCount == 0 {
+		return 0, 0, nil
+	}
+	return float64(totals.ScoreSum) / float64(totals.RatingCount), totals.RatingCount, nil
+}
+
+// ListTopRated 按贝叶斯加权评分倒序分页获取评分汇总
+func (r *ratingRepositoryImpl) ListTopRated(ctx context.Context, targetType string, priorMean, priorWeight float64, offset, limit int) ([]*entities.RatingSummary, int64, error) {
+	query := r.db.WithContext(ctx).
+		Model(&entities.RatingSummary{}).
+		Where("target_type = ? AND rating_count > 0", targetType)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("统计评分汇总失败: %w", err)
+	}
+
+	var summaries []*entities.RatingSummary
+	if err := query.
+		Order(clause.OrderBy{Expression: clause.Expr{
+			SQL:  "(? * ? + score_sum) / (? + rating_count) DESC",
+			Vars: []interface{}{priorMean, priorWeight, priorWeight},
+		}}).
+		Order("rating_count DESC").
+		Order("target_id ASC").
+		Offset(offset).
+		Limit(limit).
+		Find(&summaries).Error; err != nil {
+		return nil, 0, fmt.Errorf("获取评分排行失败: %w", err)
+	}
+	return summaries, total, nil
+}
+
+// adjustSummary 增量调整评分汇总：countDelta为评分数变化，removed和added为移出和计入的星级，0表示无
+func (r *ratingRepositoryImpl) adjustSummary(tx *gorm.DB, targetType string, targetID uuid.UUID, countDelta, removed, added int) error {
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&entities.RatingSummary{TargetType: targetType, TargetID: targetID}).Error; err != nil {
+		return err
+	}
+
+	sumDelta := added - removed
+	updates := map[string]interface{}{
+		"rating_count": gorm.Expr("rating_count + ?", countDelta),
+		"score_sum":    gorm.Expr("score_sum + ?", sumDelta),
+		"average": gorm.Expr(
+			"CASE WHEN rating_count + ? > 0 THEN 1.0 * (score_sum + ?) / (rating_count + ?) ELSE 0 END",
+			countDelta, sumDelta, countDelta),
+		"updated_at": time.Now(),
+	}
+	if removed > 0 {
+		column := ratingStarColumns[removed]
+		updates[column] = gorm.Expr(column + " - 1")
+	}
+	if added > 0 {
+		column := ratingStarColumns[added]
+		updates[column] = gorm.Expr(column + " + 1")
+	}
+	return tx.Model(&entities.RatingSummary{}).
+		Where("target_type = ? AND target_id = ?", targetType, targetID).
+		Updates(updates).Error
+}
+
+// preload 加载评价作者
+func (r *ratingRepositoryImpl) preload(query *gorm.DB) *gorm.DB {
+	return query.Preload("User", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "username")
+	})
+}
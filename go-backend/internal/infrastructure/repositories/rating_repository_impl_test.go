@@ -0,0 +1,144 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// dryRunConn 空连接，DryRun模式下只生成SQL不访问数据库
+type dryRunConn struct{}
+
+func (dryRunConn) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return nil, errors.New("dry run")
+}
+
+func (dryRunConn) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return nil, errors.New("dry run")
+}
+
+func (dryRunConn) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return nil, errors.New("dry run")
+}
+
+func (dryRunConn) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return nil
+}
+
+// newDryRunDB 创建只记录更新语句的PostgreSQL方言数据库
+func newDryRunDB(t *testing.T) (*gorm.DB, *[]string) {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: dryRunConn{}}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatalf("gorm.Open() error = %v", err)
+	}
+	var updates []string
+	err = db.Callback().Update().After("gorm:update").Register("test:capture", func(tx *gorm.DB) {
+		updates = append(updates, tx.Dialector.Explain(tx.Statement.SQL.String(), tx.Statement.Vars...))
+	})
+	if err != nil {
+		t.Fatalf("register callback error = %v", err)
+	}
+	return db, &updates
+}
+
+var (
+	summaryCounterPattern = regexp.MustCompile(`"(\w+)"=(\w+) ([+-]) (-?\d+)`)
+	summaryAveragePattern = regexp.MustCompile(`"average"=CASE WHEN rating_count \+ (-?\d+) > 0 THEN 1\.0 \* \(score_sum \+ (-?\d+)\) / \(rating_count \+ (-?\d+)\) ELSE 0 END`)
+)
+
+// testSummary 内存中的评分汇总行
+type testSummary struct {
+	counters map[string]int
+	average  float64
+}
+
+// apply 按UPDATE语句的SET子句更新汇总，与数据库相同，所有表达式都基于更新前的值计算
+func (s *testSummary) apply(t *testing.T, statement string) {
+	t.Helper()
+	next := make(map[string]int, len(s.counters))
+	for column, value := range s.counters {
+		next[column] = value
+	}
+	for _, m := range summaryCounterPattern.FindAllStringSubmatch(statement, -1) {
+		if m[1] != m[2] {
+			t.Fatalf("column %s is set from %s: %s", m[1], m[2], statement)
+		}
+		delta, _ := strconv.Atoi(m[4])
+		if m[3] == "-" {
+			delta = -delta
+		}
+		next[m[1]] = s.counters[m[1]] + delta
+	}
+
+	m := summaryAveragePattern.FindStringSubmatch(statement)
+	if m == nil {
+		t.Fatalf("average not updated: %s", statement)
+	}
+	countDelta, _ := strconv.Atoi(m[1])
+	sumDelta, _ := strconv.Atoi(m[2])
+	if m[3] != m[1] {
+		t.Fatalf("average uses inconsistent count deltas: %s", statement)
+	}
+	if count := s.counters["rating_count"] + countDelta; count > 0 {
+		s.average = float64(s.counters["score_sum"]+sumDelta) / float64(count)
+	} else {
+		s.average = 0
+	}
+	s.counters = next
+}
+
+func TestRatingAdjustSummaryArithmetic(t *testing.T) {
+	db, updates := newDryRunDB(t)
+	repo := &ratingRepositoryImpl{db: db}
+	targetID := uuid.New()
+
+	// countDelta, removed, added 与Create、Update、Delete传入的参数一致
+	steps := []struct {
+		name                       string
+		countDelta, removed, added int
+		wantCount, wantSum         int
+		wantAverage                float64
+		wantStars                  [6]int
+	}{
+		{"create 4 stars", 1, 0, 4, 1, 4, 4, [6]int{0, 0, 0, 0, 1, 0}},
+		{"create 5 stars", 1, 0, 5, 2, 9, 4.5, [6]int{0, 0, 0, 0, 1, 1}},
+		{"update 4 to 1", 0, 4, 1, 2, 6, 3, [6]int{0, 1, 0, 0, 0, 1}},
+		{"create 2 stars", 1, 0, 2, 3, 8, 8.0 / 3, [6]int{0, 1, 1, 0, 0, 1}},
+		{"delete 5 stars", -1, 5, 0, 2, 3, 1.5, [6]int{0, 1, 1, 0, 0, 0}},
+		{"delete 1 star", -1, 1, 0, 1, 2, 2, [6]int{0, 0, 1, 0, 0, 0}},
+		{"delete last", -1, 2, 0, 0, 0, 0, [6]int{}},
+	}
+
+	summary := &testSummary{counters: map[string]int{}}
+	for _, step := range steps {
+		*updates = nil
+		if err := repo.adjustSummary(db, "knowledge_point", targetID, step.countDelta, step.removed, step.added); err != nil {
+			t.Fatalf("%s: adjustSummary() error = %v", step.name, err)
+		}
+		if len(*updates) != 1 {
+			t.Fatalf("%s: updates = %v, want one statement", step.name, *updates)
+		}
+		summary.apply(t, (*updates)[0])
+
+		if summary.counters["rating_count"] != step.wantCount || summary.counters["score_sum"] != step.wantSum {
+			t.Fatalf("%s: count = %d, sum = %d, want %d, %d", step.name,
+				summary.counters["rating_count"], summary.counters["score_sum"], step.wantCount, step.wantSum)
+		}
+		if summary.average != step.wantAverage {
+			t.Fatalf("%s: average = %v, want %v", step.name, summary.average, step.wantAverage)
+		}
+		for star := 1; star <= 5; star++ {
+			if got := summary.counters[ratingStarColumns[star]]; got != step.wantStars[star] {
+				t.Fatalf("%s: %s = %d, want %d", step.name, ratingStarColumns[star], got, step.wantStars[star])
+			}
+		}
+	}
+}
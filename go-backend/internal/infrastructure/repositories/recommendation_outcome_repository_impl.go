@@ -0,0 +1,67 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+)
+
+// recommendationOutcomeRepositoryImpl 推荐处理记录仓储实现
+type recommendationOutcomeRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewRecommendationOutcomeRepository 创建推荐处理记录仓储实例
+func NewRecommendationOutcomeRepository(db *gorm.DB) repositories.RecommendationOutcomeRepository {
+	return &recommendationOutcomeRepositoryImpl{
+		db: db,
+	}
+}
+
+// Create 创建处理记录
+func (r *recommendationOutcomeRepositoryImpl) Create(ctx context.Context, outcome *entities.RecommendationOutcome) error {
+	if err := r.db.WithContext(ctx).Create(outcome).Error; err != nil {
+		return fmt.Errorf("创建推荐处理记录失败: %w", err)
+	}
+	return nil
+}
+
+// Delete 删除处理记录
+func (r *recommendationOutcomeRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := r.db.WithContext(ctx).Delete(&entities.RecommendationOutcome{}, "id = ?", id).Error; err != nil {
+		return fmt.Errorf("删除推荐处理记录失败: %w", err)
+	}
+	return nil
+}
+
+// GetByRecommendationID 获取推荐的处理记录
+func (r *recommendationOutcomeRepositoryImpl) GetByRecommendationID(ctx context.Context, recommendationID uuid.UUID) ([]*entities.RecommendationOutcome, error) {
+	var outcomes []*entities.RecommendationOutcome
+	if err := r.db.WithContext(ctx).
+		Where("recommendation_id = ?", recommendationID).
+		Order("created_at DESC").
+		Find(&outcomes).Error; err != nil {
+		return nil, fmt.Errorf("获取推荐处理记录失败: %w", err)
+	}
+	return outcomes, nil
+}
+
+// CountByAction 按分析器、分析类型、推荐类型和处理动作分组统计
+func (r *recommendationOutcomeRepositoryImpl) CountByAction(ctx context.Context, since time.Time) ([]*entities.RecommendationOutcomeStat, error) {
+	var stats []*entities.RecommendationOutcomeStat
+	if err := r.db.WithContext(ctx).
+		Model(&entities.RecommendationOutcome{}).
+		Select("analyzer, analysis_type, recommendation_type, action, COUNT(*) AS count").
+		Where("created_at >= ?", since).
+		Group("analyzer, analysis_type, recommendation_type, action").
+		Order("analyzer, analysis_type, recommendation_type, action").
+		Scan(&stats).Error; err != nil {
+		return nil, fmt.Errorf("统计推荐处理记录失败: %w", err)
+	}
+	return stats, nil
+}
@@ -0,0 +1,92 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+)
+
+// regradeRequestRepositoryImpl 评分复核申请仓储实现
+type regradeRequestRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewRegradeRequestRepository 创建评分复核申请仓储实例
+func NewRegradeRequestRepository(db *gorm.DB) repositories.RegradeRequestRepository {
+	return &regradeRequestRepositoryImpl{
+		db: db,
+	}
+}
+
+// Create 创建复核申请
+func (r *regradeRequestRepositoryImpl) Create(ctx context.Context, request *entities.RegradeRequest) error {
+	if err := r.db.WithContext(ctx).Create(request).Error; err != nil {
+		return fmt.Errorf("创建复核申请失败: %w", err)
+	}
+	return nil
+}
+
+// GetByID 根据ID获取复核申请
+func (r *regradeRequestRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*entities.RegradeRequest, error) {
+	var request entities.RegradeRequest
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&request).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("复核申请不存在")
+		}
+		return nil, fmt.Errorf("获取复核申请失败: %w", err)
+	}
+	return &request, nil
+}
+
+// ListByStatus 按状态分页获取复核申请
+func (r *regradeRequestRepositoryImpl) ListByStatus(ctx context.Context, status string, offset, limit int) ([]*entities.RegradeRequest, int64, error) {
+	query := r.db.WithContext(ctx).Model(&entities.RegradeRequest{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("统计复核申请失败: %w", err)
+	}
+
+	var requests []*entities.RegradeRequest
+	if err := query.Order("created_at ASC").Offset(offset).Limit(limit).Find(&requests).Error; err != nil {
+		return nil, 0, fmt.Errorf("获取复核申请失败: %w", err)
+	}
+	return requests, total, nil
+}
+
+// ListByAttempt 获取作答的复核申请
+func (r *regradeRequestRepositoryImpl) ListByAttempt(ctx context.Context, attemptID uuid.UUID) ([]*entities.RegradeRequest, error) {
+	var requests []*entities.RegradeRequest
+	if err := r.db.WithContext(ctx).
+		Where("attempt_id = ?", attemptID).
+		Order("created_at DESC").
+		Find(&requests).Error; err != nil {
+		return nil, fmt.Errorf("获取复核申请失败: %w", err)
+	}
+	return requests, nil
+}
+
+// Resolve 以条件更新处理复核申请，避免重复处理
+func (r *regradeRequestRepositoryImpl) Resolve(ctx context.Context, request *entities.RegradeRequest) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&entities.RegradeRequest{}).
+		Where("id = ? AND status = ?", request.ID, string(entities.RegradePending)).
+		Updates(map[string]interface{}{
+			"status":      request.Status,
+			"response":    request.Response,
+			"new_points":  request.NewPoints,
+			"resolved_by": request.ResolvedBy,
+			"resolved_at": request.ResolvedAt,
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("处理复核申请失败: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,52 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+)
+
+// likeEscaper 转义LIKE模式中的通配符
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// searchLogRepositoryImpl 检索日志仓储实现
+type searchLogRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewSearchLogRepository 创建检索日志仓储实例
+func NewSearchLogRepository(db *gorm.DB) repositories.SearchLogRepository {
+	return &searchLogRepositoryImpl{
+		db: db,
+	}
+}
+
+// Create 记录一次检索
+func (r *searchLogRepositoryImpl) Create(ctx context.Context, log *entities.SearchLog) error {
+	if err := r.db.WithContext(ctx).Create(log).Error; err != nil {
+		return fmt.Errorf("记录检索日志失败: %w", err)
+	}
+	return nil
+}
+
+// PopularQueries 获取since之后以prefix开头且有结果的热门检索词，按检索次数倒序
+func (r *searchLogRepositoryImpl) PopularQueries(ctx context.Context, prefix string, since time.Time, limit int) ([]repositories.QueryPopularity, error) {
+	var queries []repositories.QueryPopularity
+	if err := r.db.WithContext(ctx).
+		Model(&entities.SearchLog{}).
+		Select("normalized AS query, COUNT(*) AS count").
+		Where("normalized LIKE ? AND created_at >= ? AND result_count > 0", likeEscaper.Replace(prefix)+"%", since).
+		Group("normalized").
+		Order("count DESC").
+		Order("normalized ASC").
+		Limit(limit).
+		Scan(&queries).Error; err != nil {
+		return nil, fmt.Errorf("获取热门检索词失败: %w", err)
+	}
+	return queries, nil
+}
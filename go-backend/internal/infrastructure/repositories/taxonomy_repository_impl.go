@@ -0,0 +1,205 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+)
+
+// taxonomyRepositoryImpl 类别与技能体系仓储实现
+type taxonomyRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewTaxonomyRepository 创建类别与技能体系仓储实例
+func NewTaxonomyRepository(db *gorm.DB) repositories.TaxonomyRepository {
+	return &taxonomyRepositoryImpl{
+		db: db,
+	}
+}
+
+// CreateCategory 创建类别
+func (r *taxonomyRepositoryImpl) CreateCategory(ctx context.Context, category *entities.Category) error {
+	if err := r.db.WithContext(ctx).Create(category).Error; err != nil {
+		return fmt.Errorf("创建类别失败: %w", err)
+	}
+	return nil
+}
+
+// GetCategoryByID 根据ID获取类别
+func (r *taxonomyRepositoryImpl) GetCategoryByID(ctx context.Context, id uuid.UUID) (*entities.Category, error) {
+	var category entities.Category
+	if err := r.db.WithContext(ctx).Preload("Durations").Where("id = ?", id).First(&category).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("类别不存在")
+		}
+		return nil, fmt.Errorf("获取类别失败: %w", err)
+	}
+	return &category, nil
+}
+
+// GetCategoryByName 根据名称获取类别
+func (r *taxonomyRepositoryImpl) GetCategoryByName(ctx context.Context, name string) (*entities.Category, error) {
+	var category entities.Category
+	if err := r.db.WithContext(ctx).Preload("Durations").Where("name = ?", name).First(&category).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("类别不存在")
+		}
+		return nil, fmt.Errorf("获取类别失败: %w", err)
+	}
+	return &category, nil
+}
+
+// ListCategories 获取类别列表（含子类别和基准时间）
+func (r *taxonomyRepositoryImpl) ListCategories(ctx context.Context, includeInactive bool) ([]*entities.Category, error) {
+	var categories []*entities.Category
+	query := r.db.WithContext(ctx).Preload("Durations").Order("sort_order ASC, name ASC")
+	if !includeInactive {
+		query = query.Where("is_active = ?", true)
+	}
+	if err := query.Find(&categories).Error; err != nil {
+		return nil, fmt.Errorf("获取类别列表失败: %w", err)
+	}
+	return categories, nil
+}
+
+// UpdateCategory 更新类别
+func (r *taxonomyRepositoryImpl) UpdateCategory(ctx context.Context, category *entities.Category) error {
+	if err := r.db.WithContext(ctx).Omit(clause.Associations).Save(category).Error; err != nil {
+		return fmt.Errorf("更新类别失败: %w", err)
+	}
+	return nil
+}
+
+// DeleteCategory 删除类别及其技能映射和基准时间
+func (r *taxonomyRepositoryImpl) DeleteCategory(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&entities.CategorySkill{}, "category_id = ?", id).Error; err != nil {
+			return fmt.Errorf("删除类别技能映射失败: %w", err)
+		}
+		if err := tx.Delete(&entities.CategoryDuration{}, "category_id = ?", id).Error; err != nil {
+			return fmt.Errorf("删除类别基准时间失败: %w", err)
+		}
+		if err := tx.Delete(&entities.Category{}, "id = ?", id).Error; err != nil {
+			return fmt.Errorf("删除类别失败: %w", err)
+		}
+		return nil
+	})
+}
+
+// CreateSkill 创建技能
+func (r *taxonomyRepositoryImpl) CreateSkill(ctx context.Context, skill *entities.Skill) error {
+	if err := r.db.WithContext(ctx).Create(skill).Error; err != nil {
+		return fmt.Errorf("创建技能失败: %w", err)
+	}
+	return nil
+}
+
+// GetSkillByID 根据ID获取技能
+func (r *taxonomyRepositoryImpl) GetSkillByID(ctx context.Context, id uuid.UUID) (*entities.Skill, error) {
+	var skill entities.Skill
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&skill).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("技能不存在")
+		}
+		return nil, fmt.Errorf("获取技能失败: %w", err)
+	}
+	return &skill, nil
+}
+
+// GetSkillByName 根据名称获取技能
+func (r *taxonomyRepositoryImpl) GetSkillByName(ctx context.Context, name string) (*entities.Skill, error) {
+	var skill entities.Skill
+	if err := r.db.WithContext(ctx).Where("name = ?", name).First(&skill).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("技能不存在")
+		}
+		return nil, fmt.Errorf("获取技能失败: %w", err)
+	}
+	return &skill, nil
+}
+
+// ListSkills 获取技能列表
+func (r *taxonomyRepositoryImpl) ListSkills(ctx context.Context) ([]*entities.Skill, error) {
+	var skills []*entities.Skill
+	if err := r.db.WithContext(ctx).Order("name ASC").Find(&skills).Error; err != nil {
+		return nil, fmt.Errorf("获取技能列表失败: %w", err)
+	}
+	return skills, nil
+}
+
+// UpdateSkill 更新技能
+func (r *taxonomyRepositoryImpl) UpdateSkill(ctx context.Context, skill *entities.Skill) error {
+	if err := r.db.WithContext(ctx).Save(skill).Error; err != nil {
+		return fmt.Errorf("更新技能失败: %w", err)
+	}
+	return nil
+}
+
+// DeleteSkill 删除技能及其类别映射
+func (r *taxonomyRepositoryImpl) DeleteSkill(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&entities.CategorySkill{}, "skill_id = ?", id).Error; err != nil {
+			return fmt.Errorf("删除类别技能映射失败: %w", err)
+		}
+		if err := tx.Delete(&entities.Skill{}, "id = ?", id).Error; err != nil {
+			return fmt.Errorf("删除技能失败: %w", err)
+		}
+		return nil
+	})
+}
+
+// CreateCategorySkill 创建类别技能映射
+func (r *taxonomyRepositoryImpl) CreateCategorySkill(ctx context.Context, mapping *entities.CategorySkill) error {
+	if err := r.db.WithContext(ctx).Omit(clause.Associations).Create(mapping).Error; err != nil {
+		return fmt.Errorf("创建类别技能映射失败: %w", err)
+	}
+	return nil
+}
+
+// GetCategorySkills 获取类别的技能映射（含技能信息）
+func (r *taxonomyRepositoryImpl) GetCategorySkills(ctx context.Context, categoryID uuid.UUID, kind string) ([]*entities.CategorySkill, error) {
+	var mappings []*entities.CategorySkill
+	query := r.db.WithContext(ctx).Preload("Skill").Where("category_id = ?", categoryID)
+	if kind != "" {
+		query = query.Where("kind = ?", kind)
+	}
+	if err := query.Order("sort_order ASC, created_at ASC").Find(&mappings).Error; err != nil {
+		return nil, fmt.Errorf("获取类别技能映射失败: %w", err)
+	}
+	return mappings, nil
+}
+
+// DeleteCategorySkill 删除类别技能映射
+func (r *taxonomyRepositoryImpl) DeleteCategorySkill(ctx context.Context, id uuid.UUID) error {
+	if err := r.db.WithContext(ctx).Delete(&entities.CategorySkill{}, "id = ?", id).Error; err != nil {
+		return fmt.Errorf("删除类别技能映射失败: %w", err)
+	}
+	return nil
+}
+
+// UpsertCategoryDuration 创建或更新类别难度基准时间
+func (r *taxonomyRepositoryImpl) UpsertCategoryDuration(ctx context.Context, duration *entities.CategoryDuration) error {
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "category_id"}, {Name: "difficulty"}},
+		DoUpdates: clause.AssignmentColumns([]string{"step_hours", "updated_at"}),
+	}).Create(duration).Error
+	if err != nil {
+		return fmt.Errorf("保存类别基准时间失败: %w", err)
+	}
+	return nil
+}
+
+// GetCategoryDurations 获取类别各难度的基准时间
+func (r *taxonomyRepositoryImpl) GetCategoryDurations(ctx context.Context, categoryID uuid.UUID) ([]*entities.CategoryDuration, error) {
+	var durations []*entities.CategoryDuration
+	if err := r.db.WithContext(ctx).Where("category_id = ?", categoryID).Find(&durations).Error; err != nil {
+		return nil, fmt.Errorf("获取类别基准时间失败: %w", err)
+	}
+	return durations, nil
+}
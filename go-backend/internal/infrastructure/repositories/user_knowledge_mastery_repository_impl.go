@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+)
+
+// userKnowledgeMasteryRepositoryImpl 用户知识点掌握度仓储实现
+type userKnowledgeMasteryRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewUserKnowledgeMasteryRepository 创建用户知识点掌握度仓储实例
+func NewUserKnowledgeMasteryRepository(db *gorm.DB) repositories.UserKnowledgeMasteryRepository {
+	return &userKnowledgeMasteryRepositoryImpl{
+		db: db,
+	}
+}
+
+// Get 获取用户对知识点的掌握度
+func (r *userKnowledgeMasteryRepositoryImpl) Get(ctx context.Context, userID uint, knowledgePointID uuid.UUID) (*entities.UserKnowledgeMastery, error) {
+	var records []*entities.UserKnowledgeMastery
+	if err := r.db.WithContext(ctx).
+		Preload("KnowledgePoint").
+		Where("user_id = ? AND knowledge_point_id = ?", userID, knowledgePointID).
+		Limit(1).
+		Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("获取知识点掌握度失败: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+	return records[0], nil
+}
+
+// Save 创建或更新掌握度记录，按(用户, 知识点)覆盖
+func (r *userKnowledgeMasteryRepositoryImpl) Save(ctx context.Context, mastery *entities.UserKnowledgeMastery) error {
+	err := r.db.WithContext(ctx).
+		Omit(clause.Associations).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "user_id"}, {Name: "knowledge_point_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"mastery", "evidence_count", "last_source", "ease_factor", "interval_days",
+				"repetitions", "last_quality", "last_reviewed_at", "next_review_at", "updated_at",
+			}),
+		}).
+		Create(mastery).Error
+	if err != nil {
+		return fmt.Errorf("保存知识点掌握度失败: %w", err)
+	}
+	return nil
+}
+
+// ListByUser 获取用户全部知识点掌握度
+func (r *userKnowledgeMasteryRepositoryImpl) ListByUser(ctx context.Context, userID uint) ([]*entities.UserKnowledgeMastery, error) {
+	var records []*entities.UserKnowledgeMastery
+	if err := r.db.WithContext(ctx).
+		Preload("KnowledgePoint").
+		Where("user_id = ?", userID).
+		Order("mastery ASC").
+		Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("获取知识点掌握度失败: %w", err)
+	}
+	return records, nil
+}
+
+// ListDue 分页获取到期需要复习的知识点
+func (r *userKnowledgeMasteryRepositoryImpl) ListDue(ctx context.Context, userID uint, before time.Time, offset, limit int) ([]*entities.UserKnowledgeMastery, int64, error) {
+	query := r.db.WithContext(ctx).
+		Model(&entities.UserKnowledgeMastery{}).
+		Where("user_id = ? AND next_review_at <= ?", userID, before)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("统计待复习知识点失败: %w", err)
+	}
+
+	var records []*entities.UserKnowledgeMastery
+	if err := query.
+		Preload("KnowledgePoint").
+		Order("next_review_at ASC, mastery ASC").
+		Offset(offset).
+		Limit(limit).
+		Find(&records).Error; err != nil {
+		return nil, 0, fmt.Errorf("获取待复习知识点失败: %w", err)
+	}
+	return records, total, nil
+}
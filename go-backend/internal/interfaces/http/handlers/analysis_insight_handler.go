@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/services"
+	"sical-go-backend/pkg/logger"
+)
+
+// AnalysisInsightHandler 分析结果洞察处理器
+type AnalysisInsightHandler struct {
+	insightService *services.AnalysisInsightService
+}
+
+// NewAnalysisInsightHandler 创建分析结果洞察处理器
+func NewAnalysisInsightHandler(insightService *services.AnalysisInsightService) *AnalysisInsightHandler {
+	return &AnalysisInsightHandler{
+		insightService: insightService,
+	}
+}
+
+// FindSkillGaps 查询最新分析中标记了指定技能差距的目标
+func (h *AnalysisInsightHandler) FindSkillGaps(c *gin.Context) {
+	skill := c.Query("skill")
+	if skill == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "缺少技能名称"})
+		return
+	}
+	offset, limit := parsePagination(c)
+
+	analyses, total, err := h.insightService.FindGoalsWithSkillGap(c.Request.Context(), skill, offset, limit)
+	if err != nil {
+		logger.Error("查询技能差距分析失败", logger.String("skill", skill), logger.String("error", err.Error()))
+		handleServiceError(c, err, "查询技能差距分析失败")
+		return
+	}
+
+	h.respond(c, analyses, total, offset, limit)
+}
+
+// FindLowConfidence 查询置信度低于阈值的分析记录
+func (h *AnalysisInsightHandler) FindLowConfidence(c *gin.Context) {
+	threshold, err := strconv.ParseFloat(c.DefaultQuery("threshold", "0.5"), 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "置信度阈值格式无效"})
+		return
+	}
+	latestOnly := c.DefaultQuery("latest", "true") != "false"
+	offset, limit := parsePagination(c)
+
+	analyses, total, err := h.insightService.FindLowConfidenceAnalyses(c.Request.Context(), threshold, latestOnly, offset, limit)
+	if err != nil {
+		logger.Error("查询低置信度分析失败", logger.Float64("threshold", threshold), logger.String("error", err.Error()))
+		handleServiceError(c, err, "查询低置信度分析失败")
+		return
+	}
+
+	h.respond(c, analyses, total, offset, limit)
+}
+
+// GetRecommendationQuality 统计推荐的接受、忽略和暂缓情况，days 指定统计最近多少天（默认30天）
+func (h *AnalysisInsightHandler) GetRecommendationQuality(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil || days <= 0 || days > 365 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "统计天数无效"})
+		return
+	}
+
+	since := time.Now().AddDate(0, 0, -days)
+	stats, err := h.insightService.GetRecommendationQuality(c.Request.Context(), since)
+	if err != nil {
+		logger.Error("统计推荐处理结果失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "统计推荐处理结果失败"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  stats,
+		"count": len(stats),
+		"since": since,
+	})
+}
+
+// respond 输出分析记录列表
+func (h *AnalysisInsightHandler) respond(c *gin.Context, analyses []*entities.GoalAnalysis, total int64, offset, limit int) {
+	responses := make([]*AnalysisResponse, 0, len(analyses))
+	for _, analysis := range analyses {
+		responses = append(responses, convertToAnalysisResponse(analysis))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":   responses,
+		"count":  len(responses),
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
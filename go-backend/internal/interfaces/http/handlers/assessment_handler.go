@@ -0,0 +1,495 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+	"sical-go-backend/internal/domain/services"
+	"sical-go-backend/pkg/logger"
+)
+
+// AssessmentHandler 测评处理器
+type AssessmentHandler struct {
+	assessmentService *services.AssessmentService
+}
+
+// NewAssessmentHandler 创建测评处理器
+func NewAssessmentHandler(assessmentService *services.AssessmentService) *AssessmentHandler {
+	return &AssessmentHandler{
+		assessmentService: assessmentService,
+	}
+}
+
+// CreateAssessmentRequest 创建测评请求
+type CreateAssessmentRequest struct {
+	Title             string                  `json:"title" binding:"required,max=100"`
+	Description       string                  `json:"description"`
+	Type              string                  `json:"type" binding:"required,oneof=quiz assignment exam"`
+	Category          string                  `json:"category" binding:"required"`
+	Difficulty        string                  `json:"difficulty" binding:"required,oneof=beginner intermediate advanced"`
+	TimeLimit         int                     `json:"time_limit" binding:"min=0"`
+	PassingScore      float64                 `json:"passing_score" binding:"min=0,max=100"`
+	MaxAttempts       int                     `json:"max_attempts" binding:"min=0"`
+	KnowledgePointIDs []uuid.UUID             `json:"knowledge_point_ids"`
+	Questions         []CreateQuestionRequest `json:"questions" binding:"required,min=1,dive"`
+}
+
+// CreateQuestionRequest 创建题目请求
+type CreateQuestionRequest struct {
+	Type             string                     `json:"type" binding:"required,oneof=multiple_choice true_false short_answer essay"`
+	Content          string                     `json:"content" binding:"required"`
+	Options          []string                   `json:"options"`
+	CorrectAnswer    []string                   `json:"correct_answer"`
+	Points           float64                    `json:"points" binding:"required,gt=0"`
+	Explanation      string                     `json:"explanation"`
+	Rubric           []entities.RubricCriterion `json:"rubric"`
+	KnowledgePointID *uuid.UUID                 `json:"knowledge_point_id"`
+	Difficulty       string                     `json:"difficulty" binding:"omitempty,oneof=beginner intermediate advanced"`
+	Order            int                        `json:"order"`
+}
+
+// SaveAnswersRequest 保存答案请求
+type SaveAnswersRequest struct {
+	Answers []services.AnswerInput `json:"answers" binding:"required,min=1"`
+}
+
+// AssessmentResponse 测评响应
+type AssessmentResponse struct {
+	ID              uuid.UUID                  `json:"id"`
+	Title           string                     `json:"title"`
+	Description     string                     `json:"description"`
+	Type            string                     `json:"type"`
+	Category        string                     `json:"category"`
+	Difficulty      string                     `json:"difficulty"`
+	TimeLimit       int                        `json:"time_limit"`
+	PassingScore    float64                    `json:"passing_score"`
+	MaxAttempts     int                        `json:"max_attempts"`
+	TotalPoints     float64                    `json:"total_points"`
+	QuestionCount   int                        `json:"question_count"`
+	IsPublished     bool                       `json:"is_published"`
+	PublishedAt     *time.Time                 `json:"published_at"`
+	CompletedCount  int                        `json:"completed_count"`
+	AverageScore    float64                    `json:"average_score"`
+	KnowledgePoints []AssessmentKnowledgePoint `json:"knowledge_points,omitempty"`
+	Questions       []QuestionResponse         `json:"questions,omitempty"`
+	CreatedAt       time.Time                  `json:"created_at"`
+	UpdatedAt       time.Time                  `json:"updated_at"`
+}
+
+// AssessmentKnowledgePoint 测评关联的知识点
+type AssessmentKnowledgePoint struct {
+	ID    uuid.UUID `json:"id"`
+	Title string    `json:"title"`
+}
+
+// QuestionResponse 题目响应，正确答案和解析仅对管理员或已提交的作答可见
+type QuestionResponse struct {
+	ID               uuid.UUID                  `json:"id"`
+	Type             string                     `json:"type"`
+	Content          string                     `json:"content"`
+	Options          []string                   `json:"options,omitempty"`
+	Points           float64                    `json:"points"`
+	KnowledgePointID *uuid.UUID                 `json:"knowledge_point_id,omitempty"`
+	Difficulty       string                     `json:"difficulty,omitempty"`
+	Order            int                        `json:"order"`
+	CorrectAnswer    []string                   `json:"correct_answer,omitempty"`
+	Explanation      string                     `json:"explanation,omitempty"`
+	Rubric           []entities.RubricCriterion `json:"rubric,omitempty"`
+}
+
+// AttemptResponse 作答响应
+type AttemptResponse struct {
+	ID            uuid.UUID              `json:"id"`
+	AssessmentID  uuid.UUID              `json:"assessment_id"`
+	AttemptNumber int                    `json:"attempt_number"`
+	Status        string                 `json:"status"`
+	StartedAt     time.Time              `json:"started_at"`
+	ExpiresAt     *time.Time             `json:"expires_at"`
+	SubmittedAt   *time.Time             `json:"submitted_at"`
+	GradedAt      *time.Time             `json:"graded_at"`
+	TimedOut      bool                   `json:"timed_out"`
+	TotalPoints   float64                `json:"total_points"`
+	EarnedPoints  float64                `json:"earned_points"`
+	Score         float64                `json:"score"`
+	Passed        bool                   `json:"passed"`
+	Answers       []AnswerResultResponse `json:"answers,omitempty"`
+}
+
+// AnswerResultResponse 单题作答结果
+type AnswerResultResponse struct {
+	ID            uuid.UUID              `json:"id"`
+	QuestionID    uuid.UUID              `json:"question_id"`
+	Answer        []string               `json:"answer"`
+	GradingStatus string                 `json:"grading_status"`
+	IsCorrect     *bool                  `json:"is_correct,omitempty"`
+	EarnedPoints  *float64               `json:"earned_points,omitempty"`
+	RubricScores  []entities.RubricScore `json:"rubric_scores,omitempty"`
+	Feedback      string                 `json:"feedback,omitempty"` // 人工评分评语
+	Question      *QuestionResponse      `json:"question,omitempty"`
+}
+
+// CreateAssessment 创建测评（管理员）
+func (h *AssessmentHandler) CreateAssessment(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req CreateAssessmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("绑定请求参数失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请求参数无效"})
+		return
+	}
+
+	assessment := &entities.Assessment{
+		Title:        req.Title,
+		Description:  req.Description,
+		Type:         req.Type,
+		Category:     req.Category,
+		Difficulty:   req.Difficulty,
+		TimeLimit:    req.TimeLimit,
+		PassingScore: req.PassingScore,
+		MaxAttempts:  req.MaxAttempts,
+		CreatedBy:    userID,
+	}
+	for _, q := range req.Questions {
+		assessment.Questions = append(assessment.Questions, entities.AssessmentQuestion{
+			Type:             q.Type,
+			Content:          q.Content,
+			Options:          q.Options,
+			CorrectAnswer:    q.CorrectAnswer,
+			Points:           q.Points,
+			Explanation:      q.Explanation,
+			Rubric:           q.Rubric,
+			KnowledgePointID: q.KnowledgePointID,
+			Difficulty:       q.Difficulty,
+			Order:            q.Order,
+		})
+	}
+
+	if err := h.assessmentService.CreateAssessment(c.Request.Context(), assessment, req.KnowledgePointIDs); err != nil {
+		logger.Error("创建测评失败", logger.String("error", err.Error()))
+		handleServiceError(c, err, "创建测评失败")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": convertToAssessmentResponse(assessment, true)})
+}
+
+// GetAssessmentForAdmin 获取测评详情（管理员），包含正确答案
+func (h *AssessmentHandler) GetAssessmentForAdmin(c *gin.Context) {
+	h.getAssessment(c, true)
+}
+
+// PublishAssessment 发布测评（管理员）
+func (h *AssessmentHandler) PublishAssessment(c *gin.Context) {
+	id, ok := h.parseID(c, "id", "测评ID格式无效")
+	if !ok {
+		return
+	}
+
+	assessment, err := h.assessmentService.PublishAssessment(c.Request.Context(), id)
+	if err != nil {
+		logger.Error("发布测评失败",
+			logger.String("assessment_id", id.String()),
+			logger.String("error", err.Error()))
+		handleServiceError(c, err, "发布测评失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": convertToAssessmentResponse(assessment, true)})
+}
+
+// ListAssessments 获取已发布的测评列表，支持按类别、难度和类型筛选
+func (h *AssessmentHandler) ListAssessments(c *gin.Context) {
+	offset, limit := parsePagination(c)
+	filter := repositories.AssessmentFilter{
+		Category:      c.Query("category"),
+		Difficulty:    c.Query("difficulty"),
+		Type:          c.Query("type"),
+		PublishedOnly: true,
+	}
+
+	assessments, total, err := h.assessmentService.ListAssessments(c.Request.Context(), filter, offset, limit)
+	if err != nil {
+		logger.Error("获取测评列表失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取测评列表失败"})
+		return
+	}
+
+	responses := make([]*AssessmentResponse, 0, len(assessments))
+	for _, assessment := range assessments {
+		responses = append(responses, convertToAssessmentResponse(assessment, false))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":   responses,
+		"count":  len(responses),
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// GetAssessment 获取已发布的测评详情，不包含正确答案
+func (h *AssessmentHandler) GetAssessment(c *gin.Context) {
+	h.getAssessment(c, false)
+}
+
+// StartAttempt 开始作答，已有进行中的作答时返回该作答
+func (h *AssessmentHandler) StartAttempt(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	assessmentID, ok := h.parseID(c, "id", "测评ID格式无效")
+	if !ok {
+		return
+	}
+
+	attempt, err := h.assessmentService.StartAttempt(c.Request.Context(), assessmentID, userID)
+	if err != nil {
+		logger.Error("开始作答失败",
+			logger.String("assessment_id", assessmentID.String()),
+			logger.String("error", err.Error()))
+		handleServiceError(c, err, "开始作答失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": convertToAttemptResponse(attempt, nil)})
+}
+
+// ListAttempts 获取当前用户在测评上的作答记录
+func (h *AssessmentHandler) ListAttempts(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	assessmentID, ok := h.parseID(c, "id", "测评ID格式无效")
+	if !ok {
+		return
+	}
+
+	attempts, err := h.assessmentService.ListAttempts(c.Request.Context(), userID, assessmentID)
+	if err != nil {
+		logger.Error("获取作答记录失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取作答记录失败"})
+		return
+	}
+
+	responses := make([]*AttemptResponse, 0, len(attempts))
+	for _, attempt := range attempts {
+		responses = append(responses, convertToAttemptResponse(attempt, nil))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  responses,
+		"count": len(responses),
+	})
+}
+
+// SaveAnswers 保存作答中的答案
+func (h *AssessmentHandler) SaveAnswers(c *gin.Context) {
+	userID, assessmentID, attemptID, ok := h.parseAttemptRef(c)
+	if !ok {
+		return
+	}
+
+	var req SaveAnswersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请求参数无效"})
+		return
+	}
+
+	attempt, err := h.assessmentService.SaveAnswers(c.Request.Context(), userID, assessmentID, attemptID, req.Answers)
+	if err != nil {
+		logger.Error("保存答案失败",
+			logger.String("attempt_id", attemptID.String()),
+			logger.String("error", err.Error()))
+		handleServiceError(c, err, "保存答案失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": convertToAttemptResponse(attempt, nil)})
+}
+
+// SubmitAttempt 提交作答，返回自动评分结果
+func (h *AssessmentHandler) SubmitAttempt(c *gin.Context) {
+	userID, assessmentID, attemptID, ok := h.parseAttemptRef(c)
+	if !ok {
+		return
+	}
+
+	attempt, assessment, err := h.assessmentService.SubmitAttempt(c.Request.Context(), userID, assessmentID, attemptID)
+	if err != nil {
+		logger.Error("提交作答失败",
+			logger.String("attempt_id", attemptID.String()),
+			logger.String("error", err.Error()))
+		handleServiceError(c, err, "提交作答失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": convertToAttemptResponse(attempt, assessment)})
+}
+
+// GetAttempt 获取作答详情，提交后包含每题的评分结果、正确答案和解析
+func (h *AssessmentHandler) GetAttempt(c *gin.Context) {
+	userID, assessmentID, attemptID, ok := h.parseAttemptRef(c)
+	if !ok {
+		return
+	}
+
+	attempt, assessment, err := h.assessmentService.GetAttempt(c.Request.Context(), userID, assessmentID, attemptID)
+	if err != nil {
+		handleServiceError(c, err, "获取作答记录失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": convertToAttemptResponse(attempt, assessment)})
+}
+
+// getAssessment 输出测评详情，includeAnswers为true时包含未发布测评和正确答案
+func (h *AssessmentHandler) getAssessment(c *gin.Context, includeAnswers bool) {
+	id, ok := h.parseID(c, "id", "测评ID格式无效")
+	if !ok {
+		return
+	}
+
+	assessment, err := h.assessmentService.GetAssessment(c.Request.Context(), id, includeAnswers)
+	if err != nil {
+		handleServiceError(c, err, "获取测评失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": convertToAssessmentResponse(assessment, includeAnswers)})
+}
+
+// parseAttemptRef 解析当前用户及路径中的测评和作答ID，失败时已写入响应
+func (h *AssessmentHandler) parseAttemptRef(c *gin.Context) (uint, uuid.UUID, uuid.UUID, bool) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return 0, uuid.Nil, uuid.Nil, false
+	}
+	assessmentID, ok := h.parseID(c, "id", "测评ID格式无效")
+	if !ok {
+		return 0, uuid.Nil, uuid.Nil, false
+	}
+	attemptID, ok := h.parseID(c, "attempt_id", "作答ID格式无效")
+	if !ok {
+		return 0, uuid.Nil, uuid.Nil, false
+	}
+	return userID, assessmentID, attemptID, true
+}
+
+// parseID 解析路径中的UUID参数，失败时已写入响应
+func (h *AssessmentHandler) parseID(c *gin.Context, param, message string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param(param))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": message})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// convertToAssessmentResponse 转换测评响应，includeAnswers控制是否输出正确答案和解析
+func convertToAssessmentResponse(assessment *entities.Assessment, includeAnswers bool) *AssessmentResponse {
+	response := &AssessmentResponse{
+		ID:             assessment.ID,
+		Title:          assessment.Title,
+		Description:    assessment.Description,
+		Type:           assessment.Type,
+		Category:       assessment.Category,
+		Difficulty:     assessment.Difficulty,
+		TimeLimit:      assessment.TimeLimit,
+		PassingScore:   assessment.PassingScore,
+		MaxAttempts:    assessment.MaxAttempts,
+		TotalPoints:    assessment.TotalPoints(),
+		QuestionCount:  len(assessment.Questions),
+		IsPublished:    assessment.IsPublished,
+		PublishedAt:    assessment.PublishedAt,
+		CompletedCount: assessment.CompletedCount,
+		AverageScore:   assessment.AverageScore,
+		CreatedAt:      assessment.CreatedAt,
+		UpdatedAt:      assessment.UpdatedAt,
+	}
+	for _, point := range assessment.KnowledgePoints {
+		response.KnowledgePoints = append(response.KnowledgePoints, AssessmentKnowledgePoint{ID: point.ID, Title: point.Title})
+	}
+	for i := range assessment.Questions {
+		response.Questions = append(response.Questions, *convertToQuestionResponse(&assessment.Questions[i], includeAnswers))
+	}
+	return response
+}
+
+// convertToQuestionResponse 转换题目响应
+func convertToQuestionResponse(question *entities.AssessmentQuestion, includeAnswers bool) *QuestionResponse {
+	response := &QuestionResponse{
+		ID:               question.ID,
+		Type:             question.Type,
+		Content:          question.Content,
+		Options:          question.Options,
+		Points:           question.Points,
+		KnowledgePointID: question.KnowledgePointID,
+		Difficulty:       question.Difficulty,
+		Order:            question.Order,
+	}
+	if includeAnswers {
+		response.CorrectAnswer = question.CorrectAnswer
+		response.Explanation = question.Explanation
+		response.Rubric = question.Rubric
+	}
+	return response
+}
+
+// convertToAttemptResponse 转换作答响应
+// assessment不为空时附带题目信息，作答提交后才输出评分结果、正确答案和解析
+func convertToAttemptResponse(attempt *entities.AssessmentAttempt, assessment *entities.Assessment) *AttemptResponse {
+	response := &AttemptResponse{
+		ID:            attempt.ID,
+		AssessmentID:  attempt.AssessmentID,
+		AttemptNumber: attempt.AttemptNumber,
+		Status:        attempt.Status,
+		StartedAt:     attempt.StartedAt,
+		ExpiresAt:     attempt.ExpiresAt,
+		SubmittedAt:   attempt.SubmittedAt,
+		GradedAt:      attempt.GradedAt,
+		TimedOut:      attempt.TimedOut,
+		TotalPoints:   attempt.TotalPoints,
+		EarnedPoints:  attempt.EarnedPoints,
+		Score:         attempt.Score,
+		Passed:        attempt.Passed,
+	}
+
+	finished := attempt.Status != string(entities.AttemptInProgress)
+	questions := make(map[uuid.UUID]*entities.AssessmentQuestion)
+	if assessment != nil {
+		for i := range assessment.Questions {
+			questions[assessment.Questions[i].ID] = &assessment.Questions[i]
+		}
+	}
+
+	for _, answer := range attempt.Answers {
+		result := AnswerResultResponse{
+			ID:            answer.ID,
+			QuestionID:    answer.QuestionID,
+			Answer:        answer.Answer,
+			GradingStatus: answer.GradingStatus,
+		}
+		if finished {
+			result.IsCorrect = answer.IsCorrect
+			result.EarnedPoints = answer.EarnedPoints
+			result.RubricScores = answer.RubricScores
+			result.Feedback = answer.Feedback
+		}
+		if question, ok := questions[answer.QuestionID]; ok {
+			result.Question = convertToQuestionResponse(question, finished)
+		}
+		response.Answers = append(response.Answers, result)
+	}
+	return response
+}
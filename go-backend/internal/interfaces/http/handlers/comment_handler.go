@@ -0,0 +1,402 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/services"
+	"sical-go-backend/pkg/logger"
+)
+
+// deletedCommentPlaceholder 已删除但仍有回复的评论显示的内容
+const deletedCommentPlaceholder = "该评论已删除"
+
+// CommentHandler 评论处理器
+type CommentHandler struct {
+	commentService *services.CommentService
+}
+
+// NewCommentHandler 创建评论处理器
+func NewCommentHandler(commentService *services.CommentService) *CommentHandler {
+	return &CommentHandler{
+		commentService: commentService,
+	}
+}
+
+// CreateCommentRequest 发表评论请求，回复时只需提供parent_id
+type CreateCommentRequest struct {
+	TargetType string     `json:"target_type"`
+	TargetID   *uuid.UUID `json:"target_id"`
+	ParentID   *uuid.UUID `json:"parent_id"`
+	Content    string     `json:"content" binding:"required"`
+}
+
+// UpdateCommentRequest 编辑评论请求
+type UpdateCommentRequest struct {
+	Content string `json:"content" binding:"required"`
+}
+
+// ModerateCommentRequest 评论管理请求，字段为空时保持不变
+type ModerateCommentRequest struct {
+	Hidden *bool  `json:"hidden"`
+	Reason string `json:"reason" binding:"max=255"`
+	Pinned *bool  `json:"pinned"`
+	Locked *bool  `json:"locked"`
+}
+
+// CommentAuthor 评论作者
+type CommentAuthor struct {
+	ID       uint   `json:"id"`
+	Username string `json:"username"`
+}
+
+// CommentResponse 评论响应
+type CommentResponse struct {
+	ID           uuid.UUID                 `json:"id"`
+	TargetType   string                    `json:"target_type"`
+	TargetID     uuid.UUID                 `json:"target_id"`
+	ParentID     *uuid.UUID                `json:"parent_id"`
+	RootID       *uuid.UUID                `json:"root_id"`
+	Author       *CommentAuthor            `json:"author"`
+	Content      string                    `json:"content"`
+	Mentions     []entities.CommentMention `json:"mentions"`
+	LikeCount    int                       `json:"like_count"`
+	ReplyCount   int                       `json:"reply_count"`
+	LikedByMe    bool                      `json:"liked_by_me"`
+	IsEdited     bool                      `json:"is_edited"`
+	EditedAt     *time.Time                `json:"edited_at"`
+	IsDeleted    bool                      `json:"is_deleted"`
+	IsHidden     bool                      `json:"is_hidden"`
+	HiddenReason string                    `json:"hidden_reason,omitempty"`
+	IsPinned     bool                      `json:"is_pinned"`
+	IsLocked     bool                      `json:"is_locked"`
+	CreatedAt    time.Time                 `json:"created_at"`
+	UpdatedAt    time.Time                 `json:"updated_at"`
+}
+
+// ListComments 游标分页获取知识点或学习路径下的讨论串
+func (h *CommentHandler) ListComments(c *gin.Context) {
+	viewer, ok := h.viewer(c)
+	if !ok {
+		return
+	}
+	targetID, err := uuid.Parse(c.Query("target_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "评论对象ID格式无效"})
+		return
+	}
+	_, limit := parsePagination(c)
+
+	page, err := h.commentService.ListThreads(c.Request.Context(), viewer, c.Query("target_type"), targetID, c.Query("cursor"), limit)
+	if err != nil {
+		handleServiceError(c, err, "获取讨论列表失败")
+		return
+	}
+
+	h.respondPage(c, viewer, page, limit)
+}
+
+// CreateComment 发表评论或回复
+func (h *CommentHandler) CreateComment(c *gin.Context) {
+	viewer, ok := h.viewer(c)
+	if !ok {
+		return
+	}
+
+	var req CreateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请输入评论内容"})
+		return
+	}
+	input := services.CreateCommentInput{
+		TargetType: req.TargetType,
+		ParentID:   req.ParentID,
+		Content:    req.Content,
+	}
+	if req.ParentID == nil {
+		if req.TargetType == "" || req.TargetID == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "请指定评论对象"})
+			return
+		}
+		input.TargetID = *req.TargetID
+	}
+
+	comment, err := h.commentService.Create(c.Request.Context(), viewer, input)
+	if err != nil {
+		logger.Error("发表评论失败", logger.String("error", err.Error()))
+		handleServiceError(c, err, "发表评论失败")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": convertToCommentResponse(comment, viewer, false)})
+}
+
+// GetComment 获取单条评论
+func (h *CommentHandler) GetComment(c *gin.Context) {
+	viewer, id, ok := h.parseRef(c)
+	if !ok {
+		return
+	}
+
+	comment, liked, err := h.commentService.Get(c.Request.Context(), viewer, id)
+	if err != nil {
+		handleServiceError(c, err, "获取评论失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": convertToCommentResponse(comment, viewer, liked)})
+}
+
+// ListReplies 游标分页获取讨论串内的回复
+func (h *CommentHandler) ListReplies(c *gin.Context) {
+	viewer, id, ok := h.parseRef(c)
+	if !ok {
+		return
+	}
+	_, limit := parsePagination(c)
+
+	page, err := h.commentService.ListReplies(c.Request.Context(), viewer, id, c.Query("cursor"), limit)
+	if err != nil {
+		handleServiceError(c, err, "获取回复列表失败")
+		return
+	}
+
+	h.respondPage(c, viewer, page, limit)
+}
+
+// UpdateComment 编辑自己的评论
+func (h *CommentHandler) UpdateComment(c *gin.Context) {
+	viewer, id, ok := h.parseRef(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请输入评论内容"})
+		return
+	}
+
+	comment, err := h.commentService.Update(c.Request.Context(), viewer, id, req.Content)
+	if err != nil {
+		logger.Error("编辑评论失败",
+			logger.String("comment_id", id.String()),
+			logger.String("error", err.Error()))
+		handleServiceError(c, err, "编辑评论失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": convertToCommentResponse(comment, viewer, false)})
+}
+
+// DeleteComment 删除评论
+func (h *CommentHandler) DeleteComment(c *gin.Context) {
+	viewer, id, ok := h.parseRef(c)
+	if !ok {
+		return
+	}
+
+	if err := h.commentService.Delete(c.Request.Context(), viewer, id); err != nil {
+		logger.Error("删除评论失败",
+			logger.String("comment_id", id.String()),
+			logger.String("error", err.Error()))
+		handleServiceError(c, err, "删除评论失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "评论已删除"})
+}
+
+// ListEdits 获取评论的编辑历史
+func (h *CommentHandler) ListEdits(c *gin.Context) {
+	viewer, id, ok := h.parseRef(c)
+	if !ok {
+		return
+	}
+
+	edits, err := h.commentService.ListEdits(c.Request.Context(), viewer, id)
+	if err != nil {
+		handleServiceError(c, err, "获取编辑历史失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  edits,
+		"count": len(edits),
+	})
+}
+
+// LikeComment 点赞评论
+func (h *CommentHandler) LikeComment(c *gin.Context) {
+	viewer, id, ok := h.parseRef(c)
+	if !ok {
+		return
+	}
+
+	comment, err := h.commentService.Like(c.Request.Context(), viewer, id)
+	if err != nil {
+		handleServiceError(c, err, "点赞评论失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": convertToCommentResponse(comment, viewer, true)})
+}
+
+// UnlikeComment 取消点赞
+func (h *CommentHandler) UnlikeComment(c *gin.Context) {
+	viewer, id, ok := h.parseRef(c)
+	if !ok {
+		return
+	}
+
+	comment, err := h.commentService.Unlike(c.Request.Context(), viewer, id)
+	if err != nil {
+		handleServiceError(c, err, "取消点赞失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": convertToCommentResponse(comment, viewer, false)})
+}
+
+// ListMentions 分页获取提及当前用户的评论
+func (h *CommentHandler) ListMentions(c *gin.Context) {
+	viewer, ok := h.viewer(c)
+	if !ok {
+		return
+	}
+	offset, limit := parsePagination(c)
+
+	comments, total, err := h.commentService.ListMentions(c.Request.Context(), viewer.UserID, offset, limit)
+	if err != nil {
+		logger.Error("获取提及评论失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取提及评论失败"})
+		return
+	}
+
+	responses := make([]*CommentResponse, 0, len(comments))
+	for _, comment := range comments {
+		responses = append(responses, convertToCommentResponse(comment, viewer, false))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":   responses,
+		"count":  len(responses),
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// ModerateComment 审核员隐藏、置顶或锁定评论
+func (h *CommentHandler) ModerateComment(c *gin.Context) {
+	viewer, id, ok := h.parseRef(c)
+	if !ok {
+		return
+	}
+
+	var req ModerateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请求参数无效"})
+		return
+	}
+	if req.Hidden == nil && req.Pinned == nil && req.Locked == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请指定管理操作"})
+		return
+	}
+
+	comment, err := h.commentService.Moderate(c.Request.Context(), viewer.UserID, id, services.ModerateCommentInput{
+		Hidden: req.Hidden,
+		Reason: req.Reason,
+		Pinned: req.Pinned,
+		Locked: req.Locked,
+	})
+	if err != nil {
+		logger.Error("管理评论失败",
+			logger.String("comment_id", id.String()),
+			logger.String("error", err.Error()))
+		handleServiceError(c, err, "管理评论失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": convertToCommentResponse(comment, viewer, false)})
+}
+
+// respondPage 写入游标分页的评论列表
+func (h *CommentHandler) respondPage(c *gin.Context, viewer services.CommentViewer, page *services.CommentPage, limit int) {
+	responses := make([]*CommentResponse, 0, len(page.Comments))
+	for _, comment := range page.Comments {
+		responses = append(responses, convertToCommentResponse(comment, viewer, page.Liked[comment.ID]))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":        responses,
+		"count":       len(responses),
+		"limit":       limit,
+		"next_cursor": page.NextCursor,
+	})
+}
+
+// viewer 获取当前用户及其审核权限，失败时已写入响应
+func (h *CommentHandler) viewer(c *gin.Context) (services.CommentViewer, bool) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return services.CommentViewer{}, false
+	}
+	return services.CommentViewer{UserID: userID, Moderator: isModerator(c)}, true
+}
+
+// parseRef 解析当前用户及路径中的评论ID，失败时已写入响应
+func (h *CommentHandler) parseRef(c *gin.Context) (services.CommentViewer, uuid.UUID, bool) {
+	viewer, ok := h.viewer(c)
+	if !ok {
+		return viewer, uuid.Nil, false
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "评论ID格式无效"})
+		return viewer, uuid.Nil, false
+	}
+	return viewer, id, true
+}
+
+// convertToCommentResponse 转换评论响应，已删除评论不返回内容，隐藏原因只对审核员可见
+func convertToCommentResponse(comment *entities.Comment, viewer services.CommentViewer, liked bool) *CommentResponse {
+	response := &CommentResponse{
+		ID:         comment.ID,
+		TargetType: comment.TargetType,
+		TargetID:   comment.TargetID,
+		ParentID:   comment.ParentID,
+		RootID:     comment.RootID,
+		Content:    comment.Content,
+		Mentions:   comment.Mentions,
+		LikeCount:  comment.LikeCount,
+		ReplyCount: comment.ReplyCount,
+		LikedByMe:  liked,
+		IsEdited:   comment.IsEdited,
+		EditedAt:   comment.EditedAt,
+		IsDeleted:  comment.IsDeleted(),
+		IsHidden:   comment.IsHidden,
+		IsPinned:   comment.IsPinned,
+		IsLocked:   comment.IsLocked,
+		CreatedAt:  comment.CreatedAt,
+		UpdatedAt:  comment.UpdatedAt,
+	}
+	if response.Mentions == nil {
+		response.Mentions = []entities.CommentMention{}
+	}
+	if comment.User != nil {
+		response.Author = &CommentAuthor{ID: comment.User.ID, Username: comment.User.Username}
+	}
+	if viewer.Moderator {
+		response.HiddenReason = comment.HiddenReason
+	}
+	if response.IsDeleted {
+		response.Content = deletedCommentPlaceholder
+		response.Author = nil
+		response.Mentions = []entities.CommentMention{}
+	}
+	return response
+}
@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/services"
+	"sical-go-backend/pkg/logger"
+)
+
+// DiagnosticHandler 自适应诊断处理器
+type DiagnosticHandler struct {
+	diagnosticService *services.DiagnosticService
+}
+
+// NewDiagnosticHandler 创建自适应诊断处理器
+func NewDiagnosticHandler(diagnosticService *services.DiagnosticService) *DiagnosticHandler {
+	return &DiagnosticHandler{
+		diagnosticService: diagnosticService,
+	}
+}
+
+// DiagnosticAnswerRequest 诊断作答请求
+type DiagnosticAnswerRequest struct {
+	QuestionID uuid.UUID `json:"question_id" binding:"required"`
+	Answer     []string  `json:"answer"`
+}
+
+// DiagnosticResponse 诊断测评响应
+type DiagnosticResponse struct {
+	ID               uuid.UUID                    `json:"id"`
+	GoalID           uuid.UUID                    `json:"goal_id"`
+	Status           string                       `json:"status"`
+	TargetDifficulty string                       `json:"target_difficulty"`
+	MaxItems         int                          `json:"max_items"`
+	Answered         int                          `json:"answered"`
+	Estimates        []entities.KnowledgeEstimate `json:"estimates"`
+	NextQuestion     *QuestionResponse            `json:"next_question,omitempty"`
+	CompletedAt      *time.Time                   `json:"completed_at"`
+	CreatedAt        time.Time                    `json:"created_at"`
+}
+
+// DiagnosticAnswerResponse 诊断作答响应
+type DiagnosticAnswerResponse struct {
+	Correct       bool                `json:"correct"`
+	CorrectAnswer []string            `json:"correct_answer"`
+	Explanation   string              `json:"explanation,omitempty"`
+	AnalysisJobID *uuid.UUID          `json:"analysis_job_id,omitempty"` // 诊断完成后触发的分析任务
+	Diagnostic    *DiagnosticResponse `json:"diagnostic"`
+}
+
+// StartDiagnostic 为学习目标开始自适应诊断，已有进行中的诊断时继续该诊断
+func (h *DiagnosticHandler) StartDiagnostic(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	goalID, ok := h.parseID(c, "id", "目标ID格式无效")
+	if !ok {
+		return
+	}
+
+	view, err := h.diagnosticService.Start(c.Request.Context(), userID, goalID)
+	if err != nil {
+		logger.Error("开始自适应诊断失败",
+			logger.String("goal_id", goalID.String()),
+			logger.String("error", err.Error()))
+		handleServiceError(c, err, "开始自适应诊断失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": convertToDiagnosticResponse(view)})
+}
+
+// GetDiagnostic 获取诊断测评状态和知识点掌握度估计
+func (h *DiagnosticHandler) GetDiagnostic(c *gin.Context) {
+	userID, goalID, sessionID, ok := h.parseSessionRef(c)
+	if !ok {
+		return
+	}
+
+	view, err := h.diagnosticService.Get(c.Request.Context(), userID, goalID, sessionID)
+	if err != nil {
+		handleServiceError(c, err, "获取诊断测评失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": convertToDiagnosticResponse(view)})
+}
+
+// AnswerDiagnostic 回答诊断测评的当前题目，返回判题结果和下一道题
+func (h *DiagnosticHandler) AnswerDiagnostic(c *gin.Context) {
+	userID, goalID, sessionID, ok := h.parseSessionRef(c)
+	if !ok {
+		return
+	}
+
+	var req DiagnosticAnswerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请求参数无效"})
+		return
+	}
+
+	result, err := h.diagnosticService.Answer(c.Request.Context(), userID, goalID, sessionID, req.QuestionID, req.Answer)
+	if err != nil {
+		logger.Error("诊断作答失败",
+			logger.String("session_id", sessionID.String()),
+			logger.String("error", err.Error()))
+		handleServiceError(c, err, "诊断作答失败")
+		return
+	}
+
+	response := &DiagnosticAnswerResponse{
+		Correct:       result.Correct,
+		CorrectAnswer: result.CorrectAnswer,
+		Explanation:   result.Explanation,
+		Diagnostic:    convertToDiagnosticResponse(&result.DiagnosticView),
+	}
+	if result.AnalysisJob != nil {
+		response.AnalysisJobID = &result.AnalysisJob.ID
+	}
+	c.JSON(http.StatusOK, gin.H{"data": response})
+}
+
+// parseSessionRef 解析当前用户及路径中的目标和诊断ID，失败时已写入响应
+func (h *DiagnosticHandler) parseSessionRef(c *gin.Context) (uint, uuid.UUID, uuid.UUID, bool) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return 0, uuid.Nil, uuid.Nil, false
+	}
+	goalID, ok := h.parseID(c, "id", "目标ID格式无效")
+	if !ok {
+		return 0, uuid.Nil, uuid.Nil, false
+	}
+	sessionID, ok := h.parseID(c, "session_id", "诊断ID格式无效")
+	if !ok {
+		return 0, uuid.Nil, uuid.Nil, false
+	}
+	return userID, goalID, sessionID, true
+}
+
+// parseID 解析路径中的UUID参数，失败时已写入响应
+func (h *DiagnosticHandler) parseID(c *gin.Context, param, message string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param(param))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": message})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// convertToDiagnosticResponse 转换诊断测评响应，题目不包含正确答案
+func convertToDiagnosticResponse(view *services.DiagnosticView) *DiagnosticResponse {
+	session := view.Session
+	response := &DiagnosticResponse{
+		ID:               session.ID,
+		GoalID:           session.GoalID,
+		Status:           session.Status,
+		TargetDifficulty: session.TargetDifficulty,
+		MaxItems:         session.MaxItems,
+		Answered:         len(session.Responses),
+		Estimates:        session.Estimates,
+		CompletedAt:      session.CompletedAt,
+		CreatedAt:        session.CreatedAt,
+	}
+	if view.NextQuestion != nil {
+		response.NextQuestion = convertToQuestionResponse(view.NextQuestion, false)
+	}
+	return response
+}
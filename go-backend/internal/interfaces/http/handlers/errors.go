@@ -0,0 +1,22 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	apperrors "sical-go-backend/pkg/errors"
+)
+
+// handleServiceError 将服务层错误转换为HTTP响应
+// 客户端类错误(4xx)返回服务层给出的消息，其余错误统一返回message
+func handleServiceError(c *gin.Context, err error, message string) {
+	if appErr, ok := apperrors.AsAppError(err); ok && appErr.Code >= 400 && appErr.Code < 500 {
+		body := gin.H{"error": appErr.Message}
+		if len(appErr.Details) > 0 {
+			body["details"] = appErr.Details
+		}
+		c.JSON(appErr.Code, body)
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+}
@@ -0,0 +1,259 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/services"
+	"sical-go-backend/pkg/logger"
+)
+
+// GradingHandler 人工评分处理器
+type GradingHandler struct {
+	gradingService *services.GradingService
+}
+
+// NewGradingHandler 创建人工评分处理器
+func NewGradingHandler(gradingService *services.GradingService) *GradingHandler {
+	return &GradingHandler{
+		gradingService: gradingService,
+	}
+}
+
+// RegradeRequestBody 申请复核请求
+type RegradeRequestBody struct {
+	Reason string `json:"reason" binding:"required,max=1000"`
+}
+
+// ResolveRegradeRequest 处理复核申请请求
+type ResolveRegradeRequest struct {
+	Action   string              `json:"action" binding:"required,oneof=accept reject"`
+	Grade    services.GradeInput `json:"grade"`
+	Response string              `json:"response" binding:"max=1000"`
+}
+
+// SubmissionResponse 评分人查看的作答详情
+type SubmissionResponse struct {
+	Assessment *AssessmentResponse        `json:"assessment"`
+	Attempt    *AttemptResponse           `json:"attempt"`
+	UserID     uint                       `json:"user_id"`
+	Regrades   []*entities.RegradeRequest `json:"regrades"`
+}
+
+// ListQueue 获取待人工评分的作答队列，支持按测评筛选
+func (h *GradingHandler) ListQueue(c *gin.Context) {
+	var assessmentID *uuid.UUID
+	if raw := c.Query("assessment_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "测评ID格式无效"})
+			return
+		}
+		assessmentID = &id
+	}
+	offset, limit := parsePagination(c)
+
+	attempts, total, err := h.gradingService.ListQueue(c.Request.Context(), assessmentID, offset, limit)
+	if err != nil {
+		logger.Error("获取评分队列失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取评分队列失败"})
+		return
+	}
+
+	responses := make([]*AttemptResponse, 0, len(attempts))
+	for _, attempt := range attempts {
+		responses = append(responses, convertToAttemptResponse(attempt, nil))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":   responses,
+		"count":  len(responses),
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// GetSubmission 获取作答详情，包含正确答案、评分细则和复核申请
+func (h *GradingHandler) GetSubmission(c *gin.Context) {
+	attemptID, ok := h.parseID(c, "attempt_id", "作答ID格式无效")
+	if !ok {
+		return
+	}
+
+	submission, err := h.gradingService.GetSubmission(c.Request.Context(), attemptID)
+	if err != nil {
+		handleServiceError(c, err, "获取作答详情失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": &SubmissionResponse{
+		Assessment: convertToAssessmentResponse(submission.Assessment, true),
+		Attempt:    convertToAttemptResponse(submission.Attempt, submission.Assessment),
+		UserID:     submission.Attempt.UserID,
+		Regrades:   submission.Regrades,
+	}})
+}
+
+// GradeAnswer 人工评分单题答案
+func (h *GradingHandler) GradeAnswer(c *gin.Context) {
+	graderID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	attemptID, ok := h.parseID(c, "attempt_id", "作答ID格式无效")
+	if !ok {
+		return
+	}
+	answerID, ok := h.parseID(c, "answer_id", "答案ID格式无效")
+	if !ok {
+		return
+	}
+
+	var req services.GradeInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请求参数无效"})
+		return
+	}
+
+	attempt, err := h.gradingService.GradeAnswer(c.Request.Context(), graderID, attemptID, answerID, req)
+	if err != nil {
+		logger.Error("人工评分失败",
+			logger.String("attempt_id", attemptID.String()),
+			logger.String("answer_id", answerID.String()),
+			logger.String("error", err.Error()))
+		handleServiceError(c, err, "人工评分失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": convertToAttemptResponse(attempt, nil)})
+}
+
+// ListRegrades 获取复核申请列表，默认只返回待处理的申请
+func (h *GradingHandler) ListRegrades(c *gin.Context) {
+	status := c.DefaultQuery("status", string(entities.RegradePending))
+	if status == "all" {
+		status = ""
+	}
+	offset, limit := parsePagination(c)
+
+	requests, total, err := h.gradingService.ListRegrades(c.Request.Context(), status, offset, limit)
+	if err != nil {
+		logger.Error("获取复核申请失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取复核申请失败"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":   requests,
+		"count":  len(requests),
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// ResolveRegrade 处理复核申请，接受时按新的评分更新作答成绩
+func (h *GradingHandler) ResolveRegrade(c *gin.Context) {
+	graderID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	regradeID, ok := h.parseID(c, "id", "复核申请ID格式无效")
+	if !ok {
+		return
+	}
+
+	var req ResolveRegradeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请求参数无效"})
+		return
+	}
+
+	request, err := h.gradingService.ResolveRegrade(c.Request.Context(), graderID, regradeID, req.Action == "accept", req.Grade, req.Response)
+	if err != nil {
+		logger.Error("处理复核申请失败",
+			logger.String("regrade_id", regradeID.String()),
+			logger.String("error", err.Error()))
+		handleServiceError(c, err, "处理复核申请失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": request})
+}
+
+// RequestRegrade 学习者对已评分答案申请复核
+func (h *GradingHandler) RequestRegrade(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	assessmentID, ok := h.parseID(c, "id", "测评ID格式无效")
+	if !ok {
+		return
+	}
+	attemptID, ok := h.parseID(c, "attempt_id", "作答ID格式无效")
+	if !ok {
+		return
+	}
+	answerID, ok := h.parseID(c, "answer_id", "答案ID格式无效")
+	if !ok {
+		return
+	}
+
+	var req RegradeRequestBody
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请填写复核原因"})
+		return
+	}
+
+	request, err := h.gradingService.RequestRegrade(c.Request.Context(), userID, assessmentID, attemptID, answerID, req.Reason)
+	if err != nil {
+		logger.Error("申请复核失败",
+			logger.String("answer_id", answerID.String()),
+			logger.String("error", err.Error()))
+		handleServiceError(c, err, "申请复核失败")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": request})
+}
+
+// ListAttemptRegrades 获取学习者在作答上的复核申请及处理结果
+func (h *GradingHandler) ListAttemptRegrades(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	assessmentID, ok := h.parseID(c, "id", "测评ID格式无效")
+	if !ok {
+		return
+	}
+	attemptID, ok := h.parseID(c, "attempt_id", "作答ID格式无效")
+	if !ok {
+		return
+	}
+
+	requests, err := h.gradingService.ListAttemptRegrades(c.Request.Context(), userID, assessmentID, attemptID)
+	if err != nil {
+		handleServiceError(c, err, "获取复核申请失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  requests,
+		"count": len(requests),
+	})
+}
+
+// parseID 解析路径中的UUID参数，失败时已写入响应
+func (h *GradingHandler) parseID(c *gin.Context, param, message string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param(param))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": message})
+		return uuid.Nil, false
+	}
+	return id, true
+}
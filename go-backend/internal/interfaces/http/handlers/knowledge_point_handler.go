@@ -9,58 +9,94 @@ import (
 	"github.com/google/uuid"
 	"sical-go-backend/internal/domain/entities"
 	"sical-go-backend/internal/domain/repositories"
+	"sical-go-backend/internal/domain/services"
+	apperrors "sical-go-backend/pkg/errors"
 	"sical-go-backend/pkg/logger"
+	"sical-go-backend/pkg/response"
 )
 
 // KnowledgePointHandler 知识点处理器
 type KnowledgePointHandler struct {
 	knowledgePointRepo repositories.KnowledgePointRepository
+	taxonomyService    *services.TaxonomyService
+	searchService      *services.KnowledgeSearchService
+	listService        *services.KnowledgeListService
+	revisionService    *services.KnowledgeRevisionService
+	renderService      *services.KnowledgeRenderService
 }
 
 // NewKnowledgePointHandler 创建知识点处理器
-func NewKnowledgePointHandler(knowledgePointRepo repositories.KnowledgePointRepository) *KnowledgePointHandler {
+func NewKnowledgePointHandler(knowledgePointRepo repositories.KnowledgePointRepository, taxonomyService *services.TaxonomyService, searchService *services.KnowledgeSearchService, listService *services.KnowledgeListService, revisionService *services.KnowledgeRevisionService, renderService *services.KnowledgeRenderService) *KnowledgePointHandler {
 	return &KnowledgePointHandler{
 		knowledgePointRepo: knowledgePointRepo,
+		taxonomyService:    taxonomyService,
+		searchService:      searchService,
+		listService:        listService,
+		revisionService:    revisionService,
+		renderService:      renderService,
 	}
 }
 
 // CreateKnowledgePointRequest 创建知识点请求
 type CreateKnowledgePointRequest struct {
-	Title         string `json:"title" binding:"required,min=1,max=255"`
-	Description   string `json:"description"`
-	Content       string `json:"content" binding:"required"`
-	Category      string `json:"category" binding:"required"`
-	Difficulty    string `json:"difficulty" binding:"required,oneof=beginner intermediate advanced"`
-	Resources     string `json:"resources"`
-	Prerequisites string `json:"prerequisites"`
+	Title         string   `json:"title" binding:"required,min=1,max=255"`
+	Description   string   `json:"description"`
+	Content       string   `json:"content" binding:"required"`
+	Category      string   `json:"category" binding:"required"`
+	Difficulty    string   `json:"difficulty" binding:"required,oneof=beginner intermediate advanced"`
+	Resources     string   `json:"resources"`
+	Prerequisites string   `json:"prerequisites"`
+	Tags          []string `json:"tags" binding:"max=20,dive,max=50"`
 }
 
 // UpdateKnowledgePointRequest 更新知识点请求
 type UpdateKnowledgePointRequest struct {
-	Title         *string `json:"title,omitempty"`
-	Description   *string `json:"description,omitempty"`
-	Content       *string `json:"content,omitempty"`
-	Category      *string `json:"category,omitempty"`
-	Difficulty    *string `json:"difficulty,omitempty"`
-	Resources     *string `json:"resources,omitempty"`
-	Prerequisites *string `json:"prerequisites,omitempty"`
+	Title         *string  `json:"title,omitempty"`
+	Description   *string  `json:"description,omitempty"`
+	Content       *string  `json:"content,omitempty"`
+	Category      *string  `json:"category,omitempty"`
+	Difficulty    *string  `json:"difficulty,omitempty"`
+	Resources     *string  `json:"resources,omitempty"`
+	Prerequisites *string  `json:"prerequisites,omitempty"`
+	Tags          []string `json:"tags,omitempty" binding:"omitempty,max=20,dive,max=50"`
+	ChangeSummary string   `json:"change_summary"` // 修改说明，为空时根据变化的字段生成
 }
 
 // KnowledgePointDetailResponse 知识点详细响应
 type KnowledgePointDetailResponse struct {
-	ID            string    `json:"id"`
-	Title         string    `json:"title"`
-	Description   string    `json:"description"`
-	Content       string    `json:"content"`
-	Category      string    `json:"category"`
-	Difficulty    string    `json:"difficulty"`
-	Resources     string    `json:"resources"`
-	Prerequisites string    `json:"prerequisites"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	ID            string     `json:"id"`
+	ExternalKey   *string    `json:"external_key,omitempty"`
+	Title         string     `json:"title"`
+	Description   string     `json:"description"`
+	Content       string     `json:"content"`
+	Category      string     `json:"category"`
+	Difficulty    string     `json:"difficulty"`
+	Resources     string     `json:"resources"`
+	Prerequisites string     `json:"prerequisites"`
+	Tags          []string   `json:"tags"`
+	Version       int        `json:"version"`
+	Status        string     `json:"status"`
+	AuthorID      *uint      `json:"author_id,omitempty"`
+	PublishAt     *time.Time `json:"publish_at,omitempty"`
+	PublishedAt   *time.Time `json:"published_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
 }
 
-// CreateKnowledgePoint 创建知识点
+// KnowledgePointSearchHitResponse 知识点检索结果响应，title_highlight和snippet为已转义的HTML，命中部分以<mark>标记
+type KnowledgePointSearchHitResponse struct {
+	ID             string    `json:"id"`
+	Title          string    `json:"title"`
+	Description    string    `json:"description"`
+	Category       string    `json:"category"`
+	Difficulty     string    `json:"difficulty"`
+	TitleHighlight string    `json:"title_highlight"`
+	Snippet        string    `json:"snippet"`
+	Rank           float64   `json:"rank"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// CreateKnowledgePoint 以草稿状态创建知识点，需要作者权限
 func (h *KnowledgePointHandler) CreateKnowledgePoint(c *gin.Context) {
 	var req CreateKnowledgePointRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -69,6 +105,12 @@ func (h *KnowledgePointHandler) CreateKnowledgePoint(c *gin.Context) {
 		return
 	}
 
+	// 校验类别
+	if err := h.taxonomyService.ValidateCategory(c.Request.Context(), req.Category); err != nil {
+		handleServiceError(c, err, "校验类别失败")
+		return
+	}
+
 	// 创建知识点实体
 	knowledgePoint := &entities.KnowledgePoint{
 		ID:            uuid.New(),
@@ -80,11 +122,12 @@ func (h *KnowledgePointHandler) CreateKnowledgePoint(c *gin.Context) {
 		Resources:     req.Resources,
 		Prerequisites: req.Prerequisites,
 	}
+	knowledgePoint.SetTags(req.Tags)
 
-	// 保存到数据库
-	if err := h.knowledgePointRepo.Create(c.Request.Context(), knowledgePoint); err != nil {
+	// 保存到数据库并记录首个修订
+	if err := h.revisionService.Create(c.Request.Context(), knowledgePoint, knowledgeEditor(c)); err != nil {
 		logger.Error("创建知识点失败", logger.String("error", err.Error()))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "创建知识点失败"})
+		handleServiceError(c, err, "创建知识点失败")
 		return
 	}
 
@@ -92,6 +135,7 @@ func (h *KnowledgePointHandler) CreateKnowledgePoint(c *gin.Context) {
 	response := h.convertToKnowledgePointDetailResponse(knowledgePoint)
 
 	logger.Info("知识点创建成功", logger.String("knowledge_point_id", knowledgePoint.ID.String()))
+	c.Header("ETag", knowledgePointETag(knowledgePoint.Version))
 	c.JSON(http.StatusCreated, gin.H{"data": response})
 }
 
@@ -112,12 +156,106 @@ func (h *KnowledgePointHandler) GetKnowledgePoint(c *gin.Context) {
 		return
 	}
 
+	// 未发布的知识点只对作者和审核员可见
+	if !knowledgePoint.IsPublished() && !knowledgeEditor(c).IsEditorial() {
+		c.JSON(http.StatusNotFound, gin.H{"error": "知识点不存在"})
+		return
+	}
+
 	// 转换响应
 	response := h.convertToKnowledgePointDetailResponse(knowledgePoint)
 
+	c.Header("ETag", knowledgePointETag(knowledgePoint.Version))
 	c.JSON(http.StatusOK, gin.H{"data": response})
 }
 
+// KnowledgeRenderedResponse 知识点正文渲染结果响应
+type KnowledgeRenderedResponse struct {
+	KnowledgePointID uuid.UUID                    `json:"knowledge_point_id"`
+	Version          int                          `json:"version"`
+	HTML             string                       `json:"html"`
+	TOC              []entities.KnowledgeTOCEntry `json:"toc"`
+	WordCount        int                          `json:"word_count"`
+	ReadingMinutes   int                          `json:"reading_minutes"`
+	RenderedAt       time.Time                    `json:"rendered_at"`
+}
+
+// GetRenderedContent 获取知识点正文渲染后的HTML、目录和预计阅读时间
+// HTML已清洗，公式以\(...\)、\[...\]包围，化学结构式在figure的data-smiles属性中，均需前端进一步排版
+func (h *KnowledgePointHandler) GetRenderedContent(c *gin.Context) {
+	knowledgePointID, ok := parseKnowledgePointID(c)
+	if !ok {
+		return
+	}
+
+	point, rendering, err := h.renderService.Rendered(c.Request.Context(), knowledgePointID, knowledgeEditor(c))
+	if err != nil {
+		handleServiceError(c, err, "渲染知识点正文失败")
+		return
+	}
+
+	c.Header("ETag", knowledgePointETag(point.Version))
+	c.JSON(http.StatusOK, gin.H{"data": KnowledgeRenderedResponse{
+		KnowledgePointID: point.ID,
+		Version:          point.Version,
+		HTML:             rendering.HTML,
+		TOC:              rendering.TOCEntries(),
+		WordCount:        rendering.WordCount,
+		ReadingMinutes:   rendering.ReadingMinutes,
+		RenderedAt:       rendering.RenderedAt,
+	}})
+}
+
+// ListKnowledgePoints 分页获取知识点列表，学习者只能看到已发布的知识点，作者和审核员可按status筛选
+// 支持按类别、难度、标签(逗号分隔，须全部包含)、创建时间范围和是否有前置知识点组合筛选，
+// sort指定排序字段(created_at、updated_at、title、difficulty)，前缀"-"表示倒序，
+// fields指定返回的字段(逗号分隔)，默认不返回正文，cursor为上一页返回的next_cursor
+func (h *KnowledgePointHandler) ListKnowledgePoints(c *gin.Context) {
+	_, limit := parsePagination(c)
+	input := services.KnowledgeListInput{
+		Category:   c.Query("category"),
+		Difficulty: c.Query("difficulty"),
+		Status:     c.Query("status"),
+		Editorial:  knowledgeEditor(c).IsEditorial(),
+		Tags:       splitQueryList(c, "tags"),
+		Sort:       c.Query("sort"),
+		Fields:     splitQueryList(c, "fields"),
+		Cursor:     c.Query("cursor"),
+		Limit:      limit,
+	}
+
+	var ok bool
+	if input.CreatedFrom, ok = parseQueryTime(c, "created_from"); !ok {
+		return
+	}
+	if input.CreatedTo, ok = parseQueryTime(c, "created_to"); !ok {
+		return
+	}
+	if value := c.Query("has_prerequisites"); value != "" {
+		hasPrerequisites, err := strconv.ParseBool(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "has_prerequisites参数无效"})
+			return
+		}
+		input.HasPrerequisites = &hasPrerequisites
+	}
+
+	page, err := h.listService.List(c.Request.Context(), input)
+	if err != nil {
+		logger.Error("获取知识点列表失败", logger.String("error", err.Error()))
+		handleServiceError(c, err, "获取知识点列表失败")
+		return
+	}
+
+	// 转换响应，只包含请求的字段
+	items := make([]gin.H, 0, len(page.Points))
+	for _, kp := range page.Points {
+		items = append(items, h.convertToKnowledgePointFields(kp, page.Fields))
+	}
+
+	response.Pagination(c, items, response.NewCursorPaginationMeta(page.Page, page.Limit, page.Total, page.NextCursor))
+}
+
 // GetKnowledgePointsByCategory 按分类获取知识点
 func (h *KnowledgePointHandler) GetKnowledgePointsByCategory(c *gin.Context) {
 	category := c.Query("category")
@@ -178,50 +316,70 @@ func (h *KnowledgePointHandler) GetKnowledgePointsByDifficulty(c *gin.Context) {
 	})
 }
 
-// SearchKnowledgePoints 搜索知识点
+// SearchKnowledgePoints 全文检索知识点，支持按类别和难度筛选，返回相关度排序的结果和分面统计
 func (h *KnowledgePointHandler) SearchKnowledgePoints(c *gin.Context) {
-	query := c.Query("q")
-	if query == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "缺少搜索关键词"})
-		return
-	}
-
-	// 获取分页参数
-	limitStr := c.DefaultQuery("limit", "10")
-	offsetStr := c.DefaultQuery("offset", "0")
-
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit <= 0 {
-		limit = 10
-	}
-
-	offset, err := strconv.Atoi(offsetStr)
-	if err != nil || offset < 0 {
-		offset = 0
-	}
-
-	knowledgePoints, err := h.knowledgePointRepo.Search(c.Request.Context(), query)
+	offset, limit := parsePagination(c)
+
+	page, err := h.searchService.Search(c.Request.Context(), repositories.KnowledgeSearchQuery{
+		Keyword:    c.Query("q"),
+		Category:   c.Query("category"),
+		Difficulty: c.Query("difficulty"),
+		Offset:     offset,
+		Limit:      limit,
+	})
 	if err != nil {
 		logger.Error("搜索知识点失败", logger.String("error", err.Error()))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "搜索知识点失败"})
+		handleServiceError(c, err, "搜索知识点失败")
 		return
 	}
 
 	// 转换响应
-	var responses []KnowledgePointDetailResponse
-	for _, kp := range knowledgePoints {
-		responses = append(responses, h.convertToKnowledgePointDetailResponse(kp))
+	responses := make([]KnowledgePointSearchHitResponse, 0, len(page.Hits))
+	for _, hit := range page.Hits {
+		responses = append(responses, KnowledgePointSearchHitResponse{
+			ID:             hit.Point.ID.String(),
+			Title:          hit.Point.Title,
+			Description:    hit.Point.Description,
+			Category:       hit.Point.Category,
+			Difficulty:     hit.Point.Difficulty,
+			TitleHighlight: hit.TitleHighlight,
+			Snippet:        hit.Snippet,
+			Rank:           hit.Rank,
+			UpdatedAt:      hit.Point.UpdatedAt,
+		})
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"data":   responses,
 		"count":  len(responses),
+		"total":  page.Total,
 		"limit":  limit,
 		"offset": offset,
+		"facets": gin.H{
+			"category":   page.Categories,
+			"difficulty": page.Difficulties,
+		},
 	})
 }
 
-// UpdateKnowledgePoint 更新知识点
+// SuggestKnowledgePoints 输入联想，返回前缀、拼音和模糊匹配的知识点以及热门检索词
+func (h *KnowledgePointHandler) SuggestKnowledgePoints(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "0"))
+	if err != nil {
+		limit = 0
+	}
+
+	result, err := h.searchService.Suggest(c.Request.Context(), c.Query("q"), limit)
+	if err != nil {
+		logger.Error("获取检索建议失败", logger.String("error", err.Error()))
+		handleServiceError(c, err, "获取检索建议失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": result})
+}
+
+// UpdateKnowledgePoint 更新草稿状态的知识点，需要作者权限
 func (h *KnowledgePointHandler) UpdateKnowledgePoint(c *gin.Context) {
 	knowledgePointIDStr := c.Param("id")
 	knowledgePointID, err := uuid.Parse(knowledgePointIDStr)
@@ -238,58 +396,71 @@ func (h *KnowledgePointHandler) UpdateKnowledgePoint(c *gin.Context) {
 		return
 	}
 
-	// 获取现有知识点
-	knowledgePoint, err := h.knowledgePointRepo.GetByID(c.Request.Context(), knowledgePointID)
-	if err != nil {
-		logger.Error("获取知识点失败", logger.String("error", err.Error()))
-		c.JSON(http.StatusNotFound, gin.H{"error": "知识点不存在"})
+	// 客户端须通过If-Match提交获取知识点时的ETag
+	expectedVersion, ok := ifMatchVersion(c)
+	if !ok {
 		return
 	}
 
-	// 更新字段
-	if req.Title != nil {
-		knowledgePoint.Title = *req.Title
-	}
-	if req.Description != nil {
-		knowledgePoint.Description = *req.Description
-	}
-	if req.Content != nil {
-		knowledgePoint.Content = *req.Content
-	}
-	if req.Category != nil {
-		knowledgePoint.Category = *req.Category
-	}
-	if req.Difficulty != nil {
-		// 验证难度值
-		if *req.Difficulty != "beginner" && *req.Difficulty != "intermediate" && *req.Difficulty != "advanced" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "无效的难度值"})
-			return
-		}
-		knowledgePoint.Difficulty = *req.Difficulty
-	}
-	if req.Resources != nil {
-		knowledgePoint.Resources = *req.Resources
-	}
-	if req.Prerequisites != nil {
-		knowledgePoint.Prerequisites = *req.Prerequisites
-	}
-
-	// 保存更新
-	if err := h.knowledgePointRepo.Update(c.Request.Context(), knowledgePoint); err != nil {
+	// 在预期版本上更新字段并保存为新的修订
+	knowledgePoint, err := h.revisionService.Update(c.Request.Context(), knowledgePointID, expectedVersion, knowledgeEditor(c), req.ChangeSummary,
+		func(knowledgePoint *entities.KnowledgePoint) error {
+			if req.Title != nil {
+				knowledgePoint.Title = *req.Title
+			}
+			if req.Description != nil {
+				knowledgePoint.Description = *req.Description
+			}
+			if req.Content != nil {
+				knowledgePoint.Content = *req.Content
+			}
+			if req.Category != nil && *req.Category != knowledgePoint.Category {
+				if err := h.taxonomyService.ValidateCategory(c.Request.Context(), *req.Category); err != nil {
+					return err
+				}
+				knowledgePoint.Category = *req.Category
+			}
+			if req.Difficulty != nil {
+				// 验证难度值
+				if *req.Difficulty != "beginner" && *req.Difficulty != "intermediate" && *req.Difficulty != "advanced" {
+					return apperrors.New(apperrors.ErrorTypeValidation, http.StatusBadRequest, "无效的难度值")
+				}
+				knowledgePoint.Difficulty = *req.Difficulty
+			}
+			if req.Resources != nil {
+				knowledgePoint.Resources = *req.Resources
+			}
+			if req.Prerequisites != nil {
+				knowledgePoint.Prerequisites = *req.Prerequisites
+			}
+			if req.Tags != nil {
+				knowledgePoint.SetTags(req.Tags)
+			}
+			return nil
+		})
+	if err != nil {
 		logger.Error("更新知识点失败", logger.String("error", err.Error()))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "更新知识点失败"})
+		handleServiceError(c, err, "更新知识点失败")
 		return
 	}
 
 	// 转换响应
 	response := h.convertToKnowledgePointDetailResponse(knowledgePoint)
 
-	logger.Info("知识点更新成功", logger.String("knowledge_point_id", knowledgePoint.ID.String()))
+	logger.Info("知识点更新成功",
+		logger.String("knowledge_point_id", knowledgePoint.ID.String()),
+		logger.Int("version", knowledgePoint.Version))
+	c.Header("ETag", knowledgePointETag(knowledgePoint.Version))
 	c.JSON(http.StatusOK, gin.H{"data": response})
 }
 
-// DeleteKnowledgePoint 删除知识点
+// DeleteKnowledgePoint 删除知识点，需要审核员权限
 func (h *KnowledgePointHandler) DeleteKnowledgePoint(c *gin.Context) {
+	if !knowledgeEditor(c).Reviewer {
+		c.JSON(http.StatusForbidden, gin.H{"error": "只有审核员可以删除知识点"})
+		return
+	}
+
 	knowledgePointIDStr := c.Param("id")
 	knowledgePointID, err := uuid.Parse(knowledgePointIDStr)
 	if err != nil {
@@ -304,6 +475,8 @@ func (h *KnowledgePointHandler) DeleteKnowledgePoint(c *gin.Context) {
 		return
 	}
 
+	h.searchService.InvalidateSuggestions(c.Request.Context())
+
 	logger.Info("知识点删除成功", logger.String("knowledge_point_id", knowledgePointID.String()))
 	c.JSON(http.StatusOK, gin.H{"message": "删除成功"})
 }
@@ -312,6 +485,7 @@ func (h *KnowledgePointHandler) DeleteKnowledgePoint(c *gin.Context) {
 func (h *KnowledgePointHandler) convertToKnowledgePointDetailResponse(kp *entities.KnowledgePoint) KnowledgePointDetailResponse {
 	return KnowledgePointDetailResponse{
 		ID:            kp.ID.String(),
+		ExternalKey:   kp.ExternalKey,
 		Title:         kp.Title,
 		Description:   kp.Description,
 		Content:       kp.Content,
@@ -319,7 +493,47 @@ func (h *KnowledgePointHandler) convertToKnowledgePointDetailResponse(kp *entiti
 		Difficulty:    kp.Difficulty,
 		Resources:     kp.Resources,
 		Prerequisites: kp.Prerequisites,
+		Tags:          kp.TagList(),
+		Version:       kp.Version,
+		Status:        kp.Status,
+		AuthorID:      kp.AuthorID,
+		PublishAt:     kp.PublishAt,
+		PublishedAt:   kp.PublishedAt,
 		CreatedAt:     kp.CreatedAt,
 		UpdatedAt:     kp.UpdatedAt,
 	}
-}
\ No newline at end of file
+}
+
+// convertToKnowledgePointFields 按稀疏字段集转换知识点响应
+func (h *KnowledgePointHandler) convertToKnowledgePointFields(kp *entities.KnowledgePoint, fields []string) gin.H {
+	item := gin.H{}
+	for _, field := range fields {
+		switch field {
+		case "id":
+			item[field] = kp.ID.String()
+		case "title":
+			item[field] = kp.Title
+		case "description":
+			item[field] = kp.Description
+		case "content":
+			item[field] = kp.Content
+		case "category":
+			item[field] = kp.Category
+		case "difficulty":
+			item[field] = kp.Difficulty
+		case "resources":
+			item[field] = kp.Resources
+		case "prerequisites":
+			item[field] = kp.Prerequisites
+		case "tags":
+			item[field] = kp.TagList()
+		case "status":
+			item[field] = kp.Status
+		case "created_at":
+			item[field] = kp.CreatedAt
+		case "updated_at":
+			item[field] = kp.UpdatedAt
+		}
+	}
+	return item
+}
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/services"
+)
+
+// KnowledgeRelationHandler 相关知识点处理器
+type KnowledgeRelationHandler struct {
+	relationService *services.KnowledgeRelationService
+}
+
+// NewKnowledgeRelationHandler 创建相关知识点处理器
+func NewKnowledgeRelationHandler(relationService *services.KnowledgeRelationService) *KnowledgeRelationHandler {
+	return &KnowledgeRelationHandler{
+		relationService: relationService,
+	}
+}
+
+// AddKnowledgeRelationRequest 添加相关链接请求
+type AddKnowledgeRelationRequest struct {
+	RelatedID uuid.UUID `json:"related_id" binding:"required"`
+	Note      string    `json:"note"`
+}
+
+// KnowledgeRelatedResponse 相关知识点推荐响应
+type KnowledgeRelatedResponse struct {
+	ID           string  `json:"id"`
+	Title        string  `json:"title"`
+	Description  string  `json:"description"`
+	Category     string  `json:"category"`
+	Difficulty   string  `json:"difficulty"`
+	Status       string  `json:"status"`
+	Reason       string  `json:"reason"`      // curated、learning_paths或learners_also_studied
+	ReasonText   string  `json:"reason_text"` // 展示给学习者的推荐理由
+	Score        float64 `json:"score,omitempty"`
+	PathCount    int     `json:"path_count,omitempty"`
+	LearnerCount int     `json:"learner_count,omitempty"`
+}
+
+// ListRelated 获取知识点的相关推荐，作者维护的链接在前，其后按共现相似度排序
+func (h *KnowledgeRelationHandler) ListRelated(c *gin.Context) {
+	pointID, ok := parseKnowledgePointID(c)
+	if !ok {
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit <= 0 {
+		limit = 10
+	}
+
+	items, err := h.relationService.Related(c.Request.Context(), pointID, knowledgeEditor(c), limit)
+	if err != nil {
+		handleServiceError(c, err, "获取相关知识点失败")
+		return
+	}
+
+	responses := make([]*KnowledgeRelatedResponse, 0, len(items))
+	for _, item := range items {
+		responses = append(responses, &KnowledgeRelatedResponse{
+			ID:           item.Point.ID.String(),
+			Title:        item.Point.Title,
+			Description:  item.Point.Description,
+			Category:     item.Point.Category,
+			Difficulty:   item.Point.Difficulty,
+			Status:       item.Point.Status,
+			Reason:       string(item.Reason),
+			ReasonText:   item.ReasonText,
+			Score:        item.Score,
+			PathCount:    item.PathCount,
+			LearnerCount: item.LearnerCount,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  responses,
+		"count": len(responses),
+	})
+}
+
+// AddRelation 添加双向的相关链接，需要作者权限
+func (h *KnowledgeRelationHandler) AddRelation(c *gin.Context) {
+	pointID, ok := parseKnowledgePointID(c)
+	if !ok {
+		return
+	}
+	var req AddKnowledgeRelationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	relation, err := h.relationService.AddRelation(c.Request.Context(), pointID, req.RelatedID, req.Note, knowledgeEditor(c))
+	if err != nil {
+		handleServiceError(c, err, "添加相关知识点失败")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": gin.H{
+		"knowledge_point_id": relation.KnowledgePointID.String(),
+		"related_id":         relation.RelatedID.String(),
+		"note":               relation.Note,
+		"created_at":         relation.CreatedAt,
+	}})
+}
+
+// RemoveRelation 删除双向的相关链接，需要作者权限
+func (h *KnowledgeRelationHandler) RemoveRelation(c *gin.Context) {
+	pointID, ok := parseKnowledgePointID(c)
+	if !ok {
+		return
+	}
+	relatedID, err := uuid.Parse(c.Param("relatedId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "相关知识点ID格式无效"})
+		return
+	}
+
+	if err := h.relationService.RemoveRelation(c.Request.Context(), pointID, relatedID, knowledgeEditor(c)); err != nil {
+		handleServiceError(c, err, "删除相关知识点失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "相关链接已删除"})
+}
@@ -0,0 +1,302 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/services"
+	"sical-go-backend/pkg/logger"
+)
+
+// KnowledgeResourceHandler 知识点资源处理器
+type KnowledgeResourceHandler struct {
+	resourceService *services.KnowledgeResourceService
+}
+
+// NewKnowledgeResourceHandler 创建知识点资源处理器
+func NewKnowledgeResourceHandler(resourceService *services.KnowledgeResourceService) *KnowledgeResourceHandler {
+	return &KnowledgeResourceHandler{
+		resourceService: resourceService,
+	}
+}
+
+// KnowledgeResourceRequest 创建或修改资源请求
+type KnowledgeResourceRequest struct {
+	Type        string          `json:"type" binding:"required"`
+	Title       string          `json:"title" binding:"required"`
+	Description string          `json:"description"`
+	URL         string          `json:"url"`
+	Data        json.RawMessage `json:"data"`
+	Author      string          `json:"author"`
+	Source      string          `json:"source"`
+	Year        *int            `json:"year"`
+}
+
+// ReorderKnowledgeResourcesRequest 调整资源顺序请求，ids须包含知识点的全部资源
+type ReorderKnowledgeResourcesRequest struct {
+	IDs []uuid.UUID `json:"ids" binding:"required"`
+}
+
+// KnowledgeResourceResponse 资源响应，上传的文件附带签名下载链接
+type KnowledgeResourceResponse struct {
+	*entities.KnowledgeResource
+	Data              json.RawMessage `json:"data"`
+	DownloadURL       string          `json:"download_url,omitempty"`
+	DownloadExpiresAt *time.Time      `json:"download_expires_at,omitempty"`
+}
+
+// ListResources 获取知识点的资源，按排序位置正序
+func (h *KnowledgeResourceHandler) ListResources(c *gin.Context) {
+	pointID, ok := parseKnowledgePointID(c)
+	if !ok {
+		return
+	}
+
+	resources, err := h.resourceService.List(c.Request.Context(), pointID, knowledgeEditor(c))
+	if err != nil {
+		handleServiceError(c, err, "获取知识点资源失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  h.convertResources(resources),
+		"count": len(resources),
+	})
+}
+
+// CreateResource 添加外部链接、参考文献或内嵌数据类资源，需要作者权限且知识点为草稿
+func (h *KnowledgeResourceHandler) CreateResource(c *gin.Context) {
+	pointID, ok := parseKnowledgePointID(c)
+	if !ok {
+		return
+	}
+	var req KnowledgeResourceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resource, err := h.resourceService.Create(c.Request.Context(), pointID, knowledgeEditor(c), req.toInput())
+	if err != nil {
+		handleServiceError(c, err, "添加知识点资源失败")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": h.convertResource(resource)})
+}
+
+// UploadResource 上传图片或PDF作为资源，需要作者权限且知识点为草稿
+// 以multipart表单的file字段上传，可选type、title、description字段，type省略时按文件内容判断
+func (h *KnowledgeResourceHandler) UploadResource(c *gin.Context) {
+	pointID, ok := parseKnowledgePointID(c)
+	if !ok {
+		return
+	}
+	maxSize := h.resourceService.MaxUploadSize()
+	// 为表单其他字段预留1MB
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSize+1<<20)
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("上传文件不能超过%dMB", maxSize>>20)})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请通过file字段上传文件"})
+		return
+	}
+	if header.Size > maxSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("上传文件不能超过%dMB", maxSize>>20)})
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "读取上传文件失败"})
+		return
+	}
+	defer file.Close()
+
+	input := services.KnowledgeResourceInput{
+		Type:        c.PostForm("type"),
+		Title:       c.PostForm("title"),
+		Description: c.PostForm("description"),
+	}
+	upload := services.KnowledgeUpload{FileName: header.Filename, Reader: file}
+	resource, err := h.resourceService.Upload(c.Request.Context(), pointID, knowledgeEditor(c), input, upload)
+	if err != nil {
+		logger.Warn("上传知识点资源失败", logger.String("knowledge_point_id", pointID.String()), logger.String("error", err.Error()))
+		handleServiceError(c, err, "上传知识点资源失败")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": h.convertResource(resource)})
+}
+
+// UpdateResource 修改资源，需要作者权限且知识点为草稿
+func (h *KnowledgeResourceHandler) UpdateResource(c *gin.Context) {
+	pointID, ok := parseKnowledgePointID(c)
+	if !ok {
+		return
+	}
+	resourceID, ok := parseKnowledgeResourceID(c)
+	if !ok {
+		return
+	}
+	var req KnowledgeResourceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resource, err := h.resourceService.Update(c.Request.Context(), pointID, resourceID, knowledgeEditor(c), req.toInput())
+	if err != nil {
+		handleServiceError(c, err, "修改知识点资源失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": h.convertResource(resource)})
+}
+
+// DeleteResource 删除资源及其上传的文件，需要作者权限且知识点为草稿
+func (h *KnowledgeResourceHandler) DeleteResource(c *gin.Context) {
+	pointID, ok := parseKnowledgePointID(c)
+	if !ok {
+		return
+	}
+	resourceID, ok := parseKnowledgeResourceID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.resourceService.Delete(c.Request.Context(), pointID, resourceID, knowledgeEditor(c)); err != nil {
+		handleServiceError(c, err, "删除知识点资源失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "资源已删除"})
+}
+
+// ReorderResources 调整资源顺序，需要作者权限且知识点为草稿
+func (h *KnowledgeResourceHandler) ReorderResources(c *gin.Context) {
+	pointID, ok := parseKnowledgePointID(c)
+	if !ok {
+		return
+	}
+	var req ReorderKnowledgeResourcesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resources, err := h.resourceService.Reorder(c.Request.Context(), pointID, knowledgeEditor(c), req.IDs)
+	if err != nil {
+		handleServiceError(c, err, "调整知识点资源顺序失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  h.convertResources(resources),
+		"count": len(resources),
+	})
+}
+
+// ListBrokenLinks 分页获取失效的外部链接，需要作者或审核员权限
+func (h *KnowledgeResourceHandler) ListBrokenLinks(c *gin.Context) {
+	offset, limit := parsePagination(c)
+
+	resources, total, err := h.resourceService.ListBroken(c.Request.Context(), knowledgeEditor(c), offset, limit)
+	if err != nil {
+		handleServiceError(c, err, "获取失效链接失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":   h.convertResources(resources),
+		"count":  len(resources),
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// DownloadResource 通过签名链接下载上传的文件，无需登录
+func (h *KnowledgeResourceHandler) DownloadResource(c *gin.Context) {
+	resourceID, ok := parseKnowledgeResourceID(c)
+	if !ok {
+		return
+	}
+
+	resource, file, err := h.resourceService.OpenDownload(c.Request.Context(), resourceID, c.Query("expires"), c.Query("signature"))
+	if err != nil {
+		handleServiceError(c, err, "下载文件失败")
+		return
+	}
+	defer file.Close()
+
+	c.Header("Content-Type", resource.ContentType)
+	c.Header("Content-Length", strconv.FormatInt(resource.Size, 10))
+	c.Header("Content-Disposition", mime.FormatMediaType("inline", map[string]string{"filename": resource.FileName}))
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("Cache-Control", "private, max-age=300")
+	c.Status(http.StatusOK)
+	if _, err := io.Copy(c.Writer, file); err != nil {
+		logger.Warn("发送下载文件失败", logger.String("resource_id", resourceID.String()), logger.String("error", err.Error()))
+	}
+}
+
+// toInput 转换为服务层输入
+func (r *KnowledgeResourceRequest) toInput() services.KnowledgeResourceInput {
+	return services.KnowledgeResourceInput{
+		Type:        r.Type,
+		Title:       r.Title,
+		Description: r.Description,
+		URL:         r.URL,
+		Data:        r.Data,
+		Author:      r.Author,
+		Source:      r.Source,
+		Year:        r.Year,
+	}
+}
+
+// convertResource 转换资源响应，上传的文件生成签名下载链接
+func (h *KnowledgeResourceHandler) convertResource(resource *entities.KnowledgeResource) *KnowledgeResourceResponse {
+	response := &KnowledgeResourceResponse{
+		KnowledgeResource: resource,
+		Data:              json.RawMessage(resource.Data),
+	}
+	if resource.IsUpload() {
+		url, expiresAt := h.resourceService.DownloadURL(resource)
+		response.DownloadURL = url
+		response.DownloadExpiresAt = &expiresAt
+	}
+	return response
+}
+
+// convertResources 批量转换资源响应
+func (h *KnowledgeResourceHandler) convertResources(resources []*entities.KnowledgeResource) []*KnowledgeResourceResponse {
+	responses := make([]*KnowledgeResourceResponse, 0, len(resources))
+	for _, resource := range resources {
+		responses = append(responses, h.convertResource(resource))
+	}
+	return responses
+}
+
+// parseKnowledgeResourceID 解析路径中的资源ID，失败时已写入响应
+func parseKnowledgeResourceID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("resourceId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "资源ID格式无效"})
+		return uuid.Nil, false
+	}
+	return id, true
+}
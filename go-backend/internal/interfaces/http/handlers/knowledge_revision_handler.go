@@ -0,0 +1,279 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"sical-go-backend/internal/api/middleware"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/services"
+	"sical-go-backend/pkg/logger"
+)
+
+// KnowledgeRevisionHandler 知识点修订处理器
+type KnowledgeRevisionHandler struct {
+	revisionService *services.KnowledgeRevisionService
+}
+
+// NewKnowledgeRevisionHandler 创建知识点修订处理器
+func NewKnowledgeRevisionHandler(revisionService *services.KnowledgeRevisionService) *KnowledgeRevisionHandler {
+	return &KnowledgeRevisionHandler{
+		revisionService: revisionService,
+	}
+}
+
+// RestoreKnowledgeRevisionRequest 恢复修订请求，请求体可省略
+type RestoreKnowledgeRevisionRequest struct {
+	ChangeSummary string `json:"change_summary"`
+}
+
+// KnowledgeRevisionSummaryResponse 修订摘要响应
+type KnowledgeRevisionSummaryResponse struct {
+	Version       int            `json:"version"`
+	Action        string         `json:"action"`
+	Author        *CommentAuthor `json:"author,omitempty"`
+	ChangeSummary string         `json:"change_summary"`
+	RestoredFrom  *int           `json:"restored_from,omitempty"`
+	CreatedAt     time.Time      `json:"created_at"`
+}
+
+// KnowledgeRevisionResponse 修订详情响应，包含该版本的完整内容
+type KnowledgeRevisionResponse struct {
+	KnowledgeRevisionSummaryResponse
+	Title         string   `json:"title"`
+	Description   string   `json:"description"`
+	Content       string   `json:"content"`
+	Category      string   `json:"category"`
+	Difficulty    string   `json:"difficulty"`
+	Resources     string   `json:"resources"`
+	Prerequisites string   `json:"prerequisites"`
+	Tags          []string `json:"tags"`
+}
+
+// ListRevisions 分页获取知识点的修订列表，按版本号倒序
+func (h *KnowledgeRevisionHandler) ListRevisions(c *gin.Context) {
+	if !requireKnowledgeEditorial(c) {
+		return
+	}
+	id, ok := parseKnowledgePointID(c)
+	if !ok {
+		return
+	}
+	offset, limit := parsePagination(c)
+
+	revisions, total, err := h.revisionService.ListRevisions(c.Request.Context(), id, offset, limit)
+	if err != nil {
+		handleServiceError(c, err, "获取修订列表失败")
+		return
+	}
+
+	responses := make([]*KnowledgeRevisionSummaryResponse, 0, len(revisions))
+	for _, revision := range revisions {
+		responses = append(responses, convertToKnowledgeRevisionSummary(revision))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":   responses,
+		"count":  len(responses),
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// GetRevision 获取知识点指定版本的完整内容
+func (h *KnowledgeRevisionHandler) GetRevision(c *gin.Context) {
+	if !requireKnowledgeEditorial(c) {
+		return
+	}
+	id, ok := parseKnowledgePointID(c)
+	if !ok {
+		return
+	}
+	version, ok := parseRevisionVersion(c, c.Param("version"))
+	if !ok {
+		return
+	}
+
+	revision, err := h.revisionService.GetRevision(c.Request.Context(), id, version)
+	if err != nil {
+		handleServiceError(c, err, "获取修订失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": convertToKnowledgeRevisionResponse(revision)})
+}
+
+// DiffRevisions 比较知识点任意两个版本(from、to)，返回有变化的字段
+func (h *KnowledgeRevisionHandler) DiffRevisions(c *gin.Context) {
+	if !requireKnowledgeEditorial(c) {
+		return
+	}
+	id, ok := parseKnowledgePointID(c)
+	if !ok {
+		return
+	}
+	from, ok := parseRevisionVersion(c, c.Query("from"))
+	if !ok {
+		return
+	}
+	to, ok := parseRevisionVersion(c, c.Query("to"))
+	if !ok {
+		return
+	}
+
+	diff, err := h.revisionService.Diff(c.Request.Context(), id, from, to)
+	if err != nil {
+		handleServiceError(c, err, "比较修订失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{
+		"from":    convertToKnowledgeRevisionSummary(diff.From),
+		"to":      convertToKnowledgeRevisionSummary(diff.To),
+		"changes": diff.Changes,
+	}})
+}
+
+// RestoreRevision 将草稿恢复为指定版本的内容，须通过If-Match提交当前版本的ETag
+func (h *KnowledgeRevisionHandler) RestoreRevision(c *gin.Context) {
+	id, ok := parseKnowledgePointID(c)
+	if !ok {
+		return
+	}
+	version, ok := parseRevisionVersion(c, c.Param("version"))
+	if !ok {
+		return
+	}
+	expectedVersion, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	var req RestoreKnowledgeRevisionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "请求参数无效"})
+			return
+		}
+	}
+
+	point, err := h.revisionService.Restore(c.Request.Context(), id, version, expectedVersion, knowledgeEditor(c), req.ChangeSummary)
+	if err != nil {
+		logger.Error("恢复知识点修订失败",
+			logger.String("knowledge_point_id", id.String()),
+			logger.Int("version", version),
+			logger.String("error", err.Error()))
+		handleServiceError(c, err, "恢复修订失败")
+		return
+	}
+
+	logger.Info("知识点已恢复",
+		logger.String("knowledge_point_id", id.String()),
+		logger.Int("restored_from", version),
+		logger.Int("version", point.Version))
+	c.Header("ETag", knowledgePointETag(point.Version))
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{
+		"id":      point.ID.String(),
+		"version": point.Version,
+	}})
+}
+
+// parseKnowledgePointID 解析路径中的知识点ID，失败时已写入响应
+func parseKnowledgePointID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "知识点ID格式无效"})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// parseRevisionVersion 解析版本号，失败时已写入响应
+func parseRevisionVersion(c *gin.Context, value string) (int, bool) {
+	version, err := strconv.Atoi(value)
+	if err != nil || version <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "版本号无效"})
+		return 0, false
+	}
+	return version, true
+}
+
+// knowledgePointETag 由版本号生成知识点的ETag
+func knowledgePointETag(version int) string {
+	return strconv.Quote(strconv.Itoa(version))
+}
+
+// ifMatchVersion 从If-Match请求头解析客户端持有的知识点版本号，失败时已写入响应
+func ifMatchVersion(c *gin.Context) (int, bool) {
+	value := strings.TrimSpace(c.GetHeader("If-Match"))
+	if value == "" {
+		c.JSON(http.StatusPreconditionRequired, gin.H{"error": "缺少If-Match请求头，请先获取知识点的ETag"})
+		return 0, false
+	}
+	version, err := strconv.Atoi(strings.Trim(strings.TrimPrefix(value, "W/"), `"`))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "If-Match请求头格式无效"})
+		return 0, false
+	}
+	return version, true
+}
+
+// knowledgeEditor 当前请求的知识点操作者及其作者、审核员权限
+func knowledgeEditor(c *gin.Context) services.KnowledgeEditor {
+	editor := services.KnowledgeEditor{
+		Author:   middleware.HasAnyRole(c, "author", "admin", "super_admin"),
+		Reviewer: middleware.HasAnyRole(c, "reviewer", "admin", "super_admin"),
+	}
+	if userID, ok := c.Get("user_id"); ok {
+		if id, ok := userID.(uint); ok {
+			editor.UserID = &id
+		}
+	}
+	return editor
+}
+
+// requireKnowledgeEditorial 要求当前用户为作者或审核员，失败时已写入响应
+func requireKnowledgeEditorial(c *gin.Context) bool {
+	if !knowledgeEditor(c).IsEditorial() {
+		c.JSON(http.StatusForbidden, gin.H{"error": "只有作者或审核员可以查看修订记录"})
+		return false
+	}
+	return true
+}
+
+// convertToKnowledgeRevisionSummary 转换修订摘要响应
+func convertToKnowledgeRevisionSummary(revision *entities.KnowledgePointRevision) *KnowledgeRevisionSummaryResponse {
+	response := &KnowledgeRevisionSummaryResponse{
+		Version:       revision.Version,
+		Action:        revision.Action,
+		ChangeSummary: revision.ChangeSummary,
+		RestoredFrom:  revision.RestoredFrom,
+		CreatedAt:     revision.CreatedAt,
+	}
+	if revision.Author != nil {
+		response.Author = &CommentAuthor{ID: revision.Author.ID, Username: revision.Author.Username}
+	}
+	return response
+}
+
+// convertToKnowledgeRevisionResponse 转换修订详情响应
+func convertToKnowledgeRevisionResponse(revision *entities.KnowledgePointRevision) *KnowledgeRevisionResponse {
+	snapshot := &entities.KnowledgePoint{}
+	revision.ApplyTo(snapshot)
+	return &KnowledgeRevisionResponse{
+		KnowledgeRevisionSummaryResponse: *convertToKnowledgeRevisionSummary(revision),
+		Title:                            revision.Title,
+		Description:                      revision.Description,
+		Content:                          revision.Content,
+		Category:                         revision.Category,
+		Difficulty:                       revision.Difficulty,
+		Resources:                        revision.Resources,
+		Prerequisites:                    revision.Prerequisites,
+		Tags:                             snapshot.TagList(),
+	}
+}
@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"sical-go-backend/internal/domain/repositories"
+	"sical-go-backend/internal/domain/services"
+	"sical-go-backend/pkg/logger"
+)
+
+const (
+	// maxKnowledgeImportSize 导入文件的最大字节数
+	maxKnowledgeImportSize = 20 << 20
+	// maxKnowledgeArchiveSize Markdown压缩包解压后的最大字节数
+	maxKnowledgeArchiveSize = 100 << 20
+)
+
+// knowledgeExportContentTypes 各导出格式的文件扩展名和Content-Type
+var knowledgeExportContentTypes = map[services.KnowledgeTransferFormat][2]string{
+	services.KnowledgeFormatCSV:      {"csv", "text/csv; charset=utf-8"},
+	services.KnowledgeFormatJSONL:    {"jsonl", "application/x-ndjson"},
+	services.KnowledgeFormatMarkdown: {"zip", "application/zip"},
+}
+
+// KnowledgeTransferHandler 知识点批量导入导出处理器
+type KnowledgeTransferHandler struct {
+	transferService *services.KnowledgeTransferService
+}
+
+// NewKnowledgeTransferHandler 创建知识点批量导入导出处理器
+func NewKnowledgeTransferHandler(transferService *services.KnowledgeTransferService) *KnowledgeTransferHandler {
+	return &KnowledgeTransferHandler{
+		transferService: transferService,
+	}
+}
+
+// ImportKnowledgePoints 批量导入知识点，需要作者权限
+// 以multipart表单的file字段上传，format为csv、jsonl或markdown(Markdown文件的zip压缩包)，省略时按扩展名判断；
+// dry_run=true时只校验不保存。存在任何问题时返回422和逐行的问题列表，不保存任何记录
+func (h *KnowledgeTransferHandler) ImportKnowledgePoints(c *gin.Context) {
+	header, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请通过file字段上传导入文件"})
+		return
+	}
+	if header.Size > maxKnowledgeImportSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("导入文件不能超过%dMB", maxKnowledgeImportSize>>20)})
+		return
+	}
+	format := services.KnowledgeTransferFormat(c.Query("format"))
+	if format == "" {
+		format = knowledgeFormatFromFilename(header.Filename)
+	}
+	if !format.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的导入格式，可选csv、jsonl、markdown"})
+		return
+	}
+	dryRun, _ := strconv.ParseBool(c.Query("dry_run"))
+
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "读取导入文件失败"})
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(io.LimitReader(file, maxKnowledgeImportSize))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "读取导入文件失败"})
+		return
+	}
+
+	var batch *services.KnowledgeImportBatch
+	switch format {
+	case services.KnowledgeFormatCSV:
+		batch, err = services.DecodeKnowledgeCSV(bytes.NewReader(data))
+	case services.KnowledgeFormatJSONL:
+		batch, err = services.DecodeKnowledgeJSONL(bytes.NewReader(data))
+	case services.KnowledgeFormatMarkdown:
+		archive, zipErr := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if zipErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Markdown格式须上传zip压缩包"})
+			return
+		}
+		var size uint64
+		for _, entry := range archive.File {
+			size += entry.UncompressedSize64
+		}
+		if size > maxKnowledgeArchiveSize {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("压缩包解压后不能超过%dMB", maxKnowledgeArchiveSize>>20)})
+			return
+		}
+		batch, err = services.DecodeKnowledgeMarkdown(archive)
+	}
+	if err != nil {
+		handleServiceError(c, err, "解析导入文件失败")
+		return
+	}
+
+	report, err := h.transferService.Import(c.Request.Context(), batch, knowledgeEditor(c), dryRun)
+	if err != nil {
+		logger.Error("导入知识点失败", logger.String("error", err.Error()))
+		handleServiceError(c, err, "导入知识点失败")
+		return
+	}
+
+	status := http.StatusOK
+	if len(report.Errors) > 0 {
+		status = http.StatusUnprocessableEntity
+	}
+	if report.Applied {
+		logger.Info("知识点导入完成",
+			logger.Int("created", report.Created),
+			logger.Int("updated", report.Updated),
+			logger.Int("unchanged", report.Unchanged))
+	}
+	c.JSON(status, gin.H{"data": report})
+}
+
+// ExportKnowledgePoints 导出知识点，需要作者或审核员权限
+// format为csv、jsonl(默认)或markdown(Markdown文件的zip压缩包)，可按category、status筛选，导出结果可直接重新导入
+func (h *KnowledgeTransferHandler) ExportKnowledgePoints(c *gin.Context) {
+	format := services.KnowledgeTransferFormat(c.DefaultQuery("format", string(services.KnowledgeFormatJSONL)))
+	if !format.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的导出格式，可选csv、jsonl、markdown"})
+		return
+	}
+	filter := repositories.KnowledgeExportFilter{
+		Category: c.Query("category"),
+		Status:   c.Query("status"),
+	}
+
+	records, err := h.transferService.Export(c.Request.Context(), filter, knowledgeEditor(c))
+	if err != nil {
+		handleServiceError(c, err, "导出知识点失败")
+		return
+	}
+
+	var buf bytes.Buffer
+	switch format {
+	case services.KnowledgeFormatCSV:
+		err = services.EncodeKnowledgeCSV(&buf, records)
+	case services.KnowledgeFormatJSONL:
+		err = services.EncodeKnowledgeJSONL(&buf, records)
+	case services.KnowledgeFormatMarkdown:
+		err = writeKnowledgeArchive(&buf, records)
+	}
+	if err != nil {
+		logger.Error("生成导出文件失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "导出知识点失败"})
+		return
+	}
+
+	contentType := knowledgeExportContentTypes[format]
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="knowledge-points.%s"`, contentType[0]))
+	c.Data(http.StatusOK, contentType[1], buf.Bytes())
+}
+
+// knowledgeFormatFromFilename 根据上传文件的扩展名判断导入格式
+func knowledgeFormatFromFilename(filename string) services.KnowledgeTransferFormat {
+	switch strings.ToLower(path.Ext(filename)) {
+	case ".csv":
+		return services.KnowledgeFormatCSV
+	case ".jsonl", ".ndjson":
+		return services.KnowledgeFormatJSONL
+	case ".zip":
+		return services.KnowledgeFormatMarkdown
+	}
+	return ""
+}
+
+// writeKnowledgeArchive 将知识点写为Markdown文件的zip压缩包
+func writeKnowledgeArchive(w io.Writer, records []*services.KnowledgeRecord) error {
+	files, err := services.EncodeKnowledgeMarkdown(records)
+	if err != nil {
+		return err
+	}
+	archive := zip.NewWriter(w)
+	for _, file := range files {
+		entry, err := archive.Create(file.Name)
+		if err != nil {
+			return err
+		}
+		if _, err := entry.Write(file.Data); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
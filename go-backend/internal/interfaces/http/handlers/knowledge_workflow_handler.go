@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/services"
+	"sical-go-backend/pkg/logger"
+)
+
+// KnowledgeWorkflowHandler 知识点编辑流程处理器
+type KnowledgeWorkflowHandler struct {
+	workflowService *services.KnowledgeWorkflowService
+}
+
+// NewKnowledgeWorkflowHandler 创建知识点编辑流程处理器
+func NewKnowledgeWorkflowHandler(workflowService *services.KnowledgeWorkflowService) *KnowledgeWorkflowHandler {
+	return &KnowledgeWorkflowHandler{
+		workflowService: workflowService,
+	}
+}
+
+// KnowledgeTransitionRequest 编辑流程操作请求，请求体可省略
+type KnowledgeTransitionRequest struct {
+	Comment   string     `json:"comment"`
+	PublishAt *time.Time `json:"publish_at"` // 仅发布操作使用，晚于当前时间时定时发布
+}
+
+// KnowledgeReviewEventResponse 编辑流程记录响应
+type KnowledgeReviewEventResponse struct {
+	Action     string         `json:"action"`
+	FromStatus string         `json:"from_status"`
+	ToStatus   string         `json:"to_status"`
+	Actor      *CommentAuthor `json:"actor,omitempty"`
+	Version    int            `json:"version"`
+	Comment    string         `json:"comment"`
+	PublishAt  *time.Time     `json:"publish_at,omitempty"`
+	CreatedAt  time.Time      `json:"created_at"`
+}
+
+// Transition 执行编辑流程操作
+// action为submit、approve、reject、publish、archive或reopen，reject须填写审核意见
+func (h *KnowledgeWorkflowHandler) Transition(c *gin.Context) {
+	id, ok := parseKnowledgePointID(c)
+	if !ok {
+		return
+	}
+
+	var req KnowledgeTransitionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "请求参数无效"})
+			return
+		}
+	}
+
+	action := c.Param("action")
+	point, err := h.workflowService.Transition(c.Request.Context(), id, action, knowledgeEditor(c), services.KnowledgeTransitionInput{
+		Comment:   req.Comment,
+		PublishAt: req.PublishAt,
+	})
+	if err != nil {
+		logger.Error("知识点流程操作失败",
+			logger.String("knowledge_point_id", id.String()),
+			logger.String("action", action),
+			logger.String("error", err.Error()))
+		handleServiceError(c, err, "知识点流程操作失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{
+		"id":           point.ID.String(),
+		"status":       point.Status,
+		"version":      point.Version,
+		"publish_at":   point.PublishAt,
+		"published_at": point.PublishedAt,
+	}})
+}
+
+// History 获取知识点的编辑流程记录和审核意见
+func (h *KnowledgeWorkflowHandler) History(c *gin.Context) {
+	if !requireKnowledgeEditorial(c) {
+		return
+	}
+	id, ok := parseKnowledgePointID(c)
+	if !ok {
+		return
+	}
+
+	events, err := h.workflowService.History(c.Request.Context(), id)
+	if err != nil {
+		handleServiceError(c, err, "获取流程记录失败")
+		return
+	}
+
+	responses := make([]*KnowledgeReviewEventResponse, 0, len(events))
+	for _, event := range events {
+		responses = append(responses, convertToKnowledgeReviewEventResponse(event))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  responses,
+		"count": len(responses),
+	})
+}
+
+// convertToKnowledgeReviewEventResponse 转换编辑流程记录响应
+func convertToKnowledgeReviewEventResponse(event *entities.KnowledgeReviewEvent) *KnowledgeReviewEventResponse {
+	response := &KnowledgeReviewEventResponse{
+		Action:     event.Action,
+		FromStatus: event.FromStatus,
+		ToStatus:   event.ToStatus,
+		Version:    event.Version,
+		Comment:    event.Comment,
+		PublishAt:  event.PublishAt,
+		CreatedAt:  event.CreatedAt,
+	}
+	if event.Actor != nil {
+		response.Actor = &CommentAuthor{ID: event.Actor.ID, Username: event.Actor.Username}
+	}
+	return response
+}
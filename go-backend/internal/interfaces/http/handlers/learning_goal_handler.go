@@ -1,13 +1,16 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
 	"sical-go-backend/internal/domain/services"
 	"sical-go-backend/pkg/logger"
 )
@@ -15,13 +18,29 @@ import (
 // LearningGoalHandler 学习目标处理器
 type LearningGoalHandler struct {
 	goalService     *services.GoalAnalysisService
-	goalRepository  interface{} // 这里应该是具体的仓储接口
+	jobService      *services.AnalysisJobService
+	progressService *services.ProgressService
+	statusService   *services.StatusService
+	taxonomyService *services.TaxonomyService
+	goalRepository  repositories.LearningGoalRepository
 }
 
 // NewLearningGoalHandler 创建学习目标处理器
-func NewLearningGoalHandler(goalService *services.GoalAnalysisService) *LearningGoalHandler {
+func NewLearningGoalHandler(
+	goalService *services.GoalAnalysisService,
+	jobService *services.AnalysisJobService,
+	progressService *services.ProgressService,
+	statusService *services.StatusService,
+	taxonomyService *services.TaxonomyService,
+	goalRepository repositories.LearningGoalRepository,
+) *LearningGoalHandler {
 	return &LearningGoalHandler{
-		goalService: goalService,
+		goalService:     goalService,
+		jobService:      jobService,
+		progressService: progressService,
+		statusService:   statusService,
+		taxonomyService: taxonomyService,
+		goalRepository:  goalRepository,
 	}
 }
 
@@ -35,20 +54,26 @@ type CreateGoalRequest struct {
 }
 
 // UpdateGoalRequest 更新学习目标请求
+// 进度由步骤完成情况自动计算，不支持手动修改
 type UpdateGoalRequest struct {
-	Title       *string    `json:"title,omitempty"`
+	Title       *string    `json:"title,omitempty" binding:"omitempty,min=1,max=255"`
 	Description *string    `json:"description,omitempty"`
 	Category    *string    `json:"category,omitempty"`
-	Difficulty  *string    `json:"difficulty,omitempty"`
-	Status      *string    `json:"status,omitempty"`
+	Difficulty  *string    `json:"difficulty,omitempty" binding:"omitempty,oneof=beginner intermediate advanced"`
+	Status      *string    `json:"status,omitempty" binding:"omitempty,oneof=active completed paused"`
 	TargetDate  *time.Time `json:"target_date,omitempty"`
-	Progress    *float64   `json:"progress,omitempty"`
+}
+
+// UpdateGoalStatusRequest 更新学习目标状态请求
+type UpdateGoalStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=active completed paused"`
+	Reason string `json:"reason"`
 }
 
 // GoalResponse 学习目标响应
 type GoalResponse struct {
 	ID          string     `json:"id"`
-	UserID      string     `json:"user_id"`
+	UserID      uint       `json:"user_id"`
 	Title       string     `json:"title"`
 	Description string     `json:"description"`
 	Category    string     `json:"category"`
@@ -56,6 +81,7 @@ type GoalResponse struct {
 	Status      string     `json:"status"`
 	TargetDate  *time.Time `json:"target_date"`
 	Progress    float64    `json:"progress"`
+	CompletedAt *time.Time `json:"completed_at"`
 	CreatedAt   time.Time  `json:"created_at"`
 	UpdatedAt   time.Time  `json:"updated_at"`
 }
@@ -65,17 +91,37 @@ type AnalysisResponse struct {
 	ID              string    `json:"id"`
 	GoalID          string    `json:"goal_id"`
 	AnalysisType    string    `json:"analysis_type"`
-	Result          string    `json:"result"`
-	Recommendations string    `json:"recommendations"`
+	Analyzer        string    `json:"analyzer"`
+	Result          entities.AnalysisResult  `json:"result"`
+	Recommendations entities.Recommendations `json:"recommendations"`
+	ConfidenceScore float64   `json:"confidence_score"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// ComprehensiveAnalysisResponse 综合分析响应，由各单项分析的最新结果组合而成
+type ComprehensiveAnalysisResponse struct {
+	GoalID          string                       `json:"goal_id"`
+	AnalysisType    string                       `json:"analysis_type"`
+	Result          entities.AnalysisResult      `json:"result"`
+	Recommendations entities.Recommendations     `json:"recommendations"`
+	ConfidenceScore float64                      `json:"confidence_score"`
+	Components      []*AnalysisComponentResponse `json:"components"`
+	MissingTypes    []string                     `json:"missing_types"`
+}
+
+// AnalysisComponentResponse 综合分析中的单项分析
+type AnalysisComponentResponse struct {
+	ID              string    `json:"id"`
+	AnalysisType    string    `json:"analysis_type"`
+	Analyzer        string    `json:"analyzer"`
 	ConfidenceScore float64   `json:"confidence_score"`
 	CreatedAt       time.Time `json:"created_at"`
 }
 
 // CreateGoal 创建学习目标
 func (h *LearningGoalHandler) CreateGoal(c *gin.Context) {
-	userID, exists := c.Get("user_id")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "用户未认证"})
+	userID, ok := currentUserID(c)
+	if !ok {
 		return
 	}
 
@@ -86,55 +132,33 @@ func (h *LearningGoalHandler) CreateGoal(c *gin.Context) {
 		return
 	}
 
-	// 将用户ID转换为UUID（假设中间件存储的是字符串）
-	userUUID, err := uuid.Parse(userID.(string))
-	if err != nil {
-		logger.Error("用户ID格式无效", logger.String("user_id", userID.(string)))
-		c.JSON(http.StatusBadRequest, gin.H{"error": "用户ID格式无效"})
+	// 校验类别
+	if err := h.taxonomyService.ValidateCategory(c.Request.Context(), req.Category); err != nil {
+		handleServiceError(c, err, "校验类别失败")
 		return
 	}
 
 	// 创建学习目标实体
 	goal := &entities.LearningGoal{
-		UserID:      userUUID,
+		ID:          uuid.New(),
+		UserID:      userID,
 		Title:       req.Title,
 		Description: req.Description,
 		Category:    req.Category,
 		Difficulty:  req.Difficulty,
-		Status:      "active",
+		Status:      string(entities.GoalStatusActive),
 		TargetDate:  req.TargetDate,
 		Progress:    0,
 	}
 
-	// 这里需要调用仓储来创建目标
-	// err = h.goalRepository.Create(c.Request.Context(), goal)
-	// if err != nil {
-	// 	logger.Error("创建学习目标失败", logger.String("error", err.Error()))
-	// 	c.JSON(http.StatusInternalServerError, gin.H{"error": "创建学习目标失败"})
-	// 	return
-	// }
-
-	// 暂时模拟创建成功
-	goal.ID = uuid.New()
-	goal.CreatedAt = time.Now()
-	goal.UpdatedAt = time.Now()
-
-	response := &GoalResponse{
-		ID:          goal.ID.String(),
-		UserID:      goal.UserID.String(),
-		Title:       goal.Title,
-		Description: goal.Description,
-		Category:    goal.Category,
-		Difficulty:  goal.Difficulty,
-		Status:      goal.Status,
-		TargetDate:  goal.TargetDate,
-		Progress:    goal.Progress,
-		CreatedAt:   goal.CreatedAt,
-		UpdatedAt:   goal.UpdatedAt,
+	if err := h.goalRepository.Create(c.Request.Context(), goal); err != nil {
+		logger.Error("创建学习目标失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "创建学习目标失败"})
+		return
 	}
 
 	logger.Info("学习目标创建成功", logger.String("goal_id", goal.ID.String()))
-	c.JSON(http.StatusCreated, gin.H{"data": response})
+	c.JSON(http.StatusCreated, gin.H{"data": h.convertToGoalResponse(goal)})
 }
 
 // GetGoal 获取学习目标详情
@@ -146,41 +170,23 @@ func (h *LearningGoalHandler) GetGoal(c *gin.Context) {
 		return
 	}
 
-	// 这里需要调用仓储来获取目标
-	// goal, err := h.goalRepository.GetByID(c.Request.Context(), goalID)
-	// if err != nil {
-	// 	logger.Error("获取学习目标失败", logger.String("error", err.Error()))
-	// 	c.JSON(http.StatusNotFound, gin.H{"error": "学习目标不存在"})
-	// 	return
-	// }
-
-	// 暂时模拟返回数据
-	response := &GoalResponse{
-		ID:          goalID.String(),
-		UserID:      uuid.New().String(),
-		Title:       "示例学习目标",
-		Description: "这是一个示例学习目标",
-		Category:    "programming",
-		Difficulty:  "intermediate",
-		Status:      "active",
-		Progress:    25.5,
-		CreatedAt:   time.Now(),
-		UpdatedAt:   time.Now(),
+	goal, ok := h.loadOwnedGoal(c, goalID)
+	if !ok {
+		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"data": response})
+	c.JSON(http.StatusOK, gin.H{"data": h.convertToGoalResponse(goal)})
 }
 
 // ListGoals 获取用户的学习目标列表
 func (h *LearningGoalHandler) ListGoals(c *gin.Context) {
-	userID, exists := c.Get("user_id")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "用户未认证"})
+	userID, ok := currentUserID(c)
+	if !ok {
 		return
 	}
 
 	// 获取查询参数
-	_ = c.Query("status") // 暂时不使用
+	status := c.Query("status")
 	pageStr := c.DefaultQuery("page", "1")
 	limitStr := c.DefaultQuery("limit", "10")
 
@@ -194,41 +200,30 @@ func (h *LearningGoalHandler) ListGoals(c *gin.Context) {
 		limit = 10
 	}
 
-	// 这里需要调用仓储来获取目标列表
-	// userUUID, _ := uuid.Parse(userID.(string))
-	// var goals []*entities.LearningGoal
-	// if status != "" {
-	// 	goals, err = h.goalRepository.GetByStatus(c.Request.Context(), userUUID, status)
-	// } else {
-	// 	goals, err = h.goalRepository.GetByUserID(c.Request.Context(), userUUID)
-	// }
-
-	// 暂时模拟返回数据
-	responses := []*GoalResponse{
-		{
-			ID:          uuid.New().String(),
-			UserID:      userID.(string),
-			Title:       "学习Go语言",
-			Description: "掌握Go语言基础语法和并发编程",
-			Category:    "programming",
-			Difficulty:  "intermediate",
-			Status:      "active",
-			Progress:    30.0,
-			CreatedAt:   time.Now(),
-			UpdatedAt:   time.Now(),
-		},
-		{
-			ID:          uuid.New().String(),
-			UserID:      userID.(string),
-			Title:       "学习React",
-			Description: "掌握React框架和现代前端开发",
-			Category:    "programming",
-			Difficulty:  "advanced",
-			Status:      "active",
-			Progress:    15.5,
-			CreatedAt:   time.Now(),
-			UpdatedAt:   time.Now(),
-		},
+	var goals []*entities.LearningGoal
+	if status != "" {
+		goals, err = h.goalRepository.GetByStatus(c.Request.Context(), userID, status)
+	} else {
+		goals, err = h.goalRepository.GetByUserID(c.Request.Context(), userID)
+	}
+	if err != nil {
+		logger.Error("获取学习目标列表失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取学习目标列表失败"})
+		return
+	}
+
+	total := len(goals)
+	start := (page - 1) * limit
+	if start > total {
+		start = total
+	}
+	end := start + limit
+	if end > total {
+		end = total
+	}
+	responses := make([]*GoalResponse, 0, end-start)
+	for _, goal := range goals[start:end] {
+		responses = append(responses, h.convertToGoalResponse(goal))
 	}
 
 	c.JSON(http.StatusOK, gin.H{
@@ -236,7 +231,7 @@ func (h *LearningGoalHandler) ListGoals(c *gin.Context) {
 		"pagination": gin.H{
 			"page":  page,
 			"limit": limit,
-			"total": len(responses),
+			"total": total,
 		},
 	})
 }
@@ -257,42 +252,128 @@ func (h *LearningGoalHandler) UpdateGoal(c *gin.Context) {
 		return
 	}
 
-	// 这里需要调用仓储来更新目标
-	// goal, err := h.goalRepository.GetByID(c.Request.Context(), goalID)
-	// if err != nil {
-	// 	c.JSON(http.StatusNotFound, gin.H{"error": "学习目标不存在"})
-	// 	return
-	// }
+	ctx := c.Request.Context()
+	goal, ok := h.loadOwnedGoal(c, goalID)
+	if !ok {
+		return
+	}
+	previous := *goal
 
 	// 更新字段
-	// if req.Title != nil {
-	// 	goal.Title = *req.Title
-	// }
-	// ... 其他字段更新
-
-	// err = h.goalRepository.Update(c.Request.Context(), goal)
-	// if err != nil {
-	// 	logger.Error("更新学习目标失败", logger.String("error", err.Error()))
-	// 	c.JSON(http.StatusInternalServerError, gin.H{"error": "更新学习目标失败"})
-	// 	return
-	// }
-
-	// 暂时模拟更新成功
-	response := &GoalResponse{
-		ID:          goalID.String(),
-		UserID:      uuid.New().String(),
-		Title:       "更新后的学习目标",
-		Description: "这是更新后的学习目标描述",
-		Category:    "programming",
-		Difficulty:  "intermediate",
-		Status:      "active",
-		Progress:    50.0,
-		CreatedAt:   time.Now().Add(-24 * time.Hour),
-		UpdatedAt:   time.Now(),
+	if req.Title != nil {
+		goal.Title = *req.Title
+	}
+	if req.Description != nil {
+		goal.Description = *req.Description
+	}
+	if req.Category != nil && *req.Category != goal.Category {
+		if err := h.taxonomyService.ValidateCategory(ctx, *req.Category); err != nil {
+			handleServiceError(c, err, "校验类别失败")
+			return
+		}
+		goal.Category = *req.Category
+	}
+	if req.Difficulty != nil {
+		goal.Difficulty = *req.Difficulty
+	}
+	if req.TargetDate != nil {
+		goal.TargetDate = req.TargetDate
+	}
+
+	err = h.goalRepository.Update(ctx, goal)
+	if err != nil {
+		logger.Error("更新学习目标失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "更新学习目标失败"})
+		return
+	}
+
+	// 类别、难度或描述变化后重新执行已过期的分析，失败不影响目标更新
+	if job, err := h.jobService.EnqueueStale(ctx, &previous, goal); err != nil {
+		logger.Warn("提交过期分析重算任务失败",
+			logger.String("goal_id", goalID.String()),
+			logger.String("error", err.Error()))
+	} else if job != nil {
+		logger.Info("目标内容变化，已提交分析重算任务",
+			logger.String("goal_id", goalID.String()),
+			logger.String("job_id", job.ID.String()))
+	}
+
+	// 状态变更需经过状态机校验
+	if req.Status != nil && *req.Status != goal.Status {
+		goal, err = h.statusService.TransitionGoal(ctx, goalID, entities.GoalStatus(*req.Status), services.TransitionOptions{
+			Trigger: entities.TransitionTriggerUser,
+		})
+		if err != nil {
+			logger.Error("更新学习目标状态失败", logger.String("error", err.Error()))
+			handleServiceError(c, err, "更新学习目标状态失败")
+			return
+		}
 	}
 
 	logger.Info("学习目标更新成功", logger.String("goal_id", goalID.String()))
-	c.JSON(http.StatusOK, gin.H{"data": response})
+	c.JSON(http.StatusOK, gin.H{"data": h.convertToGoalResponse(goal)})
+}
+
+// UpdateGoalStatus 更新学习目标状态
+func (h *LearningGoalHandler) UpdateGoalStatus(c *gin.Context) {
+	goalIDStr := c.Param("id")
+	goalID, err := uuid.Parse(goalIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "目标ID格式无效"})
+		return
+	}
+
+	if _, ok := h.loadOwnedGoal(c, goalID); !ok {
+		return
+	}
+
+	var req UpdateGoalStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("绑定请求参数失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请求参数无效"})
+		return
+	}
+
+	goal, err := h.statusService.TransitionGoal(c.Request.Context(), goalID, entities.GoalStatus(req.Status), services.TransitionOptions{
+		Trigger: entities.TransitionTriggerUser,
+		Reason:  req.Reason,
+	})
+	if err != nil {
+		logger.Error("更新学习目标状态失败", logger.String("error", err.Error()))
+		handleServiceError(c, err, "更新学习目标状态失败")
+		return
+	}
+
+	logger.Info("学习目标状态更新成功",
+		logger.String("goal_id", goalID.String()),
+		logger.String("status", req.Status))
+	c.JSON(http.StatusOK, gin.H{"data": h.convertToGoalResponse(goal)})
+}
+
+// GetGoalTransitions 获取学习目标及其步骤的状态转换历史
+func (h *LearningGoalHandler) GetGoalTransitions(c *gin.Context) {
+	goalIDStr := c.Param("id")
+	goalID, err := uuid.Parse(goalIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "目标ID格式无效"})
+		return
+	}
+
+	if _, ok := h.loadOwnedGoal(c, goalID); !ok {
+		return
+	}
+
+	transitions, err := h.statusService.GetGoalTransitions(c.Request.Context(), goalID)
+	if err != nil {
+		logger.Error("获取状态转换历史失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取状态转换历史失败"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  transitions,
+		"count": len(transitions),
+	})
 }
 
 // DeleteGoal 删除学习目标
@@ -304,19 +385,23 @@ func (h *LearningGoalHandler) DeleteGoal(c *gin.Context) {
 		return
 	}
 
-	// 这里需要调用仓储来删除目标
-	// err = h.goalRepository.Delete(c.Request.Context(), goalID)
-	// if err != nil {
-	// 	logger.Error("删除学习目标失败", logger.String("error", err.Error()))
-	// 	c.JSON(http.StatusInternalServerError, gin.H{"error": "删除学习目标失败"})
-	// 	return
-	// }
+	if _, ok := h.loadOwnedGoal(c, goalID); !ok {
+		return
+	}
+
+	if err := h.goalRepository.Delete(c.Request.Context(), goalID); err != nil {
+		logger.Error("删除学习目标失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "删除学习目标失败"})
+		return
+	}
 
 	logger.Info("学习目标删除成功", logger.String("goal_id", goalID.String()))
 	c.JSON(http.StatusOK, gin.H{"message": "学习目标删除成功"})
 }
 
-// AnalyzeGoal 分析学习目标
+// AnalyzeGoal 提交学习目标分析任务
+// 分析在后台执行，返回202及任务信息，可通过任务查询接口轮询状态
+// types 参数以逗号分隔指定要执行的分析类型，未指定时执行全部单项分析
 func (h *LearningGoalHandler) AnalyzeGoal(c *gin.Context) {
 	goalIDStr := c.Param("id")
 	goalID, err := uuid.Parse(goalIDStr)
@@ -325,24 +410,273 @@ func (h *LearningGoalHandler) AnalyzeGoal(c *gin.Context) {
 		return
 	}
 
-	// 调用分析服务
-	analysis, err := h.goalService.AnalyzeLearningGoal(c.Request.Context(), goalID)
+	if _, ok := h.loadOwnedGoal(c, goalID); !ok {
+		return
+	}
+
+	var types []string
+	for _, t := range strings.Split(c.Query("types"), ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			types = append(types, t)
+		}
+	}
+
+	job, deduplicated, err := h.jobService.Enqueue(c.Request.Context(), goalID, types)
+	if err != nil {
+		logger.Error("提交分析任务失败", logger.String("error", err.Error()))
+		handleServiceError(c, err, "提交分析任务失败")
+		return
+	}
+
+	c.Header("Location", fmt.Sprintf("%s/analysis-jobs/%s", strings.TrimSuffix(c.Request.URL.Path, "/analyze"), job.ID))
+	c.JSON(http.StatusAccepted, gin.H{
+		"data":         job,
+		"deduplicated": deduplicated,
+	})
+}
+
+// GetAnalysisJob 获取分析任务状态
+func (h *LearningGoalHandler) GetAnalysisJob(c *gin.Context) {
+	goalID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "目标ID格式无效"})
+		return
+	}
+	jobID, err := uuid.Parse(c.Param("job_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "任务ID格式无效"})
+		return
+	}
+
+	if _, ok := h.loadOwnedGoal(c, goalID); !ok {
+		return
+	}
+
+	job, err := h.jobService.GetJob(c.Request.Context(), goalID, jobID)
+	if err != nil {
+		handleServiceError(c, err, "获取分析任务失败")
+		return
+	}
+
+	analyses, err := h.jobService.GetJobAnalyses(c.Request.Context(), jobID)
+	if err != nil {
+		logger.Error("获取任务分析结果失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取任务分析结果失败"})
+		return
+	}
+
+	responses := make([]*AnalysisResponse, 0, len(analyses))
+	for _, analysis := range analyses {
+		responses = append(responses, convertToAnalysisResponse(analysis))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":     job,
+		"analyses": responses,
+	})
+}
+
+// ListAnalyses 获取学习目标的分析历史，可按 type 参数筛选分析类型
+func (h *LearningGoalHandler) ListAnalyses(c *gin.Context) {
+	goalID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "目标ID格式无效"})
+		return
+	}
+
+	if _, ok := h.loadOwnedGoal(c, goalID); !ok {
+		return
+	}
+
+	analyses, err := h.jobService.GetAnalysisHistory(c.Request.Context(), goalID, c.Query("type"))
+	if err != nil {
+		logger.Error("获取分析历史失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取分析历史失败"})
+		return
+	}
+
+	responses := make([]*AnalysisResponse, 0, len(analyses))
+	for _, analysis := range analyses {
+		responses = append(responses, convertToAnalysisResponse(analysis))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  responses,
+		"count": len(responses),
+	})
+}
+
+// GetLatestAnalysis 获取学习目标的最新分析结果
+// 指定 type 参数时返回该类型的最新分析，否则返回由各单项最新分析组合的综合分析
+func (h *LearningGoalHandler) GetLatestAnalysis(c *gin.Context) {
+	goalID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "目标ID格式无效"})
+		return
+	}
+
+	if _, ok := h.loadOwnedGoal(c, goalID); !ok {
+		return
+	}
+
+	if analysisType := c.Query("type"); analysisType != "" && analysisType != entities.AnalysisTypeComprehensive {
+		analysis, err := h.jobService.GetLatestAnalysis(c.Request.Context(), goalID, analysisType)
+		if err != nil {
+			handleServiceError(c, err, "获取分析结果失败")
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": convertToAnalysisResponse(analysis)})
+		return
+	}
+
+	comprehensive, err := h.goalService.GetComprehensiveAnalysis(c.Request.Context(), goalID)
+	if err != nil {
+		logger.Error("获取综合分析失败", logger.String("error", err.Error()))
+		handleServiceError(c, err, "获取分析结果失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": convertToComprehensiveAnalysisResponse(comprehensive)})
+}
+
+// GetGoalProgress 获取学习目标进度汇总
+func (h *LearningGoalHandler) GetGoalProgress(c *gin.Context) {
+	goalIDStr := c.Param("id")
+	goalID, err := uuid.Parse(goalIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "目标ID格式无效"})
+		return
+	}
+
+	if _, ok := h.loadOwnedGoal(c, goalID); !ok {
+		return
+	}
+
+	progress, err := h.progressService.GetGoalProgress(c.Request.Context(), goalID)
+	if err != nil {
+		logger.Error("获取学习进度失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusNotFound, gin.H{"error": "学习目标不存在"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": progress})
+}
+
+// GetProgressHistory 获取学习目标进度历史（用于图表展示）
+func (h *LearningGoalHandler) GetProgressHistory(c *gin.Context) {
+	goalIDStr := c.Param("id")
+	goalID, err := uuid.Parse(goalIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "目标ID格式无效"})
+		return
+	}
+
+	if _, ok := h.loadOwnedGoal(c, goalID); !ok {
+		return
+	}
+
+	// 可选的时间范围参数（RFC3339格式）
+	var from, to *time.Time
+	if fromStr := c.Query("from"); fromStr != "" {
+		t, err := time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "开始时间格式无效"})
+			return
+		}
+		from = &t
+	}
+	if toStr := c.Query("to"); toStr != "" {
+		t, err := time.Parse(time.RFC3339, toStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "结束时间格式无效"})
+			return
+		}
+		to = &t
+	}
+
+	history, err := h.progressService.GetProgressHistory(c.Request.Context(), goalID, from, to)
 	if err != nil {
-		logger.Error("分析学习目标失败", logger.String("error", err.Error()))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "分析学习目标失败"})
+		logger.Error("获取进度历史失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取进度历史失败"})
 		return
 	}
 
-	response := &AnalysisResponse{
+	c.JSON(http.StatusOK, gin.H{
+		"data":  history,
+		"count": len(history),
+	})
+}
+
+// loadOwnedGoal 加载属于当前用户的学习目标，失败时已写入响应
+func (h *LearningGoalHandler) loadOwnedGoal(c *gin.Context, goalID uuid.UUID) (*entities.LearningGoal, bool) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return nil, false
+	}
+	goal, err := h.goalRepository.GetByID(c.Request.Context(), goalID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "学习目标不存在"})
+		return nil, false
+	}
+	if goal.UserID != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "无权访问该学习目标"})
+		return nil, false
+	}
+	return goal, true
+}
+
+// convertToGoalResponse 转换为学习目标响应
+func (h *LearningGoalHandler) convertToGoalResponse(goal *entities.LearningGoal) *GoalResponse {
+	return &GoalResponse{
+		ID:          goal.ID.String(),
+		UserID:      goal.UserID,
+		Title:       goal.Title,
+		Description: goal.Description,
+		Category:    goal.Category,
+		Difficulty:  goal.Difficulty,
+		Status:      goal.Status,
+		TargetDate:  goal.TargetDate,
+		Progress:    goal.Progress,
+		CompletedAt: goal.CompletedAt,
+		CreatedAt:   goal.CreatedAt,
+		UpdatedAt:   goal.UpdatedAt,
+	}
+}
+
+// convertToAnalysisResponse 转换为分析响应
+func convertToAnalysisResponse(analysis *entities.GoalAnalysis) *AnalysisResponse {
+	return &AnalysisResponse{
 		ID:              analysis.ID.String(),
 		GoalID:          analysis.GoalID.String(),
 		AnalysisType:    analysis.AnalysisType,
+		Analyzer:        analysis.Analyzer,
 		Result:          analysis.Result,
 		Recommendations: analysis.Recommendations,
 		ConfidenceScore: analysis.ConfidenceScore,
 		CreatedAt:       analysis.CreatedAt,
 	}
+}
 
-	logger.Info("学习目标分析完成", logger.String("goal_id", goalID.String()))
-	c.JSON(http.StatusOK, gin.H{"data": response})
-}
\ No newline at end of file
+// convertToComprehensiveAnalysisResponse 转换为综合分析响应
+func convertToComprehensiveAnalysisResponse(comprehensive *services.ComprehensiveAnalysis) *ComprehensiveAnalysisResponse {
+	components := make([]*AnalysisComponentResponse, 0, len(comprehensive.Components))
+	for _, analysis := range comprehensive.Components {
+		components = append(components, &AnalysisComponentResponse{
+			ID:              analysis.ID.String(),
+			AnalysisType:    analysis.AnalysisType,
+			Analyzer:        analysis.Analyzer,
+			ConfidenceScore: analysis.ConfidenceScore,
+			CreatedAt:       analysis.CreatedAt,
+		})
+	}
+
+	return &ComprehensiveAnalysisResponse{
+		GoalID:          comprehensive.GoalID.String(),
+		AnalysisType:    entities.AnalysisTypeComprehensive,
+		Result:          comprehensive.Result,
+		Recommendations: comprehensive.Recommendations,
+		ConfidenceScore: comprehensive.ConfidenceScore,
+		Components:      components,
+		MissingTypes:    comprehensive.MissingTypes,
+	}
+}
@@ -13,13 +13,21 @@ import (
 
 // LearningPathHandler 学习路径处理器
 type LearningPathHandler struct {
-	pathService *services.LearningPathService
+	pathService     *services.LearningPathService
+	progressService *services.ProgressService
+	statusService   *services.StatusService
 }
 
 // NewLearningPathHandler 创建学习路径处理器
-func NewLearningPathHandler(pathService *services.LearningPathService) *LearningPathHandler {
+func NewLearningPathHandler(
+	pathService *services.LearningPathService,
+	progressService *services.ProgressService,
+	statusService *services.StatusService,
+) *LearningPathHandler {
 	return &LearningPathHandler{
-		pathService: pathService,
+		pathService:     pathService,
+		progressService: progressService,
+		statusService:   statusService,
 	}
 }
 
@@ -51,7 +59,16 @@ type UpdatePathRequest struct {
 
 // UpdateStatusRequest 更新状态请求
 type UpdateStatusRequest struct {
-	Status string `json:"status" binding:"required,oneof=pending in_progress completed"`
+	Status   string `json:"status" binding:"required,oneof=pending in_progress completed"`
+	Override bool   `json:"override"` // 跳过前置步骤完成检查
+	Reason   string `json:"reason"`
+}
+
+// LogTimeRequest 记录学习时间请求
+type LogTimeRequest struct {
+	Minutes  int        `json:"minutes" binding:"required,min=1,max=1440"`
+	Note     string     `json:"note"`
+	LoggedAt *time.Time `json:"logged_at,omitempty"`
 }
 
 // PathResponse 路径响应
@@ -63,6 +80,9 @@ type PathResponse struct {
 	Order             int                      `json:"order"`
 	EstimatedDuration int                      `json:"estimated_duration"`
 	Status            string                   `json:"status"`
+	StartedAt         *time.Time               `json:"started_at"`
+	CompletedAt       *time.Time               `json:"completed_at"`
+	TimeSpent         int                      `json:"time_spent"`
 	CreatedAt         time.Time                `json:"created_at"`
 	UpdatedAt         time.Time                `json:"updated_at"`
 	KnowledgePoints   []KnowledgePointResponse `json:"knowledge_points,omitempty"`
@@ -112,6 +132,9 @@ func (h *LearningPathHandler) GenerateLearningPath(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "目标ID格式无效"})
 		return
 	}
+	if !h.authorizeGoal(c, goalID) {
+		return
+	}
 
 	// 构建生成请求
 	generateReq := &services.PathGenerationRequest{
@@ -152,6 +175,9 @@ func (h *LearningPathHandler) CreateLearningPath(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "目标ID格式无效"})
 		return
 	}
+	if !h.authorizeGoal(c, goalID) {
+		return
+	}
 
 	// 构建生成路径（单步）
 	generatedPath := &services.GeneratedPath{
@@ -205,6 +231,9 @@ func (h *LearningPathHandler) GetLearningPaths(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "目标ID格式无效"})
 		return
 	}
+	if !h.authorizeGoal(c, goalID) {
+		return
+	}
 
 	paths, err := h.pathService.GetLearningPaths(c.Request.Context(), goalID)
 	if err != nil {
@@ -235,10 +264,8 @@ func (h *LearningPathHandler) GetLearningPath(c *gin.Context) {
 		return
 	}
 
-	path, err := h.pathService.GetLearningPath(c.Request.Context(), pathID)
-	if err != nil {
-		logger.Error("获取学习路径失败", logger.String("error", err.Error()))
-		c.JSON(http.StatusNotFound, gin.H{"error": "学习路径不存在"})
+	path, ok := h.loadOwnedPath(c, pathID)
+	if !ok {
 		return
 	}
 
@@ -258,6 +285,10 @@ func (h *LearningPathHandler) UpdateLearningPathStatus(c *gin.Context) {
 		return
 	}
 
+	if _, ok := h.loadOwnedPath(c, pathID); !ok {
+		return
+	}
+
 	var req UpdateStatusRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		logger.Error("绑定请求参数失败", logger.String("error", err.Error()))
@@ -265,16 +296,108 @@ func (h *LearningPathHandler) UpdateLearningPathStatus(c *gin.Context) {
 		return
 	}
 
-	if err := h.pathService.UpdateLearningPathStatus(c.Request.Context(), pathID, req.Status); err != nil {
+	path, err := h.pathService.UpdateLearningPathStatus(c.Request.Context(), pathID, req.Status, req.Override, req.Reason)
+	if err != nil {
 		logger.Error("更新学习路径状态失败", logger.String("error", err.Error()))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "更新学习路径状态失败"})
+		handleServiceError(c, err, "更新学习路径状态失败")
 		return
 	}
 
 	logger.Info("学习路径状态更新成功", 
 		logger.String("path_id", pathID.String()),
 		logger.String("status", req.Status))
-	c.JSON(http.StatusOK, gin.H{"message": "状态更新成功"})
+	c.JSON(http.StatusOK, gin.H{
+		"message": "状态更新成功",
+		"data":    h.convertToPathResponse(path),
+	})
+}
+
+// GetPathTransitions 获取学习路径状态转换历史
+func (h *LearningPathHandler) GetPathTransitions(c *gin.Context) {
+	pathIDStr := c.Param("id")
+	pathID, err := uuid.Parse(pathIDStr)
+	if err != nil {
+		logger.Error("路径ID格式无效", logger.String("path_id", pathIDStr))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "路径ID格式无效"})
+		return
+	}
+
+	if _, ok := h.loadOwnedPath(c, pathID); !ok {
+		return
+	}
+
+	transitions, err := h.statusService.GetPathTransitions(c.Request.Context(), pathID)
+	if err != nil {
+		logger.Error("获取状态转换历史失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取状态转换历史失败"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  transitions,
+		"count": len(transitions),
+	})
+}
+
+// LogStepTime 记录步骤学习时间
+func (h *LearningPathHandler) LogStepTime(c *gin.Context) {
+	pathIDStr := c.Param("id")
+	pathID, err := uuid.Parse(pathIDStr)
+	if err != nil {
+		logger.Error("路径ID格式无效", logger.String("path_id", pathIDStr))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "路径ID格式无效"})
+		return
+	}
+
+	if _, ok := h.loadOwnedPath(c, pathID); !ok {
+		return
+	}
+
+	var req LogTimeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("绑定请求参数失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请求参数无效"})
+		return
+	}
+
+	timeLog, err := h.progressService.LogStepTime(c.Request.Context(), pathID, req.Minutes, req.Note, req.LoggedAt)
+	if err != nil {
+		logger.Error("记录学习时间失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "记录学习时间失败"})
+		return
+	}
+
+	logger.Info("学习时间记录成功",
+		logger.String("path_id", pathID.String()),
+		logger.Int("minutes", req.Minutes))
+	c.JSON(http.StatusCreated, gin.H{"data": timeLog})
+}
+
+// GetStepTimeLogs 获取步骤学习时间记录
+func (h *LearningPathHandler) GetStepTimeLogs(c *gin.Context) {
+	pathIDStr := c.Param("id")
+	pathID, err := uuid.Parse(pathIDStr)
+	if err != nil {
+		logger.Error("路径ID格式无效", logger.String("path_id", pathIDStr))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "路径ID格式无效"})
+		return
+	}
+
+	if _, ok := h.loadOwnedPath(c, pathID); !ok {
+		return
+	}
+
+	timeLogs, err := h.progressService.GetStepTimeLogs(c.Request.Context(), pathID)
+	if err != nil {
+		logger.Error("获取学习时间记录失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取学习时间记录失败"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  timeLogs,
+		"count": len(timeLogs),
+	})
 }
 
 // DeleteLearningPath 删除学习路径
@@ -287,6 +410,10 @@ func (h *LearningPathHandler) DeleteLearningPath(c *gin.Context) {
 		return
 	}
 
+	if _, ok := h.loadOwnedPath(c, pathID); !ok {
+		return
+	}
+
 	if err := h.pathService.DeleteLearningPath(c.Request.Context(), pathID); err != nil {
 		logger.Error("删除学习路径失败", logger.String("error", err.Error()))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "删除学习路径失败"})
@@ -297,6 +424,33 @@ func (h *LearningPathHandler) DeleteLearningPath(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "删除成功"})
 }
 
+// authorizeGoal 校验学习目标属于当前用户，失败时已写入响应
+func (h *LearningPathHandler) authorizeGoal(c *gin.Context, goalID uuid.UUID) bool {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return false
+	}
+	if _, err := h.pathService.LoadOwnedGoal(c.Request.Context(), userID, goalID); err != nil {
+		handleServiceError(c, err, "获取学习目标失败")
+		return false
+	}
+	return true
+}
+
+// loadOwnedPath 加载属于当前用户的学习路径，失败时已写入响应
+func (h *LearningPathHandler) loadOwnedPath(c *gin.Context, pathID uuid.UUID) (*entities.LearningPath, bool) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return nil, false
+	}
+	path, err := h.pathService.LoadOwnedPath(c.Request.Context(), userID, pathID)
+	if err != nil {
+		handleServiceError(c, err, "获取学习路径失败")
+		return nil, false
+	}
+	return path, true
+}
+
 // convertToPathResponse 转换为路径响应
 func (h *LearningPathHandler) convertToPathResponse(path *entities.LearningPath) PathResponse {
 	response := PathResponse{
@@ -307,6 +461,9 @@ func (h *LearningPathHandler) convertToPathResponse(path *entities.LearningPath)
 		Order:             path.Order,
 		EstimatedDuration: path.EstimatedDuration,
 		Status:            path.Status,
+		StartedAt:         path.StartedAt,
+		CompletedAt:       path.CompletedAt,
+		TimeSpent:         path.TimeSpent,
 		CreatedAt:         path.CreatedAt,
 		UpdatedAt:         path.UpdatedAt,
 	}
@@ -0,0 +1,265 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/repositories"
+	"sical-go-backend/internal/domain/services"
+	"sical-go-backend/pkg/logger"
+)
+
+// ModerationHandler 内容审核处理器
+type ModerationHandler struct {
+	moderationService *services.ModerationService
+}
+
+// NewModerationHandler 创建内容审核处理器
+func NewModerationHandler(moderationService *services.ModerationService) *ModerationHandler {
+	return &ModerationHandler{
+		moderationService: moderationService,
+	}
+}
+
+// ReportRequest 举报请求
+type ReportRequest struct {
+	ContentType string `json:"content_type" binding:"required,oneof=comment user_bio"`
+	ContentID   string `json:"content_id" binding:"required,max=64"`
+	Reason      string `json:"reason" binding:"required,oneof=spam abuse inappropriate other"`
+	Detail      string `json:"detail" binding:"max=1000"`
+}
+
+// ResolveCaseRequest 处理审核案件请求
+type ResolveCaseRequest struct {
+	Action string `json:"action" binding:"required,oneof=approve reject suspend ban"`
+	Note   string `json:"note" binding:"max=1000"`
+}
+
+// ModerationRuleRequest 过滤规则请求，更新时未提供的字段保持不变
+type ModerationRuleRequest struct {
+	Name      string `json:"name" binding:"max=100"`
+	Pattern   string `json:"pattern" binding:"max=500"`
+	MatchType string `json:"match_type" binding:"omitempty,oneof=keyword regex"`
+	Action    string `json:"action" binding:"omitempty,oneof=review block"`
+	Category  string `json:"category" binding:"max=50"`
+	IsActive  *bool  `json:"is_active"`
+}
+
+// Report 举报评论或用户简介
+func (h *ModerationHandler) Report(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req ReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请求参数无效"})
+		return
+	}
+
+	moderationCase, err := h.moderationService.Report(c.Request.Context(), userID, req.ContentType, req.ContentID, req.Reason, req.Detail)
+	if err != nil {
+		logger.Error("举报内容失败",
+			logger.String("content_type", req.ContentType),
+			logger.String("content_id", req.ContentID),
+			logger.String("error", err.Error()))
+		handleServiceError(c, err, "举报内容失败")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "举报已提交",
+		"data":    gin.H{"case_id": moderationCase.ID},
+	})
+}
+
+// ListQueue 获取审核队列，默认只返回待处理的案件
+func (h *ModerationHandler) ListQueue(c *gin.Context) {
+	status := c.DefaultQuery("status", string(entities.ModerationPending))
+	if status == "all" {
+		status = ""
+	}
+	filter := repositories.ModerationCaseFilter{
+		Status:      status,
+		ContentType: c.Query("content_type"),
+		Source:      c.Query("source"),
+	}
+	offset, limit := parsePagination(c)
+
+	cases, total, err := h.moderationService.ListCases(c.Request.Context(), filter, offset, limit)
+	if err != nil {
+		logger.Error("获取审核队列失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取审核队列失败"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":   cases,
+		"count":  len(cases),
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// GetCase 获取审核案件详情及举报记录
+func (h *ModerationHandler) GetCase(c *gin.Context) {
+	id, ok := h.parseID(c, "案件ID格式无效")
+	if !ok {
+		return
+	}
+
+	moderationCase, err := h.moderationService.GetCase(c.Request.Context(), id)
+	if err != nil {
+		handleServiceError(c, err, "获取审核案件失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": moderationCase})
+}
+
+// ResolveCase 处理审核案件，可同时暂停或封禁内容作者
+func (h *ModerationHandler) ResolveCase(c *gin.Context) {
+	moderatorID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	id, ok := h.parseID(c, "案件ID格式无效")
+	if !ok {
+		return
+	}
+
+	var req ResolveCaseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请求参数无效"})
+		return
+	}
+
+	moderationCase, err := h.moderationService.ResolveCase(c.Request.Context(), moderatorID, id, req.Action, req.Note)
+	if err != nil {
+		logger.Error("处理审核案件失败",
+			logger.String("case_id", id.String()),
+			logger.String("error", err.Error()))
+		handleServiceError(c, err, "处理审核案件失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": moderationCase})
+}
+
+// ListRules 获取全部过滤规则
+func (h *ModerationHandler) ListRules(c *gin.Context) {
+	rules, err := h.moderationService.ListRules(c.Request.Context())
+	if err != nil {
+		logger.Error("获取过滤规则失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取过滤规则失败"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  rules,
+		"count": len(rules),
+	})
+}
+
+// CreateRule 创建过滤规则，立即生效
+func (h *ModerationHandler) CreateRule(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req ModerationRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请求参数无效"})
+		return
+	}
+
+	rule, err := h.moderationService.CreateRule(c.Request.Context(), userID, h.ruleInput(&req))
+	if err != nil {
+		logger.Error("创建过滤规则失败", logger.String("error", err.Error()))
+		handleServiceError(c, err, "创建过滤规则失败")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": rule})
+}
+
+// UpdateRule 更新过滤规则，立即生效
+func (h *ModerationHandler) UpdateRule(c *gin.Context) {
+	id, ok := h.parseID(c, "规则ID格式无效")
+	if !ok {
+		return
+	}
+
+	var req ModerationRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请求参数无效"})
+		return
+	}
+
+	rule, err := h.moderationService.UpdateRule(c.Request.Context(), id, h.ruleInput(&req))
+	if err != nil {
+		logger.Error("更新过滤规则失败",
+			logger.String("rule_id", id.String()),
+			logger.String("error", err.Error()))
+		handleServiceError(c, err, "更新过滤规则失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": rule})
+}
+
+// DeleteRule 删除过滤规则，立即生效
+func (h *ModerationHandler) DeleteRule(c *gin.Context) {
+	id, ok := h.parseID(c, "规则ID格式无效")
+	if !ok {
+		return
+	}
+
+	if err := h.moderationService.DeleteRule(c.Request.Context(), id); err != nil {
+		logger.Error("删除过滤规则失败",
+			logger.String("rule_id", id.String()),
+			logger.String("error", err.Error()))
+		handleServiceError(c, err, "删除过滤规则失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "过滤规则已删除"})
+}
+
+// ReloadRules 立即重新加载过滤规则
+func (h *ModerationHandler) ReloadRules(c *gin.Context) {
+	if err := h.moderationService.ReloadRules(c.Request.Context()); err != nil {
+		logger.Error("重新加载过滤规则失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "重新加载过滤规则失败"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "过滤规则已重新加载"})
+}
+
+// ruleInput 转换过滤规则请求
+func (h *ModerationHandler) ruleInput(req *ModerationRuleRequest) services.ModerationRuleInput {
+	return services.ModerationRuleInput{
+		Name:      req.Name,
+		Pattern:   req.Pattern,
+		MatchType: req.MatchType,
+		Action:    req.Action,
+		Category:  req.Category,
+		IsActive:  req.IsActive,
+	}
+}
+
+// parseID 解析路径中的ID参数，失败时已写入响应
+func (h *ModerationHandler) parseID(c *gin.Context, message string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": message})
+		return uuid.Nil, false
+	}
+	return id, true
+}
@@ -0,0 +1,291 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/services"
+	"sical-go-backend/pkg/logger"
+)
+
+// RatingHandler 评分处理器
+type RatingHandler struct {
+	ratingService *services.RatingService
+}
+
+// NewRatingHandler 创建评分处理器
+func NewRatingHandler(ratingService *services.RatingService) *RatingHandler {
+	return &RatingHandler{
+		ratingService: ratingService,
+	}
+}
+
+// RateRequest 评分请求，重复提交时修改已有评分
+type RateRequest struct {
+	Score  int    `json:"score" binding:"required,min=1,max=5"`
+	Review string `json:"review"`
+}
+
+// RatingResponse 评分或评价响应
+type RatingResponse struct {
+	ID           uuid.UUID      `json:"id"`
+	TargetType   string         `json:"target_type"`
+	TargetID     uuid.UUID      `json:"target_id"`
+	Author       *CommentAuthor `json:"author,omitempty"`
+	Score        int            `json:"score"`
+	Review       string         `json:"review"`
+	HelpfulCount int            `json:"helpful_count"`
+	VotedHelpful bool           `json:"voted_helpful"`
+	IsEdited     bool           `json:"is_edited"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+}
+
+// RatingSummaryResponse 评分汇总响应，distribution为各星级的评分数
+type RatingSummaryResponse struct {
+	TargetType    string         `json:"target_type"`
+	TargetID      uuid.UUID      `json:"target_id"`
+	RatingCount   int            `json:"rating_count"`
+	Average       float64        `json:"average"`
+	WeightedScore float64        `json:"weighted_score"`
+	Distribution  map[string]int `json:"distribution"`
+}
+
+// Rate 对知识点或学习路径评分，可附带文字评价
+func (h *RatingHandler) Rate(c *gin.Context) {
+	userID, targetType, targetID, ok := h.parseTarget(c)
+	if !ok {
+		return
+	}
+
+	var req RateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "评分须在1到5星之间"})
+		return
+	}
+
+	rating, created, err := h.ratingService.Rate(c.Request.Context(), userID, targetType, targetID, services.RateInput{
+		Score:  req.Score,
+		Review: req.Review,
+	})
+	if err != nil {
+		logger.Error("提交评分失败",
+			logger.String("target_type", targetType),
+			logger.String("target_id", targetID.String()),
+			logger.String("error", err.Error()))
+		handleServiceError(c, err, "提交评分失败")
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	c.JSON(status, gin.H{"data": convertToRatingResponse(rating, false)})
+}
+
+// GetSummary 获取评分汇总
+func (h *RatingHandler) GetSummary(c *gin.Context) {
+	_, targetType, targetID, ok := h.parseTarget(c)
+	if !ok {
+		return
+	}
+
+	stats, err := h.ratingService.GetStats(c.Request.Context(), targetType, targetID)
+	if err != nil {
+		handleServiceError(c, err, "获取评分汇总失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": convertToRatingSummaryResponse(stats)})
+}
+
+// GetMyRating 获取当前用户的评分
+func (h *RatingHandler) GetMyRating(c *gin.Context) {
+	userID, targetType, targetID, ok := h.parseTarget(c)
+	if !ok {
+		return
+	}
+
+	rating, err := h.ratingService.GetMine(c.Request.Context(), userID, targetType, targetID)
+	if err != nil {
+		handleServiceError(c, err, "获取评分失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": convertToRatingResponse(rating, false)})
+}
+
+// DeleteMyRating 删除当前用户的评分
+func (h *RatingHandler) DeleteMyRating(c *gin.Context) {
+	userID, targetType, targetID, ok := h.parseTarget(c)
+	if !ok {
+		return
+	}
+
+	if err := h.ratingService.DeleteMine(c.Request.Context(), userID, targetType, targetID); err != nil {
+		logger.Error("删除评分失败",
+			logger.String("target_type", targetType),
+			logger.String("target_id", targetID.String()),
+			logger.String("error", err.Error()))
+		handleServiceError(c, err, "删除评分失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "评分已删除"})
+}
+
+// ListReviews 分页获取文字评价，sort为helpful(默认)或recent
+func (h *RatingHandler) ListReviews(c *gin.Context) {
+	userID, targetType, targetID, ok := h.parseTarget(c)
+	if !ok {
+		return
+	}
+	offset, limit := parsePagination(c)
+
+	page, err := h.ratingService.ListReviews(c.Request.Context(), userID, targetType, targetID, c.Query("sort"), offset, limit)
+	if err != nil {
+		handleServiceError(c, err, "获取评价列表失败")
+		return
+	}
+
+	responses := make([]*RatingResponse, 0, len(page.Reviews))
+	for _, review := range page.Reviews {
+		responses = append(responses, convertToRatingResponse(review, page.Voted[review.ID]))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":   responses,
+		"count":  len(responses),
+		"total":  page.Total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// ListTopRated 按加权评分获取评分最高的知识点或学习路径
+func (h *RatingHandler) ListTopRated(c *gin.Context) {
+	offset, limit := parsePagination(c)
+	targetType := c.DefaultQuery("target_type", string(entities.RatingTargetKnowledgePoint))
+
+	stats, total, err := h.ratingService.ListTopRated(c.Request.Context(), targetType, offset, limit)
+	if err != nil {
+		handleServiceError(c, err, "获取评分排行失败")
+		return
+	}
+
+	responses := make([]*RatingSummaryResponse, 0, len(stats))
+	for _, item := range stats {
+		responses = append(responses, convertToRatingSummaryResponse(item))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":   responses,
+		"count":  len(responses),
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// MarkHelpful 将评价标记为有帮助
+func (h *RatingHandler) MarkHelpful(c *gin.Context) {
+	userID, id, ok := h.parseRef(c)
+	if !ok {
+		return
+	}
+
+	rating, err := h.ratingService.MarkHelpful(c.Request.Context(), userID, id)
+	if err != nil {
+		handleServiceError(c, err, "标记评价有帮助失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": convertToRatingResponse(rating, true)})
+}
+
+// UnmarkHelpful 取消有帮助投票
+func (h *RatingHandler) UnmarkHelpful(c *gin.Context) {
+	userID, id, ok := h.parseRef(c)
+	if !ok {
+		return
+	}
+
+	rating, err := h.ratingService.UnmarkHelpful(c.Request.Context(), userID, id)
+	if err != nil {
+		handleServiceError(c, err, "取消有帮助投票失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": convertToRatingResponse(rating, false)})
+}
+
+// parseTarget 解析当前用户和路径中的评分对象，失败时已写入响应
+func (h *RatingHandler) parseTarget(c *gin.Context) (uint, string, uuid.UUID, bool) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return 0, "", uuid.Nil, false
+	}
+	targetID, err := uuid.Parse(c.Param("target_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "评分对象ID格式无效"})
+		return 0, "", uuid.Nil, false
+	}
+	return userID, c.Param("target_type"), targetID, true
+}
+
+// parseRef 解析当前用户和路径中的评价ID，失败时已写入响应
+func (h *RatingHandler) parseRef(c *gin.Context) (uint, uuid.UUID, bool) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return 0, uuid.Nil, false
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "评价ID格式无效"})
+		return 0, uuid.Nil, false
+	}
+	return userID, id, true
+}
+
+// convertToRatingResponse 转换评分响应
+func convertToRatingResponse(rating *entities.Rating, voted bool) *RatingResponse {
+	response := &RatingResponse{
+		ID:           rating.ID,
+		TargetType:   rating.TargetType,
+		TargetID:     rating.TargetID,
+		Score:        rating.Score,
+		Review:       rating.Review,
+		HelpfulCount: rating.HelpfulCount,
+		VotedHelpful: voted,
+		IsEdited:     rating.IsEdited,
+		CreatedAt:    rating.CreatedAt,
+		UpdatedAt:    rating.UpdatedAt,
+	}
+	if rating.User != nil {
+		response.Author = &CommentAuthor{ID: rating.User.ID, Username: rating.User.Username}
+	}
+	return response
+}
+
+// convertToRatingSummaryResponse 转换评分汇总响应
+func convertToRatingSummaryResponse(stats *services.RatingStats) *RatingSummaryResponse {
+	summary := stats.Summary
+	return &RatingSummaryResponse{
+		TargetType:    summary.TargetType,
+		TargetID:      summary.TargetID,
+		RatingCount:   summary.RatingCount,
+		Average:       summary.Average,
+		WeightedScore: stats.WeightedScore,
+		Distribution: map[string]int{
+			"1": summary.OneStar,
+			"2": summary.TwoStar,
+			"3": summary.ThreeStar,
+			"4": summary.FourStar,
+			"5": summary.FiveStar,
+		},
+	}
+}
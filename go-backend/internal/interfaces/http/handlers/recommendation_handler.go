@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/services"
+	"sical-go-backend/pkg/logger"
+)
+
+// RecommendationHandler 分析推荐处理器
+type RecommendationHandler struct {
+	recommendationService *services.RecommendationService
+}
+
+// NewRecommendationHandler 创建分析推荐处理器
+func NewRecommendationHandler(recommendationService *services.RecommendationService) *RecommendationHandler {
+	return &RecommendationHandler{
+		recommendationService: recommendationService,
+	}
+}
+
+// DismissRecommendationRequest 忽略推荐请求
+type DismissRecommendationRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+// SnoozeRecommendationRequest 暂缓推荐请求，未指定时间时暂缓7天
+type SnoozeRecommendationRequest struct {
+	Until *time.Time `json:"until"`
+}
+
+// AcceptRecommendation 接受推荐，带有路径模板的推荐会为目标创建学习路径
+func (h *RecommendationHandler) AcceptRecommendation(c *gin.Context) {
+	ref, ok := h.parseRef(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.recommendationService.Accept(c.Request.Context(), ref)
+	if err != nil {
+		logger.Error("接受推荐失败",
+			logger.String("recommendation_id", ref.RecommendationID.String()),
+			logger.String("error", err.Error()))
+		handleServiceError(c, err, "接受推荐失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": result})
+}
+
+// DismissRecommendation 忽略推荐
+func (h *RecommendationHandler) DismissRecommendation(c *gin.Context) {
+	ref, ok := h.parseRef(c)
+	if !ok {
+		return
+	}
+
+	var req DismissRecommendationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请填写忽略原因"})
+		return
+	}
+
+	result, err := h.recommendationService.Dismiss(c.Request.Context(), ref, req.Reason)
+	if err != nil {
+		logger.Error("忽略推荐失败",
+			logger.String("recommendation_id", ref.RecommendationID.String()),
+			logger.String("error", err.Error()))
+		handleServiceError(c, err, "忽略推荐失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": result})
+}
+
+// SnoozeRecommendation 暂缓推荐
+func (h *RecommendationHandler) SnoozeRecommendation(c *gin.Context) {
+	ref, ok := h.parseRef(c)
+	if !ok {
+		return
+	}
+
+	var req SnoozeRecommendationRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "请求参数无效"})
+			return
+		}
+	}
+
+	result, err := h.recommendationService.Snooze(c.Request.Context(), ref, req.Until)
+	if err != nil {
+		logger.Error("暂缓推荐失败",
+			logger.String("recommendation_id", ref.RecommendationID.String()),
+			logger.String("error", err.Error()))
+		handleServiceError(c, err, "暂缓推荐失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": result})
+}
+
+// parseRef 解析当前用户及路径中的目标、分析和推荐ID，失败时已写入响应
+func (h *RecommendationHandler) parseRef(c *gin.Context) (services.RecommendationRef, bool) {
+	var ref services.RecommendationRef
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return ref, false
+	}
+	ref.UserID = userID
+
+	params := []struct {
+		name    string
+		message string
+		target  *uuid.UUID
+	}{
+		{"id", "目标ID格式无效", &ref.GoalID},
+		{"analysis_id", "分析ID格式无效", &ref.AnalysisID},
+		{"recommendation_id", "推荐ID格式无效", &ref.RecommendationID},
+	}
+	for _, p := range params {
+		id, err := uuid.Parse(c.Param(p.name))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": p.message})
+			return ref, false
+		}
+		*p.target = id
+	}
+
+	return ref, true
+}
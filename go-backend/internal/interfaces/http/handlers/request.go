@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"sical-go-backend/internal/api/middleware"
+)
+
+// currentUserID 获取当前登录用户的账号ID，失败时已写入响应
+func currentUserID(c *gin.Context) (uint, bool) {
+	value, exists := c.Get("user_id")
+	userID, ok := value.(uint)
+	if !exists || !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "用户未认证"})
+		return 0, false
+	}
+	return userID, true
+}
+
+// isModerator 当前用户是否具有审核员或管理员权限
+func isModerator(c *gin.Context) bool {
+	return middleware.HasAnyRole(c, "moderator", "admin", "super_admin")
+}
+
+// parsePagination 解析分页参数
+func parsePagination(c *gin.Context) (int, int) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+	return offset, limit
+}
+
+// splitQueryList 解析逗号分隔或重复出现的查询参数，忽略空项
+func splitQueryList(c *gin.Context, key string) []string {
+	var values []string
+	for _, raw := range c.QueryArray(key) {
+		for _, value := range strings.Split(raw, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
+
+// parseQueryTime 解析RFC3339时间或日期格式的查询参数，参数为空时返回nil，失败时已写入响应
+func parseQueryTime(c *gin.Context, key string) (*time.Time, bool) {
+	value := c.Query(key)
+	if value == "" {
+		return nil, true
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return &parsed, true
+		}
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": key + "参数格式无效"})
+	return nil, false
+}
@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/services"
+	"sical-go-backend/pkg/logger"
+)
+
+// ReviewHandler 知识点掌握度与复习处理器
+type ReviewHandler struct {
+	masteryService *services.MasteryService
+}
+
+// NewReviewHandler 创建知识点掌握度与复习处理器
+func NewReviewHandler(masteryService *services.MasteryService) *ReviewHandler {
+	return &ReviewHandler{
+		masteryService: masteryService,
+	}
+}
+
+// SubmitReviewRequest 提交复习结果请求
+type SubmitReviewRequest struct {
+	Quality *int `json:"quality" binding:"required,min=0,max=5"` // 回忆质量：0完全遗忘，5轻松回忆
+}
+
+// ListDue 分页获取今天需要复习的知识点，按到期时间排序
+func (h *ReviewHandler) ListDue(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	offset, limit := parsePagination(c)
+
+	records, total, err := h.masteryService.ListDue(c.Request.Context(), userID, time.Now(), offset, limit)
+	if err != nil {
+		logger.Error("获取待复习知识点失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取待复习知识点失败"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":   records,
+		"count":  len(records),
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// SubmitReview 提交知识点复习结果，更新掌握度和下次复习时间
+func (h *ReviewHandler) SubmitReview(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	pointID, ok := h.parseID(c, "knowledge_point_id", "知识点ID格式无效")
+	if !ok {
+		return
+	}
+
+	var req SubmitReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "回忆质量必须在0到5之间"})
+		return
+	}
+
+	record, err := h.masteryService.Review(c.Request.Context(), userID, pointID, *req.Quality)
+	if err != nil {
+		logger.Error("提交复习结果失败",
+			logger.String("knowledge_point_id", pointID.String()),
+			logger.String("error", err.Error()))
+		handleServiceError(c, err, "提交复习结果失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": record})
+}
+
+// ListMastery 获取当前用户全部知识点掌握度
+func (h *ReviewHandler) ListMastery(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	records, err := h.masteryService.List(c.Request.Context(), userID)
+	if err != nil {
+		logger.Error("获取知识点掌握度失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取知识点掌握度失败"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  records,
+		"count": len(records),
+	})
+}
+
+// GetMastery 获取当前用户对单个知识点的掌握度和复习计划
+func (h *ReviewHandler) GetMastery(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	pointID, ok := h.parseID(c, "knowledge_point_id", "知识点ID格式无效")
+	if !ok {
+		return
+	}
+
+	record, err := h.masteryService.Get(c.Request.Context(), userID, pointID)
+	if err != nil {
+		handleServiceError(c, err, "获取知识点掌握度失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": record})
+}
+
+// parseID 解析路径中的UUID参数，失败时已写入响应
+func (h *ReviewHandler) parseID(c *gin.Context, param, message string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param(param))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": message})
+		return uuid.Nil, false
+	}
+	return id, true
+}
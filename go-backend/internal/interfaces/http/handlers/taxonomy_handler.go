@@ -0,0 +1,384 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"sical-go-backend/internal/domain/entities"
+	"sical-go-backend/internal/domain/services"
+	"sical-go-backend/pkg/logger"
+)
+
+// TaxonomyHandler 类别与技能体系处理器
+type TaxonomyHandler struct {
+	taxonomyService *services.TaxonomyService
+}
+
+// NewTaxonomyHandler 创建类别与技能体系处理器
+func NewTaxonomyHandler(taxonomyService *services.TaxonomyService) *TaxonomyHandler {
+	return &TaxonomyHandler{
+		taxonomyService: taxonomyService,
+	}
+}
+
+// CreateCategoryRequest 创建类别请求
+type CreateCategoryRequest struct {
+	Name        string  `json:"name" binding:"required,min=1,max=100"`
+	ParentID    *string `json:"parent_id"`
+	Description string  `json:"description"`
+	BaseHours   int     `json:"base_hours" binding:"min=0"`
+	SortOrder   int     `json:"sort_order"`
+}
+
+// UpdateCategoryRequest 更新类别请求（类别名称不可修改）
+type UpdateCategoryRequest struct {
+	Description *string `json:"description,omitempty"`
+	BaseHours   *int    `json:"base_hours,omitempty" binding:"omitempty,min=0"`
+	SortOrder   *int    `json:"sort_order,omitempty"`
+	IsActive    *bool   `json:"is_active,omitempty"`
+}
+
+// SkillRequest 创建/更新技能请求
+type SkillRequest struct {
+	Name        string `json:"name" binding:"required,min=1,max=100"`
+	Description string `json:"description"`
+}
+
+// CategorySkillRequest 添加类别技能映射请求
+type CategorySkillRequest struct {
+	SkillID    string `json:"skill_id" binding:"required"`
+	Kind       string `json:"kind" binding:"required,oneof=required prerequisite"`
+	Difficulty string `json:"difficulty" binding:"omitempty,oneof=beginner intermediate advanced"`
+	SortOrder  int    `json:"sort_order"`
+}
+
+// CategoryDurationRequest 设置类别基准时间请求
+type CategoryDurationRequest struct {
+	StepHours int `json:"step_hours" binding:"required,min=1"`
+}
+
+// ListCategories 获取类别列表
+func (h *TaxonomyHandler) ListCategories(c *gin.Context) {
+	includeInactive := c.Query("include_inactive") == "true"
+
+	categories, err := h.taxonomyService.ListCategories(c.Request.Context(), includeInactive)
+	if err != nil {
+		logger.Error("获取类别列表失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取类别列表失败"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  categories,
+		"count": len(categories),
+	})
+}
+
+// GetCategory 获取类别详情
+func (h *TaxonomyHandler) GetCategory(c *gin.Context) {
+	categoryID, ok := h.parseID(c, "id", "类别ID格式无效")
+	if !ok {
+		return
+	}
+
+	category, err := h.taxonomyService.GetCategory(c.Request.Context(), categoryID)
+	if err != nil {
+		logger.Error("获取类别失败", logger.String("error", err.Error()))
+		handleServiceError(c, err, "获取类别失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": category})
+}
+
+// CreateCategory 创建类别
+func (h *TaxonomyHandler) CreateCategory(c *gin.Context) {
+	var req CreateCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("绑定请求参数失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请求参数无效"})
+		return
+	}
+
+	category := &entities.Category{
+		Name:        req.Name,
+		Description: req.Description,
+		BaseHours:   req.BaseHours,
+		SortOrder:   req.SortOrder,
+		IsActive:    true,
+	}
+	if req.ParentID != nil {
+		parentID, err := uuid.Parse(*req.ParentID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "父类别ID格式无效"})
+			return
+		}
+		category.ParentID = &parentID
+	}
+
+	if err := h.taxonomyService.CreateCategory(c.Request.Context(), category); err != nil {
+		logger.Error("创建类别失败", logger.String("error", err.Error()))
+		handleServiceError(c, err, "创建类别失败")
+		return
+	}
+
+	logger.Info("类别创建成功", logger.String("category_id", category.ID.String()))
+	c.JSON(http.StatusCreated, gin.H{"data": category})
+}
+
+// UpdateCategory 更新类别
+func (h *TaxonomyHandler) UpdateCategory(c *gin.Context) {
+	categoryID, ok := h.parseID(c, "id", "类别ID格式无效")
+	if !ok {
+		return
+	}
+
+	var req UpdateCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("绑定请求参数失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请求参数无效"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	category, err := h.taxonomyService.GetCategory(ctx, categoryID)
+	if err != nil {
+		handleServiceError(c, err, "获取类别失败")
+		return
+	}
+
+	if req.Description != nil {
+		category.Description = *req.Description
+	}
+	if req.BaseHours != nil {
+		category.BaseHours = *req.BaseHours
+	}
+	if req.SortOrder != nil {
+		category.SortOrder = *req.SortOrder
+	}
+	if req.IsActive != nil {
+		category.IsActive = *req.IsActive
+	}
+
+	if err := h.taxonomyService.UpdateCategory(ctx, category); err != nil {
+		logger.Error("更新类别失败", logger.String("error", err.Error()))
+		handleServiceError(c, err, "更新类别失败")
+		return
+	}
+
+	logger.Info("类别更新成功", logger.String("category_id", categoryID.String()))
+	c.JSON(http.StatusOK, gin.H{"data": category})
+}
+
+// DeleteCategory 删除类别
+func (h *TaxonomyHandler) DeleteCategory(c *gin.Context) {
+	categoryID, ok := h.parseID(c, "id", "类别ID格式无效")
+	if !ok {
+		return
+	}
+
+	if err := h.taxonomyService.DeleteCategory(c.Request.Context(), categoryID); err != nil {
+		logger.Error("删除类别失败", logger.String("error", err.Error()))
+		handleServiceError(c, err, "删除类别失败")
+		return
+	}
+
+	logger.Info("类别删除成功", logger.String("category_id", categoryID.String()))
+	c.JSON(http.StatusOK, gin.H{"message": "删除成功"})
+}
+
+// GetCategorySkills 获取类别的技能映射
+func (h *TaxonomyHandler) GetCategorySkills(c *gin.Context) {
+	categoryID, ok := h.parseID(c, "id", "类别ID格式无效")
+	if !ok {
+		return
+	}
+
+	mappings, err := h.taxonomyService.GetCategorySkills(c.Request.Context(), categoryID)
+	if err != nil {
+		logger.Error("获取类别技能映射失败", logger.String("error", err.Error()))
+		handleServiceError(c, err, "获取类别技能映射失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  mappings,
+		"count": len(mappings),
+	})
+}
+
+// AddCategorySkill 添加类别技能映射
+func (h *TaxonomyHandler) AddCategorySkill(c *gin.Context) {
+	categoryID, ok := h.parseID(c, "id", "类别ID格式无效")
+	if !ok {
+		return
+	}
+
+	var req CategorySkillRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("绑定请求参数失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请求参数无效"})
+		return
+	}
+
+	skillID, err := uuid.Parse(req.SkillID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "技能ID格式无效"})
+		return
+	}
+
+	mapping := &entities.CategorySkill{
+		CategoryID: categoryID,
+		SkillID:    skillID,
+		Kind:       req.Kind,
+		Difficulty: req.Difficulty,
+		SortOrder:  req.SortOrder,
+	}
+	if err := h.taxonomyService.AddCategorySkill(c.Request.Context(), mapping); err != nil {
+		logger.Error("添加类别技能映射失败", logger.String("error", err.Error()))
+		handleServiceError(c, err, "添加类别技能映射失败")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": mapping})
+}
+
+// RemoveCategorySkill 删除类别技能映射
+func (h *TaxonomyHandler) RemoveCategorySkill(c *gin.Context) {
+	categoryID, ok := h.parseID(c, "id", "类别ID格式无效")
+	if !ok {
+		return
+	}
+	mappingID, ok := h.parseID(c, "mapping_id", "映射ID格式无效")
+	if !ok {
+		return
+	}
+
+	if err := h.taxonomyService.RemoveCategorySkill(c.Request.Context(), categoryID, mappingID); err != nil {
+		logger.Error("删除类别技能映射失败", logger.String("error", err.Error()))
+		handleServiceError(c, err, "删除类别技能映射失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "删除成功"})
+}
+
+// SetCategoryDuration 设置类别在指定难度下的基准学习时间
+func (h *TaxonomyHandler) SetCategoryDuration(c *gin.Context) {
+	categoryID, ok := h.parseID(c, "id", "类别ID格式无效")
+	if !ok {
+		return
+	}
+
+	var req CategoryDurationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("绑定请求参数失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请求参数无效"})
+		return
+	}
+
+	duration, err := h.taxonomyService.SetCategoryDuration(c.Request.Context(), categoryID, c.Param("difficulty"), req.StepHours)
+	if err != nil {
+		logger.Error("设置类别基准时间失败", logger.String("error", err.Error()))
+		handleServiceError(c, err, "设置类别基准时间失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": duration})
+}
+
+// ListSkills 获取技能列表
+func (h *TaxonomyHandler) ListSkills(c *gin.Context) {
+	skills, err := h.taxonomyService.ListSkills(c.Request.Context())
+	if err != nil {
+		logger.Error("获取技能列表失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取技能列表失败"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  skills,
+		"count": len(skills),
+	})
+}
+
+// CreateSkill 创建技能
+func (h *TaxonomyHandler) CreateSkill(c *gin.Context) {
+	var req SkillRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("绑定请求参数失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请求参数无效"})
+		return
+	}
+
+	skill := &entities.Skill{
+		Name:        req.Name,
+		Description: req.Description,
+	}
+	if err := h.taxonomyService.CreateSkill(c.Request.Context(), skill); err != nil {
+		logger.Error("创建技能失败", logger.String("error", err.Error()))
+		handleServiceError(c, err, "创建技能失败")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": skill})
+}
+
+// UpdateSkill 更新技能
+func (h *TaxonomyHandler) UpdateSkill(c *gin.Context) {
+	skillID, ok := h.parseID(c, "id", "技能ID格式无效")
+	if !ok {
+		return
+	}
+
+	var req SkillRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("绑定请求参数失败", logger.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请求参数无效"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	skill, err := h.taxonomyService.GetSkill(ctx, skillID)
+	if err != nil {
+		handleServiceError(c, err, "获取技能失败")
+		return
+	}
+
+	skill.Name = req.Name
+	skill.Description = req.Description
+	if err := h.taxonomyService.UpdateSkill(ctx, skill); err != nil {
+		logger.Error("更新技能失败", logger.String("error", err.Error()))
+		handleServiceError(c, err, "更新技能失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": skill})
+}
+
+// DeleteSkill 删除技能
+func (h *TaxonomyHandler) DeleteSkill(c *gin.Context) {
+	skillID, ok := h.parseID(c, "id", "技能ID格式无效")
+	if !ok {
+		return
+	}
+
+	if err := h.taxonomyService.DeleteSkill(c.Request.Context(), skillID); err != nil {
+		logger.Error("删除技能失败", logger.String("error", err.Error()))
+		handleServiceError(c, err, "删除技能失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "删除成功"})
+}
+
+// parseID 解析路径参数中的UUID，失败时写入400响应
+func (h *TaxonomyHandler) parseID(c *gin.Context, param, message string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param(param))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": message})
+		return uuid.Nil, false
+	}
+	return id, true
+}
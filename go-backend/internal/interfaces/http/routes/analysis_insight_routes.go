@@ -0,0 +1,29 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"sical-go-backend/internal/domain/services"
+	"sical-go-backend/internal/infrastructure/repositories"
+	"sical-go-backend/internal/interfaces/http/handlers"
+)
+
+// SetupAnalysisInsightRoutes 设置分析结果洞察路由（管理员）
+func SetupAnalysisInsightRoutes(admin *gin.RouterGroup, db *gorm.DB) {
+	// 初始化仓储层
+	goalAnalysisRepo := repositories.NewGoalAnalysisRepository(db)
+	recommendationOutcomeRepo := repositories.NewRecommendationOutcomeRepository(db)
+
+	// 初始化服务层
+	insightService := services.NewAnalysisInsightService(goalAnalysisRepo, recommendationOutcomeRepo)
+
+	// 初始化处理器
+	insightHandler := handlers.NewAnalysisInsightHandler(insightService)
+
+	analyses := admin.Group("/analyses")
+	{
+		analyses.GET("/skill-gaps", insightHandler.FindSkillGaps)                        // 最新分析标记了指定技能差距的目标
+		analyses.GET("/low-confidence", insightHandler.FindLowConfidence)                // 置信度低于阈值的分析
+		analyses.GET("/recommendation-quality", insightHandler.GetRecommendationQuality) // 推荐接受/忽略统计
+	}
+}
@@ -0,0 +1,68 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"sical-go-backend/internal/domain/services"
+	"sical-go-backend/internal/infrastructure/repositories"
+	"sical-go-backend/internal/interfaces/http/handlers"
+)
+
+// SetupAssessmentRoutes 设置测评路由
+// router 挂载学习者的作答接口，admin 挂载需要管理员权限的维护接口，grading 挂载需要审核员权限的人工评分接口
+func SetupAssessmentRoutes(router *gin.RouterGroup, admin *gin.RouterGroup, grading *gin.RouterGroup, db *gorm.DB) {
+	// 初始化仓储层
+	assessmentRepo := repositories.NewAssessmentRepository(db)
+	attemptRepo := repositories.NewAssessmentAttemptRepository(db)
+	regradeRepo := repositories.NewRegradeRequestRepository(db)
+	knowledgePointRepo := repositories.NewKnowledgePointRepository(db)
+	taxonomyRepo := repositories.NewTaxonomyRepository(db)
+	masteryRepo := repositories.NewUserKnowledgeMasteryRepository(db)
+
+	// 初始化服务层
+	taxonomyService := services.NewTaxonomyService(taxonomyRepo)
+	masteryService := services.NewMasteryService(masteryRepo, knowledgePointRepo)
+	assessmentService := services.NewAssessmentService(
+		assessmentRepo,
+		attemptRepo,
+		knowledgePointRepo,
+		taxonomyService,
+		masteryService,
+	)
+	gradingService := services.NewGradingService(assessmentRepo, attemptRepo, regradeRepo, masteryService)
+
+	// 初始化处理器
+	assessmentHandler := handlers.NewAssessmentHandler(assessmentService)
+	gradingHandler := handlers.NewGradingHandler(gradingService)
+
+	// 学习者接口
+	assessments := router.Group("/assessments")
+	{
+		assessments.GET("", assessmentHandler.ListAssessments)                                                  // 获取已发布的测评列表
+		assessments.GET("/:id", assessmentHandler.GetAssessment)                                                // 获取测评详情
+		assessments.POST("/:id/attempts", assessmentHandler.StartAttempt)                                       // 开始作答
+		assessments.GET("/:id/attempts", assessmentHandler.ListAttempts)                                        // 获取我的作答记录
+		assessments.GET("/:id/attempts/:attempt_id", assessmentHandler.GetAttempt)                              // 获取作答详情和评分结果
+		assessments.PUT("/:id/attempts/:attempt_id/answers", assessmentHandler.SaveAnswers)                     // 保存答案
+		assessments.POST("/:id/attempts/:attempt_id/submit", assessmentHandler.SubmitAttempt)                   // 提交作答
+		assessments.GET("/:id/attempts/:attempt_id/regrades", gradingHandler.ListAttemptRegrades)               // 获取复核申请
+		assessments.POST("/:id/attempts/:attempt_id/answers/:answer_id/regrade", gradingHandler.RequestRegrade) // 申请复核
+	}
+
+	// 管理接口
+	manage := admin.Group("/assessments")
+	{
+		manage.POST("", assessmentHandler.CreateAssessment)              // 创建测评
+		manage.GET("/:id", assessmentHandler.GetAssessmentForAdmin)      // 获取测评详情（含正确答案）
+		manage.POST("/:id/publish", assessmentHandler.PublishAssessment) // 发布测评
+	}
+
+	// 人工评分接口
+	{
+		grading.GET("/queue", gradingHandler.ListQueue)                                     // 获取待评分作答队列
+		grading.GET("/attempts/:attempt_id", gradingHandler.GetSubmission)                  // 获取作答详情
+		grading.PUT("/attempts/:attempt_id/answers/:answer_id", gradingHandler.GradeAnswer) // 评分单题答案
+		grading.GET("/regrades", gradingHandler.ListRegrades)                               // 获取复核申请列表
+		grading.POST("/regrades/:id/resolve", gradingHandler.ResolveRegrade)                // 处理复核申请
+	}
+}
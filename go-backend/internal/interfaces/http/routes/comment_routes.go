@@ -0,0 +1,49 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"sical-go-backend/internal/domain/services"
+	"sical-go-backend/internal/infrastructure/repositories"
+	"sical-go-backend/internal/interfaces/http/handlers"
+)
+
+// SetupCommentRoutes 设置评论路由
+// router 挂载需要认证的评论接口，moderation 挂载需要审核员权限的管理接口
+// 发布和编辑的评论需经过 moderationService 内容审核
+func SetupCommentRoutes(router *gin.RouterGroup, moderation *gin.RouterGroup, db *gorm.DB, moderationService *services.ModerationService) {
+	// 初始化仓储层
+	commentRepo := repositories.NewCommentRepository(db)
+	userRepo := repositories.NewUserRepository(db)
+	knowledgePointRepo := repositories.NewKnowledgePointRepository(db)
+	learningPathRepo := repositories.NewLearningPathRepository(db)
+
+	// 初始化服务层
+	commentService := services.NewCommentService(
+		commentRepo,
+		userRepo,
+		knowledgePointRepo,
+		learningPathRepo,
+		moderationService,
+	)
+
+	// 初始化处理器
+	commentHandler := handlers.NewCommentHandler(commentService)
+
+	comments := router.Group("/comments")
+	{
+		comments.GET("", commentHandler.ListComments)              // 获取知识点或学习路径下的讨论串
+		comments.POST("", commentHandler.CreateComment)            // 发表评论或回复
+		comments.GET("/mentions", commentHandler.ListMentions)     // 获取提及我的评论
+		comments.GET("/:id", commentHandler.GetComment)            // 获取单条评论
+		comments.GET("/:id/replies", commentHandler.ListReplies)   // 获取讨论串内的回复
+		comments.PUT("/:id", commentHandler.UpdateComment)         // 编辑评论
+		comments.DELETE("/:id", commentHandler.DeleteComment)      // 删除评论
+		comments.GET("/:id/edits", commentHandler.ListEdits)       // 获取编辑历史
+		comments.POST("/:id/like", commentHandler.LikeComment)     // 点赞
+		comments.DELETE("/:id/like", commentHandler.UnlikeComment) // 取消点赞
+	}
+
+	// 审核员接口
+	moderation.PATCH("/comments/:id", commentHandler.ModerateComment) // 隐藏、置顶或锁定评论
+}
@@ -1,29 +1,111 @@
 package routes
 
 import (
+	"context"
+	"time"
+
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
+	"sical-go-backend/internal/infrastructure/cache"
+	"sical-go-backend/internal/domain/services"
 	"sical-go-backend/internal/infrastructure/repositories"
+	"sical-go-backend/internal/infrastructure/storage"
 	"sical-go-backend/internal/interfaces/http/handlers"
+	"sical-go-backend/internal/pkg"
+	"sical-go-backend/pkg/logger"
 )
 
-// SetupKnowledgePointRoutes 设置知识点路由
-func SetupKnowledgePointRoutes(router *gin.Engine, db *gorm.DB) {
+// knowledgePublishInterval 检查并发布定时发布到期知识点的间隔
+const knowledgePublishInterval = time.Minute
+
+// knowledgeLinkCheckInterval 检查一批到期外部链接的间隔
+const knowledgeLinkCheckInterval = 10 * time.Minute
+
+// knowledgeRelationInterval 重新计算相关知识点共现推荐的间隔
+const knowledgeRelationInterval = 6 * time.Hour
+
+// SetupKnowledgePointRoutes 设置知识点路由，redisCache不为空时缓存检索建议
+// router 须已挂载认证中间件，编辑和审核权限由作者、审核员角色在处理器中校验；
+// public 不需要认证，用于签名下载链接；storageConfig为空时不支持上传文件
+// 定时发布、链接检查和共现推荐计算注册到workers随服务启动
+func SetupKnowledgePointRoutes(router, public *gin.RouterGroup, db *gorm.DB, redisCache *cache.Redis, storageConfig *pkg.StorageConfig, workers *Workers) {
 	// 初始化仓储层
 	knowledgePointRepo := repositories.NewKnowledgePointRepository(db)
+	taxonomyRepo := repositories.NewTaxonomyRepository(db)
+	searchLogRepo := repositories.NewSearchLogRepository(db)
+	revisionRepo := repositories.NewKnowledgeRevisionRepository(db)
+	workflowRepo := repositories.NewKnowledgeWorkflowRepository(db)
+	transferRepo := repositories.NewKnowledgeTransferRepository(db)
+	resourceRepo := repositories.NewKnowledgeResourceRepository(db)
+	renderingRepo := repositories.NewKnowledgeRenderingRepository(db)
+	relationRepo := repositories.NewKnowledgeRelationRepository(db)
+
+	// 初始化服务层
+	taxonomyService := services.NewTaxonomyService(taxonomyRepo)
+	var suggestionCache services.SuggestionCache
+	if redisCache != nil {
+		suggestionCache = redisCache
+	}
+	searchService := services.NewKnowledgeSearchService(knowledgePointRepo, searchLogRepo, suggestionCache)
+	listService := services.NewKnowledgeListService(knowledgePointRepo)
+	revisionService := services.NewKnowledgeRevisionService(knowledgePointRepo, revisionRepo)
+	renderService := services.NewKnowledgeRenderService(knowledgePointRepo, renderingRepo)
+	workflowService := services.NewKnowledgeWorkflowService(knowledgePointRepo, workflowRepo, searchService)
+	workers.Add("knowledge-publish", func(ctx context.Context) {
+		workflowService.Start(ctx, knowledgePublishInterval)
+	})
+	transferService := services.NewKnowledgeTransferService(transferRepo, taxonomyService)
+	resourceConfig := services.KnowledgeResourceConfig{
+		DownloadPath: public.BasePath() + "/files/knowledge-resources",
+	}
+	var blobStorage services.BlobStorage
+	if storageConfig != nil {
+		resourceConfig.SigningKey = []byte(storageConfig.SigningKey)
+		resourceConfig.URLExpiration = storageConfig.URLExpiration
+		resourceConfig.MaxUploadSize = int64(storageConfig.MaxUploadSizeMB) << 20
+		local, err := storage.NewLocal(storageConfig.LocalDir)
+		if err != nil {
+			logger.Error("初始化文件存储失败，暂不支持上传", logger.String("error", err.Error()))
+		} else {
+			blobStorage = local
+		}
+	}
+	resourceService := services.NewKnowledgeResourceService(knowledgePointRepo, resourceRepo, blobStorage, resourceConfig)
+	linkChecker := services.NewKnowledgeLinkChecker(resourceRepo, services.KnowledgeLinkCheckerConfig{})
+	workers.Add("knowledge-link-check", func(ctx context.Context) {
+		linkChecker.Start(ctx, knowledgeLinkCheckInterval)
+	})
+	relationService := services.NewKnowledgeRelationService(knowledgePointRepo, relationRepo, services.KnowledgeRelationConfig{})
+	workers.Add("knowledge-relations", func(ctx context.Context) {
+		relationService.Start(ctx, knowledgeRelationInterval)
+	})
 
 	// 初始化处理器
-	knowledgePointHandler := handlers.NewKnowledgePointHandler(knowledgePointRepo)
+	knowledgePointHandler := handlers.NewKnowledgePointHandler(knowledgePointRepo, taxonomyService, searchService, listService, revisionService, renderService)
+	revisionHandler := handlers.NewKnowledgeRevisionHandler(revisionService)
+	workflowHandler := handlers.NewKnowledgeWorkflowHandler(workflowService)
+	transferHandler := handlers.NewKnowledgeTransferHandler(transferService)
+	resourceHandler := handlers.NewKnowledgeResourceHandler(resourceService)
+	relationHandler := handlers.NewKnowledgeRelationHandler(relationService)
 
 	// 知识点路由组
-	knowledgeGroup := router.Group("/api/v1/knowledge-points")
-	// TODO: 添加认证中间件
+	knowledgeGroup := router.Group("/knowledge-points")
 	{
 		// 创建知识点
 		knowledgeGroup.POST("/", knowledgePointHandler.CreateKnowledgePoint)
+
+		// 批量导入导出(CSV、JSON Lines、Markdown)
+		knowledgeGroup.POST("/import", transferHandler.ImportKnowledgePoints)
+		knowledgeGroup.GET("/export", transferHandler.ExportKnowledgePoints)
+
+		// 分页获取知识点列表
+		knowledgeGroup.GET("", knowledgePointHandler.ListKnowledgePoints)
 		
 		// 获取单个知识点
 		knowledgeGroup.GET("/:id", knowledgePointHandler.GetKnowledgePoint)
+
+		// 正文渲染结果：清洗后的HTML、目录和预计阅读时间
+		knowledgeGroup.GET("/:id/rendered", knowledgePointHandler.GetRenderedContent)
 		
 		// 按分类获取知识点
 		knowledgeGroup.GET("/category", knowledgePointHandler.GetKnowledgePointsByCategory)
@@ -31,13 +113,45 @@ func SetupKnowledgePointRoutes(router *gin.Engine, db *gorm.DB) {
 		// 按难度获取知识点
 		knowledgeGroup.GET("/difficulty", knowledgePointHandler.GetKnowledgePointsByDifficulty)
 		
-		// 搜索知识点
+		// 全文检索知识点
 		knowledgeGroup.GET("/search", knowledgePointHandler.SearchKnowledgePoints)
+
+		// 检索建议
+		knowledgeGroup.GET("/suggest", knowledgePointHandler.SuggestKnowledgePoints)
 		
 		// 更新知识点
 		knowledgeGroup.PUT("/:id", knowledgePointHandler.UpdateKnowledgePoint)
 		
 		// 删除知识点
 		knowledgeGroup.DELETE("/:id", knowledgePointHandler.DeleteKnowledgePoint)
+
+		// 修订历史、版本比较和回滚
+		knowledgeGroup.GET("/:id/revisions", revisionHandler.ListRevisions)
+		knowledgeGroup.GET("/:id/revisions/diff", revisionHandler.DiffRevisions)
+		knowledgeGroup.GET("/:id/revisions/:version", revisionHandler.GetRevision)
+		knowledgeGroup.POST("/:id/revisions/:version/restore", revisionHandler.RestoreRevision)
+
+		// 编辑流程：提交审核、审核、发布、归档和撤回
+		knowledgeGroup.GET("/:id/workflow", workflowHandler.History)
+		knowledgeGroup.POST("/:id/workflow/:action", workflowHandler.Transition)
+
+		// 结构化资源：外部链接、参考文献、内嵌数据和上传的图片、PDF
+		knowledgeGroup.GET("/:id/resources", resourceHandler.ListResources)
+		knowledgeGroup.POST("/:id/resources", resourceHandler.CreateResource)
+		knowledgeGroup.POST("/:id/resources/upload", resourceHandler.UploadResource)
+		knowledgeGroup.PUT("/:id/resources/order", resourceHandler.ReorderResources)
+		knowledgeGroup.PUT("/:id/resources/:resourceId", resourceHandler.UpdateResource)
+		knowledgeGroup.DELETE("/:id/resources/:resourceId", resourceHandler.DeleteResource)
+
+		// 相关知识点：作者维护的链接和"学过的人还学了"推荐
+		knowledgeGroup.GET("/:id/related", relationHandler.ListRelated)
+		knowledgeGroup.POST("/:id/related", relationHandler.AddRelation)
+		knowledgeGroup.DELETE("/:id/related/:relatedId", relationHandler.RemoveRelation)
 	}
+
+	// 失效的外部链接
+	router.GET("/knowledge-resources/broken", resourceHandler.ListBrokenLinks)
+
+	// 上传文件的签名下载链接，无需登录
+	public.GET("/files/knowledge-resources/:resourceId", resourceHandler.DownloadResource)
 }
\ No newline at end of file
@@ -1,30 +1,108 @@
 package routes
 
 import (
+	"time"
+
 	"github.com/gin-gonic/gin"
 	"sical-go-backend/internal/domain/services"
 	"sical-go-backend/internal/infrastructure/repositories"
 	"sical-go-backend/internal/interfaces/http/handlers"
+	"sical-go-backend/internal/pkg"
+	"sical-go-backend/pkg/llm"
+	"sical-go-backend/pkg/logger"
 	"gorm.io/gorm"
 )
 
-// SetupLearningGoalRoutes 设置学习目标相关路由
-func SetupLearningGoalRoutes(router *gin.RouterGroup, db *gorm.DB) {
+// SetupLearningGoalRoutes 设置学习目标相关路由，分析任务工作协程注册到workers随服务启动
+func SetupLearningGoalRoutes(router *gin.RouterGroup, db *gorm.DB, aiConfig *pkg.AIConfig, workers *Workers) {
 	// 初始化仓储层
 	learningGoalRepo := repositories.NewLearningGoalRepository(db)
 	goalAnalysisRepo := repositories.NewGoalAnalysisRepository(db)
+	learningPathRepo := repositories.NewLearningPathRepository(db)
+	knowledgePointRepo := repositories.NewKnowledgePointRepository(db)
+	stepTimeLogRepo := repositories.NewStepTimeLogRepository(db)
+	progressHistoryRepo := repositories.NewGoalProgressHistoryRepository(db)
+	statusTransitionRepo := repositories.NewStatusTransitionRepository(db)
+	analysisJobRepo := repositories.NewAnalysisJobRepository(db)
+	taxonomyRepo := repositories.NewTaxonomyRepository(db)
+	recommendationOutcomeRepo := repositories.NewRecommendationOutcomeRepository(db)
+	assessmentRepo := repositories.NewAssessmentRepository(db)
+	diagnosticSessionRepo := repositories.NewDiagnosticSessionRepository(db)
+	masteryRepo := repositories.NewUserKnowledgeMasteryRepository(db)
+	ratingRepo := repositories.NewRatingRepository(db)
+	renderingRepo := repositories.NewKnowledgeRenderingRepository(db)
 	
 	// 初始化服务层
+	taxonomyService := services.NewTaxonomyService(taxonomyRepo)
+	masteryService := services.NewMasteryService(masteryRepo, knowledgePointRepo)
+	ratingService := services.NewRatingService(ratingRepo, knowledgePointRepo, learningPathRepo)
+	renderService := services.NewKnowledgeRenderService(knowledgePointRepo, renderingRepo)
+	goalAnalyzers := newGoalAnalyzers(db, aiConfig, taxonomyService, services.NewRuleAnalyzer(knowledgePointRepo, taxonomyService))
 	goalAnalysisService := services.NewGoalAnalysisService(
 		learningGoalRepo,
 		goalAnalysisRepo,
 		nil, // userRepo 暂时为空
+		learningPathRepo,
+		knowledgePointRepo,
+		masteryService,
+		goalAnalyzers,
+	)
+	analysisJobService := services.NewAnalysisJobService(
+		analysisJobRepo,
+		learningGoalRepo,
+		goalAnalysisRepo,
+		goalAnalysisService,
+		newAnalysisJobConfig(aiConfig),
+	)
+	workers.Add("analysis-jobs", analysisJobService.Start)
+	progressService := services.NewProgressService(
+		learningGoalRepo,
+		learningPathRepo,
+		stepTimeLogRepo,
+		progressHistoryRepo,
+	)
+	statusService := services.NewStatusService(
+		learningGoalRepo,
+		learningPathRepo,
+		statusTransitionRepo,
+		progressService,
+	)
+	statusService.OnAfterTransition(masteryService.OnPathTransition)
+	pathService := services.NewLearningPathService(
+		learningPathRepo,
+		learningGoalRepo,
+		knowledgePointRepo,
+		statusService,
+		taxonomyService,
+		ratingService,
+		renderService,
+	)
+	recommendationService := services.NewRecommendationService(
+		learningGoalRepo,
+		goalAnalysisRepo,
+		recommendationOutcomeRepo,
+		pathService,
+	)
+	diagnosticService := services.NewDiagnosticService(
+		learningGoalRepo,
+		knowledgePointRepo,
+		assessmentRepo,
+		diagnosticSessionRepo,
+		analysisJobService,
+		masteryService,
 	)
 	
 	// 初始化处理器
 	learningGoalHandler := handlers.NewLearningGoalHandler(
 		goalAnalysisService,
+		analysisJobService,
+		progressService,
+		statusService,
+		taxonomyService,
+		learningGoalRepo,
 	)
+	recommendationHandler := handlers.NewRecommendationHandler(recommendationService)
+	diagnosticHandler := handlers.NewDiagnosticHandler(diagnosticService)
 	
 	// 学习目标路由组
 	goals := router.Group("/goals")
@@ -34,6 +112,63 @@ func SetupLearningGoalRoutes(router *gin.RouterGroup, db *gorm.DB) {
 		goals.GET("", learningGoalHandler.ListGoals)            // 获取学习目标列表
 		goals.PUT("/:id", learningGoalHandler.UpdateGoal)       // 更新学习目标
 		goals.DELETE("/:id", learningGoalHandler.DeleteGoal)    // 删除学习目标
-		goals.POST("/:id/analyze", learningGoalHandler.AnalyzeGoal) // 分析学习目标
+		goals.POST("/:id/analyze", learningGoalHandler.AnalyzeGoal) // 提交分析任务
+		goals.GET("/:id/analysis-jobs/:job_id", learningGoalHandler.GetAnalysisJob) // 查询分析任务状态
+		goals.GET("/:id/analyses", learningGoalHandler.ListAnalyses)              // 获取分析历史
+		goals.GET("/:id/analyses/latest", learningGoalHandler.GetLatestAnalysis)  // 获取综合分析或指定类型的最新分析
+		goals.POST("/:id/analyses/:analysis_id/recommendations/:recommendation_id/accept", recommendationHandler.AcceptRecommendation)   // 接受推荐
+		goals.POST("/:id/analyses/:analysis_id/recommendations/:recommendation_id/dismiss", recommendationHandler.DismissRecommendation) // 忽略推荐
+		goals.POST("/:id/analyses/:analysis_id/recommendations/:recommendation_id/snooze", recommendationHandler.SnoozeRecommendation)   // 暂缓推荐
+		goals.POST("/:id/diagnostics", diagnosticHandler.StartDiagnostic)                         // 开始自适应诊断
+		goals.GET("/:id/diagnostics/:session_id", diagnosticHandler.GetDiagnostic)                // 获取诊断状态和掌握度估计
+		goals.POST("/:id/diagnostics/:session_id/answers", diagnosticHandler.AnswerDiagnostic)    // 回答诊断题目
+		goals.PATCH("/:id/status", learningGoalHandler.UpdateGoalStatus)        // 更新学习目标状态
+		goals.GET("/:id/transitions", learningGoalHandler.GetGoalTransitions)   // 获取状态转换历史
+		goals.GET("/:id/progress", learningGoalHandler.GetGoalProgress)          // 获取学习进度
+		goals.GET("/:id/progress/history", learningGoalHandler.GetProgressHistory) // 获取进度历史
+	}
+}
+
+// newGoalAnalyzers 根据配置创建各单项分析器，未启用LLM时使用规则分析器
+func newGoalAnalyzers(db *gorm.DB, aiConfig *pkg.AIConfig, taxonomyService *services.TaxonomyService, ruleAnalyzer *services.RuleAnalyzer) services.Analyzers {
+	ruleAnalyzers := ruleAnalyzer.Analyzers()
+	if aiConfig == nil || aiConfig.Analyzer != services.AnalyzerLLM {
+		return ruleAnalyzers
+	}
+
+	client := llm.NewClient(llm.Config{
+		BaseURL:    aiConfig.BaseURL,
+		APIKey:     aiConfig.APIKey,
+		Model:      aiConfig.Model,
+		Timeout:    aiConfig.Timeout,
+		MaxRetries: aiConfig.MaxRetries,
+	})
+
+	logger.Info("目标分析使用LLM分析器",
+		logger.String("base_url", aiConfig.BaseURL),
+		logger.String("model", aiConfig.Model))
+
+	return services.NewLLMAnalyzers(
+		client,
+		ruleAnalyzers,
+		repositories.NewAnalysisUsageRepository(db),
+		taxonomyService,
+		services.LLMAnalyzerConfig{
+			PromptCostPer1K:     aiConfig.PromptCostPer1K,
+			CompletionCostPer1K: aiConfig.CompletionCostPer1K,
+			MonthlyBudget:       aiConfig.UserMonthlyBudget,
+		},
+	)
+}
+
+// newAnalysisJobConfig 根据配置创建分析任务队列配置
+func newAnalysisJobConfig(aiConfig *pkg.AIConfig) services.AnalysisJobConfig {
+	if aiConfig == nil {
+		return services.AnalysisJobConfig{DedupWindow: time.Minute}
+	}
+	return services.AnalysisJobConfig{
+		Workers:     aiConfig.AnalysisWorkers,
+		DedupWindow: aiConfig.AnalysisDedupWindow,
+		JobTimeout:  aiConfig.AnalysisJobTimeout,
 	}
-}
\ No newline at end of file
+}
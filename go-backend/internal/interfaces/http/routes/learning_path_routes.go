@@ -8,26 +8,53 @@ import (
 	"sical-go-backend/internal/interfaces/http/handlers"
 )
 
-// SetupLearningPathRoutes 设置学习路径路由
-func SetupLearningPathRoutes(router *gin.Engine, db *gorm.DB) {
+// SetupLearningPathRoutes 设置学习路径路由，router须已挂载认证中间件，只能访问当前用户学习目标下的路径
+func SetupLearningPathRoutes(router *gin.RouterGroup, db *gorm.DB) {
 	// 初始化仓储层
 	learningGoalRepo := repositories.NewLearningGoalRepository(db)
 	learningPathRepo := repositories.NewLearningPathRepository(db)
 	knowledgePointRepo := repositories.NewKnowledgePointRepository(db)
+	stepTimeLogRepo := repositories.NewStepTimeLogRepository(db)
+	progressHistoryRepo := repositories.NewGoalProgressHistoryRepository(db)
+	statusTransitionRepo := repositories.NewStatusTransitionRepository(db)
+	taxonomyRepo := repositories.NewTaxonomyRepository(db)
+	masteryRepo := repositories.NewUserKnowledgeMasteryRepository(db)
+	ratingRepo := repositories.NewRatingRepository(db)
+	renderingRepo := repositories.NewKnowledgeRenderingRepository(db)
 
 	// 初始化服务层
+	taxonomyService := services.NewTaxonomyService(taxonomyRepo)
+	masteryService := services.NewMasteryService(masteryRepo, knowledgePointRepo)
+	ratingService := services.NewRatingService(ratingRepo, knowledgePointRepo, learningPathRepo)
+	renderService := services.NewKnowledgeRenderService(knowledgePointRepo, renderingRepo)
+	progressService := services.NewProgressService(
+		learningGoalRepo,
+		learningPathRepo,
+		stepTimeLogRepo,
+		progressHistoryRepo,
+	)
+	statusService := services.NewStatusService(
+		learningGoalRepo,
+		learningPathRepo,
+		statusTransitionRepo,
+		progressService,
+	)
+	statusService.OnAfterTransition(masteryService.OnPathTransition)
 	pathService := services.NewLearningPathService(
 		learningPathRepo,
 		learningGoalRepo,
 		knowledgePointRepo,
+		statusService,
+		taxonomyService,
+		ratingService,
+		renderService,
 	)
 
 	// 初始化处理器
-	pathHandler := handlers.NewLearningPathHandler(pathService)
+	pathHandler := handlers.NewLearningPathHandler(pathService, progressService, statusService)
 
 	// 学习路径路由组
-	pathGroup := router.Group("/api/v1/learning-paths")
-	// TODO: 添加认证中间件
+	pathGroup := router.Group("/learning-paths")
 	{
 		// 生成学习路径
 		pathGroup.POST("/generate", pathHandler.GenerateLearningPath)
@@ -44,6 +71,13 @@ func SetupLearningPathRoutes(router *gin.Engine, db *gorm.DB) {
 		// 更新学习路径状态
 		pathGroup.PATCH("/:id/status", pathHandler.UpdateLearningPathStatus)
 		
+		// 获取状态转换历史
+		pathGroup.GET("/:id/transitions", pathHandler.GetPathTransitions)
+		
+		// 记录/获取步骤学习时间
+		pathGroup.POST("/:id/time-logs", pathHandler.LogStepTime)
+		pathGroup.GET("/:id/time-logs", pathHandler.GetStepTimeLogs)
+		
 		// 删除学习路径
 		pathGroup.DELETE("/:id", pathHandler.DeleteLearningPath)
 	}
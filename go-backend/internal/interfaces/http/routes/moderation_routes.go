@@ -0,0 +1,72 @@
+package routes
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"sical-go-backend/internal/domain/services"
+	"sical-go-backend/internal/infrastructure/repositories"
+	"sical-go-backend/internal/interfaces/http/handlers"
+	"sical-go-backend/pkg/logger"
+)
+
+// moderationRuleRefreshInterval 定期刷新过滤规则的间隔，用于同步其他实例的规则修改
+const moderationRuleRefreshInterval = time.Minute
+
+// NewModerationService 创建内容审核服务并加载过滤规则，定期刷新规则的任务注册到workers随服务启动
+// 在路由组装处只创建一次，由用户服务、评论和审核路由共用同一实例
+func NewModerationService(db *gorm.DB, workers *Workers) *services.ModerationService {
+	// 初始化仓储层
+	ruleRepo := repositories.NewModerationRuleRepository(db)
+	caseRepo := repositories.NewModerationCaseRepository(db)
+	userRepo := repositories.NewUserRepository(db)
+	profileRepo := repositories.NewUserProfileRepository(db)
+	sessionRepo := repositories.NewUserSessionRepository(db)
+	commentRepo := repositories.NewCommentRepository(db)
+
+	// 初始化服务层
+	keywordModerator := services.NewKeywordModerator(ruleRepo)
+	if err := keywordModerator.Reload(context.Background()); err != nil {
+		logger.Error("加载内容过滤规则失败", logger.String("error", err.Error()))
+	}
+	workers.Add("moderation-rules", func(ctx context.Context) {
+		keywordModerator.Start(ctx, moderationRuleRefreshInterval)
+	})
+	moderationService := services.NewModerationService(
+		keywordModerator,
+		ruleRepo,
+		caseRepo,
+		userRepo,
+		profileRepo,
+		sessionRepo,
+		commentRepo,
+	)
+	return moderationService
+}
+
+// SetupModerationRoutes 设置内容审核路由
+// router 挂载需要认证的举报接口，moderation 挂载需要审核员权限的审核队列，admin 挂载过滤规则维护接口
+func SetupModerationRoutes(router *gin.RouterGroup, moderation *gin.RouterGroup, admin *gin.RouterGroup, moderationService *services.ModerationService) {
+	// 初始化处理器
+	moderationHandler := handlers.NewModerationHandler(moderationService)
+
+	// 用户举报
+	router.POST("/reports", moderationHandler.Report)
+
+	// 审核员接口
+	moderation.GET("/queue", moderationHandler.ListQueue)                // 获取审核队列
+	moderation.GET("/cases/:id", moderationHandler.GetCase)              // 获取审核案件详情
+	moderation.POST("/cases/:id/resolve", moderationHandler.ResolveCase) // 通过、驳回、暂停或封禁
+
+	// 过滤规则维护接口
+	rules := admin.Group("/moderation/rules")
+	{
+		rules.GET("", moderationHandler.ListRules)           // 获取过滤规则
+		rules.POST("", moderationHandler.CreateRule)         // 创建过滤规则
+		rules.PUT("/:id", moderationHandler.UpdateRule)      // 更新过滤规则
+		rules.DELETE("/:id", moderationHandler.DeleteRule)   // 删除过滤规则
+		rules.POST("/reload", moderationHandler.ReloadRules) // 立即重新加载过滤规则
+	}
+}
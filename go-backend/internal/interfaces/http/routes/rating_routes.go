@@ -0,0 +1,35 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"sical-go-backend/internal/domain/services"
+	"sical-go-backend/internal/infrastructure/repositories"
+	"sical-go-backend/internal/interfaces/http/handlers"
+)
+
+// SetupRatingRoutes 设置知识点和学习路径的评分与评价路由
+func SetupRatingRoutes(router *gin.RouterGroup, db *gorm.DB) {
+	// 初始化仓储层
+	ratingRepo := repositories.NewRatingRepository(db)
+	knowledgePointRepo := repositories.NewKnowledgePointRepository(db)
+	learningPathRepo := repositories.NewLearningPathRepository(db)
+
+	// 初始化服务层
+	ratingService := services.NewRatingService(ratingRepo, knowledgePointRepo, learningPathRepo)
+
+	// 初始化处理器
+	ratingHandler := handlers.NewRatingHandler(ratingService)
+
+	ratings := router.Group("/ratings")
+	{
+		ratings.GET("/top", ratingHandler.ListTopRated)                               // 按加权评分获取排行
+		ratings.POST("/reviews/:id/helpful", ratingHandler.MarkHelpful)               // 标记评价有帮助
+		ratings.DELETE("/reviews/:id/helpful", ratingHandler.UnmarkHelpful)           // 取消有帮助投票
+		ratings.GET("/:target_type/:target_id", ratingHandler.GetSummary)             // 获取评分汇总
+		ratings.PUT("/:target_type/:target_id", ratingHandler.Rate)                   // 评分或修改评分
+		ratings.GET("/:target_type/:target_id/mine", ratingHandler.GetMyRating)       // 获取我的评分
+		ratings.DELETE("/:target_type/:target_id/mine", ratingHandler.DeleteMyRating) // 删除我的评分
+		ratings.GET("/:target_type/:target_id/reviews", ratingHandler.ListReviews)    // 获取文字评价
+	}
+}
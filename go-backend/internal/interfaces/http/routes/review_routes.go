@@ -0,0 +1,34 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"sical-go-backend/internal/domain/services"
+	"sical-go-backend/internal/infrastructure/repositories"
+	"sical-go-backend/internal/interfaces/http/handlers"
+)
+
+// SetupReviewRoutes 设置知识点掌握度与间隔复习路由
+func SetupReviewRoutes(router *gin.RouterGroup, db *gorm.DB) {
+	// 初始化仓储层
+	masteryRepo := repositories.NewUserKnowledgeMasteryRepository(db)
+	knowledgePointRepo := repositories.NewKnowledgePointRepository(db)
+
+	// 初始化服务层
+	masteryService := services.NewMasteryService(masteryRepo, knowledgePointRepo)
+
+	// 初始化处理器
+	reviewHandler := handlers.NewReviewHandler(masteryService)
+
+	review := router.Group("/review")
+	{
+		review.GET("/due", reviewHandler.ListDue)                       // 获取今天需要复习的知识点
+		review.POST("/:knowledge_point_id", reviewHandler.SubmitReview) // 提交复习结果
+	}
+
+	mastery := router.Group("/mastery")
+	{
+		mastery.GET("", reviewHandler.ListMastery)                    // 获取全部知识点掌握度
+		mastery.GET("/:knowledge_point_id", reviewHandler.GetMastery) // 获取单个知识点掌握度
+	}
+}
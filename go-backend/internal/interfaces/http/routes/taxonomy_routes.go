@@ -0,0 +1,45 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"sical-go-backend/internal/domain/services"
+	"sical-go-backend/internal/infrastructure/repositories"
+	"sical-go-backend/internal/interfaces/http/handlers"
+)
+
+// SetupTaxonomyRoutes 设置类别与技能体系路由
+// router 挂载只读接口，admin 挂载需要管理员权限的维护接口
+func SetupTaxonomyRoutes(router *gin.RouterGroup, admin *gin.RouterGroup, db *gorm.DB) {
+	// 初始化仓储层
+	taxonomyRepo := repositories.NewTaxonomyRepository(db)
+
+	// 初始化服务层
+	taxonomyService := services.NewTaxonomyService(taxonomyRepo)
+
+	// 初始化处理器
+	taxonomyHandler := handlers.NewTaxonomyHandler(taxonomyService)
+
+	// 只读接口
+	taxonomy := router.Group("/taxonomy")
+	{
+		taxonomy.GET("/categories", taxonomyHandler.ListCategories)               // 获取类别列表
+		taxonomy.GET("/categories/:id", taxonomyHandler.GetCategory)              // 获取类别详情
+		taxonomy.GET("/categories/:id/skills", taxonomyHandler.GetCategorySkills) // 获取类别技能映射
+		taxonomy.GET("/skills", taxonomyHandler.ListSkills)                       // 获取技能列表
+	}
+
+	// 管理接口
+	manage := admin.Group("/taxonomy")
+	{
+		manage.POST("/categories", taxonomyHandler.CreateCategory)                               // 创建类别
+		manage.PUT("/categories/:id", taxonomyHandler.UpdateCategory)                            // 更新类别
+		manage.DELETE("/categories/:id", taxonomyHandler.DeleteCategory)                         // 删除类别
+		manage.POST("/categories/:id/skills", taxonomyHandler.AddCategorySkill)                  // 添加类别技能映射
+		manage.DELETE("/categories/:id/skills/:mapping_id", taxonomyHandler.RemoveCategorySkill) // 删除类别技能映射
+		manage.PUT("/categories/:id/durations/:difficulty", taxonomyHandler.SetCategoryDuration) // 设置难度基准时间
+		manage.POST("/skills", taxonomyHandler.CreateSkill)                                      // 创建技能
+		manage.PUT("/skills/:id", taxonomyHandler.UpdateSkill)                                   // 更新技能
+		manage.DELETE("/skills/:id", taxonomyHandler.DeleteSkill)                                // 删除技能
+	}
+}
@@ -0,0 +1,68 @@
+package routes
+
+import (
+	"context"
+	"sync"
+
+	"sical-go-backend/pkg/logger"
+)
+
+// Workers 后台任务注册表
+// 设置路由时只注册任务，由服务生命周期在启动时以可取消的上下文统一运行，关闭时统一停止
+type Workers struct {
+	mu     sync.Mutex
+	tasks  []workerTask
+	ctx    context.Context // 启动后的运行上下文，未启动时为空
+	cancel context.CancelFunc
+}
+
+// workerTask 注册的后台任务，start 须启动协程后立即返回，ctx取消时协程退出
+type workerTask struct {
+	name  string
+	start func(ctx context.Context)
+}
+
+// NewWorkers 创建后台任务注册表
+func NewWorkers() *Workers {
+	return &Workers{}
+}
+
+// Add 注册后台任务，已启动时以当前上下文立即运行
+func (w *Workers) Add(name string, start func(ctx context.Context)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.tasks = append(w.tasks, workerTask{name: name, start: start})
+	if w.ctx != nil {
+		start(w.ctx)
+		logger.Info("后台任务已启动", logger.String("worker", name))
+	}
+}
+
+// Start 以ctx派生的可取消上下文启动全部已注册的后台任务
+// 重复调用无效，停止后也不能再次启动
+func (w *Workers) Start(ctx context.Context) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.ctx != nil {
+		return
+	}
+	w.ctx, w.cancel = context.WithCancel(ctx)
+	for _, task := range w.tasks {
+		task.start(w.ctx)
+		logger.Info("后台任务已启动", logger.String("worker", task.name))
+	}
+}
+
+// Stop 取消后台任务的上下文，通知全部后台任务退出
+func (w *Workers) Stop() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.cancel == nil {
+		return
+	}
+	w.cancel()
+	logger.Info("后台任务已停止", logger.Int("workers", len(w.tasks)))
+}
@@ -17,6 +17,8 @@ type Config struct {
 	JWT      JWTConfig      `json:"jwt"`
 	App      AppConfig      `json:"app"`
 	Log      LogConfig      `json:"log"`
+	AI       AIConfig       `json:"ai"`
+	Storage  StorageConfig  `json:"storage"`
 }
 
 // ServerConfig 服务器配置
@@ -85,6 +87,30 @@ type LogConfig struct {
 	Compress   bool   `json:"compress"`
 }
 
+// AIConfig 目标分析模型配置
+type AIConfig struct {
+	Analyzer            string        `json:"analyzer"` // rules, llm
+	BaseURL             string        `json:"base_url"` // OpenAI兼容接口地址
+	APIKey              string        `json:"-"`
+	Model               string        `json:"model"`
+	Timeout             time.Duration `json:"timeout"`
+	MaxRetries          int           `json:"max_retries"`
+	PromptCostPer1K     float64       `json:"prompt_cost_per_1k"`     // 美元
+	CompletionCostPer1K float64       `json:"completion_cost_per_1k"` // 美元
+	UserMonthlyBudget   float64       `json:"user_monthly_budget"`    // 美元，0表示不限
+	AnalysisWorkers     int           `json:"analysis_workers"`       // 分析任务并发数
+	AnalysisDedupWindow time.Duration `json:"analysis_dedup_window"`  // 相同分析请求的去重窗口
+	AnalysisJobTimeout  time.Duration `json:"analysis_job_timeout"`
+}
+
+// StorageConfig 上传文件存储配置
+type StorageConfig struct {
+	LocalDir        string        `json:"local_dir"`         // 本地存储根目录
+	SigningKey      string        `json:"-"`                 // 下载链接签名密钥，为空时每次启动随机生成
+	URLExpiration   time.Duration `json:"url_expiration"`    // 下载链接有效期
+	MaxUploadSizeMB int           `json:"max_upload_size_mb"` // 单个上传文件的最大MB数
+}
+
 // LoadConfig 加载配置
 func LoadConfig() (*Config, error) {
 	// 加载.env文件
@@ -148,6 +174,26 @@ func LoadConfig() (*Config, error) {
 			MaxAge:     getEnvAsInt("LOG_MAX_AGE", 28),
 			Compress:   getEnvAsBool("LOG_COMPRESS", true),
 		},
+		AI: AIConfig{
+			Analyzer:            getEnv("AI_ANALYZER", "rules"),
+			BaseURL:             getEnv("LLM_BASE_URL", "https://api.openai.com/v1"),
+			APIKey:              getEnv("LLM_API_KEY", ""),
+			Model:               getEnv("LLM_MODEL", "gpt-4o-mini"),
+			Timeout:             getEnvAsDuration("LLM_TIMEOUT", "30s"),
+			MaxRetries:          getEnvAsInt("LLM_MAX_RETRIES", 2),
+			PromptCostPer1K:     getEnvAsFloat("LLM_PROMPT_COST_PER_1K", 0.00015),
+			CompletionCostPer1K: getEnvAsFloat("LLM_COMPLETION_COST_PER_1K", 0.0006),
+			UserMonthlyBudget:   getEnvAsFloat("LLM_USER_MONTHLY_BUDGET", 1.0),
+			AnalysisWorkers:     getEnvAsInt("ANALYSIS_WORKERS", 2),
+			AnalysisDedupWindow: getEnvAsDuration("ANALYSIS_DEDUP_WINDOW", "1m"),
+			AnalysisJobTimeout:  getEnvAsDuration("ANALYSIS_JOB_TIMEOUT", "2m"),
+		},
+		Storage: StorageConfig{
+			LocalDir:        getEnv("STORAGE_LOCAL_DIR", "uploads"),
+			SigningKey:      getEnv("STORAGE_SIGNING_KEY", ""),
+			URLExpiration:   getEnvAsDuration("STORAGE_URL_EXPIRATION", "15m"),
+			MaxUploadSizeMB: getEnvAsInt("STORAGE_MAX_UPLOAD_SIZE_MB", 10),
+		},
 	}
 
 	// 验证配置
@@ -180,6 +226,14 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("JWT secret must be set and not use default value")
 	}
 
+	if c.AI.Analyzer != "rules" && c.AI.Analyzer != "llm" {
+		return fmt.Errorf("invalid AI analyzer: %s", c.AI.Analyzer)
+	}
+
+	if c.AI.Analyzer == "llm" && c.AI.BaseURL == "" {
+		return fmt.Errorf("LLM base URL is required when AI analyzer is llm")
+	}
+
 	return nil
 }
 
@@ -227,6 +281,15 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
 func getEnvAsBool(key string, defaultValue bool) bool {
 	if value := os.Getenv(key); value != "" {
 		if boolValue, err := strconv.ParseBool(value); err == nil {
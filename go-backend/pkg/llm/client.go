@@ -0,0 +1,195 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Config LLM客户端配置
+type Config struct {
+	BaseURL    string // OpenAI兼容接口地址，如 https://api.openai.com/v1
+	APIKey     string
+	Model      string
+	Timeout    time.Duration // 单次请求超时
+	MaxRetries int           // 失败后的最大重试次数
+}
+
+// defaultRetryBackoff 首次重试前的等待时间，之后每次翻倍
+const defaultRetryBackoff = 500 * time.Millisecond
+
+// Client OpenAI兼容的Chat Completions客户端
+type Client struct {
+	config       Config
+	httpClient   *http.Client
+	retryBackoff time.Duration
+}
+
+// NewClient 创建LLM客户端
+func NewClient(config Config) *Client {
+	if config.Timeout <= 0 {
+		config.Timeout = 30 * time.Second
+	}
+	if config.MaxRetries < 0 {
+		config.MaxRetries = 0
+	}
+	config.BaseURL = strings.TrimRight(config.BaseURL, "/")
+
+	return &Client{
+		config:       config,
+		httpClient:   &http.Client{Timeout: config.Timeout},
+		retryBackoff: defaultRetryBackoff,
+	}
+}
+
+// Model 返回客户端使用的模型
+func (c *Client) Model() string {
+	return c.config.Model
+}
+
+// Message 对话消息
+type Message struct {
+	Role    string `json:"role"` // system, user, assistant
+	Content string `json:"content"`
+}
+
+// JSONSchema 结构化输出的JSON Schema
+type JSONSchema struct {
+	Name   string                 `json:"name"`
+	Schema map[string]interface{} `json:"schema"`
+	Strict bool                   `json:"strict"`
+}
+
+// ResponseFormat 输出格式
+type ResponseFormat struct {
+	Type       string      `json:"type"` // json_schema
+	JSONSchema *JSONSchema `json:"json_schema,omitempty"`
+}
+
+// ChatRequest 对话请求
+type ChatRequest struct {
+	Model          string          `json:"model"`
+	Messages       []Message       `json:"messages"`
+	ResponseFormat *ResponseFormat `json:"response_format,omitempty"`
+	Temperature    float64         `json:"temperature"`
+}
+
+// Usage token用量
+type Usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// ChatResponse 对话响应
+type ChatResponse struct {
+	ID      string `json:"id"`
+	Model   string `json:"model"`
+	Choices []struct {
+		Message      Message `json:"message"`
+		FinishReason string  `json:"finish_reason"`
+	} `json:"choices"`
+	Usage Usage `json:"usage"`
+}
+
+// Content 返回第一条回复内容
+func (r *ChatResponse) Content() string {
+	if len(r.Choices) == 0 {
+		return ""
+	}
+	return r.Choices[0].Message.Content
+}
+
+// APIError 接口返回的错误
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("LLM接口返回错误 %d: %s", e.StatusCode, e.Body)
+}
+
+// retryable 限流和服务端错误可以重试
+func (e *APIError) retryable() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+// ChatCompletion 调用Chat Completions接口，网络错误、限流和服务端错误时按指数退避重试
+func (c *Client) ChatCompletion(ctx context.Context, req *ChatRequest) (*ChatResponse, error) {
+	if req.Model == "" {
+		req.Model = c.config.Model
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("序列化LLM请求失败: %w", err)
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= c.config.MaxRetries; attempt++ {
+		if attempt > 0 {
+			backoff := time.Duration(1<<uint(attempt-1)) * c.retryBackoff
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(backoff):
+			}
+		}
+
+		resp, err := c.do(ctx, body)
+		if err == nil {
+			return resp, nil
+		}
+		lastErr = err
+
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && !apiErr.retryable() {
+			break
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	return nil, lastErr
+}
+
+// do 发送单次请求
+func (c *Client) do(ctx context.Context, body []byte) (*ChatResponse, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.config.BaseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("创建LLM请求失败: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if c.config.APIKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+c.config.APIKey)
+	}
+
+	httpResp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("请求LLM接口失败: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(httpResp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("读取LLM响应失败: %w", err)
+	}
+
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, &APIError{StatusCode: httpResp.StatusCode, Body: string(respBody)}
+	}
+
+	var resp ChatResponse
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, fmt.Errorf("解析LLM响应失败: %w", err)
+	}
+	return &resp, nil
+}
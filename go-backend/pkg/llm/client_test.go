@@ -0,0 +1,140 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestClient 创建指向测试服务的客户端，缩短重试等待
+func newTestClient(baseURL string, timeout time.Duration, maxRetries int) *Client {
+	client := NewClient(Config{BaseURL: baseURL, Model: "test-model", Timeout: timeout, MaxRetries: maxRetries})
+	client.retryBackoff = time.Millisecond
+	return client
+}
+
+// statusSequence 按顺序返回给定状态码，最后一个状态码之后一直返回成功
+func statusSequence(calls *atomic.Int64, statuses ...int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		n := int(calls.Add(1))
+		w.Header().Set("Content-Type", "application/json")
+		if n <= len(statuses) {
+			w.WriteHeader(statuses[n-1])
+			_, _ = w.Write([]byte(`{"error":{"message":"failed"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":"1","model":"test-model","choices":[{"message":{"role":"assistant","content":"ok"}}],"usage":{"prompt_tokens":3,"completion_tokens":1,"total_tokens":4}}`))
+	}
+}
+
+func TestChatCompletionRetriesRateLimitAndServerErrors(t *testing.T) {
+	var calls atomic.Int64
+	server := httptest.NewServer(statusSequence(&calls, http.StatusTooManyRequests, http.StatusServiceUnavailable))
+	defer server.Close()
+
+	resp, err := newTestClient(server.URL, time.Second, 2).ChatCompletion(context.Background(), &ChatRequest{
+		Messages: []Message{{Role: "user", Content: "hi"}},
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletion() error = %v", err)
+	}
+	if resp.Content() != "ok" || resp.Usage.TotalTokens != 4 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+	if got := calls.Load(); got != 3 {
+		t.Fatalf("calls = %d, want 3", got)
+	}
+}
+
+func TestChatCompletionStopsAfterMaxRetries(t *testing.T) {
+	var calls atomic.Int64
+	server := httptest.NewServer(statusSequence(&calls, 500, 500, 500, 500))
+	defer server.Close()
+
+	_, err := newTestClient(server.URL, time.Second, 1).ChatCompletion(context.Background(), &ChatRequest{
+		Messages: []Message{{Role: "user", Content: "hi"}},
+	})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("error = %v, want APIError 500", err)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Fatalf("calls = %d, want 2", got)
+	}
+}
+
+func TestChatCompletionDoesNotRetryClientErrors(t *testing.T) {
+	for _, status := range []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound} {
+		var calls atomic.Int64
+		server := httptest.NewServer(statusSequence(&calls, status))
+
+		_, err := newTestClient(server.URL, time.Second, 3).ChatCompletion(context.Background(), &ChatRequest{
+			Messages: []Message{{Role: "user", Content: "hi"}},
+		})
+		server.Close()
+
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != status {
+			t.Fatalf("status %d: error = %v, want APIError", status, err)
+		}
+		if got := calls.Load(); got != 1 {
+			t.Fatalf("status %d: calls = %d, want 1", status, got)
+		}
+	}
+}
+
+func TestChatCompletionTimeout(t *testing.T) {
+	var calls atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		// 读完请求体后服务端才能感知客户端断开
+		_, _ = io.Copy(io.Discard, r.Body)
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer server.Close()
+
+	start := time.Now()
+	_, err := newTestClient(server.URL, 50*time.Millisecond, 1).ChatCompletion(context.Background(), &ChatRequest{
+		Messages: []Message{{Role: "user", Content: "hi"}},
+	})
+	if err == nil {
+		t.Fatal("ChatCompletion() error = nil, want timeout")
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		t.Fatalf("error = %v, want transport timeout", err)
+	}
+	if elapsed := time.Since(start); elapsed >= time.Second {
+		t.Fatalf("elapsed = %v, request was not cut off by the timeout", elapsed)
+	}
+	// 超时属于网络错误，会按重试次数再请求一次
+	if got := calls.Load(); got != 2 {
+		t.Fatalf("calls = %d, want 2", got)
+	}
+}
+
+func TestChatCompletionContextCanceled(t *testing.T) {
+	var calls atomic.Int64
+	server := httptest.NewServer(statusSequence(&calls, 503, 503, 503))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := newTestClient(server.URL, time.Second, 3).ChatCompletion(ctx, &ChatRequest{
+		Messages: []Message{{Role: "user", Content: "hi"}},
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("error = %v, want context.Canceled", err)
+	}
+	if got := calls.Load(); got != 0 {
+		t.Fatalf("calls = %d, want 0", got)
+	}
+}
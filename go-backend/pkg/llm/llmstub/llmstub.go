@@ -0,0 +1,207 @@
+// Package llmstub 提供OpenAI兼容Chat Completions接口的本地桩服务
+// 按请求中的JSON Schema生成确定性的结构化输出，用于本地开发和测试，不调用任何付费模型
+package llmstub
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+
+	"sical-go-backend/pkg/llm"
+)
+
+// Handler 桩服务的HTTP处理器，处理以 /chat/completions 结尾的POST请求
+type Handler struct {
+	Latency time.Duration // 每次响应前的等待时间，用于模拟慢速模型
+
+	requests atomic.Int64
+}
+
+// NewHandler 创建桩服务处理器
+func NewHandler() *Handler {
+	return &Handler{}
+}
+
+// Requests 已处理的请求数
+func (h *Handler) Requests() int64 {
+	return h.requests.Load()
+}
+
+// ServeHTTP 实现http.Handler
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || !strings.HasSuffix(r.URL.Path, "/chat/completions") {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	h.requests.Add(1)
+
+	var req llm.ChatRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request: %v", err))
+		return
+	}
+	if len(req.Messages) == 0 {
+		writeError(w, http.StatusBadRequest, "messages is required")
+		return
+	}
+
+	if h.Latency > 0 {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-time.After(h.Latency):
+		}
+	}
+
+	content := "ok"
+	if req.ResponseFormat != nil && req.ResponseFormat.JSONSchema != nil {
+		data, err := json.Marshal(generate(req.ResponseFormat.JSONSchema.Schema, parsePrompt(req.Messages)))
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		content = string(data)
+	}
+
+	promptChars := 0
+	for _, message := range req.Messages {
+		promptChars += utf8.RuneCountInString(message.Content)
+	}
+	resp := map[string]interface{}{
+		"id":    fmt.Sprintf("stub-%d", h.requests.Load()),
+		"model": req.Model,
+		"choices": []map[string]interface{}{{
+			"message":       llm.Message{Role: "assistant", Content: content},
+			"finish_reason": "stop",
+		}},
+		"usage": llm.Usage{
+			PromptTokens:     estimateTokens(promptChars),
+			CompletionTokens: estimateTokens(utf8.RuneCountInString(content)),
+			TotalTokens:      estimateTokens(promptChars) + estimateTokens(utf8.RuneCountInString(content)),
+		},
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// stubPrompt 从用户提示词中读取的目标和知识点，提示词不是JSON时为空
+type stubPrompt struct {
+	Goal struct {
+		Difficulty string `json:"difficulty"`
+	} `json:"goal"`
+	KnowledgePoints []struct {
+		Title    string `json:"title"`
+		Mastered bool   `json:"mastered"`
+	} `json:"knowledge_points"`
+}
+
+// parsePrompt 解析最后一条用户消息
+func parsePrompt(messages []llm.Message) *stubPrompt {
+	prompt := &stubPrompt{}
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role == "user" {
+			_ = json.Unmarshal([]byte(messages[i].Content), prompt)
+			break
+		}
+	}
+	return prompt
+}
+
+// generate 按Schema的必填字段生成输出，已知的分析字段依据提示词中的知识点掌握情况填写
+func generate(schema map[string]interface{}, prompt *stubPrompt) map[string]interface{} {
+	var gaps, strengths []string
+	for _, point := range prompt.KnowledgePoints {
+		if point.Mastered {
+			strengths = append(strengths, point.Title)
+		} else {
+			gaps = append(gaps, point.Title)
+		}
+	}
+
+	properties, _ := schema["properties"].(map[string]interface{})
+	required, _ := schema["required"].([]interface{})
+	output := make(map[string]interface{}, len(required))
+	for _, field := range required {
+		name, _ := field.(string)
+		switch name {
+		case "skill_gaps":
+			output[name] = nonNil(gaps)
+		case "strengths":
+			output[name] = nonNil(strengths)
+		case "difficulty_level":
+			level := prompt.Goal.Difficulty
+			if level != "beginner" && level != "intermediate" && level != "advanced" {
+				level = "intermediate"
+			}
+			output[name] = level
+		case "difficulty_factors":
+			output[name] = []string{fmt.Sprintf("%d个知识点尚未掌握", len(gaps))}
+		case "estimated_time":
+			output[name] = 10 * (len(gaps) + 1)
+		case "confidence_score":
+			output[name] = 0.5
+		case "recommendations":
+			recommendations := []map[string]interface{}{}
+			if len(gaps) > 0 {
+				recommendations = append(recommendations, map[string]interface{}{
+					"type":             "resource",
+					"title":            "复习未掌握的知识点",
+					"description":      strings.Join(gaps, ", "),
+					"priority":         "medium",
+					"estimated_time":   10 * len(gaps),
+					"reason":           fmt.Sprintf("%d个知识点尚未掌握", len(gaps)),
+					"knowledge_points": gaps,
+				})
+			}
+			output[name] = recommendations
+		default:
+			property, _ := properties[name].(map[string]interface{})
+			output[name] = zeroValue(property)
+		}
+	}
+	return output
+}
+
+// zeroValue 按Schema类型生成零值
+func zeroValue(property map[string]interface{}) interface{} {
+	switch property["type"] {
+	case "array":
+		return []interface{}{}
+	case "object":
+		return map[string]interface{}{}
+	case "integer", "number":
+		return 0
+	case "boolean":
+		return false
+	}
+	if enum, ok := property["enum"].([]interface{}); ok && len(enum) > 0 {
+		return enum[0]
+	}
+	return ""
+}
+
+// nonNil 保证切片序列化为空数组而不是null
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+// estimateTokens 按约4个字符一个token估算用量
+func estimateTokens(chars int) int {
+	return (chars + 3) / 4
+}
+
+// writeError 写入OpenAI格式的错误响应
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"error": map[string]string{"message": message, "type": "stub_error"},
+	})
+}
@@ -0,0 +1,31 @@
+package pinyin
+
+import (
+	"strings"
+	"unicode"
+
+	gopinyin "github.com/mozillazg/go-pinyin"
+)
+
+// Convert 将文本转换为不带声调的连续全拼和拼音首字母，均为小写
+// 汉字取常用读音，字母和数字原样保留，其余字符忽略。如："感冒灵 2号" 转换为 "ganmaoling2hao" 和 "gml2h"
+func Convert(text string) (string, string) {
+	args := gopinyin.NewArgs()
+	var full, initials strings.Builder
+	for _, r := range strings.ToLower(text) {
+		if unicode.Is(unicode.Han, r) {
+			readings := gopinyin.SinglePinyin(r, args)
+			if len(readings) == 0 || readings[0] == "" {
+				continue
+			}
+			full.WriteString(readings[0])
+			initials.WriteByte(readings[0][0])
+			continue
+		}
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			full.WriteRune(r)
+			initials.WriteRune(r)
+		}
+	}
+	return full.String(), initials.String()
+}
@@ -36,6 +36,7 @@ type PaginationMeta struct {
 	TotalPages  int   `json:"total_pages"`
 	HasNext     bool  `json:"has_next"`
 	HasPrev     bool  `json:"has_prev"`
+	NextCursor  string `json:"next_cursor,omitempty"` // 游标分页时用于获取下一页
 }
 
 // 响应状态码常量
@@ -167,4 +168,12 @@ func NewPaginationMeta(currentPage, perPage int, total int64) *PaginationMeta {
 		HasNext:     currentPage < totalPages,
 		HasPrev:     currentPage > 1,
 	}
+}
+
+// NewCursorPaginationMeta 创建游标分页元数据，nextCursor为空表示没有下一页
+func NewCursorPaginationMeta(currentPage, perPage int, total int64, nextCursor string) *PaginationMeta {
+	meta := NewPaginationMeta(currentPage, perPage, total)
+	meta.HasNext = nextCursor != ""
+	meta.NextCursor = nextCursor
+	return meta
 }
\ No newline at end of file